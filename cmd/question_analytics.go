@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+	"github.com/Infinite-Locus-Product/thums_up_backend/vendors"
+)
+
+var questionAnalyticsCmd = &cobra.Command{
+	Use:   "question-analytics",
+	Short: "Rebuild the materialized question analytics",
+	Long: `Counts the users who answered each question, and each option or rating value,
+per state, signup month and contest participation, replacing the previous
+counts in one transaction. Meant to run periodically from a scheduler; the
+admin analytics endpoint only reads these counts. Prints a summary as JSON.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runQuestionAnalytics()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(questionAnalyticsCmd)
+}
+
+func runQuestionAnalytics() {
+	db := vendors.InitDatabase()
+
+	analyticsService := services.NewQuestionAnalyticsService(
+		utils.NewTransactionManager(db),
+		repository.NewQuestionAnalyticsRepository(),
+		repository.NewQuestionRepository(),
+		repository.NewOptionMasterRepository(db),
+	)
+
+	response, err := analyticsService.Refresh(context.Background())
+	if err != nil {
+		log.Fatalf("Question analytics refresh failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(response); err != nil {
+		log.Fatalf("Failed to write summary: %v", err)
+	}
+}
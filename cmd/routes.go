@@ -16,9 +16,13 @@ func (s *Server) setupRouter() *gin.Engine {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 	router.Use(middlewares.CORSMiddleware())
+	router.Use(middlewares.LocalizeErrors(s.localization))
 	router.Use(middlewares.ErrorHandler())
 
 	routes.SetupHealthAndDocs(router)
+	if s.storageHandler != nil {
+		routes.SetupStorageRoutes(router, s.storageMountPath, s.storageHandler)
+	}
 	s.setupAPIRoutes(router)
 
 	return router
@@ -36,6 +40,7 @@ func (s *Server) setupAPIRoutes(router *gin.Engine) {
 		s.handlers.profile,
 		s.handlers.address,
 		s.handlers.question,
+		s.handlers.points,
 	)
 
 	routes.SetupQuestionRoutes(
@@ -50,6 +55,7 @@ func (s *Server) setupAPIRoutes(router *gin.Engine) {
 		s.db,
 		s.repositories.user,
 		s.handlers.thunderSeat,
+		s.handlers.moderation,
 	)
 
 	routes.SetupWinnerRoutes(
@@ -59,23 +65,27 @@ func (s *Server) setupAPIRoutes(router *gin.Engine) {
 		s.handlers.winner,
 	)
 
-	routes.SetupContestWeekRoutes(
+	routes.SetupLeaderboardRoutes(
 		api,
 		s.db,
 		s.repositories.user,
-		s.handlers.contestWeek,
+		s.handlers.leaderboard,
 	)
 
-	routes.SetupAvatarRoutes(
+	routes.SetupContestWeekRoutes(
 		api,
 		s.db,
 		s.repositories.user,
-		s.handlers.avatar,
+		s.handlers.contestWeek,
 	)
 
+	routes.SetupAvatarRoutes(api, s.handlers.avatar)
+
 	routes.SetupWebsiteStatusRoutes(api, s.handlers.websiteStatus)
 
 	routes.SetupStateRoutes(api, s.handlers.state)
 
-	routes.SetupAdminRoutes(api, s.handlers.winner)
+	routes.SetupLanguageRoutes(api, s.handlers.language)
+
+	routes.SetupAdminRoutes(api, s.handlers.winner, s.handlers.contestWeek, s.handlers.moderation, s.handlers.avatar, s.handlers.profile, s.handlers.question, s.handlers.language, s.handlers.translation, s.handlers.analytics)
 }
@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
@@ -97,9 +101,70 @@ func (s *Server) initVendors() {
 	s.infobipClient = vendors.InitInfobip()
 	log.Info("Infobip client initialized")
 
-	if err := s.initGCSService(); err != nil {
-		log.Fatalf("Failed to initialize GCS service (required): %v", err)
+	// Push notifications are best effort: without Firebase they are logged and skipped
+	s.firebaseClient = vendors.InitFirebase()
+
+	if err := s.initStorage(); err != nil {
+		log.Fatalf("Failed to initialize storage (required): %v", err)
+	}
+}
+
+// initStorage creates the storage driver selected by STORAGE_DRIVER
+func (s *Server) initStorage() error {
+	switch driver := s.cfg.StorageConfig.Driver; driver {
+	case "", "gcs":
+		return s.initGCSService()
+	case "local", "memory":
+		return s.initLocalStorage(driver)
+	default:
+		return fmt.Errorf("unknown storage driver %q, expected gcs, local or memory", driver)
+	}
+}
+
+// initLocalStorage creates a disk or in-memory driver whose public and signed URLs
+// are served by this server under the path of STORAGE_BASE_URL
+func (s *Server) initLocalStorage(driver string) error {
+	baseURL := s.cfg.StorageConfig.BaseURL
+	if baseURL == "" {
+		port := s.cfg.AppPort
+		if portFlag != "" {
+			port = portFlag
+		}
+		baseURL = fmt.Sprintf("http://localhost:%s/storage", port)
+	}
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil || parsedURL.Path == "" || parsedURL.Path == "/" {
+		return fmt.Errorf("STORAGE_BASE_URL must be an absolute URL with a path, got %q", baseURL)
+	}
+
+	signingKey := s.cfg.StorageConfig.SigningKey
+	if signingKey == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate storage signing key: %w", err)
+		}
+		signingKey = hex.EncodeToString(key)
+		log.Warn("STORAGE_SIGNING_KEY is not set; signed storage URLs will stop working when the server restarts")
+	}
+
+	var storage interface {
+		utils.GCSService
+		http.Handler
+	}
+	if driver == "memory" {
+		storage, err = utils.NewMemoryStorageService(baseURL, signingKey)
+	} else {
+		storage, err = utils.NewLocalStorageService(s.cfg.StorageConfig.LocalDir, baseURL, signingKey)
+	}
+	if err != nil {
+		return err
 	}
+
+	s.gcsService = storage
+	s.storageHandler = storage
+	s.storageMountPath = strings.TrimRight(parsedURL.Path, "/")
+	log.Infof("Using %s storage served at %s", driver, baseURL)
+	return nil
 }
 
 func (s *Server) initGCSService() error {
@@ -150,7 +215,8 @@ func (s *Server) initRepositories() {
 		otp:                    repository.NewOTPRepository(),
 		refreshToken:           repository.NewRefreshTokenRepository(),
 		address:                repository.NewGormRepository[entities.Address](),
-		avatar:                 repository.NewGormRepository[entities.Avatar](),
+		avatar:                 repository.NewAvatarRepository(),
+		userProfilePhoto:       repository.NewUserProfilePhotoRepository(),
 		state:                  repository.NewStateRepository(),
 		city:                   repository.NewCityRepository(),
 		pinCode:                repository.NewPinCodeRepository(),
@@ -158,13 +224,27 @@ func (s *Server) initRepositories() {
 		questionMasterLanguage: repository.NewQuestionMasterLanguageRepository(s.db),
 		optionMaster:           repository.NewOptionMasterRepository(s.db),
 		optionMasterLanguage:   repository.NewOptionMasterLanguageRepository(s.db),
+		questionVersion:        repository.NewQuestionVersionRepository(),
+		questionSection:        repository.NewQuestionSectionRepository(),
+		questionCondition:      repository.NewQuestionConditionRepository(),
+		questionSubmission:     repository.NewQuestionSubmissionRepository(),
+		language:               repository.NewLanguageRepository(),
 		userQuestionAnswer:     repository.NewUserQuestionAnswerRepository(s.db),
+		questionAnalytics:      repository.NewQuestionAnalyticsRepository(),
 		thunderSeat:            repository.NewThunderSeatRepository(),
+		thunderSeatRevision:    repository.NewGormRepository[entities.ThunderSeatRevision](),
+		thunderSeatReport:      repository.NewThunderSeatReportRepository(),
+		thunderSeatScreening:   repository.NewGormRepository[entities.ThunderSeatScreening](),
+		uploadSession:          repository.NewUploadSessionRepository(),
+		mediaAsset:             repository.NewMediaAssetRepository(),
 		winner:                 repository.NewWinnerRepository(),
 		contestWeek:            repository.NewContestWeekRepository(),
+		contestWeekRule:        repository.NewContestWeekRuleRepository(),
 		userAadharCard:         repository.NewUserAadharCardRepository(),
 		userAdditionalInfo:     repository.NewUserAdditionalInfoRepository(),
 		loginCount:             repository.NewLoginCountRepository(),
+		pointTransaction:       repository.NewPointTransactionRepository(),
+		leaderboard:            repository.NewLeaderboardRepository(),
 	}
 	log.Debug("All repositories initialized")
 }
@@ -172,15 +252,49 @@ func (s *Server) initRepositories() {
 func (s *Server) initHandlers() {
 	txnManager := utils.NewTransactionManager(s.db)
 
+	s.localization = services.NewLocalizationService(txnManager, s.repositories.language)
+	notificationService := services.NewNotificationService(s.firebaseClient, nil, s.localization)
+
+	// Shared so signed URLs and avatar images are cached across services
+	mediaResolver := services.NewMediaURLResolver(
+		txnManager,
+		s.repositories.avatar,
+		s.repositories.mediaAsset,
+		s.repositories.userProfilePhoto,
+		utils.NewObjectURLResolver(s.gcsService),
+	)
+
+	leaderboardService := services.NewLeaderboardService(
+		txnManager,
+		s.repositories.leaderboard,
+		s.repositories.contestWeek,
+		mediaResolver,
+	)
+
+	pointsService := services.NewPointsService(txnManager, s.repositories.pointTransaction, leaderboardService)
+
 	authService := services.NewAuthService(
 		txnManager,
 		s.repositories.user,
 		s.repositories.otp,
 		s.repositories.refreshToken,
 		s.repositories.loginCount,
+		pointsService,
 		s.infobipClient,
 	)
 
+	// Shared so question changes made through the admin API invalidate the
+	// questionnaires served to users
+	questionCatalog := services.NewQuestionCatalog(
+		txnManager,
+		s.repositories.question,
+		s.repositories.questionMasterLanguage,
+		s.repositories.optionMaster,
+		s.repositories.optionMasterLanguage,
+		s.repositories.questionSection,
+		s.repositories.questionCondition,
+	)
+
 	userService := services.NewUserService(
 		txnManager,
 		s.repositories.user,
@@ -190,18 +304,47 @@ func (s *Server) initHandlers() {
 		s.repositories.pinCode,
 		s.repositories.avatar,
 		s.gcsService,
+		mediaResolver,
 		s.repositories.userQuestionAnswer,
 		s.repositories.question,
 		s.repositories.questionMasterLanguage,
 		s.repositories.optionMaster,
 		s.repositories.optionMasterLanguage,
 		s.repositories.winner,
+		pointsService,
+		s.repositories.questionSection,
+		s.repositories.questionCondition,
+		s.localization,
+		questionCatalog,
+	)
+
+	filePolicy := utils.NewFilePolicy(
+		int64(s.cfg.UploadConfig.MaxAudioSizeMB)<<20,
+		int64(s.cfg.UploadConfig.MaxVideoSizeMB)<<20,
+		int64(s.cfg.UploadConfig.MaxImageSizeMB)<<20,
+	)
+
+	mediaPipelineService := services.NewMediaPipelineService(
+		txnManager,
+		s.repositories.mediaAsset,
+		s.gcsService,
+		s.workerPool,
+	)
+
+	profilePhotoService := services.NewProfilePhotoService(
+		txnManager,
+		s.repositories.userProfilePhoto,
+		s.gcsService,
+		mediaResolver,
 	)
 
 	avatarService := services.NewAvatarService(
 		txnManager,
 		s.repositories.avatar,
+		s.repositories.mediaAsset,
 		s.gcsService,
+		mediaResolver,
+		mediaPipelineService,
 	)
 
 	questionService := services.NewQuestionService(
@@ -209,19 +352,72 @@ func (s *Server) initHandlers() {
 		s.repositories.question,
 		s.repositories.userQuestionAnswer,
 		s.repositories.optionMaster,
+		s.repositories.questionVersion,
+		s.repositories.questionSection,
+		s.repositories.questionCondition,
+		s.repositories.questionSubmission,
+		s.localization,
+		questionCatalog,
+	)
+
+	translationService := services.NewTranslationService(
+		txnManager,
+		s.repositories.question,
+		s.repositories.optionMaster,
+		s.repositories.questionMasterLanguage,
+		s.repositories.optionMasterLanguage,
+		s.localization,
+		questionCatalog,
+	)
+
+	questionAnalyticsService := services.NewQuestionAnalyticsService(
+		txnManager,
+		s.repositories.questionAnalytics,
+		s.repositories.question,
+		s.repositories.optionMaster,
 	)
 
 	contestWeekService := services.NewContestWeekService(
 		txnManager,
 		s.repositories.contestWeek,
+		s.repositories.contestWeekRule,
+	)
+
+	screeningService := services.NewScreeningService(
+		txnManager,
+		s.repositories.thunderSeat,
+		s.repositories.thunderSeatScreening,
+		s.workerPool,
+		services.NewLocalScreeningChain(txnManager, s.repositories.thunderSeat),
+		pointsService,
 	)
 
 	thunderSeatService := services.NewThunderSeatService(
 		txnManager,
 		s.repositories.thunderSeat,
 		s.repositories.contestWeek,
+		s.repositories.contestWeekRule,
+		s.repositories.thunderSeatRevision,
+		s.repositories.uploadSession,
+		s.repositories.mediaAsset,
 		s.repositories.user,
 		s.gcsService,
+		mediaResolver,
+		filePolicy,
+		screeningService,
+		mediaPipelineService,
+		pointsService,
+	)
+
+	moderationService := services.NewModerationService(
+		txnManager,
+		s.repositories.thunderSeat,
+		s.repositories.thunderSeatReport,
+		s.gcsService,
+		pointsService,
+		s.repositories.user,
+		notificationService,
+		s.workerPool,
 	)
 
 	winnerService := services.NewWinnerService(
@@ -233,6 +429,7 @@ func (s *Server) initHandlers() {
 		s.repositories.userAadharCard,
 		s.repositories.userAdditionalInfo,
 		s.gcsService,
+		mediaResolver,
 	)
 
 	websiteStatusService := services.NewWebsiteStatusService(s.db, s.repositories.winner, s.repositories.contestWeek)
@@ -241,15 +438,21 @@ func (s *Server) initHandlers() {
 
 	s.handlers = &Handlers{
 		auth:          handlers.NewAuthHandler(authService),
-		profile:       handlers.NewProfileHandler(userService),
+		profile:       handlers.NewProfileHandler(userService, profilePhotoService, filePolicy),
 		address:       handlers.NewAddressHandler(userService),
-		avatar:        handlers.NewAvatarHandler(avatarService),
-		question:      handlers.NewQuestionHandler(questionService, userService),
-		thunderSeat:   handlers.NewThunderSeatHandler(thunderSeatService),
-		winner:        handlers.NewWinnerHandler(winnerService, s.gcsService),
+		avatar:        handlers.NewAvatarHandler(avatarService, filePolicy),
+		question:      handlers.NewQuestionHandler(questionService, userService, s.localization),
+		thunderSeat:   handlers.NewThunderSeatHandler(thunderSeatService, filePolicy, s.localization),
+		moderation:    handlers.NewModerationHandler(moderationService),
+		winner:        handlers.NewWinnerHandler(winnerService, s.gcsService, filePolicy),
 		contestWeek:   handlers.NewContestWeekHandler(contestWeekService),
 		websiteStatus: handlers.NewWebsiteStatusHandler(websiteStatusService),
 		state:         handlers.NewStateHandler(stateService),
+		points:        handlers.NewPointsHandler(pointsService),
+		leaderboard:   handlers.NewLeaderboardHandler(leaderboardService),
+		language:      handlers.NewLanguageHandler(s.localization),
+		translation:   handlers.NewTranslationHandler(translationService),
+		analytics:     handlers.NewQuestionAnalyticsHandler(questionAnalyticsService),
 	}
 
 	log.Debug("All handlers initialized")
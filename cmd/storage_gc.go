@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/config"
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+	"github.com/Infinite-Locus-Product/thums_up_backend/vendors"
+)
+
+var (
+	storageGCApply       bool
+	storageGCQuarantine  bool
+	storageGCGracePeriod time.Duration
+	storageGCReportFile  string
+)
+
+var storageGCCmd = &cobra.Command{
+	Use:   "storage-gc",
+	Short: "Remove stored objects no database row references",
+	Long: `Lists every known object class prefix, cross-references the objects with the
+rows that point at them and deletes, or with --quarantine moves aside, the
+unreferenced objects older than the grace period. Runs as a dry run unless
+--apply is given, and prints the report as JSON.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runStorageGC()
+	},
+}
+
+func init() {
+	storageGCCmd.Flags().BoolVar(&storageGCApply, "apply", false, "Delete or quarantine orphans instead of only reporting them")
+	storageGCCmd.Flags().BoolVar(&storageGCQuarantine, "quarantine", false, "Move orphans under "+constants.STORAGE_GC_QUARANTINE_PREFIX+" instead of deleting them")
+	storageGCCmd.Flags().DurationVar(&storageGCGracePeriod, "grace-period", constants.STORAGE_GC_DEFAULT_GRACE_PERIOD, "Only collect objects older than this")
+	storageGCCmd.Flags().StringVar(&storageGCReportFile, "report", "", "Write the report to this file instead of stdout")
+	rootCmd.AddCommand(storageGCCmd)
+}
+
+func runStorageGC() {
+	cfg := config.GetConfig()
+	srv := &Server{cfg: cfg}
+	if err := srv.initStorage(); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	db := vendors.InitDatabase()
+
+	gcService := services.NewStorageGCService(
+		utils.NewTransactionManager(db),
+		repository.NewStorageReferenceRepository(),
+		srv.gcsService,
+	)
+
+	report, err := gcService.Run(context.Background(), services.StorageGCOptions{
+		GracePeriod: storageGCGracePeriod,
+		DryRun:      !storageGCApply,
+		Quarantine:  storageGCQuarantine,
+	})
+	if err != nil {
+		log.Fatalf("Storage garbage collection failed: %v", err)
+	}
+
+	out := os.Stdout
+	if storageGCReportFile != "" {
+		f, err := os.Create(storageGCReportFile)
+		if err != nil {
+			log.Fatalf("Failed to create report file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/config"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+	"github.com/Infinite-Locus-Product/thums_up_backend/vendors"
+)
+
+var storageMigrateApply bool
+
+var storageMigrateCmd = &cobra.Command{
+	Use:   "storage-migrate",
+	Short: "Move stored objects to the per-class visibility policy",
+	Long: `Rewrites the Cache-Control of every object in a known object class so
+private objects are no longer kept by shared caches, and replaces Aadhaar image
+URLs stored by older releases with object paths. Runs as a dry run unless --apply
+is given. Once it has been applied, public access to the bucket can be limited to
+the avatars/ prefix.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runStorageMigration(storageMigrateApply)
+	},
+}
+
+func init() {
+	storageMigrateCmd.Flags().BoolVar(&storageMigrateApply, "apply", false, "Apply the changes instead of only reporting them")
+	rootCmd.AddCommand(storageMigrateCmd)
+}
+
+type storageMigrationReport struct {
+	ObjectsScanned  int
+	ObjectsUpdated  int
+	ObjectsFailed   int
+	AadharCards     int
+	AadharForeign   int
+	AadharRewritten int
+}
+
+func runStorageMigration(apply bool) {
+	cfg := config.GetConfig()
+	srv := &Server{cfg: cfg}
+	if err := srv.initStorage(); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	db := vendors.InitDatabase()
+
+	ctx := context.Background()
+	report := &storageMigrationReport{}
+
+	for _, class := range utils.ObjectClasses {
+		migrateObjectClass(ctx, srv.gcsService, class, apply, report)
+	}
+
+	if err := migrateAadharKeys(db, srv.gcsService, apply, report); err != nil {
+		log.Fatalf("Failed to migrate Aadhaar image keys: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"applied":          apply,
+		"objects_scanned":  report.ObjectsScanned,
+		"objects_updated":  report.ObjectsUpdated,
+		"objects_failed":   report.ObjectsFailed,
+		"aadhar_cards":     report.AadharCards,
+		"aadhar_rewritten": report.AadharRewritten,
+		"aadhar_foreign":   report.AadharForeign,
+	}).Info("Storage migration finished")
+}
+
+// migrateObjectClass sets the Cache-Control the class's objects are now uploaded with
+func migrateObjectClass(ctx context.Context, storage utils.GCSService, class utils.ObjectClass, apply bool, report *storageMigrationReport) {
+	objects, err := storage.ListObjects(ctx, class.Prefix)
+	if err != nil {
+		log.WithError(err).WithField("class", class.Name).Error("Failed to list objects")
+		return
+	}
+
+	cacheControl := utils.CacheControlFor(class.Prefix)
+	updated := 0
+	for _, object := range objects {
+		report.ObjectsScanned++
+		if !apply {
+			continue
+		}
+		if err := storage.SetCacheControl(ctx, object.Path, cacheControl); err != nil {
+			log.WithError(err).WithField("object_path", object.Path).Warn("Failed to update object metadata")
+			report.ObjectsFailed++
+			continue
+		}
+		updated++
+	}
+	report.ObjectsUpdated += updated
+
+	log.WithFields(log.Fields{
+		"class":         class.Name,
+		"visibility":    class.Visibility,
+		"cache_control": cacheControl,
+		"objects":       len(objects),
+		"updated":       updated,
+	}).Info("Migrated object class")
+}
+
+// migrateAadharKeys replaces Aadhaar image URLs with object paths, which the KYC
+// flow stores now that the images are private
+func migrateAadharKeys(db *gorm.DB, storage utils.GCSService, apply bool, report *storageMigrationReport) error {
+	var cards []entities.UserAadharCard
+	if err := db.Where("aadhar_front_key LIKE 'http%' OR aadhar_back_key LIKE 'http%'").Find(&cards).Error; err != nil {
+		return err
+	}
+	report.AadharCards = len(cards)
+
+	toObjectPath := func(stored string) string {
+		if !strings.HasPrefix(stored, "http://") && !strings.HasPrefix(stored, "https://") {
+			return stored
+		}
+		if objectPath, ok := storage.ObjectPathFromURL(stored); ok {
+			return objectPath
+		}
+		report.AadharForeign++
+		return stored
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, card := range cards {
+			front := toObjectPath(card.AadharFrontKey)
+			back := toObjectPath(card.AadharBackKey)
+			if front == card.AadharFrontKey && back == card.AadharBackKey {
+				continue
+			}
+			report.AadharRewritten++
+			if !apply {
+				continue
+			}
+			err := tx.Model(&entities.UserAadharCard{}).Where("id = ?", card.ID).Updates(map[string]interface{}{
+				"aadhar_front_key": front,
+				"aadhar_back_key":  back,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"net/http"
+
 	"gorm.io/gorm"
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/config"
@@ -8,6 +10,7 @@ import (
 	"github.com/Infinite-Locus-Product/thums_up_backend/handlers"
 	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/queue"
 	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 	"github.com/Infinite-Locus-Product/thums_up_backend/vendors"
 )
@@ -18,9 +21,14 @@ type Server struct {
 	firebaseClient *vendors.FirebaseClient
 	infobipClient  *vendors.InfobipClient
 	gcsService     utils.GCSService
-	workerPool     *queue.WorkerPool
-	repositories   *Repositories
-	handlers       *Handlers
+	// storageHandler serves object URLs when a local or in-memory driver is used
+	storageHandler   http.Handler
+	storageMountPath string
+	workerPool       *queue.WorkerPool
+	// localization translates API error messages; it is created with the handlers
+	localization services.LocalizationService
+	repositories *Repositories
+	handlers     *Handlers
 }
 
 type Repositories struct {
@@ -28,7 +36,8 @@ type Repositories struct {
 	otp                    repository.OTPRepository
 	refreshToken           repository.RefreshTokenRepository
 	address                repository.GenericRepository[entities.Address]
-	avatar                 repository.GenericRepository[entities.Avatar]
+	avatar                 repository.AvatarRepository
+	userProfilePhoto       repository.UserProfilePhotoRepository
 	state                  repository.StateRepository
 	city                   repository.CityRepository
 	pinCode                repository.PinCodeRepository
@@ -36,13 +45,27 @@ type Repositories struct {
 	questionMasterLanguage repository.QuestionMasterLanguageRepository
 	optionMaster           repository.OptionMasterRepository
 	optionMasterLanguage   repository.OptionMasterLanguageRepository
+	questionVersion        repository.QuestionVersionRepository
+	questionSection        repository.QuestionSectionRepository
+	questionCondition      repository.QuestionConditionRepository
+	questionSubmission     repository.QuestionSubmissionRepository
+	language               repository.LanguageRepository
 	userQuestionAnswer     repository.UserQuestionAnswerRepository
+	questionAnalytics      repository.QuestionAnalyticsRepository
 	thunderSeat            repository.ThunderSeatRepository
+	thunderSeatRevision    repository.GenericRepository[entities.ThunderSeatRevision]
+	thunderSeatReport      repository.ThunderSeatReportRepository
+	thunderSeatScreening   repository.GenericRepository[entities.ThunderSeatScreening]
+	uploadSession          repository.UploadSessionRepository
+	mediaAsset             repository.MediaAssetRepository
 	winner                 repository.WinnerRepository
 	contestWeek            repository.ContestWeekRepository
+	contestWeekRule        repository.ContestWeekRuleRepository
 	userAadharCard         repository.UserAadharCardRepository
 	userAdditionalInfo     repository.UserAdditionalInfoRepository
 	loginCount             repository.LoginCountRepository
+	pointTransaction       repository.PointTransactionRepository
+	leaderboard            repository.LeaderboardRepository
 }
 
 type Handlers struct {
@@ -52,8 +75,14 @@ type Handlers struct {
 	avatar        *handlers.AvatarHandler
 	question      *handlers.QuestionHandler
 	thunderSeat   *handlers.ThunderSeatHandler
+	moderation    *handlers.ModerationHandler
 	winner        *handlers.WinnerHandler
 	contestWeek   *handlers.ContestWeekHandler
 	websiteStatus *handlers.WebsiteStatusHandler
 	state         *handlers.StateHandler
+	points        *handlers.PointsHandler
+	leaderboard   *handlers.LeaderboardHandler
+	language      *handlers.LanguageHandler
+	translation   *handlers.TranslationHandler
+	analytics     *handlers.QuestionAnalyticsHandler
 }
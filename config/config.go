@@ -19,7 +19,9 @@ type Config struct {
 	JwtConfig      JwtConfig
 	FirebaseConfig FirebaseConfig
 	GcsConfig      GcsConfig
+	StorageConfig  StorageConfig
 	PubSubConfig   PubSubConfig
+	UploadConfig   UploadConfig
 	XAPIKey        string
 }
 
@@ -59,6 +61,22 @@ type GcsConfig struct {
 	GcpUrl     string
 }
 
+// StorageConfig selects the object storage driver. Driver is gcs, local or memory;
+// the local and memory drivers serve signed URLs under BaseURL themselves.
+type StorageConfig struct {
+	Driver     string
+	LocalDir   string
+	BaseURL    string
+	SigningKey string
+}
+
+// UploadConfig holds the size limits, in megabytes, of each kind of uploaded file
+type UploadConfig struct {
+	MaxAudioSizeMB int
+	MaxVideoSizeMB int
+	MaxImageSizeMB int
+}
+
 type PubSubConfig struct {
 	ProjectID      string
 	SubscriptionID string
@@ -114,12 +132,25 @@ func loadConfig() (*Config, error) {
 			GcpUrl:     getEnv("GCP_URL", ""),
 		},
 
+		StorageConfig: StorageConfig{
+			Driver:     getEnv("STORAGE_DRIVER", "gcs"),
+			LocalDir:   getEnv("STORAGE_LOCAL_DIR", "./storage"),
+			BaseURL:    getEnv("STORAGE_BASE_URL", ""),
+			SigningKey: getEnv("STORAGE_SIGNING_KEY", ""),
+		},
+
 		PubSubConfig: PubSubConfig{
 			ProjectID:      getEnv("GOOGLE_PUBSUB_PROJECT_ID", ""),
 			SubscriptionID: getEnv("GOOGLE_PUBSUB_SUBSCRIPTION_ID", ""),
 			TopicID:        getEnv("GOOGLE_PUBSUB_TOPIC_ID", ""),
 		},
 
+		UploadConfig: UploadConfig{
+			MaxAudioSizeMB: parseEnvInt("UPLOAD_MAX_AUDIO_SIZE_MB", 100),
+			MaxVideoSizeMB: parseEnvInt("UPLOAD_MAX_VIDEO_SIZE_MB", 10),
+			MaxImageSizeMB: parseEnvInt("UPLOAD_MAX_IMAGE_SIZE_MB", 10),
+		},
+
 		XAPIKey: getEnv("X_API_KEY", ""),
 	}, nil
 }
@@ -14,6 +14,11 @@ const (
 
 	NOTIFICATION_CATEGORY = "thums_up_notification"
 
+	// Notification templates, translated through their <template>.title and
+	// <template>.body language keys
+	NOTIFICATION_TEMPLATE_SUBMISSION_APPROVED = "thunder_seat.submission_approved"
+	NOTIFICATION_TEMPLATE_SUBMISSION_REJECTED = "thunder_seat.submission_rejected"
+
 	ROLE_USER  = "user"
 	ROLE_ADMIN = "admin"
 
@@ -56,6 +61,78 @@ const (
 
 	WEBSITE_LAUNCH_DATE = "2026-01-05T00:00:00Z"
 
+	// Contest week timeline
+	CONTEST_WEEK_STATUS_UPCOMING          = "upcoming"
+	CONTEST_WEEK_STATUS_OPEN              = "open"
+	CONTEST_WEEK_STATUS_CLOSED            = "closed"
+	CONTEST_WEEK_STATUS_RESULTS_ANNOUNCED = "results_announced"
+	DEFAULT_DRAW_DELAY                    = 24 * time.Hour
+	DEFAULT_RESULTS_DELAY                 = 48 * time.Hour
+	CONTEST_TIMELINE_CACHE_TTL            = 5 * time.Minute
+
+	// Thunder Seat submission rules
+	DEFAULT_MAX_SUBMISSIONS_PER_USER = 1
+	DEFAULT_LANGUAGE_ID              = 1
+	IDEMPOTENCY_KEY_HEADER           = "Idempotency-Key"
+	MAX_IDEMPOTENCY_KEY_LENGTH       = 255
+
+	// Thunder Seat moderation
+	MODERATION_SIGNED_URL_EXPIRY       = 1 * time.Hour
+	THUNDER_SEAT_REPORT_FLAG_THRESHOLD = 3
+
+	// Automated content screening
+	SCREENING_TIMEOUT            = 30 * time.Second
+	SCREENING_MIN_MEDIA_DURATION = 1 * time.Second
+	SCREENING_MAX_AUDIO_DURATION = 5 * time.Minute
+	SCREENING_MAX_VIDEO_DURATION = 3 * time.Minute
+
+	// Direct media uploads
+	UPLOAD_SESSION_EXPIRY = 1 * time.Hour
+
+	// Media processing pipeline
+	MEDIA_PIPELINE_TIMEOUT       = 2 * time.Minute
+	MEDIA_PIPELINE_MAX_IN_MEMORY = 32 << 20
+	MEDIA_THUMBNAIL_SIZE         = 320
+	MEDIA_POSTER_SIZE            = 640
+
+	// Media URL resolution. The default avatar is uploaded once per bucket.
+	DEFAULT_AVATAR_OBJECT_PATH = "avatars/default/avatar.png"
+	AVATAR_IMAGES_CACHE_TTL    = 10 * time.Minute
+
+	// Custom profile photos are cropped square and stored in these sizes
+	PROFILE_PHOTO_FOLDER     = "profile-photos"
+	PROFILE_PHOTO_FULL_SIZE  = 512
+	PROFILE_PHOTO_THUMB_SIZE = 128
+	PROFILE_PHOTO_MIN_SIZE   = 128
+
+	// Points ledger awards. Profile questions award their own ques_point.
+	POINTS_THUNDER_SEAT_ENTRY    = 50
+	POINTS_REFERRAL              = 100
+	POINTS_LOGIN_STREAK          = 25
+	LOGIN_STREAK_DAYS            = 7
+	POINTS_HISTORY_DEFAULT_LIMIT = 20
+
+	// Leaderboards
+	LEADERBOARD_DEFAULT_LIMIT = 20
+
+	// Translations are reloaded from the language tables at most this often
+	TRANSLATION_CACHE_TTL    = 10 * time.Minute
+	TRANSLATION_RELOAD_RETRY = 1 * time.Minute
+
+	// Cached profile questionnaires expire so changes made on another instance show up
+	QUESTION_CATALOG_CACHE_TTL = 5 * time.Minute
+
+	// Largest translation sheet accepted by the admin import
+	TRANSLATION_IMPORT_MAX_SIZE = 5 << 20
+
+	// Question suggestions a user may have waiting for review at once
+	MAX_PENDING_QUESTION_SUBMISSIONS = 5
+
+	// Orphaned object garbage collection
+	STORAGE_GC_DEFAULT_GRACE_PERIOD = 24 * time.Hour
+	STORAGE_GC_MIN_GRACE_PERIOD     = 2 * UPLOAD_SESSION_EXPIRY
+	STORAGE_GC_QUARANTINE_PREFIX    = "quarantine/"
+
 	// System UUID for system/admin operationss
 	SYSTEM_USER_ID = "00000000-0000-0000-0000-000000000000"
 )
@@ -3,6 +3,7 @@ package dtos
 type ErrorResponse struct {
 	Success bool        `json:"success"`
 	Error   string      `json:"error"`
+	Code    string      `json:"code,omitempty"`
 	Details interface{} `json:"details,omitempty"`
 }
 
@@ -24,3 +25,10 @@ type PaginatedResponse struct {
 	Data    interface{}    `json:"data"`
 	Meta    PaginationMeta `json:"meta"`
 }
+
+// ImageVariants are URLs of the same image at different sizes. Thumb falls back
+// to Full when no thumbnail has been generated.
+type ImageVariants struct {
+	Thumb string `json:"thumb"`
+	Full  string `json:"full"`
+}
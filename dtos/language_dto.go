@@ -0,0 +1,79 @@
+package dtos
+
+type LanguageDTO struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Code string `json:"code,omitempty"`
+}
+
+// TranslationReloadResponse reports what the translation cache holds after a reload
+type TranslationReloadResponse struct {
+	Languages    int `json:"languages"`
+	Translations int `json:"translations"`
+}
+
+// TranslationDTO is the text of a question or option in one language
+type TranslationDTO struct {
+	ID         int    `json:"id"`
+	LanguageID int    `json:"language_id"`
+	Text       string `json:"text"`
+	IsActive   bool   `json:"is_active"`
+}
+
+type SetTranslationRequestDTO struct {
+	Text string `json:"text" binding:"required,max=5000"`
+}
+
+// QuestionTranslationsDTO is a question and its options with every translation
+// they have. language_id is the language the question itself is written in.
+type QuestionTranslationsDTO struct {
+	ID           int                     `json:"id"`
+	QuestionText string                  `json:"question_text"`
+	LanguageID   int                     `json:"language_id"`
+	Translations []TranslationDTO        `json:"translations"`
+	Options      []OptionTranslationsDTO `json:"options"`
+}
+
+type OptionTranslationsDTO struct {
+	ID           int              `json:"id"`
+	OptionText   string           `json:"option_text"`
+	DisplayOrder int              `json:"display_order"`
+	IsActive     bool             `json:"is_active"`
+	Translations []TranslationDTO `json:"translations"`
+}
+
+// TranslationCoverageDTO counts how much of the question content is available
+// in a language and lists what is missing
+type TranslationCoverageDTO struct {
+	LanguageID   int                     `json:"language_id"`
+	LanguageName string                  `json:"language_name"`
+	LanguageCode string                  `json:"language_code,omitempty"`
+	Questions    TranslationCountDTO     `json:"questions"`
+	Options      TranslationCountDTO     `json:"options"`
+	Missing      []MissingTranslationDTO `json:"missing"`
+}
+
+// TranslationCountDTO is how many items are translated. Percent is 100 when
+// there is nothing to translate.
+type TranslationCountDTO struct {
+	Total      int `json:"total"`
+	Translated int `json:"translated"`
+	Percent    int `json:"percent"`
+}
+
+// MissingTranslationDTO is a question, or one of its options when option_id is
+// set, that has no translation in the language
+type MissingTranslationDTO struct {
+	QuestionID int    `json:"question_id"`
+	OptionID   int    `json:"option_id,omitempty"`
+	SourceText string `json:"source_text"`
+}
+
+// TranslationImportResponse counts what an import did with its rows. Rows with
+// no text are skipped; rows matching the stored translation are unchanged.
+type TranslationImportResponse struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+}
@@ -0,0 +1,41 @@
+package dtos
+
+const (
+	LeaderboardScopeWeek    = "week"
+	LeaderboardScopeAllTime = "all_time"
+)
+
+// LeaderboardRequest selects a leaderboard. A week scope without a week number
+// shows the active contest week.
+type LeaderboardRequest struct {
+	Scope  string `form:"scope" binding:"omitempty,oneof=week all_time"`
+	Week   int    `form:"week" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
+
+// LeaderboardEntryDTO is a ranked user. Names are masked on every entry, including
+// the caller's own.
+type LeaderboardEntryDTO struct {
+	Rank           int            `json:"rank"`
+	Name           string         `json:"name"`
+	AvatarVariants *ImageVariants `json:"avatar_variants,omitempty"`
+	Points         int            `json:"points"`
+	IsMe           bool           `json:"is_me"`
+}
+
+// LeaderboardMeDTO is the caller's own standing. Rank is omitted when they have no
+// points on the leaderboard yet or have opted out.
+type LeaderboardMeDTO struct {
+	Rank     *int `json:"rank,omitempty"`
+	Points   int  `json:"points"`
+	OptedOut bool `json:"opted_out"`
+}
+
+type LeaderboardResponse struct {
+	Scope      string                `json:"scope"`
+	WeekNumber *int                  `json:"week_number,omitempty"`
+	Entries    []LeaderboardEntryDTO `json:"entries"`
+	Me         LeaderboardMeDTO      `json:"me"`
+	Meta       PaginationMeta        `json:"meta"`
+}
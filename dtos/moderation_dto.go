@@ -0,0 +1,62 @@
+package dtos
+
+type ModerationQueueRequest struct {
+	Status     string `form:"status" binding:"omitempty,oneof=pending approved rejected flagged"`
+	WeekNumber int    `form:"week" binding:"omitempty,min=1"`
+	MediaType  string `form:"media_type" binding:"omitempty,oneof=audio video image"`
+	Reported   bool   `form:"reported"`
+	Limit      int    `form:"limit" binding:"required,min=1,max=100"`
+	Offset     int    `form:"offset" binding:"min=0"`
+}
+
+type ModerationQueueItem struct {
+	ID               int     `json:"id"`
+	UserID           string  `json:"user_id"`
+	Name             *string `json:"name,omitempty"`
+	WeekNumber       int     `json:"week_number"`
+	Answer           string  `json:"answer"`
+	MediaType        *string `json:"media_type,omitempty"`
+	SignedMediaURL   *string `json:"signed_media_url,omitempty"`
+	ModerationStatus string  `json:"moderation_status"`
+	ModerationReason *string `json:"moderation_reason,omitempty"`
+	ReportCount      int     `json:"report_count"`
+	CreatedOn        string  `json:"created_on"`
+}
+
+type ModerateSubmissionsRequest struct {
+	IDs    []int   `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+	Action string  `json:"action" binding:"required,oneof=approve reject"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ModerateSubmissionsResponse struct {
+	Status  string `json:"status"`
+	Updated int64  `json:"updated"`
+}
+
+type ReportSubmissionRequest struct {
+	Reason  string  `json:"reason" binding:"required,max=255"`
+	Details *string `json:"details" binding:"omitempty,max=1000"`
+}
+
+type ProfilePhotoQueueRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Limit  int    `form:"limit" binding:"required,min=1,max=100"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
+
+type ProfilePhotoQueueItem struct {
+	ID               int           `json:"id"`
+	UserID           string        `json:"user_id"`
+	Name             *string       `json:"name,omitempty"`
+	Variants         ImageVariants `json:"variants"`
+	ModerationStatus string        `json:"moderation_status"`
+	ModerationReason *string       `json:"moderation_reason,omitempty"`
+	CreatedOn        string        `json:"created_on"`
+}
+
+type ModerateProfilePhotosRequest struct {
+	IDs    []int   `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+	Action string  `json:"action" binding:"required,oneof=approve reject"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
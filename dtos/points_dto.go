@@ -0,0 +1,23 @@
+package dtos
+
+type PointsHistoryRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+type PointTransactionDTO struct {
+	ID           int    `json:"id"`
+	SourceType   string `json:"source_type"`
+	SourceID     string `json:"source_id"`
+	Amount       int    `json:"amount"`
+	BalanceAfter int    `json:"balance_after"`
+	Description  string `json:"description"`
+	CreatedOn    string `json:"created_on"`
+}
+
+// PointsResponse is the user's balance with one page of their ledger, newest first
+type PointsResponse struct {
+	Balance      int                   `json:"balance"`
+	Transactions []PointTransactionDTO `json:"transactions"`
+	Meta         PaginationMeta        `json:"meta"`
+}
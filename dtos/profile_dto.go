@@ -5,20 +5,50 @@ import (
 )
 
 type CreateAvatarRequestDTO struct {
-	Name        string `form:"name" binding:"required"`
-	IsPublished bool   `form:"is_published"`
+	Name         string   `form:"name" binding:"required,max=100"`
+	IsPublished  bool     `form:"is_published"`
+	Category     *string  `form:"category" binding:"omitempty,max=50"`
+	Tags         []string `form:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	DisplayOrder *int     `form:"display_order" binding:"omitempty,min=0"`
+}
+
+// UpdateAvatarRequestDTO changes only the fields that are set. Tags, when set,
+// replace the avatar's tags; an empty category clears it.
+type UpdateAvatarRequestDTO struct {
+	Name         *string   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Category     *string   `json:"category,omitempty" binding:"omitempty,max=50"`
+	Tags         *[]string `json:"tags,omitempty" binding:"omitempty,max=20,dive,min=1,max=50"`
+	DisplayOrder *int      `json:"display_order,omitempty" binding:"omitempty,min=0"`
+	IsActive     *bool     `json:"is_active,omitempty"`
+}
+
+// ReorderAvatarsRequestDTO lists avatar IDs in the order the picker shows them
+type ReorderAvatarsRequestDTO struct {
+	AvatarIDs []int `json:"avatar_ids" binding:"required,min=1,dive,min=1"`
+}
+
+type AvatarListRequestDTO struct {
+	IsPublished    *bool   `form:"is_published"`
+	Category       *string `form:"category"`
+	Tag            *string `form:"tag"`
+	IncludeDeleted bool    `form:"include_deleted"`
 }
 
 type AvatarResponseDTO struct {
-	ID             int        `json:"id"`
-	Name           string     `json:"name"`
-	ImageURL       string     `json:"image_url"`
-	IsPublished    bool       `json:"is_published"`
-	PublishedBy    *string    `json:"published_by,omitempty"`
-	PublishedOn    *time.Time `json:"published_on,omitempty"`
-	IsActive       bool       `json:"is_active"`
-	CreatedOn      time.Time  `json:"created_on"`
-	LastModifiedOn *time.Time `json:"last_modified_on,omitempty"`
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	ImageURL       string        `json:"image_url"`
+	Variants       ImageVariants `json:"variants"`
+	DisplayOrder   int           `json:"display_order"`
+	Category       *string       `json:"category,omitempty"`
+	Tags           []string      `json:"tags"`
+	IsPublished    bool          `json:"is_published"`
+	PublishedBy    *string       `json:"published_by,omitempty"`
+	PublishedOn    *time.Time    `json:"published_on,omitempty"`
+	IsActive       bool          `json:"is_active"`
+	IsDeleted      bool          `json:"is_deleted,omitempty"`
+	CreatedOn      time.Time     `json:"created_on"`
+	LastModifiedOn *time.Time    `json:"last_modified_on,omitempty"`
 }
 
 type AddressRequestDTO struct {
@@ -50,27 +80,45 @@ type AddressResponseDTO struct {
 type UpdateProfileRequestDTO struct {
 	Name             *string `json:"name,omitempty"`
 	Email            *string `json:"email,omitempty"`
-	AvatarID         *int    `json:"avatar_id,omitempty"`
+	AvatarID         *int    `json:"avatar_id,omitempty" binding:"omitempty,min=0"`
 	IsViewed         *bool   `json:"is_viewed,omitempty"`
 	SharingPlatform  *string `json:"sharing_platform,omitempty"`
 	PlatformUserName *string `json:"platform_user_name,omitempty"`
+	// LeaderboardOptOut hides the user from leaderboards
+	LeaderboardOptOut *bool `json:"leaderboard_opt_out,omitempty"`
+	// PreferredLanguageID is the language the app is served in; 0 clears it
+	PreferredLanguageID *int `json:"preferred_language_id,omitempty" binding:"omitempty,min=0"`
 }
 
 type UserProfileDTO struct {
-	ID           string    `json:"id"`
-	PhoneNumber  string    `json:"phone_number"`
-	Name         *string   `json:"name,omitempty"`
-	Email        *string   `json:"email,omitempty"`
-	AvatarImage  *string   `json:"avatar_image,omitempty"`
-	QRCodeURL    *string   `json:"qr_code_url,omitempty"`
-	IsWinner     bool      `json:"is_winner"`
-	IsActive     bool      `json:"is_active"`
-	IsVerified   bool      `json:"is_verified"`
-	ReferralCode *string   `json:"referral_code,omitempty"`
-	ReferredBy   *string   `json:"referred_by,omitempty"`
-	IsViewed     bool      `json:"is_viewed"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                  string           `json:"id"`
+	PhoneNumber         string           `json:"phone_number"`
+	Name                *string          `json:"name,omitempty"`
+	Email               *string          `json:"email,omitempty"`
+	AvatarImage         *string          `json:"avatar_image,omitempty"`
+	AvatarVariants      *ImageVariants   `json:"avatar_variants,omitempty"`
+	ProfilePhoto        *ProfilePhotoDTO `json:"profile_photo,omitempty"`
+	QRCodeURL           *string          `json:"qr_code_url,omitempty"`
+	IsWinner            bool             `json:"is_winner"`
+	IsActive            bool             `json:"is_active"`
+	IsVerified          bool             `json:"is_verified"`
+	ReferralCode        *string          `json:"referral_code,omitempty"`
+	ReferredBy          *string          `json:"referred_by,omitempty"`
+	IsViewed            bool             `json:"is_viewed"`
+	LeaderboardOptOut   bool             `json:"leaderboard_opt_out"`
+	PreferredLanguageID *int             `json:"preferred_language_id,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// ProfilePhotoDTO is a user's custom profile photo as its owner sees it, including
+// photos still waiting for moderation
+type ProfilePhotoDTO struct {
+	ID               int           `json:"id"`
+	ModerationStatus string        `json:"moderation_status"`
+	ModerationReason *string       `json:"moderation_reason,omitempty"`
+	Variants         ImageVariants `json:"variants"`
+	CreatedOn        string        `json:"created_on"`
 }
 
 type ProfileResponseDTO struct {
@@ -0,0 +1,48 @@
+package dtos
+
+// QuestionAnalyticsRequest filters question analytics by user segment. state_id
+// 0 selects users without a default address; cohorts are signup months as
+// YYYY-MM and are inclusive.
+type QuestionAnalyticsRequest struct {
+	QuestionID   int    `form:"question_id" binding:"omitempty,min=1"`
+	StateID      *int   `form:"state_id" binding:"omitempty,min=0"`
+	CohortFrom   string `form:"cohort_from" binding:"omitempty,datetime=2006-01"`
+	CohortTo     string `form:"cohort_to" binding:"omitempty,datetime=2006-01"`
+	Participated *bool  `form:"participated"`
+}
+
+// QuestionAnalyticsResponse holds answer distributions as of refreshed_on, which
+// is empty until the analytics have been refreshed once
+type QuestionAnalyticsResponse struct {
+	RefreshedOn string                 `json:"refreshed_on,omitempty"`
+	Questions   []QuestionAnalyticsDTO `json:"questions"`
+}
+
+// QuestionAnalyticsDTO is how the users in the selected segments answered a
+// question. Choice questions list their options and rating questions their
+// values; text and date answers are only counted. Percentages are of
+// respondents, so they add up to more than 100 for multi-select questions.
+type QuestionAnalyticsDTO struct {
+	QuestionID   int              `json:"question_id"`
+	QuestionText string           `json:"question_text"`
+	QuestionType string           `json:"question_type"`
+	Respondents  int              `json:"respondents"`
+	Options      []AnswerCountDTO `json:"options,omitempty"`
+	Ratings      []AnswerCountDTO `json:"ratings,omitempty"`
+}
+
+// AnswerCountDTO counts the users who picked an option or rating value
+type AnswerCountDTO struct {
+	OptionID   int     `json:"option_id,omitempty"`
+	OptionText string  `json:"option_text,omitempty"`
+	Value      *int    `json:"value,omitempty"`
+	Users      int     `json:"users"`
+	Percent    float64 `json:"percent"`
+}
+
+// QuestionAnalyticsRefreshResponse reports a rebuild of the question analytics
+type QuestionAnalyticsRefreshResponse struct {
+	RefreshedOn string `json:"refreshed_on"`
+	Rows        int64  `json:"rows"`
+	DurationMs  int64  `json:"duration_ms"`
+}
@@ -1,8 +1,11 @@
 package dtos
 
+import "time"
+
+// QuestionSubmitRequest suggests a question, which is queued for admin review
 type QuestionSubmitRequest struct {
-	QuestionText string `json:"question_text" binding:"required"`
-	LanguageID   int    `json:"language_id" binding:"required"`
+	QuestionText string `json:"question_text" binding:"required,max=1000"`
+	LanguageID   int    `json:"language_id" binding:"required,min=1"`
 }
 
 type QuestionResponse struct {
@@ -12,10 +15,17 @@ type QuestionResponse struct {
 	IsActive     bool   `json:"is_active"`
 }
 
+// AnswerQuestionsRequestDTO answers one question. Which answer field is used
+// depends on the question type: answer_id for single choice, answer_ids for
+// multi-select, text, rating, or date as YYYY-MM-DD.
 type AnswerQuestionsRequestDTO struct {
-	QuestionID     int `json:"question_id"`
-	AnswerID       int `json:"answer_id"`
-	QuestionNumber int `json:"question_number"`
+	QuestionID     int     `json:"question_id" binding:"required,min=1"`
+	AnswerID       int     `json:"answer_id" binding:"omitempty,min=1"`
+	AnswerIDs      []int   `json:"answer_ids" binding:"omitempty,dive,min=1"`
+	Text           *string `json:"text"`
+	Rating         *int    `json:"rating"`
+	Date           *string `json:"date"`
+	QuestionNumber int     `json:"question_number"`
 }
 
 type OptionDTO struct {
@@ -25,12 +35,25 @@ type OptionDTO struct {
 }
 
 type QuestionResponseDTO struct {
-	ID             int         `json:"id"`
-	QuestionText   string      `json:"question_text"`
-	LanguageID     int         `json:"language_id"`
-	QuesPoint      int         `json:"ques_point"`
-	Options        []OptionDTO `json:"options"`
-	SelectedOption *int        `json:"selected_option,omitempty"`
+	ID              int         `json:"id"`
+	QuestionText    string      `json:"question_text"`
+	LanguageID      int         `json:"language_id"`
+	QuesPoint       int         `json:"ques_point"`
+	QuestionType    string      `json:"question_type"`
+	MinSelections   *int        `json:"min_selections,omitempty"`
+	MaxSelections   *int        `json:"max_selections,omitempty"`
+	MinValue        *int        `json:"min_value,omitempty"`
+	MaxValue        *int        `json:"max_value,omitempty"`
+	MaxLength       *int        `json:"max_length,omitempty"`
+	Options         []OptionDTO `json:"options"`
+	SelectedOption  *int        `json:"selected_option,omitempty"`
+	SelectedOptions []int       `json:"selected_options,omitempty"`
+	TextAnswer      *string     `json:"text_answer,omitempty"`
+	RatingAnswer    *int        `json:"rating_answer,omitempty"`
+	DateAnswer      *string     `json:"date_answer,omitempty"`
+	SectionID       *int        `json:"section_id,omitempty"`
+	DisplayOrder    int         `json:"display_order"`
+	Answered        bool        `json:"answered"`
 }
 
 type GetQuestionByTextRequestDTO struct {
@@ -46,14 +69,28 @@ type CreateOptionDTO struct {
 }
 
 type CreateQuestionDTO struct {
-	ID           *int              `json:"id"`
-	QuestionText string            `json:"question_text"`
-	QuesPoint    int               `json:"ques_point"`
-	LanguageID   int               `json:"language_id"`
-	IsActive     *bool             `json:"is_active"`
-	Options      []CreateOptionDTO `json:"options"`
+	ID            *int              `json:"id"`
+	QuestionText  string            `json:"question_text"`
+	QuesPoint     int               `json:"ques_point"`
+	LanguageID    int               `json:"language_id"`
+	QuestionType  string            `json:"question_type" binding:"omitempty,oneof=single_choice multi_select text rating date"`
+	MinSelections *int              `json:"min_selections" binding:"omitempty,min=0"`
+	MaxSelections *int              `json:"max_selections" binding:"omitempty,min=1"`
+	MinValue      *int              `json:"min_value"`
+	MaxValue      *int              `json:"max_value"`
+	MaxLength     *int              `json:"max_length" binding:"omitempty,min=1"`
+	IsActive      *bool             `json:"is_active"`
+	Options       []CreateOptionDTO `json:"options"`
+	// SectionID and DisplayOrder place the question in the questionnaire straight
+	// away, like IsActive; a section_id of 0 removes it from its section
+	SectionID    *int `json:"section_id" binding:"omitempty,min=0"`
+	DisplayOrder *int `json:"display_order"`
+	// Draft saves the changes as a draft version instead of publishing them
+	Draft       bool       `json:"draft"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
 type CreateQuestionsRequestDTO struct {
-	Questions []CreateQuestionDTO `json:"questions"`
+	Questions []CreateQuestionDTO `json:"questions" binding:"dive"`
 }
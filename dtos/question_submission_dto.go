@@ -0,0 +1,50 @@
+package dtos
+
+// QuestionSubmissionDTO is a question suggested by a user. QuestionID is set
+// once the suggestion is approved into the question bank.
+type QuestionSubmissionDTO struct {
+	ID           int     `json:"id"`
+	QuestionText string  `json:"question_text"`
+	LanguageID   int     `json:"language_id"`
+	Status       string  `json:"status"`
+	ReviewReason *string `json:"review_reason,omitempty"`
+	QuestionID   *int    `json:"question_id,omitempty"`
+	CreatedOn    string  `json:"created_on"`
+	ReviewedOn   string  `json:"reviewed_on,omitempty"`
+}
+
+type QuestionSubmissionListRequest struct {
+	Limit  int `form:"limit" binding:"required,min=1,max=100"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+type QuestionSubmissionQueueRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Limit  int    `form:"limit" binding:"required,min=1,max=100"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
+
+type QuestionSubmissionQueueItem struct {
+	ID           int     `json:"id"`
+	UserID       string  `json:"user_id"`
+	Name         *string `json:"name,omitempty"`
+	QuestionText string  `json:"question_text"`
+	LanguageID   int     `json:"language_id"`
+	Status       string  `json:"status"`
+	ReviewReason *string `json:"review_reason,omitempty"`
+	ReviewedBy   *string `json:"reviewed_by,omitempty"`
+	QuestionID   *int    `json:"question_id,omitempty"`
+	CreatedOn    string  `json:"created_on"`
+	ReviewedOn   string  `json:"reviewed_on,omitempty"`
+}
+
+// ApproveQuestionSubmissionResponse holds the approved submission and the draft
+// version of the question created from it
+type ApproveQuestionSubmissionResponse struct {
+	Submission QuestionSubmissionDTO `json:"submission"`
+	Draft      QuestionVersionDTO    `json:"draft"`
+}
+
+type RejectQuestionSubmissionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
@@ -0,0 +1,80 @@
+package dtos
+
+import "time"
+
+// QuestionScheduleDTO is the window in which a published question is shown.
+// Empty bounds leave that side of the window open.
+type QuestionScheduleDTO struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+type OptionVersionDTO struct {
+	OptionID     *int   `json:"option_id,omitempty"`
+	OptionText   string `json:"option_text"`
+	DisplayOrder int    `json:"display_order"`
+	IsActive     bool   `json:"is_active"`
+}
+
+type QuestionVersionDTO struct {
+	ID            int                `json:"id"`
+	QuestionID    int                `json:"question_id"`
+	Version       int                `json:"version"`
+	Status        string             `json:"status"`
+	QuestionText  string             `json:"question_text"`
+	QuesPoint     int                `json:"ques_point"`
+	LanguageID    int                `json:"language_id"`
+	QuestionType  string             `json:"question_type"`
+	MinSelections *int               `json:"min_selections,omitempty"`
+	MaxSelections *int               `json:"max_selections,omitempty"`
+	MinValue      *int               `json:"min_value,omitempty"`
+	MaxValue      *int               `json:"max_value,omitempty"`
+	MaxLength     *int               `json:"max_length,omitempty"`
+	Options       []OptionVersionDTO `json:"options"`
+	CreatedBy     string             `json:"created_by"`
+	CreatedOn     string             `json:"created_on"`
+	PublishedBy   *string            `json:"published_by,omitempty"`
+	PublishedOn   *string            `json:"published_on,omitempty"`
+}
+
+// QuestionVersionsResponse is a question's publishing state with its versions,
+// newest first
+type QuestionVersionsResponse struct {
+	QuestionID       int                  `json:"question_id"`
+	Status           string               `json:"status"`
+	IsActive         bool                 `json:"is_active"`
+	PublishedVersion *int                 `json:"published_version,omitempty"`
+	PublishAt        *time.Time           `json:"publish_at,omitempty"`
+	UnpublishAt      *time.Time           `json:"unpublish_at,omitempty"`
+	Versions         []QuestionVersionDTO `json:"versions"`
+}
+
+// QuestionVersionDiffRequest picks the versions to compare. To defaults to the
+// latest version and From to the version before To.
+type QuestionVersionDiffRequest struct {
+	From int `form:"from" binding:"omitempty,min=1"`
+	To   int `form:"to" binding:"omitempty,min=1"`
+}
+
+type FieldChangeDTO struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// OptionChangeDTO is an option that was added, removed or changed between two
+// versions. Added options that were never published have no option ID.
+type OptionChangeDTO struct {
+	OptionID   *int             `json:"option_id,omitempty"`
+	OptionText string           `json:"option_text"`
+	Change     string           `json:"change"`
+	Changes    []FieldChangeDTO `json:"changes,omitempty"`
+}
+
+type QuestionVersionDiffDTO struct {
+	QuestionID int               `json:"question_id"`
+	From       int               `json:"from"`
+	To         int               `json:"to"`
+	Changes    []FieldChangeDTO  `json:"changes"`
+	Options    []OptionChangeDTO `json:"options"`
+}
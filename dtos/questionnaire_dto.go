@@ -0,0 +1,62 @@
+package dtos
+
+// QuestionnaireResponseDTO is the profile questionnaire as it applies to a user:
+// only questions whose display conditions hold, in section and display order
+type QuestionnaireResponseDTO struct {
+	Sections     []QuestionnaireSectionDTO  `json:"sections"`
+	Questions    []QuestionResponseDTO      `json:"questions"`
+	NextQuestion *QuestionResponseDTO       `json:"next_question,omitempty"`
+	Completion   QuestionnaireCompletionDTO `json:"completion"`
+}
+
+// QuestionnaireSectionDTO counts a section's applicable and answered questions
+type QuestionnaireSectionDTO struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	DisplayOrder int    `json:"display_order"`
+	Total        int    `json:"total"`
+	Answered     int    `json:"answered"`
+}
+
+// QuestionnaireCompletionDTO is how much of the applicable questionnaire is
+// answered. Percent is 100 when no question applies.
+type QuestionnaireCompletionDTO struct {
+	Answered int `json:"answered"`
+	Total    int `json:"total"`
+	Percent  int `json:"percent"`
+}
+
+type QuestionSectionRequestDTO struct {
+	Title        string `json:"title" binding:"required,max=255"`
+	DisplayOrder int    `json:"display_order"`
+	IsActive     *bool  `json:"is_active"`
+}
+
+type UpdateQuestionSectionRequestDTO struct {
+	Title        *string `json:"title" binding:"omitempty,min=1,max=255"`
+	DisplayOrder *int    `json:"display_order"`
+	IsActive     *bool   `json:"is_active"`
+}
+
+type QuestionSectionResponseDTO struct {
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	DisplayOrder int    `json:"display_order"`
+	IsActive     bool   `json:"is_active"`
+	CreatedOn    string `json:"created_on"`
+}
+
+// QuestionConditionDTO shows a question only when the answer to another question
+// matches. option_ids apply to any_of and none_of, min_value and max_value to
+// between.
+type QuestionConditionDTO struct {
+	DependsOnQuestionID int    `json:"depends_on_question_id" binding:"required,min=1"`
+	Operator            string `json:"operator" binding:"required,oneof=answered any_of none_of between"`
+	OptionIDs           []int  `json:"option_ids" binding:"omitempty,dive,min=1"`
+	MinValue            *int   `json:"min_value"`
+	MaxValue            *int   `json:"max_value"`
+}
+
+type SetQuestionConditionsRequestDTO struct {
+	Conditions []QuestionConditionDTO `json:"conditions" binding:"dive"`
+}
@@ -6,18 +6,46 @@ type ThunderSeatSubmitRequest struct {
 	PlatformUserName *string `form:"user_name" binding:"omitempty,min=3,max=255"`
 }
 
+type ThunderSeatUpdateRequest struct {
+	Answer *string `form:"description" binding:"omitempty"`
+}
+
+type UploadSessionRequest struct {
+	ContentType  string  `json:"content_type" binding:"required,max=100"`
+	Size         int64   `json:"size" binding:"required,min=1"`
+	MD5          *string `json:"md5" binding:"omitempty,base64"`
+	SubmissionID *int    `json:"submission_id" binding:"omitempty,min=1"`
+}
+
+type UploadSessionResponse struct {
+	SessionID string            `json:"session_id"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	Resumable bool              `json:"resumable"`
+	ExpiresAt string            `json:"expires_at"`
+}
+
+type FinalizeUploadRequest struct {
+	Answer           *string `json:"description" binding:"omitempty"`
+	SharingPlatform  *string `json:"social_media" binding:"omitempty,oneof=instagram snapchat facebook twitter tiktok youtube"`
+	PlatformUserName *string `json:"user_name" binding:"omitempty,min=3,max=255"`
+}
+
 type ThunderSeatResponse struct {
-	ID         int     `json:"id"`
-	UserID     string  `json:"user_id"`
-	WeekNumber int     `json:"week_number"`
-	Answer     string  `json:"answer"`
-	MediaURL   *string `json:"media_url,omitempty"`
-	MediaType  *string `json:"media_type,omitempty"`
-	CreatedOn  string  `json:"created_on"`
-	Name       *string `json:"name,omitempty"`
-	Email      *string `json:"email,omitempty"`
-	AvatarURL  *string `json:"avatar_url,omitempty"`
-	AvatarName *string `json:"avatar_name,omitempty"`
+	ID               int            `json:"id"`
+	UserID           string         `json:"user_id"`
+	WeekNumber       int            `json:"week_number"`
+	Answer           string         `json:"answer"`
+	MediaURL         *string        `json:"media_url,omitempty"`
+	MediaType        *string        `json:"media_type,omitempty"`
+	ModerationStatus string         `json:"moderation_status,omitempty"`
+	CreatedOn        string         `json:"created_on"`
+	Name             *string        `json:"name,omitempty"`
+	Email            *string        `json:"email,omitempty"`
+	AvatarURL        *string        `json:"avatar_url,omitempty"`
+	AvatarName       *string        `json:"avatar_name,omitempty"`
+	AvatarVariants   *ImageVariants `json:"avatar_variants,omitempty"`
 }
 
 type SelectWinnersRequest struct {
@@ -25,24 +53,65 @@ type SelectWinnersRequest struct {
 }
 
 type WinnerResponse struct {
-	ID            int     `json:"id"`
-	UserID        string  `json:"user_id"`
-	ThunderSeatID int     `json:"thunder_seat_id"`
-	WeekNumber    int     `json:"week_number"`
-	QRCodeURL     *string `json:"qr_code_url,omitempty"`
-	CreatedOn     string  `json:"created_on"`
-	Name          *string `json:"name,omitempty"`
-	Email         *string `json:"email,omitempty"`
-	AvatarURL     *string `json:"avatar_url,omitempty"`
-	AvatarName    *string `json:"avatar_name,omitempty"`
+	ID             int            `json:"id"`
+	UserID         string         `json:"user_id"`
+	ThunderSeatID  int            `json:"thunder_seat_id"`
+	WeekNumber     int            `json:"week_number"`
+	QRCodeURL      *string        `json:"qr_code_url,omitempty"`
+	CreatedOn      string         `json:"created_on"`
+	Name           *string        `json:"name,omitempty"`
+	Email          *string        `json:"email,omitempty"`
+	AvatarURL      *string        `json:"avatar_url,omitempty"`
+	AvatarName     *string        `json:"avatar_name,omitempty"`
+	AvatarVariants *ImageVariants `json:"avatar_variants,omitempty"`
 }
 
 type CurrentWeekResponse struct {
-	WeekNumber  int    `json:"week_number"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
-	WinnerCount int    `json:"winner_count"`
-	IsActive    bool   `json:"is_active"`
+	WeekNumber  int                     `json:"week_number"`
+	StartDate   string                  `json:"start_date"`
+	EndDate     string                  `json:"end_date"`
+	WinnerCount int                     `json:"winner_count"`
+	IsActive    bool                    `json:"is_active"`
+	Rules       SubmissionRulesResponse `json:"rules"`
+}
+
+type SubmissionRulesResponse struct {
+	Prompt                *string  `json:"prompt,omitempty"`
+	PromptLanguageID      *int     `json:"prompt_language_id,omitempty"`
+	MediaRequired         bool     `json:"media_required"`
+	AllowedMediaKinds     []string `json:"allowed_media_kinds"`
+	MaxAnswerLength       int      `json:"max_answer_length"`
+	MaxSubmissionsPerUser int      `json:"max_submissions_per_user"`
+	SharingFieldsRequired bool     `json:"sharing_fields_required"`
+}
+
+type ContestWeekPromptResponse struct {
+	LanguageID int    `json:"language_id"`
+	PromptText string `json:"prompt_text"`
+}
+
+type ContestWeekRulesResponse struct {
+	WeekNumber            int                         `json:"week_number"`
+	MediaRequired         bool                        `json:"media_required"`
+	AllowedMediaKinds     []string                    `json:"allowed_media_kinds"`
+	MaxAnswerLength       int                         `json:"max_answer_length"`
+	MaxSubmissionsPerUser int                         `json:"max_submissions_per_user"`
+	SharingFieldsRequired bool                        `json:"sharing_fields_required"`
+	Prompts               []ContestWeekPromptResponse `json:"prompts"`
+}
+
+type ContestWeekPromptRequest struct {
+	LanguageID int    `json:"language_id" binding:"required,min=1"`
+	PromptText string `json:"prompt_text" binding:"required"`
+}
+
+type ContestWeekRulesRequest struct {
+	MediaRequired         bool                       `json:"media_required"`
+	AllowedMediaKinds     []string                   `json:"allowed_media_kinds" binding:"omitempty,dive,oneof=audio video image"`
+	MaxAnswerLength       int                        `json:"max_answer_length" binding:"min=0"`
+	MaxSubmissionsPerUser int                        `json:"max_submissions_per_user" binding:"required,min=1"`
+	SharingFieldsRequired bool                       `json:"sharing_fields_required"`
+	Prompts               []ContestWeekPromptRequest `json:"prompts" binding:"omitempty,dive"`
 }
 
 type AllWinnersRequest struct {
@@ -51,10 +120,12 @@ type AllWinnersRequest struct {
 }
 
 type ContestWeekRequest struct {
-	WeekNumber  int    `json:"week_number" binding:"required,min=1"`
-	StartDate   string `json:"start_date" binding:"required"`
-	EndDate     string `json:"end_date" binding:"required"`
-	WinnerCount int    `json:"winner_count" binding:"required,min=1"`
+	WeekNumber  int     `json:"week_number" binding:"required,min=1"`
+	StartDate   string  `json:"start_date" binding:"required"`
+	EndDate     string  `json:"end_date" binding:"required"`
+	DrawDate    *string `json:"draw_date,omitempty"`
+	ResultsDate *string `json:"results_date,omitempty"`
+	WinnerCount int     `json:"winner_count" binding:"required,min=1"`
 }
 
 type ContestWeekResponse struct {
@@ -67,6 +138,24 @@ type ContestWeekResponse struct {
 	CreatedOn   string `json:"created_on"`
 }
 
+type ContestWeekTimelineItem struct {
+	WeekNumber        int    `json:"week_number"`
+	Status            string `json:"status"`
+	IsActive          bool   `json:"is_active"`
+	StartTime         string `json:"start_time"`
+	EndTime           string `json:"end_time"`
+	DrawTime          string `json:"draw_time"`
+	ResultsTime       string `json:"results_time"`
+	SecondsUntilOpen  int64  `json:"seconds_until_open"`
+	SecondsUntilClose int64  `json:"seconds_until_close"`
+	WinnerCount       int    `json:"winner_count"`
+}
+
+type ContestWeekTimelineResponse struct {
+	ServerTime string                    `json:"server_time"`
+	Weeks      []ContestWeekTimelineItem `json:"weeks"`
+}
+
 type ActivateWeekRequest struct {
 	WeekNumber int `json:"week_number" binding:"required"`
 }
@@ -3,20 +3,49 @@ package entities
 import "time"
 
 type Avatar struct {
-	ID             int        `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name           string     `json:"name" gorm:"type:text;not null"`
-	ImageKey       string     `json:"image_key" gorm:"type:text;not null"`
-	IsPublished    bool       `json:"is_published" gorm:"type:boolean;not null;default:false"`
-	PublishedBy    *string    `json:"published_by" gorm:"type:text"`
-	PublishedOn    *time.Time `json:"published_on" gorm:"type:timestamp"`
-	IsActive       bool       `json:"is_active" gorm:"type:boolean;not null;default:true"`
-	IsDeleted      bool       `json:"is_deleted" gorm:"type:boolean;not null;default:false"`
-	CreatedBy      string     `json:"created_by" gorm:"type:text;not null"`
-	CreatedOn      time.Time  `json:"created_on" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	LastModifiedBy *string    `json:"last_modified_by" gorm:"type:text"`
-	LastModifiedOn *time.Time `json:"last_modified_on" gorm:"type:timestamp"`
+	ID             int         `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name           string      `json:"name" gorm:"type:text;not null"`
+	ImageKey       string      `json:"image_key" gorm:"type:text;not null"`
+	MediaAssetID   *int        `json:"media_asset_id,omitempty" gorm:"type:int;index"`
+	DisplayOrder   int         `json:"display_order" gorm:"type:int;not null;default:0;index"`
+	Category       *string     `json:"category,omitempty" gorm:"type:varchar(50);index"`
+	IsPublished    bool        `json:"is_published" gorm:"type:boolean;not null;default:false"`
+	PublishedBy    *string     `json:"published_by" gorm:"type:text"`
+	PublishedOn    *time.Time  `json:"published_on" gorm:"type:timestamp"`
+	IsActive       bool        `json:"is_active" gorm:"type:boolean;not null;default:true"`
+	IsDeleted      bool        `json:"is_deleted" gorm:"type:boolean;not null;default:false"`
+	CreatedBy      string      `json:"created_by" gorm:"type:text;not null"`
+	CreatedOn      time.Time   `json:"created_on" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	LastModifiedBy *string     `json:"last_modified_by" gorm:"type:text"`
+	LastModifiedOn *time.Time  `json:"last_modified_on" gorm:"type:timestamp"`
+	MediaAsset     *MediaAsset `json:"media_asset,omitempty" gorm:"foreignKey:MediaAssetID;references:ID"`
+	Tags           []AvatarTag `json:"tags,omitempty" gorm:"foreignKey:AvatarID"`
 }
 
 func (Avatar) TableName() string {
 	return "avatar"
 }
+
+// IsSelectable reports whether users may pick the avatar. Users who picked an
+// avatar before it was unpublished, deactivated or deleted keep it.
+func (a *Avatar) IsSelectable() bool {
+	return a.IsPublished && a.IsActive && !a.IsDeleted
+}
+
+// ObjectPath is where the avatar image is stored
+func (a *Avatar) ObjectPath() string {
+	return "avatars/" + a.CreatedBy + "/" + a.ImageKey
+}
+
+const AvatarTagConstraint = "uq_avatar_tag"
+
+// AvatarTag is a free-form label used to filter the avatar picker
+type AvatarTag struct {
+	ID       int    `json:"id" gorm:"primaryKey;autoIncrement"`
+	AvatarID int    `json:"avatar_id" gorm:"not null;uniqueIndex:uq_avatar_tag,priority:1"`
+	Tag      string `json:"tag" gorm:"type:varchar(50);not null;index;uniqueIndex:uq_avatar_tag,priority:2"`
+}
+
+func (AvatarTag) TableName() string {
+	return "avatar_tag"
+}
@@ -3,18 +3,38 @@ package entities
 import "time"
 
 type ContestWeek struct {
-	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	WeekNumber  int       `gorm:"column:week_number;not null;unique" json:"week_number"`
-	StartDate   time.Time `gorm:"column:start_date;not null" json:"start_date"`
-	EndDate     time.Time `gorm:"column:end_date;not null" json:"end_date"`
-	WinnerCount int       `gorm:"column:winner_count;not null" json:"winner_count"`
-	IsActive    bool      `gorm:"column:is_active;default:false" json:"is_active"`
-	CreatedBy   string    `gorm:"type:varchar(255);not null" json:"created_by"`
-	CreatedOn   time.Time `gorm:"autoCreateTime" json:"created_on"`
-	UpdatedBy   string    `gorm:"type:varchar(255)" json:"updated_by"`
-	UpdatedOn   time.Time `gorm:"autoUpdateTime" json:"updated_on"`
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	WeekNumber  int        `gorm:"column:week_number;not null;unique" json:"week_number"`
+	StartDate   time.Time  `gorm:"column:start_date;not null" json:"start_date"`
+	EndDate     time.Time  `gorm:"column:end_date;not null" json:"end_date"`
+	DrawDate    *time.Time `gorm:"column:draw_date" json:"draw_date,omitempty"`
+	ResultsDate *time.Time `gorm:"column:results_date" json:"results_date,omitempty"`
+	WinnerCount int        `gorm:"column:winner_count;not null" json:"winner_count"`
+	IsActive    bool       `gorm:"column:is_active;default:false" json:"is_active"`
+	CreatedBy   string     `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedOn   time.Time  `gorm:"autoCreateTime" json:"created_on"`
+	UpdatedBy   string     `gorm:"type:varchar(255)" json:"updated_by"`
+	UpdatedOn   time.Time  `gorm:"autoUpdateTime" json:"updated_on"`
 }
 
 func (ContestWeek) TableName() string {
 	return "contest_week"
 }
+
+// DrawTime returns when winners are drawn for the week, defaulting to a fixed
+// delay after the week closes when no explicit draw date is configured.
+func (w ContestWeek) DrawTime(defaultDelay time.Duration) time.Time {
+	if w.DrawDate != nil {
+		return *w.DrawDate
+	}
+	return w.EndDate.Add(defaultDelay)
+}
+
+// ResultsTime returns when results are published for the week, defaulting to a
+// fixed delay after the week closes when no explicit results date is configured.
+func (w ContestWeek) ResultsTime(defaultDelay time.Duration) time.Time {
+	if w.ResultsDate != nil {
+		return *w.ResultsDate
+	}
+	return w.EndDate.Add(defaultDelay)
+}
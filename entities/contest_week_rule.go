@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+type ContestWeekRule struct {
+	ID                    int                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	WeekNumber            int                 `gorm:"column:week_number;not null;uniqueIndex" json:"week_number"`
+	MediaRequired         bool                `gorm:"column:media_required;default:false" json:"media_required"`
+	AllowedMediaKinds     string              `gorm:"column:allowed_media_kinds;type:varchar(100)" json:"allowed_media_kinds"`
+	MaxAnswerLength       int                 `gorm:"column:max_answer_length;default:0" json:"max_answer_length"`
+	MaxSubmissionsPerUser int                 `gorm:"column:max_submissions_per_user;not null;default:1" json:"max_submissions_per_user"`
+	SharingFieldsRequired bool                `gorm:"column:sharing_fields_required;default:false" json:"sharing_fields_required"`
+	CreatedBy             string              `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedOn             time.Time           `gorm:"autoCreateTime" json:"created_on"`
+	UpdatedBy             string              `gorm:"type:varchar(255)" json:"updated_by"`
+	UpdatedOn             time.Time           `gorm:"autoUpdateTime" json:"updated_on"`
+	Prompts               []ContestWeekPrompt `gorm:"foreignKey:WeekNumber;references:WeekNumber" json:"prompts,omitempty"`
+}
+
+func (ContestWeekRule) TableName() string {
+	return "contest_week_rule"
+}
+
+// MediaKinds returns the media kinds accepted for the week. An empty list
+// means every supported kind is accepted.
+func (r ContestWeekRule) MediaKinds() []string {
+	if strings.TrimSpace(r.AllowedMediaKinds) == "" {
+		return []string{}
+	}
+	kinds := strings.Split(r.AllowedMediaKinds, ",")
+	result := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			result = append(result, kind)
+		}
+	}
+	return result
+}
+
+// AllowsMediaKind reports whether a media file of the given kind may be
+// attached to a submission for the week
+func (r ContestWeekRule) AllowsMediaKind(kind string) bool {
+	kinds := r.MediaKinds()
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, allowed := range kinds {
+		if allowed == kind {
+			return true
+		}
+	}
+	return false
+}
+
+type ContestWeekPrompt struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	WeekNumber int       `gorm:"column:week_number;not null;uniqueIndex:idx_contest_week_prompt_week_language" json:"week_number"`
+	LanguageID int       `gorm:"column:language_id;not null;uniqueIndex:idx_contest_week_prompt_week_language" json:"language_id"`
+	PromptText string    `gorm:"column:prompt_text;type:text;not null" json:"prompt_text"`
+	CreatedBy  string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedOn  time.Time `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (ContestWeekPrompt) TableName() string {
+	return "contest_week_prompt"
+}
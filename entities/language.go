@@ -4,9 +4,18 @@ import (
 	"time"
 )
 
+// Kinds of language keys. Message keys are API error messages, named by their
+// English text; notification keys hold templates named "<template>.title" and
+// "<template>.body".
+const (
+	LanguageKeyTypeMessage      = 1
+	LanguageKeyTypeNotification = 2
+)
+
+// LanguageKey is a translatable string, translated per language by its KeyValues
 type LanguageKey struct {
 	ID             int                `gorm:"primaryKey;column:id"`
-	Name           string             `gorm:"column:name;not null"`
+	Name           string             `gorm:"column:name;not null;uniqueIndex:uq_language_key_name"`
 	KeyTypeID      int                `gorm:"column:key_type_id;not null"`
 	IsActive       bool               `gorm:"column:is_active;not null"`
 	IsDeleted      bool               `gorm:"column:is_deleted;not null"`
@@ -19,8 +28,8 @@ type LanguageKey struct {
 
 type LanguageKeyValue struct {
 	ID               int            `gorm:"primaryKey;column:id"`
-	LanguageKeyID    int            `gorm:"column:language_key_id;not null"`
-	LanguageMasterID int            `gorm:"column:language_master_id;not null"`
+	LanguageKeyID    int            `gorm:"column:language_key_id;not null;uniqueIndex:uq_language_key_value"`
+	LanguageMasterID int            `gorm:"column:language_master_id;not null;uniqueIndex:uq_language_key_value"`
 	KeyValue         string         `gorm:"column:key_value;not null"`
 	IsActive         bool           `gorm:"column:is_active;not null"`
 	IsDeleted        bool           `gorm:"column:is_deleted;not null"`
@@ -32,6 +41,8 @@ type LanguageKeyValue struct {
 	LanguageMaster   LanguageMaster `gorm:"foreignKey:LanguageMasterID"`
 }
 
+// LanguageMaster is a language content can be translated into. Its ID is the
+// language_id used across the API; LanguageCode is matched against Accept-Language.
 type LanguageMaster struct {
 	ID             int                `gorm:"primaryKey;column:id"`
 	Name           string             `gorm:"column:name;not null"`
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// LeaderboardAllTime is the week number of the all-time leaderboard
+const LeaderboardAllTime = 0
+
+// LeaderboardEntry is a user's running points total on one leaderboard: a contest
+// week, or all time when WeekNumber is LeaderboardAllTime. Entries are updated as
+// points are credited, so rankings are never recomputed from the ledger.
+type LeaderboardEntry struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	WeekNumber   int       `gorm:"column:week_number;not null;uniqueIndex:uq_leaderboard_entry_user,priority:1;index:idx_leaderboard_entry_rank,priority:1" json:"week_number"`
+	UserID       string    `gorm:"column:user_id;type:uuid;not null;uniqueIndex:uq_leaderboard_entry_user,priority:2;index:idx_leaderboard_entry_rank,priority:4" json:"user_id"`
+	Points       int       `gorm:"column:points;not null;default:0;index:idx_leaderboard_entry_rank,priority:2,sort:desc" json:"points"`
+	LastEarnedOn time.Time `gorm:"column:last_earned_on;not null;index:idx_leaderboard_entry_rank,priority:3" json:"last_earned_on"`
+	CreatedOn    time.Time `gorm:"autoCreateTime" json:"created_on"`
+	UpdatedOn    time.Time `gorm:"autoUpdateTime" json:"updated_on"`
+	User         *User     `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (LeaderboardEntry) TableName() string {
+	return "leaderboard_entries"
+}
@@ -15,6 +15,9 @@ type LoginCount struct {
 	LastLogin   time.Time `gorm:"not null" json:"last_login"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// StreakDays counts consecutive days with a login up to StreakDate
+	StreakDays int        `gorm:"not null;default:0" json:"streak_days"`
+	StreakDate *time.Time `gorm:"type:date" json:"streak_date,omitempty"`
 }
 
 func (l *LoginCount) BeforeCreate(tx *gorm.DB) error {
@@ -27,3 +30,24 @@ func (l *LoginCount) BeforeCreate(tx *gorm.DB) error {
 func (LoginCount) TableName() string {
 	return "login_counts"
 }
+
+// RecordStreakDay extends the login streak with the day of now. It reports false
+// when that day was already counted; a missed day restarts the streak at one.
+func (l *LoginCount) RecordStreakDay(now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if l.StreakDate != nil {
+		last := time.Date(l.StreakDate.Year(), l.StreakDate.Month(), l.StreakDate.Day(), 0, 0, 0, 0, now.Location())
+		switch {
+		case !last.Before(today):
+			return false
+		case last.AddDate(0, 0, 1).Equal(today):
+			l.StreakDays++
+		default:
+			l.StreakDays = 1
+		}
+	} else {
+		l.StreakDays = 1
+	}
+	l.StreakDate = &today
+	return true
+}
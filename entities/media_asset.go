@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+const (
+	MediaAssetStatusPending = "pending"
+	MediaAssetStatusReady   = "ready"
+	MediaAssetStatusFailed  = "failed"
+)
+
+const (
+	MediaRenditionOriginal  = "original"
+	MediaRenditionThumbnail = "thumbnail"
+	MediaRenditionPoster    = "poster"
+)
+
+// MediaAsset is an object in storage together with what the media pipeline learned
+// about it. Uploaded originals have no parent; derived renditions such as thumbnails
+// and poster frames point at the original they were generated from.
+type MediaAsset struct {
+	ID                  int          `gorm:"primaryKey;autoIncrement" json:"id"`
+	ParentID            *int         `gorm:"column:parent_id;index" json:"parent_id,omitempty"`
+	Rendition           string       `gorm:"column:rendition;type:varchar(20);not null;default:original" json:"rendition"`
+	ObjectPath          string       `gorm:"column:object_path;type:text;not null" json:"object_path"`
+	Kind                string       `gorm:"column:kind;type:varchar(20);not null" json:"kind"`
+	DeclaredContentType *string      `gorm:"column:declared_content_type;type:varchar(100)" json:"declared_content_type,omitempty"`
+	ContentType         *string      `gorm:"column:content_type;type:varchar(100)" json:"content_type,omitempty"`
+	Size                int64        `gorm:"column:size;not null;default:0" json:"size"`
+	DurationMs          *int64       `gorm:"column:duration_ms" json:"duration_ms,omitempty"`
+	Width               *int         `gorm:"column:width" json:"width,omitempty"`
+	Height              *int         `gorm:"column:height" json:"height,omitempty"`
+	Status              string       `gorm:"column:status;type:varchar(20);not null;default:pending;index" json:"status"`
+	FailureReason       *string      `gorm:"column:failure_reason;type:text" json:"failure_reason,omitempty"`
+	CreatedBy           string       `gorm:"column:created_by;type:text;not null" json:"created_by"`
+	CreatedOn           time.Time    `gorm:"autoCreateTime" json:"created_on"`
+	ProcessedOn         *time.Time   `gorm:"column:processed_on" json:"processed_on,omitempty"`
+	Renditions          []MediaAsset `gorm:"foreignKey:ParentID" json:"renditions,omitempty"`
+}
+
+func (MediaAsset) TableName() string {
+	return "media_assets"
+}
+
+// RenditionNamed returns the derived rendition with the given name, if it was generated
+func (m *MediaAsset) RenditionNamed(name string) *MediaAsset {
+	for i := range m.Renditions {
+		if m.Renditions[i].Rendition == name {
+			return &m.Renditions[i]
+		}
+	}
+	return nil
+}
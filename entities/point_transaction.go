@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Point sources. Each source has its own award rule, and the source ID makes an
+// award idempotent: a user is credited at most once per source type and ID.
+const (
+	PointSourceProfileQuestion = "profile_question"
+	PointSourceThunderSeat     = "thunder_seat"
+	PointSourceReferral        = "referral"
+	PointSourceLoginStreak     = "login_streak"
+)
+
+// PointReversalSourceType is the source type of the entry that takes back an award of
+// sourceType. It keeps the award's source ID, so an award is reversed at most once.
+func PointReversalSourceType(sourceType string) string {
+	return sourceType + "_reversal"
+}
+
+// PointTransaction is an entry in the append-only points ledger. BalanceAfter is
+// the user's balance once the entry is applied, so the latest entry holds the
+// current balance.
+type PointTransaction struct {
+	ID           int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       string `gorm:"column:user_id;type:uuid;not null;index;uniqueIndex:uq_point_transaction_source,priority:1" json:"user_id"`
+	SourceType   string `gorm:"column:source_type;type:varchar(30);not null;uniqueIndex:uq_point_transaction_source,priority:2" json:"source_type"`
+	SourceID     string `gorm:"column:source_id;type:varchar(100);not null;uniqueIndex:uq_point_transaction_source,priority:3" json:"source_id"`
+	Amount       int    `gorm:"column:amount;not null" json:"amount"`
+	BalanceAfter int    `gorm:"column:balance_after;not null" json:"balance_after"`
+	Description  string `gorm:"column:description;type:varchar(255);not null" json:"description"`
+	// WeekNumber is the contest week whose leaderboard the entry counted toward,
+	// empty when no week was active
+	WeekNumber *int      `gorm:"column:week_number" json:"week_number,omitempty"`
+	CreatedOn  time.Time `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (PointTransaction) TableName() string {
+	return "point_transactions"
+}
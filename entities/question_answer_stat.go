@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// QuestionAnswerStat is a materialized count of the users in one segment who
+// answered a question. Rows with neither OptionID nor RatingValue count the
+// question's respondents; the others count users per option or rating value.
+// A segment is the state of the user's default address (0 without one), the
+// month they signed up in as YYYY-MM and whether they have entered a contest.
+// Segments do not overlap, so counts can be summed across them. The table is
+// rebuilt as a whole by the question analytics refresh.
+type QuestionAnswerStat struct {
+	ID               int       `gorm:"column:id;primaryKey;autoIncrement"`
+	QuestionMasterID int       `gorm:"column:question_master_id;not null;index"`
+	OptionID         *int      `gorm:"column:option_id"`
+	RatingValue      *int      `gorm:"column:rating_value"`
+	StateID          int       `gorm:"column:state_id;not null"`
+	Cohort           string    `gorm:"column:cohort;type:varchar(7);not null"`
+	Participant      bool      `gorm:"column:participant;not null"`
+	Users            int       `gorm:"column:users;not null"`
+	RefreshedOn      time.Time `gorm:"column:refreshed_on;not null"`
+}
+
+func (QuestionAnswerStat) TableName() string {
+	return "question_answer_stats"
+}
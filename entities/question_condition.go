@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// Condition operators, evaluated against the user's answer to DependsOnQuestionID
+const (
+	// ConditionAnswered holds once the question has any answer
+	ConditionAnswered = "answered"
+	// ConditionAnyOf holds when any of OptionIDs is selected
+	ConditionAnyOf = "any_of"
+	// ConditionNoneOf holds when the question is answered without selecting any of OptionIDs
+	ConditionNoneOf = "none_of"
+	// ConditionBetween holds when a rating lies between MinValue and MaxValue inclusive
+	ConditionBetween = "between"
+)
+
+// QuestionCondition decides whether a question is shown based on the answer to an
+// earlier question. A question is shown only when all of its conditions hold and
+// the questions they depend on are shown too.
+type QuestionCondition struct {
+	ID                  int       `gorm:"column:id;primaryKey;autoIncrement"`
+	QuestionMasterID    int       `gorm:"column:question_master_id;not null;index"`
+	DependsOnQuestionID int       `gorm:"column:depends_on_question_id;not null;index"`
+	Operator            string    `gorm:"column:operator;type:varchar(20);not null"`
+	OptionIDs           []int     `gorm:"column:option_ids;type:jsonb;serializer:json"`
+	MinValue            *int      `gorm:"column:min_value"`
+	MaxValue            *int      `gorm:"column:max_value"`
+	CreatedBy           string    `gorm:"column:created_by;not null"`
+	CreatedOn           time.Time `gorm:"column:created_on;not null"`
+}
+
+func (QuestionCondition) TableName() string {
+	return "question_condition"
+}
+
+// IsMet reports whether the condition holds for the stored answer rows of the
+// question it depends on
+func (c *QuestionCondition) IsMet(answers []UserQuestionAnswer) bool {
+	if len(answers) == 0 {
+		return false
+	}
+
+	switch c.Operator {
+	case ConditionAnswered:
+		return true
+	case ConditionAnyOf, ConditionNoneOf:
+		selected := false
+		for _, answer := range answers {
+			if answer.OptionID == nil {
+				continue
+			}
+			for _, optionID := range c.OptionIDs {
+				if *answer.OptionID == optionID {
+					selected = true
+				}
+			}
+		}
+		return selected == (c.Operator == ConditionAnyOf)
+	case ConditionBetween:
+		for _, answer := range answers {
+			if answer.RatingAnswer == nil {
+				continue
+			}
+			rating := *answer.RatingAnswer
+			if (c.MinValue == nil || rating >= *c.MinValue) && (c.MaxValue == nil || rating <= *c.MaxValue) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,21 +2,112 @@ package entities
 
 import "time"
 
+// Question types decide how a question is answered and how the answer is stored
+const (
+	QuestionTypeSingleChoice = "single_choice"
+	QuestionTypeMultiSelect  = "multi_select"
+	QuestionTypeText         = "text"
+	QuestionTypeRating       = "rating"
+	QuestionTypeDate         = "date"
+)
+
+// Question statuses. Draft questions are hidden from users; published questions
+// are shown within their publish window.
+const (
+	QuestionStatusDraft     = "draft"
+	QuestionStatusPublished = "published"
+)
+
+const (
+	DefaultRatingMin     = 1
+	DefaultRatingMax     = 5
+	DefaultTextMaxLength = 500
+)
+
 type QuestionMaster struct {
-	ID             int        `gorm:"column:id;primaryKey;autoIncrement"`
-	QuestionText   string     `gorm:"column:question_text;type:text"`
-	QuesPoint      int        `gorm:"column:ques_point;not null"`
-	LanguageID     int        `gorm:"column:language_id;not null"`
-	IsActive       bool       `gorm:"column:is_active;not null"`
-	IsDeleted      bool       `gorm:"column:is_deleted;not null"`
-	ProfileOnly    bool       `gorm:"column:profile_only"`
-	CreatedBy      string     `gorm:"column:created_by;not null"`
-	CreatedOn      time.Time  `gorm:"column:created_on;not null"`
-	LastModifiedBy *string    `gorm:"column:last_modified_by"`
-	LastModifiedOn *time.Time `gorm:"column:last_modified_on"`
+	ID                 int        `gorm:"column:id;primaryKey;autoIncrement"`
+	QuestionText       string     `gorm:"column:question_text;type:text"`
+	QuesPoint          int        `gorm:"column:ques_point;not null"`
+	LanguageID         int        `gorm:"column:language_id;not null"`
+	QuestionType       string     `gorm:"column:question_type;type:varchar(20);not null;default:single_choice"`
+	MinSelections      *int       `gorm:"column:min_selections"`
+	MaxSelections      *int       `gorm:"column:max_selections"`
+	MinValue           *int       `gorm:"column:min_value"`
+	MaxValue           *int       `gorm:"column:max_value"`
+	MaxLength          *int       `gorm:"column:max_length"`
+	IsActive           bool       `gorm:"column:is_active;not null"`
+	IsDeleted          bool       `gorm:"column:is_deleted;not null"`
+	ProfileOnly        bool       `gorm:"column:profile_only"`
+	Status             string     `gorm:"column:status;type:varchar(20);not null;default:published"`
+	PublishedVersionID *int       `gorm:"column:published_version_id"`
+	PublishAt          *time.Time `gorm:"column:publish_at"`
+	UnpublishAt        *time.Time `gorm:"column:unpublish_at"`
+	SectionID          *int       `gorm:"column:section_id;index"`
+	DisplayOrder       int        `gorm:"column:display_order;not null;default:0"`
+	CreatedBy          string     `gorm:"column:created_by;not null"`
+	CreatedOn          time.Time  `gorm:"column:created_on;not null"`
+	LastModifiedBy     *string    `gorm:"column:last_modified_by"`
+	LastModifiedOn     *time.Time `gorm:"column:last_modified_on"`
 }
 
 // TableName specifies the table name for QuestionMaster
 func (QuestionMaster) TableName() string {
 	return "question_master"
 }
+
+// Type returns the question type, treating questions created before types existed as single choice
+func (q *QuestionMaster) Type() string {
+	if q.QuestionType == "" {
+		return QuestionTypeSingleChoice
+	}
+	return q.QuestionType
+}
+
+// IsLive reports whether users can see and answer the question at now
+func (q *QuestionMaster) IsLive(now time.Time) bool {
+	if !q.IsActive || q.IsDeleted || q.Status != QuestionStatusPublished {
+		return false
+	}
+	if q.PublishAt != nil && now.Before(*q.PublishAt) {
+		return false
+	}
+	return q.UnpublishAt == nil || now.Before(*q.UnpublishAt)
+}
+
+// IsChoice reports whether the question is answered by picking options
+func (q *QuestionMaster) IsChoice() bool {
+	return q.Type() == QuestionTypeSingleChoice || q.Type() == QuestionTypeMultiSelect
+}
+
+// SelectionRange returns how many options a multi-select answer may pick, given
+// the number of options available
+func (q *QuestionMaster) SelectionRange(optionCount int) (int, int) {
+	minSelections, maxSelections := 1, optionCount
+	if q.MinSelections != nil {
+		minSelections = *q.MinSelections
+	}
+	if q.MaxSelections != nil && *q.MaxSelections < maxSelections {
+		maxSelections = *q.MaxSelections
+	}
+	return minSelections, maxSelections
+}
+
+// RatingRange returns the inclusive bounds of a rating answer
+func (q *QuestionMaster) RatingRange() (int, int) {
+	minValue, maxValue := DefaultRatingMin, DefaultRatingMax
+	if q.MinValue != nil {
+		minValue = *q.MinValue
+	}
+	if q.MaxValue != nil {
+		maxValue = *q.MaxValue
+	}
+	return minValue, maxValue
+}
+
+// TextMaxLength returns the maximum length of a free text answer in characters
+func (q *QuestionMaster) TextMaxLength() int {
+	if q.MaxLength != nil {
+		return *q.MaxLength
+	}
+	return DefaultTextMaxLength
+}
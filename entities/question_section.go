@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// QuestionSection groups profile questions. Questionnaires list sections by
+// DisplayOrder; questions without a section come after every section.
+type QuestionSection struct {
+	ID             int        `gorm:"column:id;primaryKey;autoIncrement"`
+	Title          string     `gorm:"column:title;type:varchar(255);not null"`
+	DisplayOrder   int        `gorm:"column:display_order;not null;default:0"`
+	IsActive       bool       `gorm:"column:is_active;not null;default:true"`
+	IsDeleted      bool       `gorm:"column:is_deleted;not null;default:false"`
+	CreatedBy      string     `gorm:"column:created_by;not null"`
+	CreatedOn      time.Time  `gorm:"column:created_on;not null"`
+	LastModifiedBy *string    `gorm:"column:last_modified_by"`
+	LastModifiedOn *time.Time `gorm:"column:last_modified_on"`
+}
+
+func (QuestionSection) TableName() string {
+	return "question_section"
+}
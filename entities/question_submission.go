@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+const (
+	QuestionSubmissionStatusPending  = "pending"
+	QuestionSubmissionStatusApproved = "approved"
+	QuestionSubmissionStatusRejected = "rejected"
+)
+
+// QuestionSubmission is a question a user suggested for the questionnaire. It
+// stays out of the question bank until an admin approves it, which creates a
+// draft question from it and records that question's ID.
+type QuestionSubmission struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID           string     `gorm:"column:user_id;type:uuid;not null;index" json:"user_id"`
+	QuestionText     string     `gorm:"column:question_text;type:text;not null" json:"question_text"`
+	LanguageID       int        `gorm:"column:language_id;not null" json:"language_id"`
+	Status           string     `gorm:"column:status;type:varchar(20);not null;default:pending;index" json:"status"`
+	ReviewReason     *string    `gorm:"column:review_reason;type:text" json:"review_reason,omitempty"`
+	ReviewedBy       *string    `gorm:"column:reviewed_by;type:varchar(255)" json:"reviewed_by,omitempty"`
+	ReviewedOn       *time.Time `gorm:"column:reviewed_on" json:"reviewed_on,omitempty"`
+	QuestionMasterID *int       `gorm:"column:question_master_id" json:"question_master_id,omitempty"`
+	CreatedOn        time.Time  `gorm:"autoCreateTime" json:"created_on"`
+	User             *User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (QuestionSubmission) TableName() string {
+	return "question_submissions"
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+// Question version statuses. A question has at most one draft; publishing it
+// supersedes the previously published version.
+const (
+	QuestionVersionStatusDraft      = "draft"
+	QuestionVersionStatusPublished  = "published"
+	QuestionVersionStatusSuperseded = "superseded"
+)
+
+// QuestionVersion is an immutable snapshot of a question and its options once it
+// leaves draft. Publishing a version copies its content to question_master and
+// option_master, which always hold the live content.
+type QuestionVersion struct {
+	ID               int             `gorm:"column:id;primaryKey;autoIncrement"`
+	QuestionMasterID int             `gorm:"column:question_master_id;not null;uniqueIndex:uq_question_version,priority:1"`
+	Version          int             `gorm:"column:version;not null;uniqueIndex:uq_question_version,priority:2"`
+	Status           string          `gorm:"column:status;type:varchar(20);not null;index"`
+	QuestionText     string          `gorm:"column:question_text;type:text;not null"`
+	QuesPoint        int             `gorm:"column:ques_point;not null"`
+	LanguageID       int             `gorm:"column:language_id;not null"`
+	QuestionType     string          `gorm:"column:question_type;type:varchar(20);not null"`
+	MinSelections    *int            `gorm:"column:min_selections"`
+	MaxSelections    *int            `gorm:"column:max_selections"`
+	MinValue         *int            `gorm:"column:min_value"`
+	MaxValue         *int            `gorm:"column:max_value"`
+	MaxLength        *int            `gorm:"column:max_length"`
+	CreatedBy        string          `gorm:"column:created_by;not null"`
+	CreatedOn        time.Time       `gorm:"column:created_on;not null"`
+	PublishedBy      *string         `gorm:"column:published_by"`
+	PublishedOn      *time.Time      `gorm:"column:published_on"`
+	Options          []OptionVersion `gorm:"foreignKey:QuestionVersionID"`
+}
+
+func (QuestionVersion) TableName() string {
+	return "question_version"
+}
+
+// OptionVersion is an option as it was in a question version. OptionMasterID is
+// empty for options added in a draft until the draft is published.
+type OptionVersion struct {
+	ID                int    `gorm:"column:id;primaryKey;autoIncrement"`
+	QuestionVersionID int    `gorm:"column:question_version_id;not null;index"`
+	OptionMasterID    *int   `gorm:"column:option_master_id;index"`
+	OptionText        string `gorm:"column:option_text;type:text;not null"`
+	DisplayOrder      int    `gorm:"column:display_order;not null"`
+	IsActive          bool   `gorm:"column:is_active;not null"`
+}
+
+func (OptionVersion) TableName() string {
+	return "option_version"
+}
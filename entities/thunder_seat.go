@@ -1,20 +1,111 @@
 package entities
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ModerationStatusPending  = "pending"
+	ModerationStatusApproved = "approved"
+	ModerationStatusRejected = "rejected"
+	ModerationStatusFlagged  = "flagged"
+)
+
+const (
+	ThunderSeatUserWeekConstraint       = "uq_thunder_seat_user_week_seq"
+	ThunderSeatIdempotencyKeyConstraint = "uq_thunder_seat_user_idempotency_key"
+)
 
 type ThunderSeat struct {
-	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID     string    `gorm:"type:uuid;not null;index" json:"user_id"`
-	WeekNumber int       `gorm:"column:week_number;not null" json:"week_number"`
-	Answer     string    `gorm:"column:answer;type:text" json:"answer"`
-	MediaURL   *string   `gorm:"column:media_url;type:text" json:"media_url,omitempty"`
-	MediaKey   *string   `gorm:"column:media_key;type:text" json:"media_key,omitempty"`
-	MediaType  *string   `gorm:"column:media_type;type:varchar(50)" json:"media_type,omitempty"`
-	CreatedBy  string    `gorm:"type:uuid;not null" json:"created_by"`
-	CreatedOn  time.Time `gorm:"autoCreateTime" json:"created_on"`
-	User       User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	ID               int         `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID           string      `gorm:"type:uuid;not null;index;uniqueIndex:uq_thunder_seat_user_week_seq,priority:1;uniqueIndex:uq_thunder_seat_user_idempotency_key,priority:1" json:"user_id"`
+	WeekNumber       int         `gorm:"column:week_number;not null;uniqueIndex:uq_thunder_seat_user_week_seq,priority:2" json:"week_number"`
+	SubmissionSeq    int         `gorm:"column:submission_seq;not null;default:1;uniqueIndex:uq_thunder_seat_user_week_seq,priority:3" json:"submission_seq"`
+	IdempotencyKey   *string     `gorm:"column:idempotency_key;type:varchar(255);uniqueIndex:uq_thunder_seat_user_idempotency_key,priority:2" json:"-"`
+	Answer           string      `gorm:"column:answer;type:text" json:"answer"`
+	MediaURL         *string     `gorm:"column:media_url;type:text" json:"media_url,omitempty"`
+	MediaKey         *string     `gorm:"column:media_key;type:text" json:"media_key,omitempty"`
+	MediaType        *string     `gorm:"column:media_type;type:varchar(50)" json:"media_type,omitempty"`
+	MediaHash        *string     `gorm:"column:media_hash;type:varchar(64);index" json:"-"`
+	MediaAssetID     *int        `gorm:"column:media_asset_id;index" json:"media_asset_id,omitempty"`
+	CreatedBy        string      `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedOn        time.Time   `gorm:"autoCreateTime" json:"created_on"`
+	UpdatedOn        *time.Time  `gorm:"column:updated_on" json:"updated_on,omitempty"`
+	WithdrawnOn      *time.Time  `gorm:"column:withdrawn_on;index" json:"withdrawn_on,omitempty"`
+	ModerationStatus string      `gorm:"column:moderation_status;type:varchar(20);not null;default:pending;index" json:"moderation_status"`
+	ModerationReason *string     `gorm:"column:moderation_reason;type:text" json:"moderation_reason,omitempty"`
+	ModeratedBy      *string     `gorm:"column:moderated_by;type:varchar(255)" json:"moderated_by,omitempty"`
+	ModeratedOn      *time.Time  `gorm:"column:moderated_on" json:"moderated_on,omitempty"`
+	ReportCount      int         `gorm:"column:report_count;not null;default:0" json:"report_count"`
+	User             User        `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	MediaAsset       *MediaAsset `gorm:"foreignKey:MediaAssetID;references:ID" json:"media_asset,omitempty"`
 }
 
 func (ThunderSeat) TableName() string {
 	return "thunder_seat"
 }
+
+// ThunderSeatMediaPath is where media for a user's submission in a week is stored
+func ThunderSeatMediaPath(userID string, weekNumber int, mediaKey string) string {
+	return fmt.Sprintf("thunder-seat/%s/week-%d/%s", userID, weekNumber, mediaKey)
+}
+
+// MediaObjectPath returns where the submission's media is stored, or "" without media
+func (t *ThunderSeat) MediaObjectPath() string {
+	if t.MediaKey == nil || *t.MediaKey == "" {
+		return ""
+	}
+	return ThunderSeatMediaPath(t.UserID, t.WeekNumber, *t.MediaKey)
+}
+
+const (
+	ThunderSeatRevisionActionEdited    = "edited"
+	ThunderSeatRevisionActionWithdrawn = "withdrawn"
+)
+
+// ThunderSeatRevision is a snapshot of a submission taken before it was edited or withdrawn
+type ThunderSeatRevision struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ThunderSeatID int       `gorm:"column:thunder_seat_id;not null;index" json:"thunder_seat_id"`
+	Action        string    `gorm:"column:action;type:varchar(20);not null" json:"action"`
+	Answer        string    `gorm:"column:answer;type:text" json:"answer"`
+	MediaURL      *string   `gorm:"column:media_url;type:text" json:"media_url,omitempty"`
+	MediaKey      *string   `gorm:"column:media_key;type:text" json:"media_key,omitempty"`
+	MediaType     *string   `gorm:"column:media_type;type:varchar(50)" json:"media_type,omitempty"`
+	CreatedBy     string    `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedOn     time.Time `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (ThunderSeatRevision) TableName() string {
+	return "thunder_seat_revision"
+}
+
+const ThunderSeatReportConstraint = "uq_thunder_seat_report_user"
+
+// ThunderSeatReport is a user report against a submission
+type ThunderSeatReport struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ThunderSeatID int       `gorm:"column:thunder_seat_id;not null;uniqueIndex:uq_thunder_seat_report_user,priority:1" json:"thunder_seat_id"`
+	ReportedBy    string    `gorm:"column:reported_by;type:uuid;not null;uniqueIndex:uq_thunder_seat_report_user,priority:2" json:"reported_by"`
+	Reason        string    `gorm:"column:reason;type:varchar(255);not null" json:"reason"`
+	Details       *string   `gorm:"column:details;type:text" json:"details,omitempty"`
+	CreatedOn     time.Time `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (ThunderSeatReport) TableName() string {
+	return "thunder_seat_report"
+}
+
+// ThunderSeatScreening records the automated screening verdict for a submission
+type ThunderSeatScreening struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ThunderSeatID int       `gorm:"column:thunder_seat_id;not null;index" json:"thunder_seat_id"`
+	Verdict       string    `gorm:"column:verdict;type:varchar(20);not null" json:"verdict"`
+	Findings      string    `gorm:"column:findings;type:text" json:"findings"`
+	CreatedOn     time.Time `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (ThunderSeatScreening) TableName() string {
+	return "thunder_seat_screening"
+}
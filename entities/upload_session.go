@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	UploadSessionStatusPending   = "pending"
+	UploadSessionStatusFinalized = "finalized"
+	UploadSessionStatusFailed    = "failed"
+)
+
+// UploadSession tracks a direct-to-storage media upload from the moment a signed
+// upload URL is issued until the uploaded object is verified and attached to a submission
+type UploadSession struct {
+	ID            string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID        string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	WeekNumber    int        `gorm:"column:week_number;not null" json:"week_number"`
+	ThunderSeatID *int       `gorm:"column:thunder_seat_id;index" json:"thunder_seat_id,omitempty"`
+	ObjectPath    string     `gorm:"column:object_path;type:text;not null" json:"object_path"`
+	MediaKey      string     `gorm:"column:media_key;type:text;not null" json:"media_key"`
+	MediaType     string     `gorm:"column:media_type;type:varchar(50);not null" json:"media_type"`
+	ContentType   string     `gorm:"column:content_type;type:varchar(100);not null" json:"content_type"`
+	ExpectedSize  int64      `gorm:"column:expected_size;not null" json:"expected_size"`
+	ExpectedMD5   *string    `gorm:"column:expected_md5;type:varchar(64)" json:"expected_md5,omitempty"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;default:pending;index" json:"status"`
+	FailureReason *string    `gorm:"column:failure_reason;type:text" json:"failure_reason,omitempty"`
+	ExpiresOn     time.Time  `gorm:"column:expires_on;not null" json:"expires_on"`
+	FinalizedOn   *time.Time `gorm:"column:finalized_on" json:"finalized_on,omitempty"`
+	CreatedOn     time.Time  `gorm:"autoCreateTime" json:"created_on"`
+}
+
+func (u *UploadSession) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == "" {
+		u.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (UploadSession) TableName() string {
+	return "upload_session"
+}
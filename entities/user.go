@@ -25,6 +25,13 @@ type User struct {
 	UpdatedAt        time.Time  `json:"updated_at"`
 	DeletedAt        *time.Time `gorm:"index" json:"deleted_at,omitempty"`
 	Avatar           *Avatar    `gorm:"foreignKey:AvatarID;references:ID" json:"avatar,omitempty"`
+
+	// LeaderboardOptOut hides the user from leaderboards; their points still count
+	LeaderboardOptOut bool `gorm:"default:false" json:"leaderboard_opt_out"`
+
+	// PreferredLanguageID is the language the user reads the app in, used when a
+	// request does not ask for one
+	PreferredLanguageID *int `json:"preferred_language_id,omitempty"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// UserProfilePhoto is a photo a user uploaded in place of an avatar. Uploads are
+// stored as square JPEGs in two sizes and only shown to other users once a
+// moderator approved them. A user has at most one active photo; replaced and
+// removed photos are kept inactive until storage garbage collection removes
+// their objects.
+type UserProfilePhoto struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID           string     `gorm:"column:user_id;type:uuid;not null;index" json:"user_id"`
+	FullPath         string     `gorm:"column:full_path;type:text;not null" json:"full_path"`
+	ThumbPath        string     `gorm:"column:thumb_path;type:text;not null" json:"thumb_path"`
+	IsActive         bool       `gorm:"column:is_active;not null;default:true;index" json:"is_active"`
+	ModerationStatus string     `gorm:"column:moderation_status;type:varchar(20);not null;default:pending;index" json:"moderation_status"`
+	ModerationReason *string    `gorm:"column:moderation_reason;type:text" json:"moderation_reason,omitempty"`
+	ModeratedBy      *string    `gorm:"column:moderated_by;type:varchar(255)" json:"moderated_by,omitempty"`
+	ModeratedOn      *time.Time `gorm:"column:moderated_on" json:"moderated_on,omitempty"`
+	CreatedOn        time.Time  `gorm:"autoCreateTime" json:"created_on"`
+	User             *User      `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (UserProfilePhoto) TableName() string {
+	return "user_profile_photo"
+}
+
+// IsPublic reports whether other users may see the photo
+func (p *UserProfilePhoto) IsPublic() bool {
+	return p.IsActive && p.ModerationStatus == ModerationStatusApproved
+}
@@ -79,6 +79,20 @@ func NewForbiddenError(message string, err error) *AppError {
 	}
 }
 
+// DuplicateError is returned by repositories when a write violates a unique constraint
+type DuplicateError struct {
+	Constraint string
+	Err        error
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate entry violates unique constraint %q", e.Constraint)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return e.Err
+}
+
 // Sentinel errors for type checking
 var (
 	ErrStateNotFound       = errors.New("state not found")
@@ -89,6 +89,8 @@ const (
 	ErrUnnotifiedFetchFailed     = "Failed to get unnotified subscriptions"
 	ErrMarkNotifiedFailed        = "Failed to mark as notified"
 
+	ErrLanguageNotFound = "Language not found"
+
 	ErrInternalServer     = "Internal server error"
 	ErrServiceUnavailable = "Service unavailable"
 )
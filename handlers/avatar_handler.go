@@ -7,10 +7,11 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
-	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
 	"github.com/Infinite-Locus-Product/thums_up_backend/services"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
@@ -18,43 +19,36 @@ import (
 
 type AvatarHandler struct {
 	avatarService services.AvatarService
+	filePolicy    *utils.FilePolicy
 }
 
-func NewAvatarHandler(avatarService services.AvatarService) *AvatarHandler {
+func NewAvatarHandler(avatarService services.AvatarService, filePolicy *utils.FilePolicy) *AvatarHandler {
 	return &AvatarHandler{
 		avatarService: avatarService,
+		filePolicy:    filePolicy,
 	}
 }
 
 // CreateAvatar godoc
 //
 //	@Summary		Create a new avatar
-//	@Description	Create a new avatar with name and image file. Requires authentication.
-//	@Tags			Avatars
+//	@Description	Admin endpoint to create an avatar with a name, image file, optional category, tags and display order. New avatars are placed last unless a display order is given. Requires API key authentication.
+//	@Tags			Admin
 //	@Accept			multipart/form-data
 //	@Produce		json
-//	@Security		Bearer
+//	@Security		APIKey
 //	@Param			name			formData	string					true	"Avatar name"
 //	@Param			image			formData	file					true	"Avatar image file (jpg, jpeg, png, gif, webp, svg, bmp, ico)"
 //	@Param			is_published	formData	bool					false	"Whether the avatar is published"
+//	@Param			category		formData	string					false	"Avatar category"
+//	@Param			tags			formData	[]string				false	"Avatar tags"
+//	@Param			display_order	formData	int						false	"Position in the avatar picker"
 //	@Success		201				{object}	dtos.AvatarResponseDTO	"Avatar created successfully"
 //	@Failure		400				{object}	map[string]string		"Validation failed"
-//	@Failure		401				{object}	map[string]string		"Unauthorized"
+//	@Failure		401				{object}	dtos.ErrorResponse		"Unauthorized"
 //	@Failure		500				{object}	map[string]string		"Failed to create avatar"
-//	@Router			/avatars [post]
+//	@Router			/admin/avatars [post]
 func (h *AvatarHandler) CreateAvatar(ctx *gin.Context) {
-	user, exists := ctx.Get("user")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": errors.ErrUserNotAuthenticated})
-		return
-	}
-
-	userEntity, ok := user.(*entities.User)
-	if !ok || userEntity == nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errors.ErrInvalidUserContext})
-		return
-	}
-
 	// Bind form data
 	var req dtos.CreateAvatarRequestDTO
 	if err := ctx.ShouldBind(&req); err != nil {
@@ -70,12 +64,13 @@ func (h *AvatarHandler) CreateAvatar(ctx *gin.Context) {
 	}
 
 	// Validate image file
-	if err := utils.ValidateImageFile(imageFile); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	validatedFile, err := h.filePolicy.ValidateImage(imageFile)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, ""))
 		return
 	}
 
-	avatar, err := h.avatarService.CreateAvatar(ctx, req, imageFile, userEntity.ID)
+	avatar, err := h.avatarService.CreateAvatar(ctx, req, validatedFile, constants.SYSTEM_USER_ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create avatar: %v", err)})
 		return
@@ -87,27 +82,25 @@ func (h *AvatarHandler) CreateAvatar(ctx *gin.Context) {
 // GetAvatars godoc
 //
 //	@Summary		Get all avatars
-//	@Description	Retrieve all avatars, optionally filtered by publication status
+//	@Description	Retrieve active avatars in picker order, optionally filtered by publication status, category or tag
 //	@Tags			Avatars
 //	@Accept			json
 //	@Produce		json
 //	@Param			is_published	query		bool								false	"Filter by publication status"
+//	@Param			category		query		string								false	"Filter by category"
+//	@Param			tag				query		string								false	"Filter by tag"
 //	@Success		200				{object}	map[string][]dtos.AvatarResponseDTO	"Avatars retrieved successfully"
 //	@Failure		400				{object}	map[string]string					"Invalid is_published parameter"
 //	@Failure		500				{object}	map[string]string					"Failed to fetch avatars"
 //	@Router			/avatars [get]
 func (h *AvatarHandler) GetAvatars(ctx *gin.Context) {
-	var isPublished *bool
-	if publishedParam := ctx.Query("is_published"); publishedParam != "" {
-		published, err := strconv.ParseBool(publishedParam)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid is_published parameter"})
-			return
-		}
-		isPublished = &published
+	var req dtos.AvatarListRequestDTO
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid is_published parameter"})
+		return
 	}
 
-	avatars, err := h.avatarService.GetAllAvatars(ctx, isPublished)
+	avatars, err := h.avatarService.GetAllAvatars(ctx, req)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to fetch avatars: %v", err)})
 		return
@@ -149,3 +142,241 @@ func (h *AvatarHandler) GetAvatarByID(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, avatar)
 }
+
+// ListAdminAvatars godoc
+//
+//	@Summary		List avatars for administration
+//	@Description	Admin endpoint listing avatars in picker order, including inactive and unpublished ones and, when asked, deleted ones. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			is_published	query		bool										false	"Filter by publication status"
+//	@Param			category		query		string										false	"Filter by category"
+//	@Param			tag				query		string										false	"Filter by tag"
+//	@Param			include_deleted	query		bool										false	"Include deleted avatars"
+//	@Success		200				{object}	dtos.SuccessResponse{data=[]dtos.AvatarResponseDTO}	"Avatars retrieved successfully"
+//	@Failure		400				{object}	dtos.ErrorResponse							"Invalid query parameters"
+//	@Failure		401				{object}	dtos.ErrorResponse							"Unauthorized"
+//	@Failure		500				{object}	dtos.ErrorResponse							"Failed to fetch avatars"
+//	@Router			/admin/avatars [get]
+func (h *AvatarHandler) ListAdminAvatars(c *gin.Context) {
+	var req dtos.AvatarListRequestDTO
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	avatars, err := h.avatarService.ListAdminAvatars(c.Request.Context(), req)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch avatars")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    avatars,
+	})
+}
+
+// UpdateAvatar godoc
+//
+//	@Summary		Update an avatar
+//	@Description	Admin endpoint to change an avatar's name, category, tags, display order or active flag. Only the fields sent are changed; tags replace the existing ones. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			avatarId	path		int												true	"Avatar ID"
+//	@Param			request		body		dtos.UpdateAvatarRequestDTO						true	"Fields to update"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.AvatarResponseDTO}	"Avatar updated successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse								"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse								"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse								"Avatar not found"
+//	@Failure		500			{object}	dtos.ErrorResponse								"Failed to update avatar"
+//	@Router			/admin/avatars/{avatarId} [patch]
+func (h *AvatarHandler) UpdateAvatar(c *gin.Context) {
+	avatarID, ok := h.avatarIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateAvatarRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	avatar, err := h.avatarService.UpdateAvatar(c.Request.Context(), avatarID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to update avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    avatar,
+	})
+}
+
+// DeleteAvatar godoc
+//
+//	@Summary		Delete an avatar
+//	@Description	Admin endpoint to retire an avatar. The avatar disappears from the picker but users who already chose it keep it. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			avatarId	path		int					true	"Avatar ID"
+//	@Success		200			{object}	dtos.SuccessResponse	"Avatar deleted successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse	"Invalid avatar ID"
+//	@Failure		401			{object}	dtos.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse	"Avatar not found"
+//	@Failure		500			{object}	dtos.ErrorResponse	"Failed to delete avatar"
+//	@Router			/admin/avatars/{avatarId} [delete]
+func (h *AvatarHandler) DeleteAvatar(c *gin.Context) {
+	avatarID, ok := h.avatarIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.avatarService.DeleteAvatar(c.Request.Context(), avatarID, constants.SYSTEM_USER_ID); err != nil {
+		h.respondError(c, err, "Failed to delete avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Message: "Avatar deleted successfully",
+	})
+}
+
+// PublishAvatar godoc
+//
+//	@Summary		Publish an avatar
+//	@Description	Admin endpoint to make an avatar available in the picker. Records who published it and when. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			avatarId	path		int												true	"Avatar ID"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.AvatarResponseDTO}	"Avatar published successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse								"Invalid avatar ID"
+//	@Failure		401			{object}	dtos.ErrorResponse								"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse								"Avatar not found"
+//	@Failure		500			{object}	dtos.ErrorResponse								"Failed to publish avatar"
+//	@Router			/admin/avatars/{avatarId}/publish [post]
+func (h *AvatarHandler) PublishAvatar(c *gin.Context) {
+	h.setPublished(c, true)
+}
+
+// UnpublishAvatar godoc
+//
+//	@Summary		Unpublish an avatar
+//	@Description	Admin endpoint to remove an avatar from the picker. Users who already chose it keep it. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			avatarId	path		int												true	"Avatar ID"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.AvatarResponseDTO}	"Avatar unpublished successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse								"Invalid avatar ID"
+//	@Failure		401			{object}	dtos.ErrorResponse								"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse								"Avatar not found"
+//	@Failure		500			{object}	dtos.ErrorResponse								"Failed to unpublish avatar"
+//	@Router			/admin/avatars/{avatarId}/unpublish [post]
+func (h *AvatarHandler) UnpublishAvatar(c *gin.Context) {
+	h.setPublished(c, false)
+}
+
+func (h *AvatarHandler) setPublished(c *gin.Context, published bool) {
+	avatarID, ok := h.avatarIDParam(c)
+	if !ok {
+		return
+	}
+
+	avatar, err := h.avatarService.SetPublished(c.Request.Context(), avatarID, published, constants.SYSTEM_USER_ID)
+	if err != nil {
+		if published {
+			h.respondError(c, err, "Failed to publish avatar")
+		} else {
+			h.respondError(c, err, "Failed to unpublish avatar")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    avatar,
+	})
+}
+
+// ReorderAvatars godoc
+//
+//	@Summary		Reorder the avatar picker
+//	@Description	Admin endpoint to set the picker order. The listed avatars come first in the given order; the rest keep their relative order after them. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			request	body		dtos.ReorderAvatarsRequestDTO						true	"Avatar IDs in picker order"
+//	@Success		200		{object}	dtos.SuccessResponse{data=[]dtos.AvatarResponseDTO}	"Avatars reordered successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse									"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		404		{object}	dtos.ErrorResponse									"Avatar not found"
+//	@Failure		500		{object}	dtos.ErrorResponse									"Failed to reorder avatars"
+//	@Router			/admin/avatars/order [put]
+func (h *AvatarHandler) ReorderAvatars(c *gin.Context) {
+	var req dtos.ReorderAvatarsRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	avatars, err := h.avatarService.ReorderAvatars(c.Request.Context(), req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		h.respondError(c, err, "Failed to reorder avatars")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    avatars,
+	})
+}
+
+func (h *AvatarHandler) avatarIDParam(c *gin.Context) (int, bool) {
+	avatarID, err := strconv.Atoi(c.Param("avatarId"))
+	if err != nil || avatarID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid avatar ID",
+		})
+		return 0, false
+	}
+	return avatarID, true
+}
+
+func (h *AvatarHandler) respondError(c *gin.Context, err error, fallback string) {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+			Success: false,
+			Error:   appErr.Message,
+		})
+		return
+	}
+	log.WithError(err).Error(fallback)
+	c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+		Success: false,
+		Error:   fallback,
+	})
+}
@@ -258,3 +258,112 @@ func (h *ContestWeekHandler) GetActiveWeek(c *gin.Context) {
 		Data:    response,
 	})
 }
+
+// GetTimeline godoc
+//
+//	@Summary		Get contest timeline
+//	@Description	Retrieve every contest week with a server-computed status, seconds until open/close, draw time and results time. server_time is included so clients can correct for clock skew.
+//	@Tags			Contest Weeks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	dtos.SuccessResponse{data=dtos.ContestWeekTimelineResponse}	"Contest timeline retrieved successfully"
+//	@Failure		500	{object}	dtos.ErrorResponse											"Failed to get contest timeline"
+//	@Router			/contest-weeks/timeline [get]
+func (h *ContestWeekHandler) GetTimeline(c *gin.Context) {
+	response, err := h.contestWeekService.GetTimeline(c.Request.Context())
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to get contest timeline")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to get contest timeline",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UpsertWeekRules godoc
+//
+//	@Summary		Configure Thunder Seat submission rules for a week
+//	@Description	Create or replace the submission rules for a contest week: localized prompts, media requirement, allowed media kinds, max answer length, max submissions per user and whether sharing fields are required. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			weekNumber	path		int														true	"Week number"
+//	@Param			request		body		dtos.ContestWeekRulesRequest							true	"Submission rules"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.ContestWeekRulesResponse}	"Submission rules saved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Contest week not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to save contest week rules"
+//	@Router			/admin/contest-weeks/{weekNumber}/rules [put]
+func (h *ContestWeekHandler) UpsertWeekRules(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	weekNumber, err := strconv.Atoi(c.Param("weekNumber"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid week number",
+		})
+		return
+	}
+
+	var req dtos.ContestWeekRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: validationErrors,
+		})
+		return
+	}
+
+	response, err := h.contestWeekService.UpsertWeekRules(c.Request.Context(), weekNumber, req, userEntity.ID)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to save contest week rules")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to save contest week rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Submission rules saved successfully",
+	})
+}
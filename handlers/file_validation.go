@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	stderrors "errors"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// fileValidationErrorResponse builds the 400 response for a rejected upload, passing
+// the validation code and its parameters on so clients can show a localized message.
+// field, when set, names the form field the file came from.
+func fileValidationErrorResponse(err error, field string) dtos.ErrorResponse {
+	response := dtos.ErrorResponse{
+		Success: false,
+		Error:   err.Error(),
+	}
+	if field != "" {
+		response.Error = "Invalid " + field + " file: " + err.Error()
+	}
+
+	var validationErr *utils.FileValidationError
+	if stderrors.As(err, &validationErr) {
+		response.Code = validationErr.Code
+		details := map[string]interface{}{}
+		for key, value := range validationErr.Params {
+			details[key] = value
+		}
+		if field != "" {
+			details["field"] = field
+		}
+		if len(details) > 0 {
+			response.Details = details
+		}
+	}
+	return response
+}
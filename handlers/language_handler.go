@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+)
+
+type LanguageHandler struct {
+	localizationService services.LocalizationService
+}
+
+func NewLanguageHandler(localizationService services.LocalizationService) *LanguageHandler {
+	return &LanguageHandler{
+		localizationService: localizationService,
+	}
+}
+
+// GetLanguages godoc
+//
+//	@Summary		List languages
+//	@Description	Retrieve the languages the app is available in. A language's id can be sent as language_id or saved as the profile's preferred_language_id; its code is matched against Accept-Language.
+//	@Tags			Languages
+//	@Produce		json
+//	@Success		200	{object}	dtos.SuccessResponse{data=[]dtos.LanguageDTO}	"Languages retrieved successfully"
+//	@Router			/languages [get]
+func (h *LanguageHandler) GetLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    h.localizationService.GetLanguages(c.Request.Context()),
+	})
+}
+
+// ReloadTranslations godoc
+//
+//	@Summary		Reload translations
+//	@Description	Admin endpoint to reload languages and translations from the language tables straight away instead of waiting for the cache to expire. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Success		200	{object}	dtos.SuccessResponse{data=dtos.TranslationReloadResponse}	"Translations reloaded successfully"
+//	@Failure		401	{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		500	{object}	dtos.ErrorResponse										"Failed to reload translations"
+//	@Router			/admin/translations/reload [post]
+func (h *LanguageHandler) ReloadTranslations(c *gin.Context) {
+	response, err := h.localizationService.Reload(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err, "Failed to reload translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
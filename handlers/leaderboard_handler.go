@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService services.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService services.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetLeaderboard godoc
+//
+//	@Summary		Get a leaderboard
+//	@Description	Retrieve users ranked by points for a contest week or all time, with the caller's own rank. Ties go to whoever reached the score first. Names are masked, and users who set leaderboard_opt_out on their profile are not listed or ranked. A week scope without a week number shows the active contest week. Requires authentication.
+//	@Tags			Leaderboard
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			scope	query		string												false	"Leaderboard scope"	Enums(week, all_time)	default(week)
+//	@Param			week	query		int													false	"Contest week number"
+//	@Param			limit	query		int													false	"Number of entries to return (1-100, default 20)"
+//	@Param			offset	query		int													false	"Number of entries to skip"
+//	@Success		200		{object}	dtos.SuccessResponse{data=dtos.LeaderboardResponse}	"Leaderboard retrieved successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse									"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		404		{object}	dtos.ErrorResponse									"Contest week not found"
+//	@Failure		500		{object}	dtos.ErrorResponse									"Failed to get leaderboard"
+//	@Router			/leaderboard [get]
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	userEntity, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.LeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+	if req.Week != 0 && req.Scope == dtos.LeaderboardScopeAllTime {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "week can only be used with the week scope",
+		})
+		return
+	}
+
+	leaderboard, err := h.leaderboardService.GetLeaderboard(c.Request.Context(), userEntity, req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    leaderboard,
+	})
+}
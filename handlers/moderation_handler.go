@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type ModerationHandler struct {
+	moderationService services.ModerationService
+}
+
+func NewModerationHandler(moderationService services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: moderationService,
+	}
+}
+
+// GetModerationQueue godoc
+//
+//	@Summary		Get Thunder Seat moderation queue
+//	@Description	Admin endpoint to list Thunder Seat submissions for review with signed media URLs. Most reported submissions are listed first. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			status		query		string													false	"Moderation status (pending, approved, rejected, flagged)"
+//	@Param			week		query		int														false	"Week number"
+//	@Param			media_type	query		string													false	"Media type (audio, video, image)"
+//	@Param			reported	query		bool													false	"Only submissions reported by users"
+//	@Param			limit		query		int														true	"Number of items per page"	minimum(1)	maximum(100)
+//	@Param			offset		query		int														false	"Number of items to skip"	minimum(0)	default(0)
+//	@Success		200			{object}	dtos.PaginatedResponse{data=[]dtos.ModerationQueueItem}	"Moderation queue retrieved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to get moderation queue"
+//	@Router			/admin/thunder-seat/moderation [get]
+func (h *ModerationHandler) GetModerationQueue(c *gin.Context) {
+	var req dtos.ModerationQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	items, total, err := h.moderationService.GetModerationQueue(c.Request.Context(), req)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to get moderation queue")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to get moderation queue",
+		})
+		return
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit != 0 {
+		totalPages++
+	}
+	currentPage := (req.Offset / req.Limit) + 1
+
+	c.JSON(http.StatusOK, dtos.PaginatedResponse{
+		Success: true,
+		Data:    items,
+		Meta: dtos.PaginationMeta{
+			Page:       currentPage,
+			PageSize:   req.Limit,
+			TotalPages: totalPages,
+			TotalCount: total,
+		},
+	})
+}
+
+// ModerateSubmissions godoc
+//
+//	@Summary		Approve or reject Thunder Seat submissions
+//	@Description	Admin endpoint to approve or reject submissions in bulk. A reason is required when rejecting. Only approved submissions are eligible for winner selection. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			request	body		dtos.ModerateSubmissionsRequest								true	"Submission IDs, action and reason"
+//	@Success		200		{object}	dtos.SuccessResponse{data=dtos.ModerateSubmissionsResponse}	"Submissions moderated successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse											"Failed to moderate submissions"
+//	@Router			/admin/thunder-seat/moderation [post]
+func (h *ModerationHandler) ModerateSubmissions(c *gin.Context) {
+	var req dtos.ModerateSubmissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	response, err := h.moderationService.ModerateSubmissions(c.Request.Context(), req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to moderate submissions")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to moderate submissions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Submissions moderated successfully",
+	})
+}
+
+// ReportSubmission godoc
+//
+//	@Summary		Report a Thunder Seat submission
+//	@Description	Report another user's submission for review. Submissions reported by several users are flagged for moderation. Requires authentication.
+//	@Tags			Thunder Seat
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id		path		int								true	"Submission ID"
+//	@Param			request	body		dtos.ReportSubmissionRequest	true	"Report reason"
+//	@Success		201		{object}	dtos.SuccessResponse			"Submission reported successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse				"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse				"Unauthorized"
+//	@Failure		404		{object}	dtos.ErrorResponse				"Submission not found"
+//	@Failure		409		{object}	dtos.ErrorResponse				"Submission already reported"
+//	@Failure		500		{object}	dtos.ErrorResponse				"Failed to report submission"
+//	@Router			/thunder-seat/{id}/report [post]
+func (h *ModerationHandler) ReportSubmission(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid submission ID",
+		})
+		return
+	}
+
+	var req dtos.ReportSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	if err := h.moderationService.ReportSubmission(c.Request.Context(), submissionID, userEntity.ID, req); err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to report submission")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to report submission",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.SuccessResponse{
+		Success: true,
+		Message: "Submission reported successfully",
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type PointsHandler struct {
+	pointsService services.PointsService
+}
+
+func NewPointsHandler(pointsService services.PointsService) *PointsHandler {
+	return &PointsHandler{
+		pointsService: pointsService,
+	}
+}
+
+// GetPoints godoc
+//
+//	@Summary		Get points balance and history
+//	@Description	Retrieve the authenticated user's points balance and ledger, newest first. Points are credited once per profile question answered, per contest week entered in Thunder Seat, per user referred and per completed login streak. Requires authentication.
+//	@Tags			Profile
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			limit	query		int											false	"Number of transactions to return (1-100, default 20)"
+//	@Param			offset	query		int											false	"Number of transactions to skip"
+//	@Success		200		{object}	dtos.SuccessResponse{data=dtos.PointsResponse}	"Points retrieved successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse							"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse							"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse							"Failed to get points"
+//	@Router			/profile/points [get]
+func (h *PointsHandler) GetPoints(c *gin.Context) {
+	userEntity, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.PointsHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	points, err := h.pointsService.GetPoints(c.Request.Context(), userEntity.ID, req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get points")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    points,
+	})
+}
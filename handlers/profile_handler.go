@@ -6,27 +6,34 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
 	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
 type ProfileHandler struct {
-	userService services.UserService
+	userService         services.UserService
+	profilePhotoService services.ProfilePhotoService
+	filePolicy          *utils.FilePolicy
 }
 
-func NewProfileHandler(userService services.UserService) *ProfileHandler {
+func NewProfileHandler(userService services.UserService, profilePhotoService services.ProfilePhotoService, filePolicy *utils.FilePolicy) *ProfileHandler {
 	return &ProfileHandler{
-		userService: userService,
+		userService:         userService,
+		profilePhotoService: profilePhotoService,
+		filePolicy:          filePolicy,
 	}
 }
 
 // GetProfile godoc
 //
 //	@Summary		Get user profile
-//	@Description	Retrieve the authenticated user's profile information including avatar image, winner status, and QR code URL (if user has won and submitted KYC). The is_winner flag indicates if the user is a winner. The QR code is generated after the user submits their KYC details. Requires authentication. This endpoint is optimized with concurrent data fetching using goroutines.
+//	@Description	Retrieve the authenticated user's profile information including avatar image, winner status, and QR code URL (if user has won and submitted KYC). The is_winner flag indicates if the user is a winner. The QR code is generated after the user submits their KYC details. Requires authentication. A custom profile photo replaces the avatar image for its owner unless it was rejected; other users only see it once approved. This endpoint is optimized with concurrent data fetching using goroutines.
 //	@Tags			Profile
 //	@Accept			json
 //	@Produce		json
@@ -48,7 +55,7 @@ func (h *ProfileHandler) GetProfile(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errors.ErrInvalidUserContext})
 		return
 	}
-	userProfile, avatarImageURL, qrCodeURL, isWinner, err := h.userService.GetUser(ctx, userEntity.ID)
+	userProfile, avatarVariants, qrCodeURL, isWinner, err := h.userService.GetUser(ctx, userEntity.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: %v", errors.ErrProfileFetchFailed, err)})
 		return
@@ -59,22 +66,40 @@ func (h *ProfileHandler) GetProfile(ctx *gin.Context) {
 		return
 	}
 
+	profilePhoto, err := h.profilePhotoService.GetProfilePhoto(ctx, userEntity.ID)
+	if err != nil {
+		// The avatar is still a valid picture, so the profile is served without the photo
+		log.WithError(err).WithField("user_id", userEntity.ID).Warn("Failed to load profile photo")
+	}
+	if profilePhoto != nil && profilePhoto.ModerationStatus != entities.ModerationStatusRejected {
+		avatarVariants = &profilePhoto.Variants
+	}
+
+	var avatarImageURL *string
+	if avatarVariants != nil {
+		avatarImageURL = &avatarVariants.Full
+	}
+
 	response := dtos.ProfileResponseDTO{
 		User: dtos.UserProfileDTO{
-			ID:           userProfile.ID,
-			PhoneNumber:  userProfile.PhoneNumber,
-			Name:         userProfile.Name,
-			Email:        userProfile.Email,
-			AvatarImage:  avatarImageURL,
-			QRCodeURL:    qrCodeURL,
-			IsWinner:     isWinner,
-			IsViewed:     userProfile.IsViewed,
-			IsActive:     userProfile.IsActive,
-			IsVerified:   userProfile.IsVerified,
-			ReferralCode: userProfile.ReferralCode,
-			ReferredBy:   userProfile.ReferredBy,
-			CreatedAt:    userProfile.CreatedAt,
-			UpdatedAt:    userProfile.UpdatedAt,
+			ID:                  userProfile.ID,
+			PhoneNumber:         userProfile.PhoneNumber,
+			Name:                userProfile.Name,
+			Email:               userProfile.Email,
+			AvatarImage:         avatarImageURL,
+			AvatarVariants:      avatarVariants,
+			ProfilePhoto:        profilePhoto,
+			QRCodeURL:           qrCodeURL,
+			IsWinner:            isWinner,
+			IsViewed:            userProfile.IsViewed,
+			LeaderboardOptOut:   userProfile.LeaderboardOptOut,
+			PreferredLanguageID: userProfile.PreferredLanguageID,
+			IsActive:            userProfile.IsActive,
+			IsVerified:          userProfile.IsVerified,
+			ReferralCode:        userProfile.ReferralCode,
+			ReferredBy:          userProfile.ReferredBy,
+			CreatedAt:           userProfile.CreatedAt,
+			UpdatedAt:           userProfile.UpdatedAt,
 		},
 	}
 
@@ -84,7 +109,7 @@ func (h *ProfileHandler) GetProfile(ctx *gin.Context) {
 // UpdateProfile godoc
 //
 //	@Summary		Update user profile
-//	@Description	Update the authenticated user's profile information (name, email, avatar, sharing_platform, platform_user_name). Requires authentication.
+//	@Description	Update the authenticated user's profile information (name, email, avatar, sharing_platform, platform_user_name, leaderboard_opt_out). Only published avatars can be chosen; users keep an avatar that was retired after they chose it, and avatar_id 0 clears it. Requires authentication.
 //	@Tags			Profile
 //	@Accept			json
 //	@Produce		json
@@ -125,9 +150,246 @@ func (h *ProfileHandler) UpdateProfile(ctx *gin.Context) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": errors.ErrEmailAlreadyInUse})
 			return
 		}
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			ctx.JSON(appErr.StatusCode, gin.H{"error": appErr.Message})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: %v", errors.ErrProfileUpdateFailed, err)})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, updatedUser)
 }
+
+// UploadProfilePhoto godoc
+//
+//	@Summary		Upload a custom profile photo
+//	@Description	Upload a photo to show instead of the chosen avatar. The image is cropped square and stored in standard sizes. It replaces any previous photo and is shown to other users, for example in winner lists, only after moderation approves it; until then they see the chosen avatar. Requires authentication.
+//	@Tags			Profile
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			photo	formData	file										true	"Photo (jpg, jpeg, png, gif), at least 128x128 pixels"
+//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.ProfilePhotoDTO}	"Profile photo uploaded and waiting for moderation"
+//	@Failure		400		{object}	dtos.ErrorResponse							"Invalid image"
+//	@Failure		401		{object}	dtos.ErrorResponse							"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse							"Failed to upload profile photo"
+//	@Router			/profile/photo [post]
+func (h *ProfileHandler) UploadProfilePhoto(c *gin.Context) {
+	userEntity, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	photoFile, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{Success: false, Error: "photo file is required"})
+		return
+	}
+
+	validatedFile, err := h.filePolicy.ValidateImage(photoFile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, "photo"))
+		return
+	}
+
+	photo, err := h.profilePhotoService.UploadProfilePhoto(c.Request.Context(), userEntity.ID, validatedFile)
+	if err != nil {
+		respondServiceError(c, err, "Failed to upload profile photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.SuccessResponse{
+		Success: true,
+		Data:    photo,
+		Message: "Profile photo uploaded and waiting for moderation",
+	})
+}
+
+// GetProfilePhoto godoc
+//
+//	@Summary		Get the custom profile photo
+//	@Description	Retrieve the authenticated user's custom profile photo with its moderation status and rejection reason, if any. Requires authentication.
+//	@Tags			Profile
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	dtos.SuccessResponse{data=dtos.ProfilePhotoDTO}	"Profile photo retrieved successfully"
+//	@Failure		401	{object}	dtos.ErrorResponse							"Unauthorized"
+//	@Failure		404	{object}	dtos.ErrorResponse							"No profile photo"
+//	@Failure		500	{object}	dtos.ErrorResponse							"Failed to get profile photo"
+//	@Router			/profile/photo [get]
+func (h *ProfileHandler) GetProfilePhoto(c *gin.Context) {
+	userEntity, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.profilePhotoService.GetProfilePhoto(c.Request.Context(), userEntity.ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get profile photo")
+		return
+	}
+	if photo == nil {
+		c.JSON(http.StatusNotFound, dtos.ErrorResponse{Success: false, Error: "No profile photo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    photo,
+		Message: "Profile photo retrieved successfully",
+	})
+}
+
+// DeleteProfilePhoto godoc
+//
+//	@Summary		Remove the custom profile photo
+//	@Description	Remove the authenticated user's custom profile photo so the chosen avatar is shown again. Requires authentication.
+//	@Tags			Profile
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	dtos.SuccessResponse	"Profile photo removed successfully"
+//	@Failure		401	{object}	dtos.ErrorResponse		"Unauthorized"
+//	@Failure		404	{object}	dtos.ErrorResponse		"No profile photo to remove"
+//	@Failure		500	{object}	dtos.ErrorResponse		"Failed to remove profile photo"
+//	@Router			/profile/photo [delete]
+func (h *ProfileHandler) DeleteProfilePhoto(c *gin.Context) {
+	userEntity, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.profilePhotoService.DeleteProfilePhoto(c.Request.Context(), userEntity.ID); err != nil {
+		respondServiceError(c, err, "Failed to remove profile photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Message: "Profile photo removed successfully",
+	})
+}
+
+// GetProfilePhotoQueue godoc
+//
+//	@Summary		Get profile photo moderation queue
+//	@Description	Admin endpoint to list users' current profile photos for review, oldest first. Lists pending photos unless another status is given. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			status	query		string														false	"Moderation status (pending, approved, rejected)"
+//	@Param			limit	query		int															true	"Number of items per page"	minimum(1)	maximum(100)
+//	@Param			offset	query		int															false	"Number of items to skip"	minimum(0)	default(0)
+//	@Success		200		{object}	dtos.PaginatedResponse{data=[]dtos.ProfilePhotoQueueItem}	"Moderation queue retrieved successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse											"Failed to get moderation queue"
+//	@Router			/admin/profile-photos/moderation [get]
+func (h *ProfileHandler) GetProfilePhotoQueue(c *gin.Context) {
+	var req dtos.ProfilePhotoQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	items, total, err := h.profilePhotoService.GetModerationQueue(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get moderation queue")
+		return
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, dtos.PaginatedResponse{
+		Success: true,
+		Data:    items,
+		Meta: dtos.PaginationMeta{
+			Page:       (req.Offset / req.Limit) + 1,
+			PageSize:   req.Limit,
+			TotalPages: totalPages,
+			TotalCount: total,
+		},
+	})
+}
+
+// ModerateProfilePhotos godoc
+//
+//	@Summary		Approve or reject profile photos
+//	@Description	Admin endpoint to approve or reject profile photos in bulk. A reason is required when rejecting and is shown to the owner. Photos replaced or removed in the meantime are skipped. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			request	body		dtos.ModerateProfilePhotosRequest							true	"Photo IDs, action and reason"
+//	@Success		200		{object}	dtos.SuccessResponse{data=dtos.ModerateSubmissionsResponse}	"Profile photos moderated successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse											"Failed to moderate profile photos"
+//	@Router			/admin/profile-photos/moderation [post]
+func (h *ProfileHandler) ModerateProfilePhotos(c *gin.Context) {
+	var req dtos.ModerateProfilePhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	response, err := h.profilePhotoService.ModerateProfilePhotos(c.Request.Context(), req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to moderate profile photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Profile photos moderated successfully",
+	})
+}
+
+// profileUser returns the authenticated user, writing the error response when missing
+func profileUser(c *gin.Context) (*entities.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return nil, false
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return nil, false
+	}
+	return userEntity, true
+}
+
+func respondServiceError(c *gin.Context, err error, fallback string) {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		response := dtos.ErrorResponse{
+			Success: false,
+			Error:   appErr.Message,
+		}
+		if details := utils.FormatValidationErrors(err); len(details) > 0 {
+			response.Details = details
+		}
+		c.JSON(appErr.StatusCode, response)
+		return
+	}
+	log.WithError(err).Error(fallback)
+	c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+		Success: false,
+		Error:   fallback,
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type QuestionAnalyticsHandler struct {
+	questionAnalyticsService services.QuestionAnalyticsService
+}
+
+func NewQuestionAnalyticsHandler(questionAnalyticsService services.QuestionAnalyticsService) *QuestionAnalyticsHandler {
+	return &QuestionAnalyticsHandler{
+		questionAnalyticsService: questionAnalyticsService,
+	}
+}
+
+// GetQuestionAnalytics godoc
+//
+//	@Summary		Get question analytics
+//	@Description	Admin endpoint to retrieve how users answered questions: respondents per question and users per option or rating value, with percentages of respondents. Filters select users by the state of their default address (0 for none), signup month and whether they have entered a contest. Counts are as of refreshed_on and are rebuilt periodically. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			question_id		query		int														false	"Only this question"
+//	@Param			state_id		query		int														false	"State of the default address, 0 for users without one"
+//	@Param			cohort_from		query		string													false	"First signup month (YYYY-MM)"
+//	@Param			cohort_to		query		string													false	"Last signup month (YYYY-MM)"
+//	@Param			participated	query		bool													false	"Whether users have entered a contest"
+//	@Success		200				{object}	dtos.SuccessResponse{data=dtos.QuestionAnalyticsResponse}	"Question analytics retrieved successfully"
+//	@Failure		400				{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401				{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		500				{object}	dtos.ErrorResponse										"Failed to get question analytics"
+//	@Router			/admin/analytics/questions [get]
+func (h *QuestionAnalyticsHandler) GetQuestionAnalytics(c *gin.Context) {
+	var req dtos.QuestionAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	analytics, err := h.questionAnalyticsService.GetQuestionAnalytics(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question analytics")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    analytics,
+	})
+}
+
+// RefreshQuestionAnalytics godoc
+//
+//	@Summary		Refresh question analytics
+//	@Description	Admin endpoint to rebuild question analytics from the answers straight away instead of waiting for the periodic refresh. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Success		200	{object}	dtos.SuccessResponse{data=dtos.QuestionAnalyticsRefreshResponse}	"Question analytics refreshed successfully"
+//	@Failure		401	{object}	dtos.ErrorResponse												"Unauthorized"
+//	@Failure		500	{object}	dtos.ErrorResponse												"Failed to refresh question analytics"
+//	@Router			/admin/analytics/questions/refresh [post]
+func (h *QuestionAnalyticsHandler) RefreshQuestionAnalytics(c *gin.Context) {
+	response, err := h.questionAnalyticsService.Refresh(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err, "Failed to refresh question analytics")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
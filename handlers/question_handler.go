@@ -1,46 +1,52 @@
 package handlers
 
 import (
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/middlewares"
 	"github.com/Infinite-Locus-Product/thums_up_backend/services"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
 type QuestionHandler struct {
-	questionService services.QuestionService
-	userService     services.UserService
+	questionService     services.QuestionService
+	userService         services.UserService
+	localizationService services.LocalizationService
 }
 
-func NewQuestionHandler(questionService services.QuestionService, userService services.UserService) *QuestionHandler {
+func NewQuestionHandler(questionService services.QuestionService, userService services.UserService, localizationService services.LocalizationService) *QuestionHandler {
 	return &QuestionHandler{
-		questionService: questionService,
-		userService:     userService,
+		questionService:     questionService,
+		userService:         userService,
+		localizationService: localizationService,
 	}
 }
 
 // SubmitQuestion godoc
 //
-//	@Summary		Submit a new question
-//	@Description	Submit a new question with text and language. Requires authentication.
+//	@Summary		Suggest a question
+//	@Description	Suggest a question with text and language. The suggestion is queued for admin review and only added to the questionnaire once approved. A user can have a limited number of suggestions waiting for review. Requires authentication.
 //	@Tags			Questions
 //	@Accept			json
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			request	body		dtos.QuestionSubmitRequest							true	"Question text and language ID"
-//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.QuestionResponse}	"Question submitted successfully"
-//	@Failure		400		{object}	dtos.ErrorResponse									"Validation failed"
-//	@Failure		401		{object}	dtos.ErrorResponse									"Unauthorized"
-//	@Failure		500		{object}	dtos.ErrorResponse									"Failed to submit question"
+//	@Param			request	body		dtos.QuestionSubmitRequest								true	"Question text and language ID"
+//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.QuestionSubmissionDTO}	"Question submitted for review"
+//	@Failure		400		{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		429		{object}	dtos.ErrorResponse										"Too many questions waiting for review"
+//	@Failure		500		{object}	dtos.ErrorResponse										"Failed to submit question"
 //	@Router			/questions [post]
 func (h *QuestionHandler) SubmitQuestion(c *gin.Context) {
 	var req dtos.QuestionSubmitRequest
@@ -84,7 +90,7 @@ func (h *QuestionHandler) SubmitQuestion(c *gin.Context) {
 	c.JSON(http.StatusCreated, dtos.SuccessResponse{
 		Success: true,
 		Data:    response,
-		Message: "Question submitted successfully",
+		Message: "Question submitted for review",
 	})
 }
 
@@ -126,13 +132,14 @@ func (h *QuestionHandler) GetActiveQuestions(c *gin.Context) {
 // GetQuestions godoc
 //
 //	@Summary		Get Questions
-//	@Description	Get all active questions with user's answers
+//	@Description	Get the profile questions that apply to the user given their earlier answers, in section and display order, with the user's answers, the next unanswered question and the questionnaire's completion
 //	@Tags			Questions
 //	@Accept			json
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			language_id	query		int														true	"Language ID"
-//	@Success		200			{object}	dtos.SuccessResponse{data=[]dtos.QuestionResponseDTO}	"Questions retrieved successfully"
+//	@Param			language_id		query		int														false	"Language ID (defaults to the profile's preferred language, then Accept-Language)"
+//	@Param			Accept-Language	header		string													false	"Preferred languages"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionnaireResponseDTO}	"Questions retrieved successfully"
 //	@Failure		400			{object}	dtos.ErrorResponse										"Invalid request"
 //	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
 //	@Failure		500			{object}	dtos.ErrorResponse										"Failed to fetch questions"
@@ -149,17 +156,8 @@ func (h *QuestionHandler) GetQuestions(c *gin.Context) {
 
 	userEntity := user.(*entities.User)
 
-	languageIDStr, exists := c.GetQuery("language_id")
-	if !exists {
-		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-			Success: false,
-			Error:   "Language ID is required",
-		})
-		return
-	}
-
-	languageID, err := strconv.Atoi(languageIDStr)
-	if err != nil {
+	languageID, ok := middlewares.RequestLanguageID(c, h.localizationService)
+	if !ok {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
 			Success: false,
 			Error:   "Invalid language_id",
@@ -194,7 +192,7 @@ func (h *QuestionHandler) GetQuestions(c *gin.Context) {
 // AnswerQuestions godoc
 //
 //	@Summary		Answer Questions
-//	@Description	Submit answers to multiple questions
+//	@Description	Submit answers to multiple questions. Each answer is checked against its question's type: answer_id must be an active option of a single choice question, answer_ids must pick between min_selections and max_selections active options of a multi-select question, text is required for free text questions, rating must lie between min_value and max_value and date must be YYYY-MM-DD. Questions hidden by their display conditions cannot be answered. Failures are reported per field, e.g. answers[0].answer_id, and nothing is stored unless every answer is valid.
 //	@Tags			Questions
 //	@Accept			json
 //	@Produce		json
@@ -217,13 +215,12 @@ func (h *QuestionHandler) AnswerQuestions(c *gin.Context) {
 
 	userEntity := user.(*entities.User)
 
+	// Bound without validation so field errors can name the answer they belong to
 	var answers []dtos.AnswerQuestionsRequestDTO
-	if err := c.ShouldBindJSON(&answers); err != nil {
-		validationErrors := utils.FormatValidationErrors(err)
+	if err := json.NewDecoder(c.Request.Body).Decode(&answers); err != nil {
 		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
 			Success: false,
-			Error:   "Validation failed",
-			Details: validationErrors,
+			Error:   errors.ErrInvalidRequestBody,
 		})
 		return
 	}
@@ -237,32 +234,37 @@ func (h *QuestionHandler) AnswerQuestions(c *gin.Context) {
 		return
 	}
 
-	// Validate each answer
-	for _, answer := range answers {
-		if answer.QuestionID == 0 {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Success: false,
-				Error:   "Question ID is required",
-			})
-			return
-		}
-		if answer.AnswerID == 0 {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Success: false,
-				Error:   "Answer ID is required",
-			})
-			return
+	fieldErrors := utils.FieldErrors{}
+	for i := range answers {
+		if err := binding.Validator.ValidateStruct(&answers[i]); err != nil {
+			for field, messages := range utils.FormatValidationErrors(err) {
+				for _, message := range messages {
+					fieldErrors.Add(fmt.Sprintf("answers[%d].%s", i, field), message)
+				}
+			}
 		}
 	}
+	if len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(fieldErrors),
+		})
+		return
+	}
 
 	err := h.userService.AnswerQuestions(c.Request.Context(), userEntity.ID, answers)
 	if err != nil {
 		var appErr *errors.AppError
 		if stderrors.As(err, &appErr) {
-			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+			response := dtos.ErrorResponse{
 				Success: false,
 				Error:   appErr.Message,
-			})
+			}
+			if details := utils.FormatValidationErrors(err); len(details) > 0 {
+				response.Details = details
+			}
+			c.JSON(appErr.StatusCode, response)
 			return
 		}
 		if err.Error() == "question not found" {
@@ -355,29 +357,18 @@ func (h *QuestionHandler) GetQuestionByID(c *gin.Context) {
 // CreateQuestions godoc
 //
 //	@Summary		Create Questions
-//	@Description	Create or update questions and options
-//	@Tags			Questions
+//	@Description	Admin endpoint to create or update questions and options in bulk. Each question is saved as a new version and published straight away within the optional publish_at/unpublish_at window, unless draft is set. Earlier answers keep pointing at the version they were given against. Requires API key authentication.
+//	@Tags			Admin
 //	@Accept			json
 //	@Produce		json
-//	@Security		Bearer
+//	@Security		APIKey
 //	@Param			request	body		dtos.CreateQuestionsRequestDTO		true	"Create Questions Request"
 //	@Success		200		{object}	dtos.SuccessResponse{data=string}	"Questions created successfully"
 //	@Failure		400		{object}	dtos.ErrorResponse					"Invalid request"
 //	@Failure		401		{object}	dtos.ErrorResponse					"Unauthorized"
 //	@Failure		500		{object}	dtos.ErrorResponse					"Failed to create questions"
-//	@Router			/profile/questions/create [post]
+//	@Router			/admin/questions/bulk [post]
 func (h *QuestionHandler) CreateQuestions(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{
-			Success: false,
-			Error:   "User not authenticated",
-		})
-		return
-	}
-
-	userEntity := user.(*entities.User)
-
 	var req dtos.CreateQuestionsRequestDTO
 	if err := c.ShouldBindJSON(&req); err != nil {
 		validationErrors := utils.FormatValidationErrors(err)
@@ -389,7 +380,7 @@ func (h *QuestionHandler) CreateQuestions(c *gin.Context) {
 		return
 	}
 
-	if err := h.questionService.CreateQuestions(c.Request.Context(), userEntity.ID, req); err != nil {
+	if err := h.questionService.CreateQuestions(c.Request.Context(), constants.SYSTEM_USER_ID, req); err != nil {
 		var appErr *errors.AppError
 		if stderrors.As(err, &appErr) {
 			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// ListQuestionSections godoc
+//
+//	@Summary		List question sections
+//	@Description	Admin endpoint to retrieve every questionnaire section, active or not, in display order. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Success		200	{object}	dtos.SuccessResponse{data=[]dtos.QuestionSectionResponseDTO}	"Question sections retrieved successfully"
+//	@Failure		401	{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500	{object}	dtos.ErrorResponse											"Failed to get question sections"
+//	@Router			/admin/question-sections [get]
+func (h *QuestionHandler) ListQuestionSections(c *gin.Context) {
+	sections, err := h.questionService.ListQuestionSections(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question sections")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    sections,
+	})
+}
+
+// CreateQuestionSection godoc
+//
+//	@Summary		Create a question section
+//	@Description	Admin endpoint to add a section to the profile questionnaire. Questions are placed in it through section_id when they are created or saved. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			request	body		dtos.QuestionSectionRequestDTO								true	"Section"
+//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.QuestionSectionResponseDTO}	"Question section created successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse											"Failed to create question section"
+//	@Router			/admin/question-sections [post]
+func (h *QuestionHandler) CreateQuestionSection(c *gin.Context) {
+	var req dtos.QuestionSectionRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	section, err := h.questionService.CreateQuestionSection(c.Request.Context(), req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to create question section")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.SuccessResponse{
+		Success: true,
+		Data:    section,
+	})
+}
+
+// UpdateQuestionSection godoc
+//
+//	@Summary		Update a question section
+//	@Description	Admin endpoint to rename, reorder or deactivate a questionnaire section. Questions in an inactive section are not shown to users. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			sectionId	path		int															true	"Section ID"
+//	@Param			request		body		dtos.UpdateQuestionSectionRequestDTO						true	"Section changes"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionSectionResponseDTO}	"Question section updated successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse											"Question section not found"
+//	@Failure		500			{object}	dtos.ErrorResponse											"Failed to update question section"
+//	@Router			/admin/question-sections/{sectionId} [patch]
+func (h *QuestionHandler) UpdateQuestionSection(c *gin.Context) {
+	sectionID, err := strconv.Atoi(c.Param("sectionId"))
+	if err != nil || sectionID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid section ID",
+		})
+		return
+	}
+
+	var req dtos.UpdateQuestionSectionRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	section, err := h.questionService.UpdateQuestionSection(c.Request.Context(), sectionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to update question section")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    section,
+	})
+}
+
+// SetQuestionConditions godoc
+//
+//	@Summary		Set question display conditions
+//	@Description	Admin endpoint to replace the conditions under which a question is shown. A question is shown only when every condition holds for the user's answer to the question it depends on. An empty list shows the question to everyone. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Param			request		body		dtos.SetQuestionConditionsRequestDTO					true	"Conditions"
+//	@Success		200			{object}	dtos.SuccessResponse{data=[]dtos.QuestionConditionDTO}	"Question conditions saved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to save question conditions"
+//	@Router			/admin/questions/{questionId}/conditions [put]
+func (h *QuestionHandler) SetQuestionConditions(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.SetQuestionConditionsRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	conditions, err := h.questionService.SetQuestionConditions(c.Request.Context(), questionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to save question conditions")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    conditions,
+	})
+}
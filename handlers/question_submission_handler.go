@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// GetMyQuestionSubmissions godoc
+//
+//	@Summary		List my question submissions
+//	@Description	Retrieve the questions the user suggested with their review status, newest first. Approved submissions carry the ID of the question created from them. Requires authentication.
+//	@Tags			Questions
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			limit	query		int															true	"Number of items per page"	minimum(1)	maximum(100)
+//	@Param			offset	query		int															false	"Number of items to skip"	minimum(0)	default(0)
+//	@Success		200		{object}	dtos.PaginatedResponse{data=[]dtos.QuestionSubmissionDTO}	"Question submissions retrieved successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse											"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse											"Failed to get question submissions"
+//	@Router			/questions/submissions [get]
+func (h *QuestionHandler) GetMyQuestionSubmissions(c *gin.Context) {
+	user, ok := profileUser(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.QuestionSubmissionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	items, total, err := h.questionService.GetUserQuestionSubmissions(c.Request.Context(), user.ID, req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question submissions")
+		return
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, dtos.PaginatedResponse{
+		Success: true,
+		Data:    items,
+		Meta: dtos.PaginationMeta{
+			Page:       (req.Offset / req.Limit) + 1,
+			PageSize:   req.Limit,
+			TotalPages: totalPages,
+			TotalCount: total,
+		},
+	})
+}
+
+// GetQuestionSubmissionQueue godoc
+//
+//	@Summary		Get question submission queue
+//	@Description	Admin endpoint to list questions suggested by users for review, oldest first. Lists pending submissions unless another status is given. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			status	query		string																false	"Review status (pending, approved, rejected)"
+//	@Param			limit	query		int																	true	"Number of items per page"	minimum(1)	maximum(100)
+//	@Param			offset	query		int																	false	"Number of items to skip"	minimum(0)	default(0)
+//	@Success		200		{object}	dtos.PaginatedResponse{data=[]dtos.QuestionSubmissionQueueItem}	"Question submission queue retrieved successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse													"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse													"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse													"Failed to get question submission queue"
+//	@Router			/admin/question-submissions [get]
+func (h *QuestionHandler) GetQuestionSubmissionQueue(c *gin.Context) {
+	var req dtos.QuestionSubmissionQueueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	items, total, err := h.questionService.GetQuestionSubmissionQueue(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question submission queue")
+		return
+	}
+
+	totalPages := int(total) / req.Limit
+	if int(total)%req.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, dtos.PaginatedResponse{
+		Success: true,
+		Data:    items,
+		Meta: dtos.PaginationMeta{
+			Page:       (req.Offset / req.Limit) + 1,
+			PageSize:   req.Limit,
+			TotalPages: totalPages,
+			TotalCount: total,
+		},
+	})
+}
+
+// ApproveQuestionSubmission godoc
+//
+//	@Summary		Approve a question submission
+//	@Description	Admin endpoint to promote a pending question submission into the question bank. A new question is created with a draft version, to be published through the question versions endpoints. The optional body reworks the suggestion: text and language default to the submitted ones, and the type defaults to text when no options are given. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			submissionId	path		int																	true	"Submission ID"
+//	@Param			request			body		dtos.CreateQuestionDTO												false	"Question content"
+//	@Success		200				{object}	dtos.SuccessResponse{data=dtos.ApproveQuestionSubmissionResponse}	"Question submission approved successfully"
+//	@Failure		400				{object}	dtos.ErrorResponse													"Validation failed"
+//	@Failure		401				{object}	dtos.ErrorResponse													"Unauthorized"
+//	@Failure		404				{object}	dtos.ErrorResponse													"Question submission not found"
+//	@Failure		409				{object}	dtos.ErrorResponse													"Question submission has already been reviewed"
+//	@Failure		500				{object}	dtos.ErrorResponse													"Failed to approve question submission"
+//	@Router			/admin/question-submissions/{submissionId}/approve [post]
+func (h *QuestionHandler) ApproveQuestionSubmission(c *gin.Context) {
+	submissionID, ok := submissionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateQuestionDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Success: false,
+				Error:   errors.ErrValidationFailed,
+				Details: utils.FormatValidationErrors(err),
+			})
+			return
+		}
+	}
+
+	response, err := h.questionService.ApproveQuestionSubmission(c.Request.Context(), submissionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to approve question submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Question submission approved successfully",
+	})
+}
+
+// RejectQuestionSubmission godoc
+//
+//	@Summary		Reject a question submission
+//	@Description	Admin endpoint to reject a pending question submission. The reason is shown to the user who suggested it. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			submissionId	path		int														true	"Submission ID"
+//	@Param			request			body		dtos.RejectQuestionSubmissionRequest					true	"Rejection reason"
+//	@Success		200				{object}	dtos.SuccessResponse{data=dtos.QuestionSubmissionDTO}	"Question submission rejected successfully"
+//	@Failure		400				{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401				{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404				{object}	dtos.ErrorResponse										"Question submission not found"
+//	@Failure		409				{object}	dtos.ErrorResponse										"Question submission has already been reviewed"
+//	@Failure		500				{object}	dtos.ErrorResponse										"Failed to reject question submission"
+//	@Router			/admin/question-submissions/{submissionId}/reject [post]
+func (h *QuestionHandler) RejectQuestionSubmission(c *gin.Context) {
+	submissionID, ok := submissionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.RejectQuestionSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	submission, err := h.questionService.RejectQuestionSubmission(c.Request.Context(), submissionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to reject question submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    submission,
+		Message: "Question submission rejected successfully",
+	})
+}
+
+func submissionIDParam(c *gin.Context) (int, bool) {
+	submissionID, err := strconv.Atoi(c.Param("submissionId"))
+	if err != nil || submissionID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid submission ID",
+		})
+		return 0, false
+	}
+	return submissionID, true
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// CreateQuestionDraft godoc
+//
+//	@Summary		Create a draft question
+//	@Description	Admin endpoint to create a question as a draft version 1. The question stays hidden from users until the version is published. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			request	body		dtos.CreateQuestionDTO								true	"Question content"
+//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.QuestionVersionDTO}	"Draft created successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse									"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse									"Failed to save question draft"
+//	@Router			/admin/questions [post]
+func (h *QuestionHandler) CreateQuestionDraft(c *gin.Context) {
+	h.saveQuestionDraft(c, 0, http.StatusCreated)
+}
+
+// SaveQuestionDraft godoc
+//
+//	@Summary		Save a question draft
+//	@Description	Admin endpoint to save changes to a question as its draft version, replacing any earlier draft. Users keep seeing the published version until the draft is published. Options are matched to the live options by id; options without an id are new. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int													true	"Question ID"
+//	@Param			request		body		dtos.CreateQuestionDTO								true	"Question content"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionDTO}	"Draft saved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse									"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse									"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse									"Failed to save question draft"
+//	@Router			/admin/questions/{questionId}/versions [post]
+func (h *QuestionHandler) SaveQuestionDraft(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+	h.saveQuestionDraft(c, questionID, http.StatusOK)
+}
+
+func (h *QuestionHandler) saveQuestionDraft(c *gin.Context, questionID int, status int) {
+	var req dtos.CreateQuestionDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	draft, err := h.questionService.SaveQuestionDraft(c.Request.Context(), questionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to save question draft")
+		return
+	}
+
+	c.JSON(status, dtos.SuccessResponse{
+		Success: true,
+		Data:    draft,
+	})
+}
+
+// GetQuestionVersions godoc
+//
+//	@Summary		List question versions
+//	@Description	Admin endpoint to retrieve a question's publishing state, publish window and every version with its options, newest first. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionsResponse}	"Question versions retrieved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Invalid question ID"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to get question versions"
+//	@Router			/admin/questions/{questionId}/versions [get]
+func (h *QuestionHandler) GetQuestionVersions(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.questionService.GetQuestionVersions(c.Request.Context(), questionID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    versions,
+	})
+}
+
+// PublishQuestionVersion godoc
+//
+//	@Summary		Publish a question version
+//	@Description	Admin endpoint to make a draft version the live content of its question. The previously published version is superseded; answers given to it keep pointing at it. The optional window sets when the question is shown; an empty body shows it straight away with no end. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Param			version		path		int														true	"Version number"
+//	@Param			request		body		dtos.QuestionScheduleDTO								false	"Publish window"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionsResponse}	"Question version published successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question version not found"
+//	@Failure		409			{object}	dtos.ErrorResponse										"Version is not a draft"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to publish question version"
+//	@Router			/admin/questions/{questionId}/versions/{version}/publish [post]
+func (h *QuestionHandler) PublishQuestionVersion(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid version",
+		})
+		return
+	}
+
+	var req dtos.QuestionScheduleDTO
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Success: false,
+				Error:   errors.ErrValidationFailed,
+				Details: utils.FormatValidationErrors(err),
+			})
+			return
+		}
+	}
+
+	versions, err := h.questionService.PublishQuestionVersion(c.Request.Context(), questionID, version, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to publish question version")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    versions,
+	})
+}
+
+// ScheduleQuestion godoc
+//
+//	@Summary		Schedule a question
+//	@Description	Admin endpoint to set the window in which a published question is shown, without changing its content. Empty bounds leave that side of the window open. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Param			request		body		dtos.QuestionScheduleDTO								true	"Publish window"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionsResponse}	"Question scheduled successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to schedule question"
+//	@Router			/admin/questions/{questionId}/schedule [put]
+func (h *QuestionHandler) ScheduleQuestion(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.QuestionScheduleDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	versions, err := h.questionService.ScheduleQuestion(c.Request.Context(), questionID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to schedule question")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    versions,
+	})
+}
+
+// UnpublishQuestion godoc
+//
+//	@Summary		Unpublish a question
+//	@Description	Admin endpoint to hide a question from users straight away. Its versions and answers are kept; publishing a new draft shows it again. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionsResponse}	"Question unpublished successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Invalid question ID"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to unpublish question"
+//	@Router			/admin/questions/{questionId}/unpublish [post]
+func (h *QuestionHandler) UnpublishQuestion(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.questionService.UnpublishQuestion(c.Request.Context(), questionID, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to unpublish question")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    versions,
+	})
+}
+
+// DiffQuestionVersions godoc
+//
+//	@Summary		Compare question versions
+//	@Description	Admin endpoint to list the field and option changes between two versions of a question. Without parameters the latest version is compared with the one before it. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Param			from		query		int														false	"Version to compare from (default: the version before to)"
+//	@Param			to			query		int														false	"Version to compare to (default: the latest version)"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionVersionDiffDTO}	"Question versions compared successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question version not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to compare question versions"
+//	@Router			/admin/questions/{questionId}/versions/diff [get]
+func (h *QuestionHandler) DiffQuestionVersions(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.QuestionVersionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	diff, err := h.questionService.DiffQuestionVersions(c.Request.Context(), questionID, req)
+	if err != nil {
+		respondServiceError(c, err, "Failed to compare question versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    diff,
+	})
+}
+
+func questionIDParam(c *gin.Context) (int, bool) {
+	questionID, err := strconv.Atoi(c.Param("questionId"))
+	if err != nil || questionID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid question ID",
+		})
+		return 0, false
+	}
+	return questionID, true
+}
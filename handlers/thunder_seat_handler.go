@@ -3,31 +3,40 @@ package handlers
 import (
 	stderrors "errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/middlewares"
 	"github.com/Infinite-Locus-Product/thums_up_backend/services"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
 type ThunderSeatHandler struct {
-	thunderSeatService services.ThunderSeatService
+	thunderSeatService  services.ThunderSeatService
+	filePolicy          *utils.FilePolicy
+	localizationService services.LocalizationService
 }
 
-func NewThunderSeatHandler(thunderSeatService services.ThunderSeatService) *ThunderSeatHandler {
+func NewThunderSeatHandler(thunderSeatService services.ThunderSeatService, filePolicy *utils.FilePolicy, localizationService services.LocalizationService) *ThunderSeatHandler {
 	return &ThunderSeatHandler{
-		thunderSeatService: thunderSeatService,
+		thunderSeatService:  thunderSeatService,
+		filePolicy:          filePolicy,
+		localizationService: localizationService,
 	}
 }
 
 // SubmitAnswer godoc
 //
 //	@Summary		Submit Thunder Seat answer
-//	@Description	Submit an answer to a Thunder Seat question for the current week with optional media file (image/audio/video). Requires authentication.
+//	@Description	Submit an answer to a Thunder Seat question for the current week with optional media file (image/audio/video). The week's submission rules (media requirement, allowed media kinds, max answer length, max submissions and sharing fields) are enforced. Requires authentication.
 //	@Tags			Thunder Seat
 //	@Accept			multipart/form-data
 //	@Produce		json
@@ -36,9 +45,11 @@ func NewThunderSeatHandler(thunderSeatService services.ThunderSeatService) *Thun
 //	@Param			social_media	formData	string												false	"Sharing platform (instagram, snapchat, facebook, twitter, tiktok, youtube)"
 //	@Param			user_name	formData	string												false	"Platform user name (min 3, max 255 characters)"
 //	@Param			media_file	formData	file												false	"Optional media file (image max 10MB, video max 10MB, audio max 100MB)"
+//	@Param			Idempotency-Key	header	string												false	"Client generated key; retries with the same key return the original submission"
 //	@Success		201			{object}	dtos.SuccessResponse{data=dtos.ThunderSeatResponse}	"Answer submitted successfully"
 //	@Failure		400			{object}	dtos.ErrorResponse									"Validation failed"
 //	@Failure		401			{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		409			{object}	dtos.ErrorResponse									"Already submitted for this contest week"
 //	@Failure		500			{object}	dtos.ErrorResponse									"Failed to submit answer"
 //	@Router			/thunder-seat [post]
 func (h *ThunderSeatHandler) SubmitAnswer(c *gin.Context) {
@@ -71,6 +82,15 @@ func (h *ThunderSeatHandler) SubmitAnswer(c *gin.Context) {
 		return
 	}
 
+	idempotencyKey := strings.TrimSpace(c.GetHeader(constants.IDEMPOTENCY_KEY_HEADER))
+	if len(idempotencyKey) > constants.MAX_IDEMPOTENCY_KEY_LENGTH {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Idempotency-Key must not exceed 255 characters",
+		})
+		return
+	}
+
 	mediaFile, err := c.FormFile("media_file")
 	if err != nil && err != http.ErrMissingFile {
 		log.WithError(err).Error("Failed to get media file from request")
@@ -81,17 +101,16 @@ func (h *ThunderSeatHandler) SubmitAnswer(c *gin.Context) {
 		return
 	}
 
+	var validatedFile *utils.ValidatedFile
 	if mediaFile != nil {
-		if err := utils.ValidateMediaFile(mediaFile); err != nil {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Success: false,
-				Error:   err.Error(),
-			})
+		validatedFile, err = h.filePolicy.ValidateMedia(mediaFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, ""))
 			return
 		}
 	}
 
-	response, err := h.thunderSeatService.SubmitAnswer(c.Request.Context(), req, userID, mediaFile)
+	response, err := h.thunderSeatService.SubmitAnswer(c.Request.Context(), req, userID, validatedFile, idempotencyKey)
 	if err != nil {
 		var appErr *errors.AppError
 		if stderrors.As(err, &appErr) {
@@ -174,16 +193,28 @@ func (h *ThunderSeatHandler) GetUserSubmissions(c *gin.Context) {
 // GetCurrentWeek godoc
 //
 //	@Summary		Get current contest week information
-//	@Description	Retrieve the current active contest week details including dates and winner count
+//	@Description	Retrieve the current active contest week details including dates, winner count and the week's submission rules with the prompt in the requested language
 //	@Tags			Thunder Seat
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	dtos.SuccessResponse{data=dtos.CurrentWeekResponse}	"Current week retrieved successfully"
-//	@Failure		404	{object}	dtos.ErrorResponse									"No active contest week"
-//	@Failure		500	{object}	dtos.ErrorResponse									"Failed to get active contest week"
+//	@Param			language_id		query		int													false	"Language ID for the prompt (defaults to Accept-Language, then 1)"
+//	@Param			Accept-Language	header		string												false	"Preferred languages"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.CurrentWeekResponse}	"Current week retrieved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse									"Invalid language_id"
+//	@Failure		404			{object}	dtos.ErrorResponse									"No active contest week"
+//	@Failure		500			{object}	dtos.ErrorResponse									"Failed to get active contest week"
 //	@Router			/thunder-seat/current-week [get]
 func (h *ThunderSeatHandler) GetCurrentWeek(c *gin.Context) {
-	response, err := h.thunderSeatService.GetCurrentWeek(c.Request.Context())
+	languageID, ok := middlewares.RequestLanguageID(c, h.localizationService)
+	if !ok {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid language_id",
+		})
+		return
+	}
+
+	response, err := h.thunderSeatService.GetCurrentWeek(c.Request.Context(), languageID)
 	if err != nil {
 		var appErr *errors.AppError
 		if stderrors.As(err, &appErr) {
@@ -206,3 +237,308 @@ func (h *ThunderSeatHandler) GetCurrentWeek(c *gin.Context) {
 		Data:    response,
 	})
 }
+
+// UpdateSubmission godoc
+//
+//	@Summary		Edit a Thunder Seat submission
+//	@Description	Edit the answer text and/or replace the media file of the authenticated user's submission. Only allowed while the submission's contest week is open. The previous version is kept in the revision history. Requires authentication.
+//	@Tags			Thunder Seat
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id			path		int													true	"Submission ID"
+//	@Param			description	formData	string												false	"New answer text"
+//	@Param			media_file	formData	file												false	"Replacement media file (image max 10MB, video max 10MB, audio max 100MB)"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.ThunderSeatResponse}	"Submission updated successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse									"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		403			{object}	dtos.ErrorResponse									"Contest week is closed"
+//	@Failure		404			{object}	dtos.ErrorResponse									"Submission not found"
+//	@Failure		500			{object}	dtos.ErrorResponse									"Failed to update submission"
+//	@Router			/thunder-seat/{id} [put]
+func (h *ThunderSeatHandler) UpdateSubmission(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid submission ID",
+		})
+		return
+	}
+
+	var req dtos.ThunderSeatUpdateRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	mediaFile, err := c.FormFile("media_file")
+	if err != nil && err != http.ErrMissingFile {
+		log.WithError(err).Error("Failed to get media file from request")
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to process media file",
+		})
+		return
+	}
+
+	var validatedFile *utils.ValidatedFile
+	if mediaFile != nil {
+		validatedFile, err = h.filePolicy.ValidateMedia(mediaFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, ""))
+			return
+		}
+	}
+
+	response, err := h.thunderSeatService.UpdateSubmission(c.Request.Context(), submissionID, userEntity.ID, req, validatedFile)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to update thunder seat submission")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to update submission",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Submission updated successfully",
+	})
+}
+
+// WithdrawSubmission godoc
+//
+//	@Summary		Withdraw a Thunder Seat submission
+//	@Description	Withdraw the authenticated user's submission so it is no longer eligible for winner selection. Only allowed while the submission's contest week is open. Requires authentication.
+//	@Tags			Thunder Seat
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int						true	"Submission ID"
+//	@Success		200	{object}	dtos.SuccessResponse	"Submission withdrawn successfully"
+//	@Failure		400	{object}	dtos.ErrorResponse		"Invalid submission ID"
+//	@Failure		401	{object}	dtos.ErrorResponse		"Unauthorized"
+//	@Failure		403	{object}	dtos.ErrorResponse		"Contest week is closed"
+//	@Failure		404	{object}	dtos.ErrorResponse		"Submission not found"
+//	@Failure		500	{object}	dtos.ErrorResponse		"Failed to withdraw submission"
+//	@Router			/thunder-seat/{id} [delete]
+func (h *ThunderSeatHandler) WithdrawSubmission(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	submissionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid submission ID",
+		})
+		return
+	}
+
+	if err := h.thunderSeatService.WithdrawSubmission(c.Request.Context(), submissionID, userEntity.ID); err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to withdraw thunder seat submission")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to withdraw submission",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Message: "Submission withdrawn successfully",
+	})
+}
+
+// CreateUploadSession godoc
+//
+//	@Summary		Start a direct media upload
+//	@Description	Create an upload session and get a signed URL to upload a media file directly to storage instead of through the API. Send the returned method and headers to upload_url; for resumable uploads the response Location header is the session URI to upload the bytes to. Set submission_id to replace the media of an existing submission. Finalize the session once the upload completes. Requires authentication.
+//	@Tags			Thunder Seat
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		dtos.UploadSessionRequest								true	"Declared content type, size in bytes and optional base64 MD5"
+//	@Success		201		{object}	dtos.SuccessResponse{data=dtos.UploadSessionResponse}	"Upload session created"
+//	@Failure		400		{object}	dtos.ErrorResponse										"Validation failed"
+//	@Failure		401		{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		403		{object}	dtos.ErrorResponse										"Contest week is closed"
+//	@Failure		409		{object}	dtos.ErrorResponse										"Already submitted for this contest week"
+//	@Failure		500		{object}	dtos.ErrorResponse										"Failed to start upload"
+//	@Router			/thunder-seat/uploads [post]
+func (h *ThunderSeatHandler) CreateUploadSession(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	var req dtos.UploadSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return
+	}
+
+	response, err := h.thunderSeatService.CreateUploadSession(c.Request.Context(), userEntity.ID, req)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to create upload session")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to start upload",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Upload session created successfully",
+	})
+}
+
+// FinalizeUploadSession godoc
+//
+//	@Summary		Finalize a direct media upload
+//	@Description	Verify the uploaded file's size, content type and checksum and attach it to a new submission, or to the submission the session was created for. The week's submission rules are enforced. Retrying a finalized session returns the same submission. Requires authentication.
+//	@Tags			Thunder Seat
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			sessionId		path		string												true	"Upload session ID"
+//	@Param			request			body		dtos.FinalizeUploadRequest							false	"Answer text and sharing fields"
+//	@Param			Idempotency-Key	header		string												false	"Client generated key; retries with the same key return the original submission"
+//	@Success		200				{object}	dtos.SuccessResponse{data=dtos.ThunderSeatResponse}	"Upload finalized"
+//	@Failure		400				{object}	dtos.ErrorResponse									"File missing or failed verification"
+//	@Failure		401				{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		404				{object}	dtos.ErrorResponse									"Upload session not found"
+//	@Failure		409				{object}	dtos.ErrorResponse									"Already finalized or already submitted"
+//	@Failure		500				{object}	dtos.ErrorResponse									"Failed to finalize upload"
+//	@Router			/thunder-seat/uploads/{sessionId}/finalize [post]
+func (h *ThunderSeatHandler) FinalizeUploadSession(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, dtos.ErrorResponse{Success: false, Error: errors.ErrUserNotAuthenticated})
+		return
+	}
+
+	userEntity, ok := user.(*entities.User)
+	if !ok || userEntity == nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{Success: false, Error: errors.ErrInvalidUserContext})
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid upload session ID",
+		})
+		return
+	}
+
+	var req dtos.FinalizeUploadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Success: false,
+				Error:   errors.ErrValidationFailed,
+				Details: utils.FormatValidationErrors(err),
+			})
+			return
+		}
+	}
+
+	idempotencyKey := strings.TrimSpace(c.GetHeader(constants.IDEMPOTENCY_KEY_HEADER))
+	if len(idempotencyKey) > constants.MAX_IDEMPOTENCY_KEY_LENGTH {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Idempotency-Key must not exceed 255 characters",
+		})
+		return
+	}
+
+	response, err := h.thunderSeatService.FinalizeUploadSession(c.Request.Context(), sessionID, userEntity.ID, req, idempotencyKey)
+	if err != nil {
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			c.JSON(appErr.StatusCode, dtos.ErrorResponse{
+				Success: false,
+				Error:   appErr.Message,
+			})
+			return
+		}
+		log.WithError(err).Error("Failed to finalize upload session")
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Failed to finalize upload",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+		Message: "Upload finalized successfully",
+	})
+}
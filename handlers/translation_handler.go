@@ -0,0 +1,435 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/i18n"
+	"github.com/Infinite-Locus-Product/thums_up_backend/services"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// Formats translation sheets are exported and imported in
+const (
+	sheetFormatCSV  = "csv"
+	sheetFormatJSON = "json"
+)
+
+type TranslationHandler struct {
+	translationService services.TranslationService
+}
+
+func NewTranslationHandler(translationService services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// GetQuestionTranslations godoc
+//
+//	@Summary		Get question translations
+//	@Description	Admin endpoint to retrieve a question and its options with every translation they have. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int														true	"Question ID"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.QuestionTranslationsDTO}	"Question translations retrieved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse										"Invalid question ID"
+//	@Failure		401			{object}	dtos.ErrorResponse										"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse										"Question not found"
+//	@Failure		500			{object}	dtos.ErrorResponse										"Failed to get question translations"
+//	@Router			/admin/questions/{questionId}/translations [get]
+func (h *TranslationHandler) GetQuestionTranslations(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+
+	translations, err := h.translationService.GetQuestionTranslations(c.Request.Context(), questionID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get question translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    translations,
+	})
+}
+
+// SetQuestionTranslation godoc
+//
+//	@Summary		Set a question translation
+//	@Description	Admin endpoint to create or replace the text of a question in a language other than its own. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int												true	"Question ID"
+//	@Param			languageId	path		int												true	"Language ID"
+//	@Param			request		body		dtos.SetTranslationRequestDTO					true	"Translation"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.TranslationDTO}	"Question translation saved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse								"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse								"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse								"Question or language not found"
+//	@Failure		500			{object}	dtos.ErrorResponse								"Failed to save question translation"
+//	@Router			/admin/questions/{questionId}/translations/{languageId} [put]
+func (h *TranslationHandler) SetQuestionTranslation(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+	languageID, ok := languageIDParam(c)
+	if !ok {
+		return
+	}
+	req, ok := bindTranslation(c)
+	if !ok {
+		return
+	}
+
+	translation, err := h.translationService.SetQuestionTranslation(c.Request.Context(), questionID, languageID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to save question translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    translation,
+	})
+}
+
+// DeleteQuestionTranslation godoc
+//
+//	@Summary		Delete a question translation
+//	@Description	Admin endpoint to remove the text of a question in a language. Users of that language see the question in the default language again. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			questionId	path		int					true	"Question ID"
+//	@Param			languageId	path		int					true	"Language ID"
+//	@Success		200			{object}	dtos.SuccessResponse	"Question translation deleted successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse		"Invalid question or language ID"
+//	@Failure		401			{object}	dtos.ErrorResponse		"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse		"Translation not found"
+//	@Failure		500			{object}	dtos.ErrorResponse		"Failed to delete question translation"
+//	@Router			/admin/questions/{questionId}/translations/{languageId} [delete]
+func (h *TranslationHandler) DeleteQuestionTranslation(c *gin.Context) {
+	questionID, ok := questionIDParam(c)
+	if !ok {
+		return
+	}
+	languageID, ok := languageIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteQuestionTranslation(c.Request.Context(), questionID, languageID, constants.SYSTEM_USER_ID); err != nil {
+		respondServiceError(c, err, "Failed to delete question translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    "Question translation deleted successfully",
+	})
+}
+
+// SetOptionTranslation godoc
+//
+//	@Summary		Set an option translation
+//	@Description	Admin endpoint to create or replace the text of an option in a language other than its question's. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			optionId	path		int												true	"Option ID"
+//	@Param			languageId	path		int												true	"Language ID"
+//	@Param			request		body		dtos.SetTranslationRequestDTO					true	"Translation"
+//	@Success		200			{object}	dtos.SuccessResponse{data=dtos.TranslationDTO}	"Option translation saved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse								"Validation failed"
+//	@Failure		401			{object}	dtos.ErrorResponse								"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse								"Option or language not found"
+//	@Failure		500			{object}	dtos.ErrorResponse								"Failed to save option translation"
+//	@Router			/admin/options/{optionId}/translations/{languageId} [put]
+func (h *TranslationHandler) SetOptionTranslation(c *gin.Context) {
+	optionID, ok := optionIDParam(c)
+	if !ok {
+		return
+	}
+	languageID, ok := languageIDParam(c)
+	if !ok {
+		return
+	}
+	req, ok := bindTranslation(c)
+	if !ok {
+		return
+	}
+
+	translation, err := h.translationService.SetOptionTranslation(c.Request.Context(), optionID, languageID, req, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to save option translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    translation,
+	})
+}
+
+// DeleteOptionTranslation godoc
+//
+//	@Summary		Delete an option translation
+//	@Description	Admin endpoint to remove the text of an option in a language. Users of that language see the option in the default language again. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			optionId	path		int					true	"Option ID"
+//	@Param			languageId	path		int					true	"Language ID"
+//	@Success		200			{object}	dtos.SuccessResponse	"Option translation deleted successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse		"Invalid option or language ID"
+//	@Failure		401			{object}	dtos.ErrorResponse		"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse		"Translation not found"
+//	@Failure		500			{object}	dtos.ErrorResponse		"Failed to delete option translation"
+//	@Router			/admin/options/{optionId}/translations/{languageId} [delete]
+func (h *TranslationHandler) DeleteOptionTranslation(c *gin.Context) {
+	optionID, ok := optionIDParam(c)
+	if !ok {
+		return
+	}
+	languageID, ok := languageIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteOptionTranslation(c.Request.Context(), optionID, languageID, constants.SYSTEM_USER_ID); err != nil {
+		respondServiceError(c, err, "Failed to delete option translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    "Option translation deleted successfully",
+	})
+}
+
+// GetTranslationCoverage godoc
+//
+//	@Summary		Get translation coverage
+//	@Description	Admin endpoint to report, per language, how many active questions and options are translated and which are missing. Content is only counted against languages other than its own. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			language_id	query		int															false	"Only report this language"
+//	@Success		200			{object}	dtos.SuccessResponse{data=[]dtos.TranslationCoverageDTO}	"Translation coverage retrieved successfully"
+//	@Failure		400			{object}	dtos.ErrorResponse											"Invalid language ID"
+//	@Failure		401			{object}	dtos.ErrorResponse											"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse											"Language not found"
+//	@Failure		500			{object}	dtos.ErrorResponse											"Failed to get translation coverage"
+//	@Router			/admin/translations/coverage [get]
+func (h *TranslationHandler) GetTranslationCoverage(c *gin.Context) {
+	languageID := 0
+	if languageIDStr, ok := c.GetQuery("language_id"); ok {
+		parsed, err := strconv.Atoi(languageIDStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+				Success: false,
+				Error:   "Invalid language ID",
+			})
+			return
+		}
+		languageID = parsed
+	}
+
+	coverage, err := h.translationService.GetCoverage(c.Request.Context(), languageID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to get translation coverage")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    coverage,
+	})
+}
+
+// ExportTranslations godoc
+//
+//	@Summary		Export translations
+//	@Description	Admin endpoint to download a translation sheet for a language: a row for every active question and option not written in it, with its current translation or an empty text. The sheet can be filled in and imported again. Requires API key authentication.
+//	@Tags			Admin
+//	@Produce		text/csv
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			language_id	query		int					true	"Language to translate into"
+//	@Param			format		query		string				false	"Sheet format"	Enums(csv, json)	default(csv)
+//	@Success		200			{array}		i18n.Row			"Translation sheet"
+//	@Failure		400			{object}	dtos.ErrorResponse	"Invalid language ID or format"
+//	@Failure		401			{object}	dtos.ErrorResponse	"Unauthorized"
+//	@Failure		404			{object}	dtos.ErrorResponse	"Language not found"
+//	@Failure		500			{object}	dtos.ErrorResponse	"Failed to export translations"
+//	@Router			/admin/translations/export [get]
+func (h *TranslationHandler) ExportTranslations(c *gin.Context) {
+	languageID, err := strconv.Atoi(c.Query("language_id"))
+	if err != nil || languageID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid language ID",
+		})
+		return
+	}
+	format := strings.ToLower(c.DefaultQuery("format", sheetFormatCSV))
+	if format != sheetFormatCSV && format != sheetFormatJSON {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "format must be csv or json",
+		})
+		return
+	}
+
+	rows, err := h.translationService.ExportTranslations(c.Request.Context(), languageID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to export translations")
+		return
+	}
+
+	filename := fmt.Sprintf("translations-%d.%s", languageID, format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == sheetFormatJSON {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	var sheet bytes.Buffer
+	if err := i18n.WriteCSV(&sheet, rows); err != nil {
+		respondServiceError(c, err, "Failed to export translations")
+		return
+	}
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", sheet.Bytes())
+}
+
+// ImportTranslations godoc
+//
+//	@Summary		Import translations
+//	@Description	Admin endpoint to upload a filled-in translation sheet, as exported, in CSV or JSON. The format is taken from the format parameter or the file extension. Rows with an empty text are skipped. The sheet is saved as a whole: when any row is invalid nothing is saved and details lists the offending rows, counted from 0 after the header. Requires API key authentication.
+//	@Tags			Admin
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		APIKey
+//	@Param			file	formData	file												true	"Translation sheet"
+//	@Param			format	query		string												false	"Sheet format"	Enums(csv, json)
+//	@Success		200		{object}	dtos.SuccessResponse{data=dtos.TranslationImportResponse}	"Translations imported successfully"
+//	@Failure		400		{object}	dtos.ErrorResponse									"Invalid sheet"
+//	@Failure		401		{object}	dtos.ErrorResponse									"Unauthorized"
+//	@Failure		500		{object}	dtos.ErrorResponse									"Failed to import translations"
+//	@Router			/admin/translations/import [post]
+func (h *TranslationHandler) ImportTranslations(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "file is required",
+		})
+		return
+	}
+	if file.Size > constants.TRANSLATION_IMPORT_MAX_SIZE {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   fmt.Sprintf("file must be at most %d MB", constants.TRANSLATION_IMPORT_MAX_SIZE>>20),
+		})
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	}
+	if format != sheetFormatCSV && format != sheetFormatJSON {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "format must be csv or json",
+		})
+		return
+	}
+
+	sheet, err := file.Open()
+	if err != nil {
+		respondServiceError(c, err, "Failed to import translations")
+		return
+	}
+	defer sheet.Close()
+
+	var rows []i18n.Row
+	if format == sheetFormatJSON {
+		err = json.NewDecoder(sheet).Decode(&rows)
+	} else {
+		rows, err = i18n.ReadCSV(sheet)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid sheet: " + err.Error(),
+		})
+		return
+	}
+
+	response, err := h.translationService.ImportTranslations(c.Request.Context(), rows, constants.SYSTEM_USER_ID)
+	if err != nil {
+		respondServiceError(c, err, "Failed to import translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.SuccessResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+func languageIDParam(c *gin.Context) (int, bool) {
+	languageID, err := strconv.Atoi(c.Param("languageId"))
+	if err != nil || languageID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid language ID",
+		})
+		return 0, false
+	}
+	return languageID, true
+}
+
+func optionIDParam(c *gin.Context) (int, bool) {
+	optionID, err := strconv.Atoi(c.Param("optionId"))
+	if err != nil || optionID <= 0 {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   "Invalid option ID",
+		})
+		return 0, false
+	}
+	return optionID, true
+}
+
+func bindTranslation(c *gin.Context) (dtos.SetTranslationRequestDTO, bool) {
+	var req dtos.SetTranslationRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Success: false,
+			Error:   errors.ErrValidationFailed,
+			Details: utils.FormatValidationErrors(err),
+		})
+		return req, false
+	}
+	return req, true
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	stderrors "errors"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +19,14 @@ import (
 type WinnerHandler struct {
 	winnerService services.WinnerService
 	gcsService    utils.GCSService
+	filePolicy    *utils.FilePolicy
 }
 
-func NewWinnerHandler(winnerService services.WinnerService, gcsService utils.GCSService) *WinnerHandler {
+func NewWinnerHandler(winnerService services.WinnerService, gcsService utils.GCSService, filePolicy *utils.FilePolicy) *WinnerHandler {
 	return &WinnerHandler{
 		winnerService: winnerService,
 		gcsService:    gcsService,
+		filePolicy:    filePolicy,
 	}
 }
 
@@ -237,8 +240,10 @@ func (h *WinnerHandler) SubmitWinnerKYC(c *gin.Context) {
 		return
 	}
 
-	var aadharFrontURL *string
-	var aadharBackURL *string
+	// KYC images are private, so the object paths are stored rather than URLs
+	const aadharFolder = "winners/kyc/aadhar"
+	var aadharFrontPath *string
+	var aadharBackPath *string
 	var aadharNumber *string
 
 	aadharNumberStr := c.PostForm("aadhar_number")
@@ -248,15 +253,14 @@ func (h *WinnerHandler) SubmitWinnerKYC(c *gin.Context) {
 
 	aadharFrontFile, err := c.FormFile("aadhar_front")
 	if err == nil && aadharFrontFile != nil {
-		if err := utils.ValidateImageFile(aadharFrontFile); err != nil {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Success: false,
-				Error:   "Invalid aadhar_front file: " + err.Error(),
-			})
+		// Validation strips GPS metadata from the photo before it is stored
+		validatedFront, err := h.filePolicy.ValidateImage(aadharFrontFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, "aadhar_front"))
 			return
 		}
 
-		url, _, err := h.gcsService.UploadFile(c.Request.Context(), aadharFrontFile, "winners/kyc/aadhar")
+		_, filename, err := utils.UploadValidatedFile(c.Request.Context(), h.gcsService, validatedFront, aadharFolder)
 		if err != nil {
 			log.WithError(err).Error("Failed to upload aadhar front")
 			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
@@ -265,20 +269,20 @@ func (h *WinnerHandler) SubmitWinnerKYC(c *gin.Context) {
 			})
 			return
 		}
-		aadharFrontURL = &url
+		aadharFrontKey := path.Join(aadharFolder, filename)
+		aadharFrontPath = &aadharFrontKey
 	}
 
 	aadharBackFile, err := c.FormFile("aadhar_back")
 	if err == nil && aadharBackFile != nil {
-		if err := utils.ValidateImageFile(aadharBackFile); err != nil {
-			c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
-				Success: false,
-				Error:   "Invalid aadhar_back file: " + err.Error(),
-			})
+		// Validation strips GPS metadata from the photo before it is stored
+		validatedBack, err := h.filePolicy.ValidateImage(aadharBackFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, fileValidationErrorResponse(err, "aadhar_back"))
 			return
 		}
 
-		url, _, err := h.gcsService.UploadFile(c.Request.Context(), aadharBackFile, "winners/kyc/aadhar")
+		_, filename, err := utils.UploadValidatedFile(c.Request.Context(), h.gcsService, validatedBack, aadharFolder)
 		if err != nil {
 			log.WithError(err).Error("Failed to upload aadhar back")
 			c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
@@ -287,7 +291,8 @@ func (h *WinnerHandler) SubmitWinnerKYC(c *gin.Context) {
 			})
 			return
 		}
-		aadharBackURL = &url
+		aadharBackKey := path.Join(aadharFolder, filename)
+		aadharBackPath = &aadharBackKey
 	}
 
 	city1 := c.PostForm("city1")
@@ -298,8 +303,8 @@ func (h *WinnerHandler) SubmitWinnerKYC(c *gin.Context) {
 		UserName:     userName,
 		UserEmail:    userEmail,
 		AadharNumber: aadharNumber,
-		AadharFront:  aadharFrontURL,
-		AadharBack:   aadharBackURL,
+		AadharFront:  aadharFrontPath,
+		AadharBack:   aadharBackPath,
 		City1:        city1,
 		City2:        city2,
 		City3:        city3,
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+// Localizer translates messages into the language a request is served in
+type Localizer interface {
+	ResolveLanguage(ctx context.Context, requestedID int, user *entities.User, acceptLanguage string) int
+	Translate(ctx context.Context, languageID int, key string) string
+}
+
+// RequestLanguageID resolves the language of a request from its language_id query
+// parameter, the authenticated user's preference and the Accept-Language header.
+// It returns false when language_id is present but not a positive integer.
+func RequestLanguageID(c *gin.Context, localizer Localizer) (int, bool) {
+	requestedID := 0
+	if languageIDStr, ok := c.GetQuery("language_id"); ok {
+		parsed, err := strconv.Atoi(languageIDStr)
+		if err != nil || parsed < 1 {
+			return 0, false
+		}
+		requestedID = parsed
+	}
+
+	var user *entities.User
+	if value, ok := c.Get("user"); ok {
+		user, _ = value.(*entities.User)
+	}
+	return localizer.ResolveLanguage(c.Request.Context(), requestedID, user, c.GetHeader("Accept-Language")), true
+}
+
+// LocalizeErrors translates the error message of JSON error responses into the
+// caller's language. Other responses are passed through untouched.
+func LocalizeErrors(localizer Localizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &errorBodyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		if writer.body.Len() == 0 {
+			return
+		}
+		body := writer.body.Bytes()
+		if languageID, ok := RequestLanguageID(c, localizer); ok {
+			body = translateErrorBody(c.Request.Context(), localizer, languageID, body)
+		}
+		writer.ResponseWriter.Write(body)
+	}
+}
+
+// translateErrorBody replaces the "error" field of a JSON body with its
+// translation, returning the body unchanged when it has no such field
+func translateErrorBody(ctx context.Context, localizer Localizer, languageID int, body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+	var message string
+	if err := json.Unmarshal(fields["error"], &message); err != nil || message == "" {
+		return body
+	}
+
+	translated, err := json.Marshal(localizer.Translate(ctx, languageID, message))
+	if err != nil {
+		return body
+	}
+	fields["error"] = translated
+	localized, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return localized
+}
+
+// errorBodyWriter holds back JSON bodies of error responses so they can be
+// translated once the handler is done
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *errorBodyWriter) buffers() bool {
+	return w.Status() >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (w *errorBodyWriter) Write(data []byte) (int, error) {
+	if w.buffers() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	if w.buffers() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,14 @@
+-- Migration: Add timeline fields to contest_week table
+-- Created: 2026-10-18
+-- Description: Adds optional draw and results timestamps used by the contest timeline endpoint
+
+-- When winners are drawn for the week; falls back to end_date + 24h when NULL
+ALTER TABLE contest_week
+ADD COLUMN IF NOT EXISTS draw_date TIMESTAMPTZ;
+
+-- When results are announced for the week; falls back to end_date + 48h when NULL
+ALTER TABLE contest_week
+ADD COLUMN IF NOT EXISTS results_date TIMESTAMPTZ;
+
+COMMENT ON COLUMN contest_week.draw_date IS 'Scheduled winner draw time for the week';
+COMMENT ON COLUMN contest_week.results_date IS 'Scheduled results announcement time for the week';
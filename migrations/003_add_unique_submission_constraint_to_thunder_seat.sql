@@ -0,0 +1,31 @@
+-- Migration: Enforce unique Thunder Seat submissions per user and week
+-- Created: 2026-10-18
+-- Description: Adds a per-user submission sequence and idempotency key to thunder_seat
+-- and backs them with unique indexes so duplicate submissions are rejected by the database
+
+-- Sequence of the submission within the user's week (1 for the first submission)
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS submission_seq INTEGER NOT NULL DEFAULT 1;
+
+-- Client supplied Idempotency-Key of the submit request
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+
+-- Number any pre-existing duplicates so the unique index can be created
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, week_number ORDER BY created_on, id) AS seq
+    FROM thunder_seat
+)
+UPDATE thunder_seat t
+SET submission_seq = ranked.seq
+FROM ranked
+WHERE t.id = ranked.id AND t.submission_seq <> ranked.seq;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_thunder_seat_user_week_seq
+ON thunder_seat(user_id, week_number, submission_seq);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_thunder_seat_user_idempotency_key
+ON thunder_seat(user_id, idempotency_key);
+
+COMMENT ON COLUMN thunder_seat.submission_seq IS 'Sequence of the submission within the user''s contest week';
+COMMENT ON COLUMN thunder_seat.idempotency_key IS 'Idempotency-Key header sent with the submit request';
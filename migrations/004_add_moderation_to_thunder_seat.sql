@@ -0,0 +1,29 @@
+-- Migration: Add moderation to thunder_seat table
+-- Created: 2026-10-18
+-- Description: Adds a moderation status to Thunder Seat submissions. Submissions that
+-- existed before moderation was introduced are approved so they stay in the draw pool.
+
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved';
+
+-- New submissions start in the moderation queue
+ALTER TABLE thunder_seat
+ALTER COLUMN moderation_status SET DEFAULT 'pending';
+
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS moderated_by VARCHAR(255);
+
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS moderated_on TIMESTAMPTZ;
+
+ALTER TABLE thunder_seat
+ADD COLUMN IF NOT EXISTS report_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_thunder_seat_moderation_status ON thunder_seat(moderation_status);
+
+COMMENT ON COLUMN thunder_seat.moderation_status IS 'Moderation status: pending, approved, rejected or flagged';
+COMMENT ON COLUMN thunder_seat.moderation_reason IS 'Reason given by the moderator for the latest decision';
+COMMENT ON COLUMN thunder_seat.report_count IS 'Number of user reports against the submission';
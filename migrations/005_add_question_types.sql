@@ -0,0 +1,37 @@
+-- Migration: Add question types and typed answers
+-- Created: 2026-10-18
+-- Description: Profile questions can now be multi-select, free text, rating or date
+-- questions. Existing questions stay single choice. Answers to non-choice questions
+-- have no option, so option_id becomes nullable.
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS question_type VARCHAR(20) NOT NULL DEFAULT 'single_choice';
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS min_selections INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS max_selections INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS min_value INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS max_value INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS max_length INTEGER;
+
+ALTER TABLE user_question_answer
+ALTER COLUMN option_id DROP NOT NULL;
+
+ALTER TABLE user_question_answer
+ADD COLUMN IF NOT EXISTS text_answer TEXT;
+
+ALTER TABLE user_question_answer
+ADD COLUMN IF NOT EXISTS rating_answer INTEGER;
+
+ALTER TABLE user_question_answer
+ADD COLUMN IF NOT EXISTS date_answer DATE;
+
+COMMENT ON COLUMN question_master.question_type IS 'Question type: single_choice, multi_select, text, rating or date';
@@ -0,0 +1,12 @@
+-- Migration: Add login streak to login counts
+-- Created: 2026-10-18
+-- Description: Tracks consecutive login days so completed streaks can be credited
+-- to the points ledger. Existing users start without a streak.
+
+ALTER TABLE login_counts
+ADD COLUMN IF NOT EXISTS streak_days INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE login_counts
+ADD COLUMN IF NOT EXISTS streak_date DATE;
+
+COMMENT ON COLUMN login_counts.streak_days IS 'Consecutive days with a login up to streak_date';
@@ -0,0 +1,7 @@
+-- Migration: Add leaderboard opt-out to users
+-- Created: 2026-10-18
+-- Description: Users can hide themselves from leaderboards. Everyone is listed
+-- until they opt out.
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
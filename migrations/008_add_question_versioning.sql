@@ -0,0 +1,26 @@
+-- Migration: Add question versioning and publish windows
+-- Created: 2026-10-18
+-- Description: Questions are edited as draft versions and published with an
+-- optional publish window. Existing questions stay published with no window;
+-- their base version is backfilled at startup once the version tables exist.
+-- Answers record the question version they were given against; existing answers
+-- predate versioning and keep no version.
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS published_version_id INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+ALTER TABLE user_question_answer
+ADD COLUMN IF NOT EXISTS question_version_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_user_question_answer_question_version_id ON user_question_answer(question_version_id);
+
+COMMENT ON COLUMN question_master.status IS 'Question status: draft or published';
@@ -0,0 +1,12 @@
+-- Migration: Add sections and display order to questions
+-- Created: 2026-10-18
+-- Description: Profile questions are grouped into sections and ordered within
+-- them. Existing questions have no section and keep their current order.
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS section_id INTEGER;
+
+ALTER TABLE question_master
+ADD COLUMN IF NOT EXISTS display_order INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_question_master_section_id ON question_master(section_id);
@@ -0,0 +1,7 @@
+-- Migration: Add preferred language to users
+-- Created: 2026-10-18
+-- Description: Users can pick the language they read the app in. Requests that
+-- do not ask for a language are served in it; NULL falls back to Accept-Language.
+
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS preferred_language_id INTEGER;
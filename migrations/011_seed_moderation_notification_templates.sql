@@ -0,0 +1,24 @@
+-- Migration: Seed moderation notification templates
+-- Created: 2026-10-18
+-- Description: Entrants are notified when their Thunder Seat submission is
+-- approved or rejected. The default language copy is seeded here; other
+-- languages are added through the language tables and fall back to it.
+
+INSERT INTO language_key (name, key_type_id, is_active, is_deleted, created_by, created_on)
+VALUES
+    ('thunder_seat.submission_approved.title', 2, TRUE, FALSE, 'system', NOW()),
+    ('thunder_seat.submission_approved.body', 2, TRUE, FALSE, 'system', NOW()),
+    ('thunder_seat.submission_rejected.title', 2, TRUE, FALSE, 'system', NOW()),
+    ('thunder_seat.submission_rejected.body', 2, TRUE, FALSE, 'system', NOW())
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO language_key_values (language_key_id, language_master_id, key_value, is_active, is_deleted, created_by, created_on)
+SELECT k.id, 1, v.key_value, TRUE, FALSE, 'system', NOW()
+FROM (VALUES
+    ('thunder_seat.submission_approved.title', 'Your Thunder Seat entry is live'),
+    ('thunder_seat.submission_approved.body', 'Your week {week} entry has been approved. Good luck!'),
+    ('thunder_seat.submission_rejected.title', 'Your Thunder Seat entry was not approved'),
+    ('thunder_seat.submission_rejected.body', 'Your week {week} entry did not meet the contest guidelines.')
+) AS v(name, key_value)
+JOIN language_key k ON k.name = v.name
+ON CONFLICT (language_key_id, language_master_id) DO NOTHING;
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache is a small in-memory key/value cache whose entries expire after a fixed TTL.
+// Expired entries are dropped when read and swept from the map at most once per TTL on write
+type TTLCache[K comparable, V any] struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	entries   map[K]entry[V]
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewTTLCache creates a cache whose entries live for ttl
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	return &TTLCache[K, V]{
+		ttl:       ttl,
+		entries:   make(map[K]entry[V]),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Get returns the cached value for key if present and not expired
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	e, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	now := c.now()
+	if now.After(e.expiresAt) {
+		c.mu.Lock()
+		if current, exists := c.entries[key]; exists && now.After(current.expiresAt) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key using the cache's default TTL
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores value under key with an explicit TTL
+func (c *TTLCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		c.sweepLocked(now)
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(ttl)}
+}
+
+// Len returns the number of entries held, including expired ones not yet swept
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// sweepLocked removes every expired entry; the caller must hold the write lock
+func (c *TTLCache[K, V]) sweepLocked(now time.Time) {
+	for key, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+// Delete removes key from the cache
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Clear removes every entry from the cache
+func (c *TTLCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[K]entry[V])
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache_SetAndGet(t *testing.T) {
+	c := NewTTLCache[string, int](time.Minute)
+
+	c.Set("a", 1)
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	_, ok = c.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestTTLCache_Expiry(t *testing.T) {
+	now := time.Now()
+	c := NewTTLCache[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+
+	now = now.Add(2 * time.Minute)
+	_, ok := c.Get("a")
+	assert.False(t, ok, "entry should expire after TTL")
+	assert.Equal(t, 0, c.Len(), "expired entry should be removed on read")
+}
+
+func TestTTLCache_SweepsExpiredEntriesOnWrite(t *testing.T) {
+	now := time.Now()
+	c := NewTTLCache[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+	c.lastSweep = now
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	assert.Equal(t, 2, c.Len())
+
+	now = now.Add(2 * time.Minute)
+	c.Set("c", 3)
+
+	assert.Equal(t, 1, c.Len(), "expired entries should be swept once per TTL")
+	value, ok := c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+}
+
+func TestTTLCache_DeleteAndClear(t *testing.T) {
+	c := NewTTLCache[int, string](time.Minute)
+	c.Set(1, "one")
+	c.Set(2, "two")
+
+	c.Delete(1)
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+
+	c.Clear()
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+}
@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a language translations can be written in. Code is a BCP 47 tag
+// such as "hi" or "en-IN" and is matched against Accept-Language.
+type Language struct {
+	ID   int
+	Name string
+	Code string
+}
+
+// Catalog is an immutable snapshot of every translation, keyed by language ID
+// and then by key. Lookups fall back to the default language and then to the key
+// itself, so a missing translation never yields an empty string.
+type Catalog struct {
+	defaultLanguageID int
+	languages         []Language
+	values            map[int]map[string]string
+}
+
+// NewCatalog creates an empty catalog that falls back to defaultLanguageID
+func NewCatalog(defaultLanguageID int, languages []Language) *Catalog {
+	return &Catalog{
+		defaultLanguageID: defaultLanguageID,
+		languages:         languages,
+		values:            make(map[int]map[string]string),
+	}
+}
+
+// Add stores the translation of key in a language, replacing any earlier one
+func (c *Catalog) Add(languageID int, key, value string) {
+	if c.values[languageID] == nil {
+		c.values[languageID] = make(map[string]string)
+	}
+	c.values[languageID][key] = value
+}
+
+// DefaultLanguageID returns the language used when no other one matches
+func (c *Catalog) DefaultLanguageID() int {
+	return c.defaultLanguageID
+}
+
+// Languages returns the languages the catalog knows about
+func (c *Catalog) Languages() []Language {
+	return c.languages
+}
+
+// HasLanguage reports whether languageID is one of the catalog's languages
+func (c *Catalog) HasLanguage(languageID int) bool {
+	for _, language := range c.languages {
+		if language.ID == languageID {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of translations in the catalog
+func (c *Catalog) Size() int {
+	size := 0
+	for _, values := range c.values {
+		size += len(values)
+	}
+	return size
+}
+
+// Lookup returns the translation of key in the language without falling back
+func (c *Catalog) Lookup(languageID int, key string) (string, bool) {
+	value, ok := c.values[languageID][key]
+	return value, ok
+}
+
+// Translate returns the translation of key in the language, falling back to the
+// default language and then to the key itself
+func (c *Catalog) Translate(languageID int, key string) string {
+	if value, ok := c.Lookup(languageID, key); ok {
+		return value
+	}
+	if value, ok := c.Lookup(c.defaultLanguageID, key); ok {
+		return value
+	}
+	return key
+}
+
+// Match picks the catalog language that best serves an Accept-Language header.
+// Tags are tried in preference order, first exactly and then by their primary
+// subtag, so "hi-IN" is served by "hi". It returns false when nothing matches.
+func (c *Catalog) Match(acceptLanguage string) (int, bool) {
+	for _, tag := range ParseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			return c.defaultLanguageID, true
+		}
+		for _, language := range c.languages {
+			if strings.EqualFold(language.Code, tag) {
+				return language.ID, true
+			}
+		}
+		base := primarySubtag(tag)
+		for _, language := range c.languages {
+			if strings.EqualFold(primarySubtag(language.Code), base) {
+				return language.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ParseAcceptLanguage returns the tags of an Accept-Language header in order of
+// preference. Tags with a quality of 0 are left out and malformed qualities
+// count as 1.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// Format replaces every {name} placeholder in a template with params[name].
+// Placeholders without a parameter are left as they are.
+func Format(template string, params map[string]string) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+func primarySubtag(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	base, _, _ = strings.Cut(base, "_")
+	return strings.ToLower(base)
+}
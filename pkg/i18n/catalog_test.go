@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCatalog() *Catalog {
+	c := NewCatalog(1, []Language{{ID: 1, Code: "en"}, {ID: 2, Code: "hi"}, {ID: 3, Code: "ta-IN"}})
+	c.Add(1, "greeting", "Hello {name}")
+	c.Add(1, "farewell", "Goodbye")
+	c.Add(2, "greeting", "Namaste {name}")
+	return c
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"hi-IN", "hi", "en"}, ParseAcceptLanguage("en;q=0.5, hi-IN, hi;q=0.8"))
+	assert.Equal(t, []string{"ta"}, ParseAcceptLanguage("ta, fr;q=0"))
+	assert.Equal(t, []string{"en"}, ParseAcceptLanguage("en;q=abc"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	c := testCatalog()
+
+	assert.Equal(t, "Namaste {name}", c.Translate(2, "greeting"))
+	assert.Equal(t, "Goodbye", c.Translate(2, "farewell"), "falls back to the default language")
+	assert.Equal(t, "missing", c.Translate(2, "missing"), "falls back to the key")
+	assert.Equal(t, "Hello {name}", c.Translate(99, "greeting"), "unknown languages use the default")
+}
+
+func TestCatalog_Match(t *testing.T) {
+	c := testCatalog()
+
+	id, ok := c.Match("hi-IN,en;q=0.5")
+	assert.True(t, ok)
+	assert.Equal(t, 2, id, "matches on the primary subtag")
+
+	id, ok = c.Match("ta-IN")
+	assert.True(t, ok)
+	assert.Equal(t, 3, id)
+
+	id, ok = c.Match("fr, ta;q=0.4")
+	assert.True(t, ok)
+	assert.Equal(t, 3, id, "skips tags without a language")
+
+	_, ok = c.Match("fr")
+	assert.False(t, ok)
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "Hello Asha, {unknown}", Format("Hello {name}, {unknown}", map[string]string{"name": "Asha"}))
+	assert.Equal(t, "Hello {name}", Format("Hello {name}", nil))
+}
@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Kinds of content a sheet row translates
+const (
+	RowTypeQuestion = "question"
+	RowTypeOption   = "option"
+)
+
+// Row is one line of a translation sheet: the translation of a question, or of
+// one of its options, into a language. SourceText is the text being translated
+// and is informational only; an empty Text means the row is not translated yet.
+type Row struct {
+	Type       string `json:"type"`
+	QuestionID int    `json:"question_id"`
+	OptionID   int    `json:"option_id,omitempty"`
+	LanguageID int    `json:"language_id"`
+	SourceText string `json:"source_text,omitempty"`
+	Text       string `json:"text"`
+}
+
+// SheetColumns are the columns of a CSV translation sheet, in the order they are written
+var SheetColumns = []string{"type", "question_id", "option_id", "language_id", "source_text", "text"}
+
+// WriteCSV writes rows as a CSV sheet with a header line
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(SheetColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		optionID := ""
+		if row.OptionID > 0 {
+			optionID = strconv.Itoa(row.OptionID)
+		}
+		record := []string{
+			row.Type,
+			strconv.Itoa(row.QuestionID),
+			optionID,
+			strconv.Itoa(row.LanguageID),
+			row.SourceText,
+			row.Text,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads a CSV sheet. Columns are matched by the names in its header line,
+// so they may come in any order and unknown ones are ignored; source_text may be
+// left out. Errors name the line they were found on.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("sheet is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet apps often save UTF-8 with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range SheetColumns {
+		if _, ok := columns[name]; !ok && name != "source_text" {
+			return nil, fmt.Errorf("sheet has no %s column", name)
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+		number := func(name string) (int, error) {
+			value := strings.TrimSpace(field(name))
+			if value == "" {
+				return 0, nil
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: %s must be a number", line, name)
+			}
+			return n, nil
+		}
+
+		row := Row{
+			Type:       strings.ToLower(strings.TrimSpace(field("type"))),
+			SourceText: field("source_text"),
+			Text:       strings.TrimSpace(field("text")),
+		}
+		if row.QuestionID, err = number("question_id"); err != nil {
+			return nil, err
+		}
+		if row.OptionID, err = number("option_id"); err != nil {
+			return nil, err
+		}
+		if row.LanguageID, err = number("language_id"); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
@@ -0,0 +1,44 @@
+package i18n
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSheet_RoundTrip(t *testing.T) {
+	rows := []Row{
+		{Type: RowTypeQuestion, QuestionID: 4, LanguageID: 2, SourceText: "Favourite drink?", Text: "पसंदीदा ड्रिंक?"},
+		{Type: RowTypeOption, QuestionID: 4, OptionID: 9, LanguageID: 2, SourceText: "Cola, chilled"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, rows))
+	assert.True(t, strings.HasPrefix(buf.String(), "type,question_id,option_id,language_id,source_text,text\n"))
+
+	read, err := ReadCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, rows, read)
+}
+
+func TestReadCSV_ColumnsByName(t *testing.T) {
+	sheet := "\ufeffText,Language_ID,notes,Type,Question_ID,Option_ID\n Hola ,3,check this,Question,7,\n"
+
+	rows, err := ReadCSV(strings.NewReader(sheet))
+	require.NoError(t, err)
+	assert.Equal(t, []Row{{Type: RowTypeQuestion, QuestionID: 7, LanguageID: 3, Text: "Hola"}}, rows)
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader(""))
+	assert.EqualError(t, err, "sheet is empty")
+
+	_, err = ReadCSV(strings.NewReader("type,question_id,language_id,text\n"))
+	assert.EqualError(t, err, "sheet has no option_id column")
+
+	_, err = ReadCSV(strings.NewReader("type,question_id,option_id,language_id,text\nquestion,1,,2,a\nquestion,x,,2,b\n"))
+	assert.EqualError(t, err, "line 3: question_id must be a number")
+}
@@ -0,0 +1,179 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const exifGPSIFDTag = 0x8825
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+// exifTypeSizes is the size in bytes of one value of each TIFF field type
+var exifTypeSizes = map[uint16]uint32{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+// StripGPS removes location metadata from a JPEG or PNG image. In JPEGs the GPS
+// IFD of the EXIF block is emptied and XMP packets mentioning GPS are dropped, so
+// orientation and the rest of EXIF survive; PNG eXIf chunks are dropped whole.
+// The input is never modified; the second result reports whether anything was removed.
+func StripGPS(data []byte, contentType string) ([]byte, bool) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEGGPS(data)
+	case "image/png":
+		return stripPNGExif(data)
+	default:
+		return data, false
+	}
+}
+
+func stripJPEGGPS(data []byte) ([]byte, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return data, false
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	stripped := false
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			break
+		}
+		marker := data[pos+1]
+		// Metadata segments all come before the first scan
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			break
+		}
+
+		segment := data[pos:end]
+		if marker == 0xE1 {
+			payload := segment[4:]
+			switch {
+			case bytes.HasPrefix(payload, exifHeader):
+				cleaned := append([]byte(nil), segment...)
+				if clearGPSIFD(cleaned[4+len(exifHeader):]) {
+					stripped = true
+					segment = cleaned
+				}
+			case bytes.HasPrefix(payload, xmpHeader) && bytes.Contains(payload, []byte("GPS")):
+				stripped = true
+				pos = end
+				continue
+			}
+		}
+
+		out = append(out, segment...)
+		pos = end
+	}
+
+	if !stripped {
+		return data, false
+	}
+	return append(out, data[pos:]...), true
+}
+
+// clearGPSIFD zeroes the GPS IFD of a TIFF structure in place, leaving an IFD
+// with no entries behind so readers following the pointer find nothing
+func clearGPSIFD(tiff []byte) bool {
+	if len(tiff) < 8 {
+		return false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return false
+	}
+
+	ifd0 := order.Uint32(tiff[4:])
+	gpsOffset, ok := findIFDEntry(tiff, order, ifd0, exifGPSIFDTag)
+	if !ok || uint64(gpsOffset)+2 > uint64(len(tiff)) {
+		return false
+	}
+
+	count := uint32(order.Uint16(tiff[gpsOffset:]))
+	if count == 0 {
+		return false
+	}
+	entriesEnd := uint64(gpsOffset) + 2 + uint64(count)*12
+	if entriesEnd > uint64(len(tiff)) {
+		return false
+	}
+
+	for i := uint32(0); i < count; i++ {
+		entry := tiff[gpsOffset+2+i*12:]
+		size := uint64(exifTypeSizes[order.Uint16(entry[2:])]) * uint64(order.Uint32(entry[4:]))
+		// Values longer than four bytes live outside the entry
+		if size > 4 {
+			valueOffset := uint64(order.Uint32(entry[8:]))
+			if valueOffset+size <= uint64(len(tiff)) {
+				clear(tiff[valueOffset : valueOffset+size])
+			}
+		}
+	}
+	clear(tiff[gpsOffset:entriesEnd])
+	return true
+}
+
+func findIFDEntry(tiff []byte, order binary.ByteOrder, ifdOffset uint32, tag uint16) (uint32, bool) {
+	if uint64(ifdOffset)+2 > uint64(len(tiff)) {
+		return 0, false
+	}
+	count := uint32(order.Uint16(tiff[ifdOffset:]))
+	for i := uint32(0); i < count; i++ {
+		start := uint64(ifdOffset) + 2 + uint64(i)*12
+		if start+12 > uint64(len(tiff)) {
+			return 0, false
+		}
+		entry := tiff[start : start+12]
+		if order.Uint16(entry) == tag {
+			return order.Uint32(entry[8:]), true
+		}
+	}
+	return 0, false
+}
+
+func stripPNGExif(data []byte) ([]byte, bool) {
+	if len(data) < len(pngSignature) || !bytes.HasPrefix(data, pngSignature) {
+		return data, false
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	stripped := false
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			break
+		}
+		if string(data[pos+4:pos+8]) == "eXIf" {
+			stripped = true
+		} else {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	if !stripped {
+		return data, false
+	}
+	return append(out, data[pos:]...), true
+}
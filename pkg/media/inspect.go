@@ -0,0 +1,124 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"regexp"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// activeContentPatterns match markup and scripts that browsers or servers may
+// execute when a file is served or opened with the wrong type
+var activeContentPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<script`),
+	regexp.MustCompile(`(?i)<\?php`),
+	regexp.MustCompile(`(?i)<html`),
+	regexp.MustCompile(`(?i)<!doctype\s+html`),
+	regexp.MustCompile(`(?i)<iframe`),
+	regexp.MustCompile(`(?i)javascript:`),
+}
+
+// svgActivePatterns are additionally rejected in SVG images, which are markup themselves
+var svgActivePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\son[a-z]+\s*=`),
+	regexp.MustCompile(`(?i)<foreignObject`),
+}
+
+// ActiveContent returns the first script or markup marker found in data, or ""
+func ActiveContent(data []byte, contentType string) string {
+	patterns := activeContentPatterns
+	if contentType == "image/svg+xml" {
+		patterns = append(append([]*regexp.Regexp(nil), activeContentPatterns...), svgActivePatterns...)
+	}
+	for _, pattern := range patterns {
+		if match := pattern.Find(data); match != nil {
+			return string(match)
+		}
+	}
+	return ""
+}
+
+// Trailer returns the bytes stored after the end of a JPEG or PNG image. Data
+// appended after the end marker is invisible to image viewers, which makes it the
+// usual place to hide a second file. It returns nil for other formats or when the
+// image structure cannot be followed to its end.
+func Trailer(data []byte, contentType string) []byte {
+	var end int
+	switch contentType {
+	case "image/jpeg":
+		end = jpegEnd(data)
+	case "image/png":
+		end = pngEnd(data)
+	}
+	if end <= 0 || end >= len(data) {
+		return nil
+	}
+	return data[end:]
+}
+
+// jpegEnd returns the offset just past the EOI marker, or -1
+func jpegEnd(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return -1
+	}
+
+	pos := 2
+	for pos+2 <= len(data) {
+		if data[pos] != 0xFF {
+			return -1
+		}
+		marker := data[pos+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte before a marker
+			pos++
+			continue
+		case marker == 0xD9:
+			return pos + 2
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			pos += 2
+			continue
+		}
+
+		if pos+4 > len(data) {
+			return -1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		pos += 2 + length
+		if marker != 0xDA {
+			continue
+		}
+
+		// Entropy coded data only contains 0xFF followed by a stuffed zero or a
+		// restart marker; anything else is the next marker
+		for pos+1 < len(data) {
+			if data[pos] == 0xFF && data[pos+1] != 0x00 && (data[pos+1] < 0xD0 || data[pos+1] > 0xD7) {
+				break
+			}
+			pos++
+		}
+	}
+	return -1
+}
+
+// pngEnd returns the offset just past the IEND chunk, or -1
+func pngEnd(data []byte) int {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return -1
+	}
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return -1
+		}
+		if string(data[pos+4:pos+8]) == "IEND" {
+			return end
+		}
+		pos = end
+	}
+	return -1
+}
@@ -0,0 +1,182 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wavFile(byteRate, dataSize uint32) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, uint16(1))
+	binary.Write(buf, binary.LittleEndian, uint16(1))
+	binary.Write(buf, binary.LittleEndian, byteRate)
+	binary.Write(buf, binary.LittleEndian, byteRate)
+	binary.Write(buf, binary.LittleEndian, uint16(1))
+	binary.Write(buf, binary.LittleEndian, uint16(8))
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+func pngFile(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestProbe_WAV(t *testing.T) {
+	wav := wavFile(8000, 8000*2)
+	info, err := Probe(bytes.NewReader(wav), int64(len(wav)))
+	require.NoError(t, err)
+	assert.Equal(t, "audio", info.Kind)
+	assert.Equal(t, 2*time.Second, info.Duration)
+}
+
+func TestProbe_ImageDimensions(t *testing.T) {
+	data := pngFile(t, 120, 80)
+	info, err := Probe(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.Equal(t, "image", info.Kind)
+	assert.Equal(t, 120, info.Width)
+	assert.Equal(t, 80, info.Height)
+}
+
+func TestProbe_IgnoresDeclaredType(t *testing.T) {
+	data := []byte("<html><body>not a video</body></html>")
+	info, err := Probe(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "unknown", info.Kind)
+}
+
+func TestThumbnail(t *testing.T) {
+	img, err := Decode(pngFile(t, 1000, 500))
+	require.NoError(t, err)
+
+	thumb := Thumbnail(img, 320)
+	assert.Equal(t, 320, thumb.Bounds().Dx())
+	assert.Equal(t, 160, thumb.Bounds().Dy())
+
+	small := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	assert.Same(t, small, Thumbnail(small, 320))
+
+	encoded, err := EncodeJPEG(thumb)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xd8}, encoded[:2])
+}
+
+func TestSquareThumbnail(t *testing.T) {
+	img, err := Decode(pngFile(t, 900, 600))
+	require.NoError(t, err)
+
+	full := SquareThumbnail(img, 512)
+	assert.Equal(t, image.Rect(0, 0, 512, 512), full.Bounds())
+
+	small := SquareThumbnail(image.NewRGBA(image.Rect(10, 10, 110, 60)), 512)
+	assert.Equal(t, image.Rect(0, 0, 50, 50), small.Bounds())
+}
+
+func TestPosterPlaceholder(t *testing.T) {
+	width, height := FitWithin(1920, 1080, 640)
+	assert.Equal(t, 640, width)
+	assert.Equal(t, 360, height)
+
+	poster := PosterPlaceholder(width, height)
+	assert.Equal(t, image.Rect(0, 0, 640, 360), poster.Bounds())
+	assert.NotEqual(t, poster.At(0, 0), poster.At(width/2, height/2))
+}
+
+// jpegWithGPS encodes a small JPEG carrying an EXIF block with a GPS latitude
+func jpegWithGPS(t *testing.T) []byte {
+	t.Helper()
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 16, 16)), nil))
+
+	le := binary.LittleEndian
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("II")
+	binary.Write(tiff, le, uint16(42))
+	binary.Write(tiff, le, uint32(8))
+	// IFD0 with only the GPS IFD pointer
+	binary.Write(tiff, le, uint16(1))
+	binary.Write(tiff, le, []uint16{0x8825, 4})
+	binary.Write(tiff, le, []uint32{1, 26, 0})
+	// GPS IFD: latitude ref inline, latitude rationals at offset 56
+	binary.Write(tiff, le, uint16(2))
+	binary.Write(tiff, le, []uint16{0x0001, 2})
+	binary.Write(tiff, le, uint32(2))
+	tiff.WriteString("N\x00\x00\x00")
+	binary.Write(tiff, le, []uint16{0x0002, 5})
+	binary.Write(tiff, le, []uint32{3, 56, 0})
+	binary.Write(tiff, le, []uint32{12, 1, 58, 1, 30, 1})
+
+	app1 := new(bytes.Buffer)
+	app1.Write([]byte{0xFF, 0xE1})
+	binary.Write(app1, binary.BigEndian, uint16(2+6+tiff.Len()))
+	app1.WriteString("Exif\x00\x00")
+	app1.Write(tiff.Bytes())
+
+	data := encoded.Bytes()
+	return append(append(append([]byte(nil), data[:2]...), app1.Bytes()...), data[2:]...)
+}
+
+func TestStripGPS_JPEG(t *testing.T) {
+	data := jpegWithGPS(t)
+	original := append([]byte(nil), data...)
+
+	cleaned, stripped := StripGPS(data, "image/jpeg")
+	require.True(t, stripped)
+	assert.Equal(t, original, data, "input must not be modified")
+	assert.Equal(t, len(data), len(cleaned))
+
+	tiff := cleaned[bytes.Index(cleaned, []byte("Exif\x00\x00"))+6:]
+	assert.Equal(t, uint16(0x8825), binary.LittleEndian.Uint16(tiff[10:]), "IFD0 is kept")
+	assert.Equal(t, uint16(0), binary.LittleEndian.Uint16(tiff[26:]), "GPS IFD is emptied")
+	assert.Equal(t, make([]byte, 24), tiff[56:80], "GPS values are zeroed")
+
+	_, err := jpeg.Decode(bytes.NewReader(cleaned))
+	assert.NoError(t, err)
+
+	_, stripped = StripGPS(cleaned, "image/jpeg")
+	assert.False(t, stripped)
+}
+
+func TestTrailer(t *testing.T) {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 64, 64)), nil))
+	jpg := encoded.Bytes()
+	assert.Nil(t, Trailer(jpg, "image/jpeg"))
+
+	zip := []byte("PK\x03\x04payload")
+	assert.Equal(t, zip, Trailer(append(append([]byte(nil), jpg...), zip...), "image/jpeg"))
+
+	pngData := pngFile(t, 8, 8)
+	assert.Nil(t, Trailer(pngData, "image/png"))
+	assert.Equal(t, zip, Trailer(append(append([]byte(nil), pngData...), zip...), "image/png"))
+}
+
+func TestActiveContent(t *testing.T) {
+	assert.Equal(t, "", ActiveContent(pngFile(t, 8, 8), "image/png"))
+	assert.NotEmpty(t, ActiveContent([]byte("GIF89a<script>alert(1)</script>"), "image/gif"))
+	assert.NotEmpty(t, ActiveContent([]byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"/>`), "image/svg+xml"))
+	assert.Equal(t, "", ActiveContent([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="1"/></svg>`), "image/svg+xml"))
+}
@@ -0,0 +1,236 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for DecodeConfig
+	_ "image/jpeg" // register JPEG for DecodeConfig
+	_ "image/png"  // register PNG for DecodeConfig
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// HeaderSize is the number of leading bytes read for content sniffing
+const HeaderSize = 3072
+
+// Info is what could be learned about a media file from its bytes
+type Info struct {
+	ContentType string // sniffed from magic bytes, not taken from the client
+	Kind        string // audio, video, image or unknown
+	Duration    time.Duration
+	Width       int
+	Height      int
+}
+
+// Probe sniffs the real content type of a file and extracts its duration
+// (WAV, MP4/MOV) or dimensions (JPEG, PNG, GIF, MP4/MOV). Fields that cannot be
+// determined are left zero. The reader is rewound before returning.
+func Probe(r io.ReadSeeker, size int64) (*Info, error) {
+	header := make([]byte, HeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read media header: %w", err)
+	}
+	header = header[:n]
+
+	detected := mimetype.Detect(header)
+	info := &Info{
+		ContentType: detected.String(),
+		Kind:        KindOf(detected.String()),
+	}
+
+	switch {
+	case info.Kind == "image":
+		if _, err := r.Seek(0, io.SeekStart); err == nil {
+			if cfg, _, err := image.DecodeConfig(r); err == nil {
+				info.Width, info.Height = cfg.Width, cfg.Height
+			}
+		}
+	case isMP4(header):
+		info.Duration = MP4Duration(r, size)
+		info.Width, info.Height = mp4Dimensions(r, size)
+	default:
+		info.Duration = Duration(r, size, header)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind media: %w", err)
+	}
+	return info, nil
+}
+
+// KindOf maps a content type to a media kind. Containers that can hold either
+// audio or video are reported as video.
+func KindOf(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "audio/"):
+		return "audio"
+	case strings.HasPrefix(contentType, "video/"), contentType == "application/ogg":
+		return "video"
+	case strings.HasPrefix(contentType, "image/"):
+		return "image"
+	default:
+		return "unknown"
+	}
+}
+
+// Duration returns the playing time of WAV and MP4/MOV files, or zero for other formats
+func Duration(r io.ReadSeeker, size int64, header []byte) time.Duration {
+	switch {
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return wavDuration(header)
+	case isMP4(header):
+		return MP4Duration(r, size)
+	}
+	return 0
+}
+
+func isMP4(header []byte) bool {
+	return len(header) >= 8 && string(header[4:8]) == "ftyp"
+}
+
+// wavDuration reads the fmt and data chunks of a WAV header
+func wavDuration(header []byte) time.Duration {
+	var byteRate, dataSize uint32
+	offset := 12
+	for offset+8 <= len(header) {
+		chunkID := string(header[offset : offset+4])
+		chunkSize := binary.LittleEndian.Uint32(header[offset+4 : offset+8])
+		body := offset + 8
+		switch chunkID {
+		case "fmt ":
+			if body+12 <= len(header) {
+				byteRate = binary.LittleEndian.Uint32(header[body+8 : body+12])
+			}
+		case "data":
+			dataSize = chunkSize
+		}
+		if byteRate > 0 && dataSize > 0 {
+			return time.Duration(float64(dataSize) / float64(byteRate) * float64(time.Second))
+		}
+		offset = body + int(chunkSize) + int(chunkSize%2)
+	}
+	return 0
+}
+
+// MP4Duration walks the top-level boxes to the movie header (moov/mvhd)
+func MP4Duration(r io.ReadSeeker, size int64) time.Duration {
+	moov, ok := findBox(r, 0, size, "moov")
+	if !ok {
+		return 0
+	}
+	mvhd, ok := findBox(r, moov.start, moov.end, "mvhd")
+	if !ok {
+		return 0
+	}
+
+	buf := make([]byte, 32)
+	if _, err := r.Seek(mvhd.start, io.SeekStart); err != nil {
+		return 0
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0
+	}
+
+	var timescale uint32
+	var duration uint64
+	if buf[0] == 1 {
+		timescale = binary.BigEndian.Uint32(buf[20:24])
+		duration = binary.BigEndian.Uint64(buf[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(buf[12:16])
+		duration = uint64(binary.BigEndian.Uint32(buf[16:20]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// mp4Dimensions returns the display size of the first track with a non-zero
+// size in its track header (moov/trak/tkhd)
+func mp4Dimensions(r io.ReadSeeker, size int64) (int, int) {
+	moov, ok := findBox(r, 0, size, "moov")
+	if !ok {
+		return 0, 0
+	}
+
+	offset := moov.start
+	for {
+		trak, ok := findBox(r, offset, moov.end, "trak")
+		if !ok {
+			return 0, 0
+		}
+		offset = trak.end
+
+		tkhd, ok := findBox(r, trak.start, trak.end, "tkhd")
+		if !ok {
+			continue
+		}
+		buf := make([]byte, 92)
+		if _, err := r.Seek(tkhd.start, io.SeekStart); err != nil {
+			return 0, 0
+		}
+		n, _ := io.ReadFull(r, buf)
+		buf = buf[:n]
+
+		// width and height are 16.16 fixed point values at the end of the box
+		sizeOffset := 76
+		if len(buf) > 0 && buf[0] == 1 {
+			sizeOffset = 84
+		}
+		if len(buf) < sizeOffset+8 {
+			continue
+		}
+		width := int(binary.BigEndian.Uint32(buf[sizeOffset:sizeOffset+4]) >> 16)
+		height := int(binary.BigEndian.Uint32(buf[sizeOffset+4:sizeOffset+8]) >> 16)
+		if width > 0 && height > 0 {
+			return width, height
+		}
+	}
+}
+
+type box struct {
+	start int64 // first byte of the box payload
+	end   int64
+}
+
+func findBox(r io.ReadSeeker, from, to int64, boxType string) (box, bool) {
+	header := make([]byte, 16)
+	offset := from
+	for offset+8 <= to {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return box{}, false
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return box{}, false
+		}
+
+		boxSize := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerLen := int64(8)
+		switch boxSize {
+		case 0:
+			boxSize = to - offset
+		case 1:
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return box{}, false
+			}
+			boxSize = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerLen = 16
+		}
+		if boxSize < headerLen {
+			return box{}, false
+		}
+
+		if bytes.Equal(header[4:8], []byte(boxType)) {
+			return box{start: offset + headerLen, end: offset + boxSize}, true
+		}
+		offset += boxSize
+	}
+	return box{}, false
+}
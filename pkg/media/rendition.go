@@ -0,0 +1,134 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// ThumbnailJPEGQuality is the JPEG quality used for generated renditions
+const ThumbnailJPEGQuality = 80
+
+// Thumbnail scales src down so its longest side is at most maxSize, averaging
+// the source pixels covered by each output pixel. Images already small enough
+// are returned unchanged.
+func Thumbnail(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxSize && srcH <= maxSize {
+		return src
+	}
+
+	dstW, dstH := FitWithin(srcW, srcH, maxSize)
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := bounds.Min.Y + (y+1)*srcH/dstH
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := bounds.Min.X + (x+1)*srcW/dstW
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+	return dst
+}
+
+// SquareThumbnail crops the centre square of src and scales it down to at most
+// size pixels a side
+func SquareThumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), src, image.Pt(x0, y0), draw.Src)
+	return Thumbnail(square, size)
+}
+
+// FitWithin returns width and height scaled to fit a maxSize square, keeping the aspect ratio
+func FitWithin(width, height, maxSize int) (int, int) {
+	if width <= 0 || height <= 0 {
+		return maxSize, maxSize * 9 / 16
+	}
+	if width <= maxSize && height <= maxSize {
+		return width, height
+	}
+	if width >= height {
+		return maxSize, max(1, height*maxSize/width)
+	}
+	return max(1, width*maxSize/height), maxSize
+}
+
+// PosterPlaceholder draws a neutral poster frame with a play symbol, used for
+// videos until a real frame can be extracted
+func PosterPlaceholder(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBA{R: 0x20, G: 0x20, B: 0x24, A: 0xff}
+	foreground := color.RGBA{R: 0xe6, G: 0xe6, B: 0xe6, A: 0xff}
+
+	// play triangle pointing right, centred, a third of the shorter side tall
+	side := min(width, height) / 3
+	left := width/2 - side/3
+	top := height/2 - side/2
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, background)
+			dy := y - top
+			if dy < 0 || dy >= side {
+				continue
+			}
+			// the triangle narrows linearly towards its tip at the vertical centre
+			reach := side/2 - abs(dy-side/2)
+			if x >= left && x < left+reach*2 {
+				img.SetRGBA(x, y, foreground)
+			}
+		}
+	}
+	return img
+}
+
+// Decode decodes a JPEG, PNG or GIF image
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// EncodeJPEG encodes img as a JPEG rendition
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: ThumbnailJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
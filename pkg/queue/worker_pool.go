@@ -85,6 +85,12 @@ func (wp *WorkerPool) worker(id int) {
 func (wp *WorkerPool) Submit(task Task) error {
 	wp.metrics.incrementSubmitted()
 
+	// Checked first so a stopped pool never accepts work into its buffer
+	if err := wp.ctx.Err(); err != nil {
+		wp.metrics.incrementRejected()
+		return err
+	}
+
 	select {
 	case wp.tasks <- task:
 		return nil
@@ -116,7 +122,7 @@ func (wp *WorkerPool) Shutdown() {
 		log.Warn("Worker pool shutdown timeout, some tasks may not have completed")
 	}
 
-	close(wp.tasks)
+	// The tasks channel is left open so a late Submit is rejected instead of panicking
 	wp.logMetrics()
 }
 
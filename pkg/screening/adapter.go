@@ -0,0 +1,36 @@
+package screening
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/circuitbreaker"
+)
+
+// breakerScreener protects a remote screener with a circuit breaker
+type breakerScreener struct {
+	screener ContentScreener
+	breaker  *circuitbreaker.CircuitBreaker
+}
+
+// WithCircuitBreaker wraps an adapter for an external moderation API so an outage
+// fails fast; the chain records the failure as a finding that needs human review
+func WithCircuitBreaker(screener ContentScreener, breaker *circuitbreaker.CircuitBreaker) ContentScreener {
+	return &breakerScreener{
+		screener: screener,
+		breaker:  breaker,
+	}
+}
+
+func (b *breakerScreener) Name() string {
+	return b.screener.Name()
+}
+
+func (b *breakerScreener) Screen(ctx context.Context, submission Submission) ([]Finding, error) {
+	var findings []Finding
+	err := b.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		findings, err = b.screener.Screen(ctx, submission)
+		return err
+	})
+	return findings, err
+}
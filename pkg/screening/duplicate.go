@@ -0,0 +1,43 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+)
+
+// HashLookup returns the IDs of other submissions whose media has the given SHA-256
+type HashLookup func(ctx context.Context, sha256 string, excludeID int) ([]int, error)
+
+// DuplicateMediaCheck flags media that was already uploaded with another submission
+type DuplicateMediaCheck struct {
+	lookup HashLookup
+}
+
+// NewDuplicateMediaCheck creates a duplicate check backed by lookup
+func NewDuplicateMediaCheck(lookup HashLookup) *DuplicateMediaCheck {
+	return &DuplicateMediaCheck{lookup: lookup}
+}
+
+func (d *DuplicateMediaCheck) Name() string {
+	return "duplicate_media"
+}
+
+func (d *DuplicateMediaCheck) Screen(ctx context.Context, submission Submission) ([]Finding, error) {
+	if submission.Media == nil || submission.Media.SHA256 == "" {
+		return nil, nil
+	}
+
+	ids, err := d.lookup(ctx, submission.Media.SHA256, submission.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return []Finding{{
+		Check:   d.Name(),
+		Verdict: VerdictReview,
+		Reason:  fmt.Sprintf("media is identical to submissions %v", ids),
+	}}, nil
+}
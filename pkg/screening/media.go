@@ -0,0 +1,105 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// containerKinds lists container formats that legitimately hold more than one media kind
+var containerKinds = map[string][]string{
+	"video/mp4":       {"audio", "video"},
+	"video/webm":      {"audio", "video"},
+	"video/quicktime": {"audio", "video"},
+	"video/3gpp":      {"audio", "video"},
+	"audio/mp4":       {"audio", "video"},
+	"application/ogg": {"audio", "video"},
+}
+
+// MediaSanityCheck sniffs the file header and verifies size and duration limits
+type MediaSanityCheck struct {
+	minDuration  time.Duration
+	maxDurations map[string]time.Duration
+}
+
+// NewMediaSanityCheck creates a media check. maxDurations is keyed by media kind;
+// kinds without an entry have no duration limit.
+func NewMediaSanityCheck(minDuration time.Duration, maxDurations map[string]time.Duration) *MediaSanityCheck {
+	return &MediaSanityCheck{
+		minDuration:  minDuration,
+		maxDurations: maxDurations,
+	}
+}
+
+func (m *MediaSanityCheck) Name() string {
+	return "media_sanity"
+}
+
+func (m *MediaSanityCheck) Screen(ctx context.Context, submission Submission) ([]Finding, error) {
+	media := submission.Media
+	if media == nil {
+		return nil, nil
+	}
+
+	if media.Size == 0 || len(media.Header) == 0 {
+		return []Finding{{Check: m.Name(), Verdict: VerdictReject, Reason: "media file is empty"}}, nil
+	}
+
+	var findings []Finding
+
+	detected := mimetype.Detect(media.Header)
+	if !kindMatches(detected, media.Kind) {
+		verdict := VerdictReject
+		if detected.Is("application/octet-stream") {
+			verdict = VerdictReview
+		}
+		findings = append(findings, Finding{
+			Check:   m.Name(),
+			Verdict: verdict,
+			Reason:  fmt.Sprintf("file content is %s but was uploaded as %s", detected.String(), media.Kind),
+		})
+	}
+
+	if media.Duration > 0 && (media.Kind == "audio" || media.Kind == "video") {
+		if maxDuration, ok := m.maxDurations[media.Kind]; ok && media.Duration > maxDuration {
+			findings = append(findings, Finding{
+				Check:   m.Name(),
+				Verdict: VerdictReject,
+				Reason:  fmt.Sprintf("%s is %s long, longer than the allowed %s", media.Kind, media.Duration.Round(time.Second), maxDuration),
+			})
+		}
+		if media.Duration < m.minDuration {
+			findings = append(findings, Finding{
+				Check:   m.Name(),
+				Verdict: VerdictReview,
+				Reason:  fmt.Sprintf("%s is only %s long", media.Kind, media.Duration.Round(time.Millisecond)),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func kindMatches(detected *mimetype.MIME, kind string) bool {
+	for mime := detected; mime != nil; mime = mime.Parent() {
+		if strings.HasPrefix(mime.String(), kind+"/") {
+			return true
+		}
+		for _, allowed := range containerKinds[mime.String()] {
+			if allowed == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HeaderMatchesKind sniffs header and reports the detected content type and
+// whether it is plausible for the declared media kind
+func HeaderMatchesKind(header []byte, kind string) (string, bool) {
+	detected := mimetype.Detect(header)
+	return detected.String(), kindMatches(detected, kind)
+}
@@ -0,0 +1,47 @@
+package screening
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	mediaprobe "github.com/Infinite-Locus-Product/thums_up_backend/pkg/media"
+)
+
+// HeaderSize is the number of leading bytes kept for content sniffing
+const HeaderSize = mediaprobe.HeaderSize
+
+// ProbeMedia reads an uploaded file once to capture its header, SHA-256 and, for
+// MP4/MOV and WAV files, its duration
+func ProbeMedia(r io.ReadSeeker, size int64, kind, contentType string) (*Media, error) {
+	header := make([]byte, HeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("failed to read media header: %w", err)
+	}
+	header = header[:n]
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind media: %w", err)
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return nil, fmt.Errorf("failed to hash media: %w", err)
+	}
+
+	media := &Media{
+		Kind:        kind,
+		ContentType: contentType,
+		Size:        size,
+		Header:      header,
+		SHA256:      hex.EncodeToString(hasher.Sum(nil)),
+	}
+
+	media.Duration = mediaprobe.Duration(r, size, header)
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind media: %w", err)
+	}
+	return media, nil
+}
@@ -0,0 +1,80 @@
+package screening
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultEnglishBlocklist is the built-in English blocklist
+var DefaultEnglishBlocklist = []string{
+	"asshole", "bastard", "bitch", "bullshit", "cunt", "dick", "fuck", "fucked",
+	"fucker", "fucking", "motherfucker", "shit", "slut", "whore",
+}
+
+// DefaultHindiBlocklist is the built-in Hindi blocklist in Devanagari and romanized spellings
+var DefaultHindiBlocklist = []string{
+	"bhenchod", "behenchod", "bhosdike", "bhosdiwale", "chutiya", "chutiye", "gaandu",
+	"gandu", "harami", "lauda", "lavda", "lund", "madarchod", "randi",
+	"चूतिया", "चुतिया", "मादरचोद", "बहनचोद", "भेनचोद", "भोसडीके", "गांडू", "गाण्डू",
+	"हरामी", "रंडी", "लौड़ा", "लंड",
+}
+
+// leetReplacer undoes common character substitutions used to dodge blocklists
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// ProfanityCheck rejects answers containing blocklisted words
+type ProfanityCheck struct {
+	blocklist map[string]struct{}
+}
+
+// NewProfanityCheck creates a blocklist check from one or more word lists
+func NewProfanityCheck(wordLists ...[]string) *ProfanityCheck {
+	blocklist := make(map[string]struct{})
+	for _, words := range wordLists {
+		for _, word := range words {
+			blocklist[strings.ToLower(strings.TrimSpace(word))] = struct{}{}
+		}
+	}
+	return &ProfanityCheck{blocklist: blocklist}
+}
+
+func (p *ProfanityCheck) Name() string {
+	return "profanity"
+}
+
+func (p *ProfanityCheck) Screen(ctx context.Context, submission Submission) ([]Finding, error) {
+	var matches []string
+	seen := make(map[string]bool)
+
+	for _, token := range tokenize(submission.Text) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if _, blocked := p.blocklist[token]; blocked {
+			matches = append(matches, token)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		Check:   p.Name(),
+		Verdict: VerdictReject,
+		Reason:  fmt.Sprintf("answer contains blocked words: %s", strings.Join(matches, ", ")),
+	}}, nil
+}
+
+// tokenize lowercases the text, undoes leetspeak and splits it into words. Combining
+// marks are kept so Devanagari words stay intact.
+func tokenize(text string) []string {
+	normalized := leetReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.Is(unicode.M, r)
+	})
+}
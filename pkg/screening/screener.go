@@ -0,0 +1,119 @@
+package screening
+
+import (
+	"context"
+	"time"
+)
+
+// Verdict is the outcome of screening a submission
+type Verdict string
+
+const (
+	VerdictPass   Verdict = "pass"
+	VerdictReview Verdict = "review"
+	VerdictReject Verdict = "reject"
+)
+
+// severity orders verdicts so the strictest one wins when results are combined
+func (v Verdict) severity() int {
+	switch v {
+	case VerdictReject:
+		return 2
+	case VerdictReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Media describes the uploaded file of a submission as captured at upload time
+type Media struct {
+	Kind        string // audio, video or image
+	ContentType string // content type declared by the client
+	Size        int64
+	Header      []byte // leading bytes of the file used for content sniffing
+	SHA256      string
+	Duration    time.Duration // zero when the duration could not be determined
+}
+
+// Submission is the content handed to a screener
+type Submission struct {
+	ID    int
+	Text  string
+	Media *Media
+}
+
+// Finding is a single observation made by a screener
+type Finding struct {
+	Check   string  `json:"check"`
+	Verdict Verdict `json:"verdict"`
+	Reason  string  `json:"reason"`
+}
+
+// Result is the combined outcome of one or more checks
+type Result struct {
+	Verdict  Verdict   `json:"verdict"`
+	Findings []Finding `json:"findings"`
+}
+
+// ContentScreener inspects a submission and reports its findings. Local rule-based
+// checks and adapters for external moderation APIs both implement this interface.
+type ContentScreener interface {
+	Name() string
+	Screen(ctx context.Context, submission Submission) ([]Finding, error)
+}
+
+// ScreenerFunc adapts a function into a ContentScreener
+type ScreenerFunc struct {
+	ScreenerName string
+	Fn           func(ctx context.Context, submission Submission) ([]Finding, error)
+}
+
+func (f ScreenerFunc) Name() string {
+	return f.ScreenerName
+}
+
+func (f ScreenerFunc) Screen(ctx context.Context, submission Submission) ([]Finding, error) {
+	return f.Fn(ctx, submission)
+}
+
+// Chain runs screeners in order and combines their findings
+type Chain struct {
+	screeners []ContentScreener
+}
+
+// NewChain creates a chain of screeners
+func NewChain(screeners ...ContentScreener) *Chain {
+	return &Chain{screeners: screeners}
+}
+
+// Run screens the submission with every screener in the chain. A failing screener
+// does not stop the chain; it is recorded as a finding that needs human review.
+func (c *Chain) Run(ctx context.Context, submission Submission) Result {
+	result := Result{Verdict: VerdictPass, Findings: []Finding{}}
+
+	for _, screener := range c.screeners {
+		if ctx.Err() != nil {
+			result.add(Finding{Check: screener.Name(), Verdict: VerdictReview, Reason: "screening cancelled"})
+			break
+		}
+
+		findings, err := screener.Screen(ctx, submission)
+		if err != nil {
+			result.add(Finding{Check: screener.Name(), Verdict: VerdictReview, Reason: "screener failed: " + err.Error()})
+			continue
+		}
+		for _, finding := range findings {
+			result.add(finding)
+		}
+	}
+
+	return result
+}
+
+func (r *Result) add(finding Finding) {
+	r.Findings = append(r.Findings, finding)
+	if finding.Verdict.severity() > r.Verdict.severity() {
+		r.Verdict = finding.Verdict
+	}
+}
@@ -0,0 +1,118 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain_StrictestVerdictWins(t *testing.T) {
+	pass := ScreenerFunc{ScreenerName: "pass", Fn: func(ctx context.Context, s Submission) ([]Finding, error) {
+		return nil, nil
+	}}
+	review := ScreenerFunc{ScreenerName: "review", Fn: func(ctx context.Context, s Submission) ([]Finding, error) {
+		return []Finding{{Check: "review", Verdict: VerdictReview, Reason: "needs a look"}}, nil
+	}}
+	failing := ScreenerFunc{ScreenerName: "failing", Fn: func(ctx context.Context, s Submission) ([]Finding, error) {
+		return nil, errors.New("upstream down")
+	}}
+
+	result := NewChain(pass).Run(context.Background(), Submission{})
+	assert.Equal(t, VerdictPass, result.Verdict)
+	assert.Empty(t, result.Findings)
+
+	result = NewChain(pass, review, failing).Run(context.Background(), Submission{})
+	assert.Equal(t, VerdictReview, result.Verdict)
+	assert.Len(t, result.Findings, 2)
+
+	result = NewChain(review, NewProfanityCheck(DefaultEnglishBlocklist)).Run(context.Background(), Submission{Text: "this is shit"})
+	assert.Equal(t, VerdictReject, result.Verdict)
+}
+
+func TestProfanityCheck(t *testing.T) {
+	check := NewProfanityCheck(DefaultEnglishBlocklist, DefaultHindiBlocklist)
+
+	cases := []struct {
+		text    string
+		blocked bool
+	}{
+		{"Thums Up is my favourite drink", false},
+		{"What the FUCK", true},
+		{"sh1t happens", true},
+		{"tu chutiya hai", true},
+		{"तू चूतिया है", true},
+		{"Scunthorpe dickens", false},
+	}
+
+	for _, tc := range cases {
+		findings, err := check.Screen(context.Background(), Submission{Text: tc.text})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.blocked, len(findings) > 0, tc.text)
+	}
+}
+
+func TestMediaSanityCheck(t *testing.T) {
+	check := NewMediaSanityCheck(time.Second, map[string]time.Duration{"audio": time.Minute})
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	findings, err := check.Screen(context.Background(), Submission{Media: &Media{Kind: "image", Size: 100, Header: png}})
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+
+	findings, _ = check.Screen(context.Background(), Submission{Media: &Media{Kind: "video", Size: 100, Header: []byte("<html><body>hi</body></html>")}})
+	assert.Len(t, findings, 1)
+	assert.Equal(t, VerdictReject, findings[0].Verdict)
+
+	wav := wavHeader(8000, 8000*120)
+	findings, _ = check.Screen(context.Background(), Submission{Media: &Media{Kind: "audio", Size: int64(len(wav)), Header: wav, Duration: 2 * time.Minute}})
+	assert.Len(t, findings, 1)
+	assert.Equal(t, VerdictReject, findings[0].Verdict)
+}
+
+func TestProbeMedia_WAVDuration(t *testing.T) {
+	wav := wavHeader(16000, 16000*3)
+	media, err := ProbeMedia(bytes.NewReader(wav), int64(len(wav)), "audio", "audio/wav")
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, media.Duration)
+	assert.Len(t, media.SHA256, 64)
+}
+
+func TestDuplicateMediaCheck(t *testing.T) {
+	check := NewDuplicateMediaCheck(func(ctx context.Context, hash string, excludeID int) ([]int, error) {
+		if hash == "seen" {
+			return []int{7}, nil
+		}
+		return nil, nil
+	})
+
+	findings, err := check.Screen(context.Background(), Submission{ID: 1, Media: &Media{SHA256: "seen"}})
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+
+	findings, _ = check.Screen(context.Background(), Submission{ID: 1, Media: &Media{SHA256: "new"}})
+	assert.Empty(t, findings)
+}
+
+// wavHeader builds a PCM WAV header followed by dataSize bytes of silence
+func wavHeader(byteRate uint32, dataSize uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, byteRate)
+	binary.Write(&buf, binary.LittleEndian, byteRate)
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(8))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
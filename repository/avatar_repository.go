@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"gorm.io/gorm"
+)
+
+// AvatarFilter narrows an avatar listing. Nil fields do not filter.
+type AvatarFilter struct {
+	IsPublished    *bool
+	IsActive       *bool
+	Category       *string
+	Tag            *string
+	IncludeDeleted bool
+}
+
+type AvatarRepository interface {
+	GenericRepository[entities.Avatar]
+	FindWithTags(ctx context.Context, db *gorm.DB, id int) (*entities.Avatar, error)
+	FindFiltered(ctx context.Context, db *gorm.DB, filter AvatarFilter) ([]entities.Avatar, error)
+	ReplaceTags(ctx context.Context, db *gorm.DB, avatarID int, tags []string) error
+	UpdateDisplayOrder(ctx context.Context, db *gorm.DB, orderedIDs []int) error
+	MaxDisplayOrder(ctx context.Context, db *gorm.DB) (int, error)
+}
+
+type avatarRepository struct {
+	*GormRepository[entities.Avatar]
+}
+
+func NewAvatarRepository() AvatarRepository {
+	return &avatarRepository{
+		GormRepository: NewGormRepository[entities.Avatar](),
+	}
+}
+
+func (r *avatarRepository) FindWithTags(ctx context.Context, db *gorm.DB, id int) (*entities.Avatar, error) {
+	var avatar entities.Avatar
+	err := db.WithContext(ctx).
+		Preload("Tags").
+		First(&avatar, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+// FindFiltered returns avatars in picker order: display order, then oldest first
+func (r *avatarRepository) FindFiltered(ctx context.Context, db *gorm.DB, filter AvatarFilter) ([]entities.Avatar, error) {
+	query := db.WithContext(ctx).Model(&entities.Avatar{}).Preload("Tags")
+
+	if !filter.IncludeDeleted {
+		query = query.Where("is_deleted = ?", false)
+	}
+	if filter.IsPublished != nil {
+		query = query.Where("is_published = ?", *filter.IsPublished)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.Category != nil {
+		query = query.Where("category = ?", *filter.Category)
+	}
+	if filter.Tag != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM avatar_tag t WHERE t.avatar_id = avatar.id AND t.tag = ?)", *filter.Tag)
+	}
+
+	var avatars []entities.Avatar
+	if err := query.Order("display_order ASC, id ASC").Find(&avatars).Error; err != nil {
+		return nil, err
+	}
+	return avatars, nil
+}
+
+func (r *avatarRepository) ReplaceTags(ctx context.Context, db *gorm.DB, avatarID int, tags []string) error {
+	if err := db.WithContext(ctx).Where("avatar_id = ?", avatarID).Delete(&entities.AvatarTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]entities.AvatarTag, len(tags))
+	for i, tag := range tags {
+		rows[i] = entities.AvatarTag{AvatarID: avatarID, Tag: tag}
+	}
+	return db.WithContext(ctx).Create(&rows).Error
+}
+
+// UpdateDisplayOrder sets each avatar's display order to its position in orderedIDs
+func (r *avatarRepository) UpdateDisplayOrder(ctx context.Context, db *gorm.DB, orderedIDs []int) error {
+	for position, id := range orderedIDs {
+		err := db.WithContext(ctx).
+			Model(&entities.Avatar{}).
+			Where("id = ?", id).
+			Update("display_order", position).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *avatarRepository) MaxDisplayOrder(ctx context.Context, db *gorm.DB) (int, error) {
+	var maxOrder *int
+	err := db.WithContext(ctx).
+		Model(&entities.Avatar{}).
+		Where("is_deleted = ?", false).
+		Select("MAX(display_order)").
+		Scan(&maxOrder).Error
+	if err != nil || maxOrder == nil {
+		return 0, err
+	}
+	return *maxOrder, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"gorm.io/gorm"
+)
+
+type ContestWeekRuleRepository interface {
+	GenericRepository[entities.ContestWeekRule]
+	FindByWeekNumber(ctx context.Context, db *gorm.DB, weekNumber int) (*entities.ContestWeekRule, error)
+	ReplacePrompts(ctx context.Context, db *gorm.DB, weekNumber int, prompts []entities.ContestWeekPrompt) error
+}
+
+type contestWeekRuleRepository struct {
+	*GormRepository[entities.ContestWeekRule]
+}
+
+func NewContestWeekRuleRepository() ContestWeekRuleRepository {
+	return &contestWeekRuleRepository{
+		GormRepository: NewGormRepository[entities.ContestWeekRule](),
+	}
+}
+
+func (r *contestWeekRuleRepository) FindByWeekNumber(ctx context.Context, db *gorm.DB, weekNumber int) (*entities.ContestWeekRule, error) {
+	var rule entities.ContestWeekRule
+	if err := db.WithContext(ctx).
+		Preload("Prompts").
+		Where("week_number = ?", weekNumber).
+		First(&rule).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *contestWeekRuleRepository) ReplacePrompts(ctx context.Context, db *gorm.DB, weekNumber int, prompts []entities.ContestWeekPrompt) error {
+	if err := db.WithContext(ctx).
+		Where("week_number = ?", weekNumber).
+		Delete(&entities.ContestWeekPrompt{}).Error; err != nil {
+		return err
+	}
+	if len(prompts) == 0 {
+		return nil
+	}
+	return db.WithContext(ctx).Create(&prompts).Error
+}
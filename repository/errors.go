@@ -0,0 +1,24 @@
+package repository
+
+import (
+	stderrors "errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+)
+
+const pgUniqueViolationCode = "23505"
+
+// mapDuplicateError converts a Postgres unique violation into an *errors.DuplicateError
+// and returns any other error unchanged
+func mapDuplicateError(err error) error {
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) && pgErr.Code == pgUniqueViolationCode {
+		return &errors.DuplicateError{
+			Constraint: pgErr.ConstraintName,
+			Err:        err,
+		}
+	}
+	return err
+}
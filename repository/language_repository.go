@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type LanguageRepository interface {
+	FindActiveLanguages(ctx context.Context, db *gorm.DB) ([]entities.LanguageMaster, error)
+	// FindActiveKeyValues returns the active translations of active keys in active
+	// languages, with their key
+	FindActiveKeyValues(ctx context.Context, db *gorm.DB) ([]entities.LanguageKeyValue, error)
+}
+
+type languageRepository struct{}
+
+func NewLanguageRepository() LanguageRepository {
+	return &languageRepository{}
+}
+
+func (r *languageRepository) FindActiveLanguages(ctx context.Context, db *gorm.DB) ([]entities.LanguageMaster, error) {
+	var languages []entities.LanguageMaster
+	err := db.WithContext(ctx).
+		Where("is_active = ? AND is_deleted = ?", true, false).
+		Order("id ASC").
+		Find(&languages).Error
+	return languages, err
+}
+
+func (r *languageRepository) FindActiveKeyValues(ctx context.Context, db *gorm.DB) ([]entities.LanguageKeyValue, error) {
+	var values []entities.LanguageKeyValue
+	err := db.WithContext(ctx).
+		Joins("JOIN language_key ON language_key.id = language_key_values.language_key_id").
+		Joins("JOIN language_master ON language_master.id = language_key_values.language_master_id").
+		Where("language_key_values.is_active = ? AND language_key_values.is_deleted = ?", true, false).
+		Where("language_key.is_active = ? AND language_key.is_deleted = ?", true, false).
+		Where("language_master.is_active = ? AND language_master.is_deleted = ?", true, false).
+		Preload("LanguageKey").
+		Find(&values).Error
+	return values, err
+}
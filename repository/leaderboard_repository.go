@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+// Ranking order. Ties on points go to whoever reached the score first, then to the
+// lower user ID so every position is stable between requests.
+const leaderboardOrder = "leaderboard_entries.points DESC, leaderboard_entries.last_earned_on ASC, leaderboard_entries.user_id ASC"
+
+type LeaderboardRepository interface {
+	// AddPoints adds points to the user's entry on a leaderboard, creating the entry
+	// on their first points. Negative points take points back without changing when
+	// the user last earned any.
+	AddPoints(ctx context.Context, db *gorm.DB, weekNumber int, userID string, points int, earnedOn time.Time) error
+	// FindPage returns a page of the entries shown on a leaderboard in rank order,
+	// with their users. Users who opted out or were removed are not shown.
+	FindPage(ctx context.Context, db *gorm.DB, weekNumber int, limit, offset int) ([]entities.LeaderboardEntry, int64, error)
+	FindByUserID(ctx context.Context, db *gorm.DB, weekNumber int, userID string) (*entities.LeaderboardEntry, error)
+	// CountAhead counts the shown entries ranked above the given entry
+	CountAhead(ctx context.Context, db *gorm.DB, entry *entities.LeaderboardEntry) (int64, error)
+}
+
+type leaderboardRepository struct{}
+
+func NewLeaderboardRepository() LeaderboardRepository {
+	return &leaderboardRepository{}
+}
+
+func (r *leaderboardRepository) AddPoints(ctx context.Context, db *gorm.DB, weekNumber int, userID string, points int, earnedOn time.Time) error {
+	entry := &entities.LeaderboardEntry{
+		WeekNumber:   weekNumber,
+		UserID:       userID,
+		Points:       points,
+		LastEarnedOn: earnedOn,
+	}
+	return db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "week_number"}, {Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"points":         gorm.Expr("leaderboard_entries.points + EXCLUDED.points"),
+				"last_earned_on": gorm.Expr("CASE WHEN EXCLUDED.points > 0 THEN EXCLUDED.last_earned_on ELSE leaderboard_entries.last_earned_on END"),
+				"updated_on":     gorm.Expr("EXCLUDED.updated_on"),
+			}),
+		}).
+		Create(entry).Error
+}
+
+func (r *leaderboardRepository) FindPage(ctx context.Context, db *gorm.DB, weekNumber int, limit, offset int) ([]entities.LeaderboardEntry, int64, error) {
+	query := r.shown(ctx, db, weekNumber).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entities.LeaderboardEntry
+	err := query.
+		Preload("User.Avatar").
+		Order(leaderboardOrder).
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *leaderboardRepository) FindByUserID(ctx context.Context, db *gorm.DB, weekNumber int, userID string) (*entities.LeaderboardEntry, error) {
+	var entry entities.LeaderboardEntry
+	err := db.WithContext(ctx).
+		Where("week_number = ? AND user_id = ?", weekNumber, userID).
+		First(&entry).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *leaderboardRepository) CountAhead(ctx context.Context, db *gorm.DB, entry *entities.LeaderboardEntry) (int64, error) {
+	var count int64
+	err := r.shown(ctx, db, entry.WeekNumber).
+		Where(
+			"leaderboard_entries.points > ? OR (leaderboard_entries.points = ? AND leaderboard_entries.last_earned_on < ?) OR (leaderboard_entries.points = ? AND leaderboard_entries.last_earned_on = ? AND leaderboard_entries.user_id < ?)",
+			entry.Points,
+			entry.Points, entry.LastEarnedOn,
+			entry.Points, entry.LastEarnedOn, entry.UserID,
+		).
+		Count(&count).Error
+	return count, err
+}
+
+// shown scopes a leaderboard to the entries of active users who have not opted out
+func (r *leaderboardRepository) shown(ctx context.Context, db *gorm.DB, weekNumber int) *gorm.DB {
+	return db.WithContext(ctx).
+		Model(&entities.LeaderboardEntry{}).
+		Joins("JOIN users ON users.id = leaderboard_entries.user_id").
+		Where("leaderboard_entries.week_number = ?", weekNumber).
+		Where("users.is_active = ? AND users.deleted_at IS NULL AND users.leaderboard_opt_out = ?", true, false)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"gorm.io/gorm"
+)
+
+type MediaAssetRepository interface {
+	GenericRepository[entities.MediaAsset]
+	FindWithRenditions(ctx context.Context, db *gorm.DB, id int) (*entities.MediaAsset, error)
+	ReplaceRenditions(ctx context.Context, db *gorm.DB, parentID int, renditions []entities.MediaAsset) error
+	FindReadyRenditions(ctx context.Context, db *gorm.DB, parentIDs []int, rendition string) ([]entities.MediaAsset, error)
+}
+
+type mediaAssetRepository struct {
+	*GormRepository[entities.MediaAsset]
+}
+
+func NewMediaAssetRepository() MediaAssetRepository {
+	return &mediaAssetRepository{
+		GormRepository: NewGormRepository[entities.MediaAsset](),
+	}
+}
+
+func (r *mediaAssetRepository) FindWithRenditions(ctx context.Context, db *gorm.DB, id int) (*entities.MediaAsset, error) {
+	var asset entities.MediaAsset
+	err := db.WithContext(ctx).
+		Preload("Renditions").
+		First(&asset, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// ReplaceRenditions swaps the derived renditions of an asset for a freshly generated set
+func (r *mediaAssetRepository) ReplaceRenditions(ctx context.Context, db *gorm.DB, parentID int, renditions []entities.MediaAsset) error {
+	if err := db.WithContext(ctx).Where("parent_id = ?", parentID).Delete(&entities.MediaAsset{}).Error; err != nil {
+		return err
+	}
+	if len(renditions) == 0 {
+		return nil
+	}
+	for i := range renditions {
+		renditions[i].ParentID = &parentID
+	}
+	return db.WithContext(ctx).Create(&renditions).Error
+}
+
+// FindReadyRenditions returns the named rendition of each of the given originals
+// that has one, in a single query
+func (r *mediaAssetRepository) FindReadyRenditions(ctx context.Context, db *gorm.DB, parentIDs []int, rendition string) ([]entities.MediaAsset, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+
+	var renditions []entities.MediaAsset
+	err := db.WithContext(ctx).
+		Where("parent_id IN ? AND rendition = ? AND status = ?", parentIDs, rendition, entities.MediaAssetStatusReady).
+		Find(&renditions).Error
+	if err != nil {
+		return nil, err
+	}
+	return renditions, nil
+}
@@ -14,6 +14,7 @@ type OptionMasterLanguageRepository interface {
 	FindByOptionMasterIDAndLanguageID(ctx context.Context, tx *gorm.DB, optionMasterID int, languageID int) (*entities.OptionMasterLanguage, error)
 	FindActiveByLanguageID(ctx context.Context, tx *gorm.DB, languageID int) ([]entities.OptionMasterLanguage, error)
 	FindByOptionMasterIDsAndLanguageID(ctx context.Context, tx *gorm.DB, optionMasterIDs []int, languageID int) ([]entities.OptionMasterLanguage, error)
+	FindByOptionMasterIDs(ctx context.Context, tx *gorm.DB, optionMasterIDs []int) ([]entities.OptionMasterLanguage, error)
 	Create(ctx context.Context, tx *gorm.DB, optionLanguage *entities.OptionMasterLanguage) error
 	Update(ctx context.Context, tx *gorm.DB, optionLanguage *entities.OptionMasterLanguage) error
 	Delete(ctx context.Context, tx *gorm.DB, id int) error
@@ -83,6 +84,17 @@ func (r *optionMasterLanguageRepository) FindByOptionMasterIDsAndLanguageID(ctx
 	return optionLanguages, nil
 }
 
+func (r *optionMasterLanguageRepository) FindByOptionMasterIDs(ctx context.Context, tx *gorm.DB, optionMasterIDs []int) ([]entities.OptionMasterLanguage, error) {
+	var optionLanguages []entities.OptionMasterLanguage
+	if len(optionMasterIDs) == 0 {
+		return optionLanguages, nil
+	}
+	if err := tx.WithContext(ctx).Where("option_master_id IN ? AND is_deleted = false", optionMasterIDs).Order("language_id ASC").Find(&optionLanguages).Error; err != nil {
+		return nil, err
+	}
+	return optionLanguages, nil
+}
+
 func (r *optionMasterLanguageRepository) Create(ctx context.Context, tx *gorm.DB, optionLanguage *entities.OptionMasterLanguage) error {
 	return tx.Create(optionLanguage).Error
 }
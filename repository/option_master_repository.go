@@ -17,6 +17,7 @@ type OptionMasterRepository interface {
 	Delete(ctx context.Context, tx *gorm.DB, id int) error
 	DeleteByQuestionID(ctx context.Context, tx *gorm.DB, questionID int) error
 	FindByQuestionIDs(ctx context.Context, tx *gorm.DB, questionIDs []int) ([]entities.OptionMaster, error)
+	FindActiveByQuestionIDs(ctx context.Context, tx *gorm.DB, questionIDs []int) ([]entities.OptionMaster, error)
 }
 
 type optionMasterRepository struct {
@@ -80,6 +81,18 @@ func (r *optionMasterRepository) DeleteByQuestionID(ctx context.Context, tx *gor
 	return tx.Model(&entities.OptionMaster{}).Where("question_master_id = ?", questionID).Update("is_deleted", true).Error
 }
 
+func (r *optionMasterRepository) FindActiveByQuestionIDs(ctx context.Context, tx *gorm.DB, questionIDs []int) ([]entities.OptionMaster, error) {
+	var options []entities.OptionMaster
+	if len(questionIDs) == 0 {
+		return options, nil
+	}
+	err := tx.WithContext(ctx).
+		Where("question_master_id IN ? AND is_active = true AND is_deleted = false", questionIDs).
+		Order("display_order ASC, id ASC").
+		Find(&options).Error
+	return options, err
+}
+
 func (r *optionMasterRepository) FindByQuestionIDs(ctx context.Context, tx *gorm.DB, questionIDs []int) ([]entities.OptionMaster, error) {
 	db := r.db
 	if tx != nil {
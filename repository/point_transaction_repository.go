@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type PointTransactionRepository interface {
+	// LockBalance locks the user's row for the rest of the transaction and returns
+	// the current balance, so concurrent awards to one user apply one after another
+	LockBalance(ctx context.Context, db *gorm.DB, userID string) (int, error)
+	// Balance returns the user's current balance without locking
+	Balance(ctx context.Context, db *gorm.DB, userID string) (int, error)
+	// Append inserts a ledger entry unless one exists for the same user, source type
+	// and source ID, reporting whether it was inserted
+	Append(ctx context.Context, db *gorm.DB, entry *entities.PointTransaction) (bool, error)
+	// FindBySource returns the user's entry for a source type and ID, or nil when there is none
+	FindBySource(ctx context.Context, db *gorm.DB, userID, sourceType, sourceID string) (*entities.PointTransaction, error)
+	FindByUserID(ctx context.Context, db *gorm.DB, userID string, limit, offset int) ([]entities.PointTransaction, int64, error)
+}
+
+type pointTransactionRepository struct{}
+
+func NewPointTransactionRepository() PointTransactionRepository {
+	return &pointTransactionRepository{}
+}
+
+func (r *pointTransactionRepository) LockBalance(ctx context.Context, db *gorm.DB, userID string) (int, error) {
+	var user entities.User
+	err := db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		Take(&user).Error
+	if err != nil {
+		return 0, err
+	}
+	return r.Balance(ctx, db, userID)
+}
+
+func (r *pointTransactionRepository) Balance(ctx context.Context, db *gorm.DB, userID string) (int, error) {
+	var balances []int
+	err := db.WithContext(ctx).
+		Model(&entities.PointTransaction{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(1).
+		Pluck("balance_after", &balances).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(balances) == 0 {
+		return 0, nil
+	}
+	return balances[0], nil
+}
+
+func (r *pointTransactionRepository) Append(ctx context.Context, db *gorm.DB, entry *entities.PointTransaction) (bool, error) {
+	result := db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(entry)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *pointTransactionRepository) FindBySource(ctx context.Context, db *gorm.DB, userID, sourceType, sourceID string) (*entities.PointTransaction, error) {
+	var entry entities.PointTransaction
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND source_type = ? AND source_id = ?", userID, sourceType, sourceID).
+		Take(&entry).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *pointTransactionRepository) FindByUserID(ctx context.Context, db *gorm.DB, userID string, limit, offset int) ([]entities.PointTransaction, int64, error) {
+	query := db.WithContext(ctx).
+		Model(&entities.PointTransaction{}).
+		Where("user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entities.PointTransaction
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
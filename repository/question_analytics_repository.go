@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+// QuestionAnswerStatFilter narrows question analytics to a set of segments. Zero
+// values and nil fields do not filter; cohorts are YYYY-MM and inclusive.
+type QuestionAnswerStatFilter struct {
+	QuestionID  int
+	StateID     *int
+	CohortFrom  string
+	CohortTo    string
+	Participant *bool
+}
+
+// QuestionAnswerTotal is the number of users who answered a question, or picked
+// one of its options or rating values, across the filtered segments
+type QuestionAnswerTotal struct {
+	QuestionMasterID int
+	OptionID         *int
+	RatingValue      *int
+	Users            int
+}
+
+type QuestionAnalyticsRepository interface {
+	// Refresh replaces every stat with counts computed from the current answers.
+	// It must run in a transaction so readers keep seeing the previous stats
+	// until it commits.
+	Refresh(ctx context.Context, tx *gorm.DB, refreshedOn time.Time) (int64, error)
+	SumStats(ctx context.Context, db *gorm.DB, filter QuestionAnswerStatFilter) ([]QuestionAnswerTotal, error)
+	// LastRefreshedOn returns when the stats were last rebuilt, or nil when they are empty
+	LastRefreshedOn(ctx context.Context, db *gorm.DB) (*time.Time, error)
+}
+
+type questionAnalyticsRepository struct{}
+
+func NewQuestionAnalyticsRepository() QuestionAnalyticsRepository {
+	return &questionAnalyticsRepository{}
+}
+
+// answerSegmentsQuery places every answering user in a segment and is shared by
+// the respondent and distribution counts. A user with several default addresses
+// is counted under the newest one.
+const answerSegmentsQuery = `
+WITH segments AS (
+	SELECT u.id::text AS user_id,
+		COALESCE((
+			SELECT a.state_id FROM address a
+			WHERE a.user_id = u.id AND a.is_default AND a.is_active AND NOT a.is_deleted
+			ORDER BY a.id DESC LIMIT 1
+		), 0) AS state_id,
+		to_char(u.created_at, 'YYYY-MM') AS cohort,
+		EXISTS (
+			SELECT 1 FROM thunder_seat t WHERE t.user_id = u.id AND t.withdrawn_on IS NULL
+		) AS participant
+	FROM users u
+	WHERE u.deleted_at IS NULL
+),
+answers AS (
+	SELECT qa.question_master_id, qa.user_id, qa.option_id, qa.rating_answer,
+		s.state_id, s.cohort, s.participant
+	FROM user_question_answer qa
+	JOIN segments s ON s.user_id = qa.user_id
+	WHERE qa.is_active AND NOT qa.is_deleted
+)
+`
+
+const refreshRespondentStatsQuery = answerSegmentsQuery + `
+INSERT INTO question_answer_stats (question_master_id, state_id, cohort, participant, users, refreshed_on)
+SELECT question_master_id, state_id, cohort, participant, COUNT(DISTINCT user_id), CAST(? AS timestamptz)
+FROM answers
+GROUP BY question_master_id, state_id, cohort, participant
+`
+
+const refreshDistributionStatsQuery = answerSegmentsQuery + `
+INSERT INTO question_answer_stats (question_master_id, option_id, rating_value, state_id, cohort, participant, users, refreshed_on)
+SELECT question_master_id, option_id, rating_answer, state_id, cohort, participant, COUNT(DISTINCT user_id), CAST(? AS timestamptz)
+FROM answers
+WHERE option_id IS NOT NULL OR rating_answer IS NOT NULL
+GROUP BY question_master_id, option_id, rating_answer, state_id, cohort, participant
+`
+
+func (r *questionAnalyticsRepository) Refresh(ctx context.Context, tx *gorm.DB, refreshedOn time.Time) (int64, error) {
+	db := tx.WithContext(ctx)
+	// Readers are not blocked, but a concurrent refresh waits instead of
+	// inserting its rows next to ours
+	if err := db.Exec("LOCK TABLE question_answer_stats IN EXCLUSIVE MODE").Error; err != nil {
+		return 0, err
+	}
+	if err := db.Exec("DELETE FROM question_answer_stats").Error; err != nil {
+		return 0, err
+	}
+
+	respondents := db.Exec(refreshRespondentStatsQuery, refreshedOn)
+	if respondents.Error != nil {
+		return 0, respondents.Error
+	}
+	distribution := db.Exec(refreshDistributionStatsQuery, refreshedOn)
+	if distribution.Error != nil {
+		return 0, distribution.Error
+	}
+	return respondents.RowsAffected + distribution.RowsAffected, nil
+}
+
+func (r *questionAnalyticsRepository) SumStats(ctx context.Context, db *gorm.DB, filter QuestionAnswerStatFilter) ([]QuestionAnswerTotal, error) {
+	query := db.WithContext(ctx).
+		Model(&entities.QuestionAnswerStat{}).
+		Select("question_master_id, option_id, rating_value, SUM(users) AS users")
+	if filter.QuestionID > 0 {
+		query = query.Where("question_master_id = ?", filter.QuestionID)
+	}
+	if filter.StateID != nil {
+		query = query.Where("state_id = ?", *filter.StateID)
+	}
+	if filter.CohortFrom != "" {
+		query = query.Where("cohort >= ?", filter.CohortFrom)
+	}
+	if filter.CohortTo != "" {
+		query = query.Where("cohort <= ?", filter.CohortTo)
+	}
+	if filter.Participant != nil {
+		query = query.Where("participant = ?", *filter.Participant)
+	}
+
+	var totals []QuestionAnswerTotal
+	err := query.
+		Group("question_master_id, option_id, rating_value").
+		Order("question_master_id ASC, option_id ASC NULLS FIRST, rating_value ASC NULLS FIRST").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *questionAnalyticsRepository) LastRefreshedOn(ctx context.Context, db *gorm.DB) (*time.Time, error) {
+	var stat entities.QuestionAnswerStat
+	err := db.WithContext(ctx).Select("refreshed_on").Order("refreshed_on DESC").Limit(1).Find(&stat).Error
+	if err != nil {
+		return nil, err
+	}
+	if stat.RefreshedOn.IsZero() {
+		return nil, nil
+	}
+	return &stat.RefreshedOn, nil
+}
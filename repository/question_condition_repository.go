@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type QuestionConditionRepository interface {
+	FindByQuestionIDs(ctx context.Context, db *gorm.DB, questionIDs []int) ([]entities.QuestionCondition, error)
+	FindAll(ctx context.Context, db *gorm.DB) ([]entities.QuestionCondition, error)
+	// ReplaceForQuestion replaces every condition of a question
+	ReplaceForQuestion(ctx context.Context, db *gorm.DB, questionID int, conditions []entities.QuestionCondition) error
+}
+
+type questionConditionRepository struct{}
+
+func NewQuestionConditionRepository() QuestionConditionRepository {
+	return &questionConditionRepository{}
+}
+
+func (r *questionConditionRepository) FindByQuestionIDs(ctx context.Context, db *gorm.DB, questionIDs []int) ([]entities.QuestionCondition, error) {
+	var conditions []entities.QuestionCondition
+	if len(questionIDs) == 0 {
+		return conditions, nil
+	}
+	err := db.WithContext(ctx).
+		Where("question_master_id IN ?", questionIDs).
+		Order("id ASC").
+		Find(&conditions).Error
+	return conditions, err
+}
+
+func (r *questionConditionRepository) FindAll(ctx context.Context, db *gorm.DB) ([]entities.QuestionCondition, error) {
+	var conditions []entities.QuestionCondition
+	err := db.WithContext(ctx).Order("id ASC").Find(&conditions).Error
+	return conditions, err
+}
+
+func (r *questionConditionRepository) ReplaceForQuestion(ctx context.Context, db *gorm.DB, questionID int, conditions []entities.QuestionCondition) error {
+	if err := db.WithContext(ctx).Where("question_master_id = ?", questionID).Delete(&entities.QuestionCondition{}).Error; err != nil {
+		return err
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	for i := range conditions {
+		conditions[i].ID = 0
+		conditions[i].QuestionMasterID = questionID
+	}
+	return db.WithContext(ctx).Create(&conditions).Error
+}
@@ -12,6 +12,7 @@ type QuestionMasterLanguageRepository interface {
 	FindByQuestionMasterID(ctx context.Context, tx *gorm.DB, questionMasterID int) ([]entities.QuestionMasterLanguage, error)
 	FindByLanguageID(ctx context.Context, tx *gorm.DB, languageID int) ([]entities.QuestionMasterLanguage, error)
 	FindByQuestionMasterIDAndLanguageID(ctx context.Context, tx *gorm.DB, questionMasterID int, languageID int) (*entities.QuestionMasterLanguage, error)
+	FindByQuestionMasterIDsAndLanguageID(ctx context.Context, tx *gorm.DB, questionMasterIDs []int, languageID int) ([]entities.QuestionMasterLanguage, error)
 	FindActiveByLanguageID(ctx context.Context, tx *gorm.DB, languageID int) ([]entities.QuestionMasterLanguage, error)
 	FindByQuestionTextAndLanguageID(ctx context.Context, tx *gorm.DB, questionText string, languageID int) (*entities.QuestionMasterLanguage, error)
 	Create(ctx context.Context, tx *gorm.DB, questionLanguage *entities.QuestionMasterLanguage) error
@@ -67,6 +68,17 @@ func (r *questionMasterLanguageRepository) FindByQuestionMasterIDAndLanguageID(c
 	return &questionLanguage, nil
 }
 
+func (r *questionMasterLanguageRepository) FindByQuestionMasterIDsAndLanguageID(ctx context.Context, tx *gorm.DB, questionMasterIDs []int, languageID int) ([]entities.QuestionMasterLanguage, error) {
+	var questionLanguages []entities.QuestionMasterLanguage
+	if len(questionMasterIDs) == 0 {
+		return questionLanguages, nil
+	}
+	if err := tx.WithContext(ctx).Where("question_master_id IN ? AND language_id = ? AND is_deleted = false", questionMasterIDs, languageID).Find(&questionLanguages).Error; err != nil {
+		return nil, err
+	}
+	return questionLanguages, nil
+}
+
 func (r *questionMasterLanguageRepository) FindActiveByLanguageID(ctx context.Context, tx *gorm.DB, languageID int) ([]entities.QuestionMasterLanguage, error) {
 	var questionLanguages []entities.QuestionMasterLanguage
 	if err := tx.Where("language_id = ? AND is_active = true AND is_deleted = false", languageID).Find(&questionLanguages).Error; err != nil {
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type QuestionRepository interface {
@@ -12,7 +14,15 @@ type QuestionRepository interface {
 	FindActiveQuestions(ctx context.Context, db *gorm.DB, limit, offset int) ([]entities.QuestionMaster, error)
 	FindByLanguageID(ctx context.Context, db *gorm.DB, languageID int, limit, offset int) ([]entities.QuestionMaster, error)
 	FindByIDTx(ctx context.Context, tx *gorm.DB, id int) (*entities.QuestionMaster, error)
+	// LockByID loads the question and locks it for the rest of the transaction
+	LockByID(ctx context.Context, tx *gorm.DB, id int) (*entities.QuestionMaster, error)
 	FindActive(ctx context.Context, tx *gorm.DB) ([]entities.QuestionMaster, error)
+	// FindPublishedProfileQuestions returns published profile questions whatever
+	// their publish window, for callers that check the window themselves
+	FindPublishedProfileQuestions(ctx context.Context, db *gorm.DB) ([]entities.QuestionMaster, error)
+	// FindTranslatable returns every active question, drafts and scheduled ones
+	// included, in ID order
+	FindTranslatable(ctx context.Context, db *gorm.DB) ([]entities.QuestionMaster, error)
 	FindByQuestionTextAndLanguageID(ctx context.Context, tx *gorm.DB, questionText string, languageID int) (*entities.QuestionMaster, error)
 }
 
@@ -28,7 +38,7 @@ func NewQuestionRepository() QuestionRepository {
 
 func (r *questionRepository) FindActiveQuestions(ctx context.Context, db *gorm.DB, limit, offset int) ([]entities.QuestionMaster, error) {
 	var questions []entities.QuestionMaster
-	query := db.WithContext(ctx).Where("is_active = ? AND is_deleted = ?", true, false)
+	query := db.WithContext(ctx).Where("is_active = ? AND is_deleted = ?", true, false).Scopes(livePublishWindow(time.Now()))
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -40,7 +50,7 @@ func (r *questionRepository) FindActiveQuestions(ctx context.Context, db *gorm.D
 
 func (r *questionRepository) FindByLanguageID(ctx context.Context, db *gorm.DB, languageID int, limit, offset int) ([]entities.QuestionMaster, error) {
 	var questions []entities.QuestionMaster
-	query := db.WithContext(ctx).Where("language_id = ? AND is_active = ? AND is_deleted = ?", languageID, true, false)
+	query := db.WithContext(ctx).Where("language_id = ? AND is_active = ? AND is_deleted = ?", languageID, true, false).Scopes(livePublishWindow(time.Now()))
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -61,14 +71,47 @@ func (r *questionRepository) FindByIDTx(ctx context.Context, tx *gorm.DB, id int
 	return &question, nil
 }
 
+func (r *questionRepository) LockByID(ctx context.Context, tx *gorm.DB, id int) (*entities.QuestionMaster, error) {
+	var question entities.QuestionMaster
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND is_deleted = false", id).
+		First(&question).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
 func (r *questionRepository) FindActive(ctx context.Context, tx *gorm.DB) ([]entities.QuestionMaster, error) {
 	var questions []entities.QuestionMaster
-	if err := tx.Where("is_active = true AND is_deleted = false AND profile_only = true").Find(&questions).Error; err != nil {
+	if err := tx.Where("is_active = true AND is_deleted = false AND profile_only = true").Scopes(livePublishWindow(time.Now())).Find(&questions).Error; err != nil {
 		return nil, err
 	}
 	return questions, nil
 }
 
+func (r *questionRepository) FindPublishedProfileQuestions(ctx context.Context, db *gorm.DB) ([]entities.QuestionMaster, error) {
+	var questions []entities.QuestionMaster
+	err := db.WithContext(ctx).
+		Where("is_active = ? AND is_deleted = ? AND profile_only = ?", true, false, true).
+		Where("status = ?", entities.QuestionStatusPublished).
+		Find(&questions).Error
+	return questions, err
+}
+
+func (r *questionRepository) FindTranslatable(ctx context.Context, db *gorm.DB) ([]entities.QuestionMaster, error) {
+	var questions []entities.QuestionMaster
+	err := db.WithContext(ctx).
+		Where("is_active = ? AND is_deleted = ?", true, false).
+		Order("id ASC").
+		Find(&questions).Error
+	return questions, err
+}
+
 func (r *questionRepository) FindByQuestionTextAndLanguageID(ctx context.Context, tx *gorm.DB, questionText string, languageID int) (*entities.QuestionMaster, error) {
 	var question entities.QuestionMaster
 	if err := tx.Where("question_text = ? AND language_id = ? AND is_deleted = false", questionText, languageID).First(&question).Error; err != nil {
@@ -79,3 +122,13 @@ func (r *questionRepository) FindByQuestionTextAndLanguageID(ctx context.Context
 	}
 	return &question, nil
 }
+
+// livePublishWindow limits questions to published ones whose publish window
+// includes now; see entities.QuestionMaster.IsLive
+func livePublishWindow(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", entities.QuestionStatusPublished).
+			Where("publish_at IS NULL OR publish_at <= ?", now).
+			Where("unpublish_at IS NULL OR unpublish_at > ?", now)
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type QuestionSectionRepository interface {
+	GenericRepository[entities.QuestionSection]
+	// FindOrdered returns the sections that are not deleted in display order,
+	// optionally only the active ones
+	FindOrdered(ctx context.Context, db *gorm.DB, activeOnly bool) ([]entities.QuestionSection, error)
+}
+
+type questionSectionRepository struct {
+	*GormRepository[entities.QuestionSection]
+}
+
+func NewQuestionSectionRepository() QuestionSectionRepository {
+	return &questionSectionRepository{
+		GormRepository: NewGormRepository[entities.QuestionSection](),
+	}
+}
+
+func (r *questionSectionRepository) FindOrdered(ctx context.Context, db *gorm.DB, activeOnly bool) ([]entities.QuestionSection, error) {
+	query := db.WithContext(ctx).Where("is_deleted = ?", false)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var sections []entities.QuestionSection
+	if err := query.Order("display_order ASC, id ASC").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type QuestionSubmissionRepository interface {
+	GenericRepository[entities.QuestionSubmission]
+	CountPendingByUserID(ctx context.Context, db *gorm.DB, userID string) (int64, error)
+	// FindByUserID returns the user's submissions, newest first
+	FindByUserID(ctx context.Context, db *gorm.DB, userID string, limit, offset int) ([]entities.QuestionSubmission, int64, error)
+	// FindForReview returns submissions oldest first with their submitters; an
+	// empty status returns every submission
+	FindForReview(ctx context.Context, db *gorm.DB, status string, limit, offset int) ([]entities.QuestionSubmission, int64, error)
+	// LockByID loads the submission and locks it for the rest of the transaction
+	LockByID(ctx context.Context, tx *gorm.DB, id int) (*entities.QuestionSubmission, error)
+}
+
+type questionSubmissionRepository struct {
+	*GormRepository[entities.QuestionSubmission]
+}
+
+func NewQuestionSubmissionRepository() QuestionSubmissionRepository {
+	return &questionSubmissionRepository{
+		GormRepository: NewGormRepository[entities.QuestionSubmission](),
+	}
+}
+
+func (r *questionSubmissionRepository) CountPendingByUserID(ctx context.Context, db *gorm.DB, userID string) (int64, error) {
+	var count int64
+	err := db.WithContext(ctx).
+		Model(&entities.QuestionSubmission{}).
+		Where("user_id = ? AND status = ?", userID, entities.QuestionSubmissionStatusPending).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *questionSubmissionRepository) FindByUserID(ctx context.Context, db *gorm.DB, userID string, limit, offset int) ([]entities.QuestionSubmission, int64, error) {
+	query := db.WithContext(ctx).
+		Model(&entities.QuestionSubmission{}).
+		Where("user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var submissions []entities.QuestionSubmission
+	if err := query.
+		Order("created_on DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&submissions).Error; err != nil {
+		return nil, 0, err
+	}
+	return submissions, total, nil
+}
+
+func (r *questionSubmissionRepository) FindForReview(ctx context.Context, db *gorm.DB, status string, limit, offset int) ([]entities.QuestionSubmission, int64, error) {
+	query := db.WithContext(ctx).Model(&entities.QuestionSubmission{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var submissions []entities.QuestionSubmission
+	if err := query.
+		Preload("User").
+		Order("created_on ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&submissions).Error; err != nil {
+		return nil, 0, err
+	}
+	return submissions, total, nil
+}
+
+func (r *questionSubmissionRepository) LockByID(ctx context.Context, tx *gorm.DB, id int) (*entities.QuestionSubmission, error) {
+	var submission entities.QuestionSubmission
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&submission).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
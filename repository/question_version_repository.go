@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type QuestionVersionRepository interface {
+	// FindByQuestionID returns every version of a question with its options, newest first
+	FindByQuestionID(ctx context.Context, db *gorm.DB, questionID int) ([]entities.QuestionVersion, error)
+	FindByVersion(ctx context.Context, db *gorm.DB, questionID, version int) (*entities.QuestionVersion, error)
+	FindDraft(ctx context.Context, db *gorm.DB, questionID int) (*entities.QuestionVersion, error)
+	LatestVersionNumber(ctx context.Context, db *gorm.DB, questionID int) (int, error)
+	// Create inserts the version together with its options
+	Create(ctx context.Context, db *gorm.DB, version *entities.QuestionVersion) error
+	// Update saves the version's own columns; options are saved separately
+	Update(ctx context.Context, db *gorm.DB, version *entities.QuestionVersion) error
+	ReplaceOptions(ctx context.Context, db *gorm.DB, versionID int, options []entities.OptionVersion) error
+	UpdateOption(ctx context.Context, db *gorm.DB, option *entities.OptionVersion) error
+	// SupersedePublished marks the question's published versions other than keepID as superseded
+	SupersedePublished(ctx context.Context, db *gorm.DB, questionID, keepID int) error
+}
+
+type questionVersionRepository struct{}
+
+func NewQuestionVersionRepository() QuestionVersionRepository {
+	return &questionVersionRepository{}
+}
+
+func (r *questionVersionRepository) FindByQuestionID(ctx context.Context, db *gorm.DB, questionID int) ([]entities.QuestionVersion, error) {
+	var versions []entities.QuestionVersion
+	err := db.WithContext(ctx).
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("display_order ASC, id ASC")
+		}).
+		Where("question_master_id = ?", questionID).
+		Order("version DESC").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *questionVersionRepository) FindByVersion(ctx context.Context, db *gorm.DB, questionID, version int) (*entities.QuestionVersion, error) {
+	return r.findOne(ctx, db.Where("question_master_id = ? AND version = ?", questionID, version))
+}
+
+func (r *questionVersionRepository) FindDraft(ctx context.Context, db *gorm.DB, questionID int) (*entities.QuestionVersion, error) {
+	return r.findOne(ctx, db.Where("question_master_id = ? AND status = ?", questionID, entities.QuestionVersionStatusDraft))
+}
+
+func (r *questionVersionRepository) LatestVersionNumber(ctx context.Context, db *gorm.DB, questionID int) (int, error) {
+	var latest int
+	err := db.WithContext(ctx).
+		Model(&entities.QuestionVersion{}).
+		Where("question_master_id = ?", questionID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	return latest, err
+}
+
+func (r *questionVersionRepository) Create(ctx context.Context, db *gorm.DB, version *entities.QuestionVersion) error {
+	return db.WithContext(ctx).Create(version).Error
+}
+
+func (r *questionVersionRepository) Update(ctx context.Context, db *gorm.DB, version *entities.QuestionVersion) error {
+	return db.WithContext(ctx).Omit("Options").Save(version).Error
+}
+
+func (r *questionVersionRepository) ReplaceOptions(ctx context.Context, db *gorm.DB, versionID int, options []entities.OptionVersion) error {
+	if err := db.WithContext(ctx).Where("question_version_id = ?", versionID).Delete(&entities.OptionVersion{}).Error; err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	for i := range options {
+		options[i].ID = 0
+		options[i].QuestionVersionID = versionID
+	}
+	return db.WithContext(ctx).Create(&options).Error
+}
+
+func (r *questionVersionRepository) UpdateOption(ctx context.Context, db *gorm.DB, option *entities.OptionVersion) error {
+	return db.WithContext(ctx).Save(option).Error
+}
+
+func (r *questionVersionRepository) SupersedePublished(ctx context.Context, db *gorm.DB, questionID, keepID int) error {
+	return db.WithContext(ctx).
+		Model(&entities.QuestionVersion{}).
+		Where("question_master_id = ? AND status = ? AND id <> ?", questionID, entities.QuestionVersionStatusPublished, keepID).
+		Update("status", entities.QuestionVersionStatusSuperseded).Error
+}
+
+func (r *questionVersionRepository) findOne(ctx context.Context, query *gorm.DB) (*entities.QuestionVersion, error) {
+	var version entities.QuestionVersion
+	err := query.WithContext(ctx).
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("display_order ASC, id ASC")
+		}).
+		First(&version).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+// StorageReferenceRepository finds every stored object the database still points at
+type StorageReferenceRepository interface {
+	// FindReferencedObjects returns the object paths referenced by any table. Older
+	// rows may hold a storage URL instead of a path; callers normalize those.
+	FindReferencedObjects(ctx context.Context, db *gorm.DB) ([]string, error)
+}
+
+type storageReferenceRepository struct{}
+
+func NewStorageReferenceRepository() StorageReferenceRepository {
+	return &storageReferenceRepository{}
+}
+
+// Submission media lives at thunder-seat/{user}/week-{n}/{media_key}; revisions are
+// kept as an audit trail, so their media stays referenced as well. Pending upload
+// sessions reference objects that are about to be finalized. Inactive profile
+// photos were replaced or removed by their owner.
+const referencedObjectsQuery = `
+SELECT 'thunder-seat/' || ts.user_id::text || '/week-' || ts.week_number::text || '/' || ts.media_key
+	FROM thunder_seat ts WHERE ts.media_key IS NOT NULL AND ts.media_key <> ''
+UNION SELECT ts.media_url FROM thunder_seat ts WHERE ts.media_url IS NOT NULL AND ts.media_url <> ''
+UNION SELECT 'thunder-seat/' || ts.user_id::text || '/week-' || ts.week_number::text || '/' || r.media_key
+	FROM thunder_seat_revision r JOIN thunder_seat ts ON ts.id = r.thunder_seat_id
+	WHERE r.media_key IS NOT NULL AND r.media_key <> ''
+UNION SELECT r.media_url FROM thunder_seat_revision r WHERE r.media_url IS NOT NULL AND r.media_url <> ''
+UNION SELECT u.object_path FROM upload_session u WHERE u.status = ?
+UNION SELECT m.object_path FROM media_assets m
+UNION SELECT 'avatars/' || a.created_by || '/' || a.image_key FROM avatar a
+UNION SELECT p.full_path FROM user_profile_photo p WHERE p.is_active
+UNION SELECT p.thumb_path FROM user_profile_photo p WHERE p.is_active
+UNION SELECT w.qr_code FROM thunder_seat_winner w WHERE w.qr_code <> ''
+UNION SELECT c.aadhar_front_key FROM user_adhar_cards c WHERE c.aadhar_front_key <> ''
+UNION SELECT c.aadhar_back_key FROM user_adhar_cards c WHERE c.aadhar_back_key <> ''
+`
+
+func (r *storageReferenceRepository) FindReferencedObjects(ctx context.Context, db *gorm.DB) ([]string, error) {
+	var paths []string
+	err := db.WithContext(ctx).Raw(referencedObjectsQuery, entities.UploadSessionStatusPending).Scan(&paths).Error
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"gorm.io/gorm"
+)
+
+type ThunderSeatReportRepository interface {
+	GenericRepository[entities.ThunderSeatReport]
+}
+
+type thunderSeatReportRepository struct {
+	*GormRepository[entities.ThunderSeatReport]
+}
+
+func NewThunderSeatReportRepository() ThunderSeatReportRepository {
+	return &thunderSeatReportRepository{
+		GormRepository: NewGormRepository[entities.ThunderSeatReport](),
+	}
+}
+
+// Create inserts a report, returning *errors.DuplicateError when the user already reported the submission
+func (r *thunderSeatReportRepository) Create(ctx context.Context, db *gorm.DB, entity *entities.ThunderSeatReport) error {
+	return mapDuplicateError(r.GormRepository.Create(ctx, db, entity))
+}
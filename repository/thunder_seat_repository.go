@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"gorm.io/gorm"
@@ -14,6 +15,24 @@ type ThunderSeatRepository interface {
 	CheckUserSubmission(ctx context.Context, db *gorm.DB, userID string, questionID int) (*entities.ThunderSeat, error)
 	GetRandomEntries(ctx context.Context, db *gorm.DB, limit int, excludeUserIDs []string) ([]entities.ThunderSeat, error)
 	GetRandomEntriesByWeek(ctx context.Context, db *gorm.DB, weekNumber int, limit int, excludeUserIDs []string) ([]entities.ThunderSeat, error)
+	CountByUserAndWeek(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int64, error)
+	// CountLiveByUserAndWeek counts the user's entries in a week that are neither withdrawn nor rejected
+	CountLiveByUserAndWeek(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int64, error)
+	FindByIdempotencyKey(ctx context.Context, db *gorm.DB, userID string, idempotencyKey string) (*entities.ThunderSeat, error)
+	NextSubmissionSeq(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int, error)
+	FindForModeration(ctx context.Context, db *gorm.DB, filter ModerationQueueFilter, limit, offset int) ([]entities.ThunderSeat, int64, error)
+	UpdateModerationStatus(ctx context.Context, db *gorm.DB, ids []int, status string, reason *string, moderatedBy string) (int64, error)
+	IncrementReportCount(ctx context.Context, db *gorm.DB, id int, flagThreshold int) error
+	UpdatePendingModerationStatus(ctx context.Context, db *gorm.DB, id int, status string, reason *string, moderatedBy string) error
+	FindIDsByMediaHash(ctx context.Context, db *gorm.DB, mediaHash string, excludeID int) ([]int, error)
+}
+
+// ModerationQueueFilter narrows the moderation queue; zero values are ignored
+type ModerationQueueFilter struct {
+	Status       string
+	WeekNumber   int
+	MediaType    string
+	ReportedOnly bool
 }
 
 type thunderSeatRepository struct {
@@ -26,11 +45,16 @@ func NewThunderSeatRepository() ThunderSeatRepository {
 	}
 }
 
+// Create inserts a submission, returning *errors.DuplicateError on unique violations
+func (r *thunderSeatRepository) Create(ctx context.Context, db *gorm.DB, entity *entities.ThunderSeat) error {
+	return mapDuplicateError(r.GormRepository.Create(ctx, db, entity))
+}
+
 func (r *thunderSeatRepository) FindByUserID(ctx context.Context, db *gorm.DB, userID string) ([]entities.ThunderSeat, error) {
 	var entries []entities.ThunderSeat
 	if err := db.WithContext(ctx).
 		Preload("User.Avatar").
-		Where("user_id = ?", userID).
+		Where("user_id = ? AND withdrawn_on IS NULL", userID).
 		Find(&entries).Error; err != nil {
 		return nil, err
 	}
@@ -62,6 +86,7 @@ func (r *thunderSeatRepository) GetRandomEntries(ctx context.Context, db *gorm.D
 	subquery := db.WithContext(ctx).
 		Model(&entities.ThunderSeat{}).
 		Select("user_id").
+		Where("withdrawn_on IS NULL AND moderation_status = ?", entities.ModerationStatusApproved).
 		Group("user_id")
 	
 	if len(excludeUserIDs) > 0 {
@@ -78,7 +103,7 @@ func (r *thunderSeatRepository) GetRandomEntries(ctx context.Context, db *gorm.D
 	
 	var entries []entities.ThunderSeat
 	query := db.WithContext(ctx).
-		Where("user_id IN ?", userIDs).
+		Where("user_id IN ? AND withdrawn_on IS NULL AND moderation_status = ?", userIDs, entities.ModerationStatusApproved).
 		Order("RANDOM()")
 	
 	if err := query.Find(&entries).Error; err != nil {
@@ -106,7 +131,7 @@ func (r *thunderSeatRepository) GetRandomEntriesByWeek(ctx context.Context, db *
 	subquery := db.WithContext(ctx).
 		Model(&entities.ThunderSeat{}).
 		Select("user_id").
-		Where("week_number = ?", weekNumber).
+		Where("week_number = ? AND withdrawn_on IS NULL AND moderation_status = ?", weekNumber, entities.ModerationStatusApproved).
 		Group("user_id")
 	
 	if len(excludeUserIDs) > 0 {
@@ -123,7 +148,7 @@ func (r *thunderSeatRepository) GetRandomEntriesByWeek(ctx context.Context, db *
 	
 	var entries []entities.ThunderSeat
 	query := db.WithContext(ctx).
-		Where("week_number = ? AND user_id IN ?", weekNumber, userIDs).
+		Where("week_number = ? AND user_id IN ? AND withdrawn_on IS NULL AND moderation_status = ?", weekNumber, userIDs, entities.ModerationStatusApproved).
 		Order("RANDOM()")
 	
 	if err := query.Find(&entries).Error; err != nil {
@@ -144,3 +169,143 @@ func (r *thunderSeatRepository) GetRandomEntriesByWeek(ctx context.Context, db *
 	
 	return result, nil
 }
+
+func (r *thunderSeatRepository) CountByUserAndWeek(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int64, error) {
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("user_id = ? AND week_number = ? AND withdrawn_on IS NULL", userID, weekNumber).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *thunderSeatRepository) CountLiveByUserAndWeek(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int64, error) {
+	var count int64
+	if err := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("user_id = ? AND week_number = ? AND withdrawn_on IS NULL AND moderation_status <> ?", userID, weekNumber, entities.ModerationStatusRejected).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *thunderSeatRepository) FindByIdempotencyKey(ctx context.Context, db *gorm.DB, userID string, idempotencyKey string) (*entities.ThunderSeat, error) {
+	var entry entities.ThunderSeat
+	if err := db.WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ?", userID, idempotencyKey).
+		First(&entry).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// NextSubmissionSeq returns the sequence number for a new submission, counting withdrawn entries
+// so the (user_id, week_number, submission_seq) unique index is never reused
+func (r *thunderSeatRepository) NextSubmissionSeq(ctx context.Context, db *gorm.DB, userID string, weekNumber int) (int, error) {
+	var maxSeq int
+	if err := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Select("COALESCE(MAX(submission_seq), 0)").
+		Where("user_id = ? AND week_number = ?", userID, weekNumber).
+		Scan(&maxSeq).Error; err != nil {
+		return 0, err
+	}
+	return maxSeq + 1, nil
+}
+
+func (r *thunderSeatRepository) FindForModeration(ctx context.Context, db *gorm.DB, filter ModerationQueueFilter, limit, offset int) ([]entities.ThunderSeat, int64, error) {
+	query := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("withdrawn_on IS NULL")
+
+	if filter.Status != "" {
+		query = query.Where("moderation_status = ?", filter.Status)
+	}
+	if filter.WeekNumber > 0 {
+		query = query.Where("week_number = ?", filter.WeekNumber)
+	}
+	if filter.MediaType != "" {
+		query = query.Where("media_type = ?", filter.MediaType)
+	}
+	if filter.ReportedOnly {
+		query = query.Where("report_count > 0")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entities.ThunderSeat
+	if err := query.
+		Preload("User").
+		Order("report_count DESC, created_on ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *thunderSeatRepository) UpdateModerationStatus(ctx context.Context, db *gorm.DB, ids []int, status string, reason *string, moderatedBy string) (int64, error) {
+	result := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("id IN ? AND withdrawn_on IS NULL", ids).
+		Updates(map[string]interface{}{
+			"moderation_status": status,
+			"moderation_reason": reason,
+			"moderated_by":      moderatedBy,
+			"moderated_on":      time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// IncrementReportCount records a user report and moves pending or approved submissions
+// to flagged once the report count reaches flagThreshold
+func (r *thunderSeatRepository) IncrementReportCount(ctx context.Context, db *gorm.DB, id int, flagThreshold int) error {
+	return db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"report_count": gorm.Expr("report_count + 1"),
+			"moderation_status": gorm.Expr(
+				"CASE WHEN report_count + 1 >= ? AND moderation_status IN ? THEN ? ELSE moderation_status END",
+				flagThreshold,
+				[]string{entities.ModerationStatusPending, entities.ModerationStatusApproved},
+				entities.ModerationStatusFlagged,
+			),
+		}).Error
+}
+
+// UpdatePendingModerationStatus changes the status only while the submission is still pending,
+// so automated verdicts never override a moderator's decision
+func (r *thunderSeatRepository) UpdatePendingModerationStatus(ctx context.Context, db *gorm.DB, id int, status string, reason *string, moderatedBy string) error {
+	return db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("id = ? AND moderation_status = ?", id, entities.ModerationStatusPending).
+		Updates(map[string]interface{}{
+			"moderation_status": status,
+			"moderation_reason": reason,
+			"moderated_by":      moderatedBy,
+			"moderated_on":      time.Now(),
+		}).Error
+}
+
+func (r *thunderSeatRepository) FindIDsByMediaHash(ctx context.Context, db *gorm.DB, mediaHash string, excludeID int) ([]int, error) {
+	var ids []int
+	if err := db.WithContext(ctx).
+		Model(&entities.ThunderSeat{}).
+		Where("media_hash = ? AND id <> ? AND withdrawn_on IS NULL", mediaHash, excludeID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"gorm.io/gorm"
+)
+
+type UploadSessionRepository interface {
+	GenericRepository[entities.UploadSession]
+	ClaimPending(ctx context.Context, db *gorm.DB, id string, status string, fields map[string]interface{}) (bool, error)
+}
+
+type uploadSessionRepository struct {
+	*GormRepository[entities.UploadSession]
+}
+
+func NewUploadSessionRepository() UploadSessionRepository {
+	return &uploadSessionRepository{
+		GormRepository: NewGormRepository[entities.UploadSession](),
+	}
+}
+
+// ClaimPending moves a pending session to status together with fields. It returns
+// false when the session was no longer pending, e.g. a concurrent finalize won.
+func (r *uploadSessionRepository) ClaimPending(ctx context.Context, db *gorm.DB, id string, status string, fields map[string]interface{}) (bool, error) {
+	updates := map[string]interface{}{"status": status}
+	for key, value := range fields {
+		updates[key] = value
+	}
+
+	result := db.WithContext(ctx).
+		Model(&entities.UploadSession{}).
+		Where("id = ? AND status = ?", id, entities.UploadSessionStatusPending).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
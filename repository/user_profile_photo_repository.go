@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+)
+
+type UserProfilePhotoRepository interface {
+	GenericRepository[entities.UserProfilePhoto]
+	FindActiveByUserID(ctx context.Context, db *gorm.DB, userID string) (*entities.UserProfilePhoto, error)
+	// FindApprovedByUserIDs returns the approved active photo of each user that has one
+	FindApprovedByUserIDs(ctx context.Context, db *gorm.DB, userIDs []string) ([]entities.UserProfilePhoto, error)
+	// DeactivateByUserID retires the user's active photo, if any
+	DeactivateByUserID(ctx context.Context, db *gorm.DB, userID string) (int64, error)
+	FindForModeration(ctx context.Context, db *gorm.DB, status string, limit, offset int) ([]entities.UserProfilePhoto, int64, error)
+	UpdateModerationStatus(ctx context.Context, db *gorm.DB, ids []int, status string, reason *string, moderatedBy string) (int64, error)
+}
+
+type userProfilePhotoRepository struct {
+	*GormRepository[entities.UserProfilePhoto]
+}
+
+func NewUserProfilePhotoRepository() UserProfilePhotoRepository {
+	return &userProfilePhotoRepository{
+		GormRepository: NewGormRepository[entities.UserProfilePhoto](),
+	}
+}
+
+func (r *userProfilePhotoRepository) FindActiveByUserID(ctx context.Context, db *gorm.DB, userID string) (*entities.UserProfilePhoto, error) {
+	var photo entities.UserProfilePhoto
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("id DESC").
+		First(&photo).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &photo, nil
+}
+
+func (r *userProfilePhotoRepository) FindApprovedByUserIDs(ctx context.Context, db *gorm.DB, userIDs []string) ([]entities.UserProfilePhoto, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var photos []entities.UserProfilePhoto
+	err := db.WithContext(ctx).
+		Where("user_id IN ? AND is_active = ? AND moderation_status = ?", userIDs, true, entities.ModerationStatusApproved).
+		Find(&photos).Error
+	if err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (r *userProfilePhotoRepository) DeactivateByUserID(ctx context.Context, db *gorm.DB, userID string) (int64, error) {
+	result := db.WithContext(ctx).
+		Model(&entities.UserProfilePhoto{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Update("is_active", false)
+	return result.RowsAffected, result.Error
+}
+
+func (r *userProfilePhotoRepository) FindForModeration(ctx context.Context, db *gorm.DB, status string, limit, offset int) ([]entities.UserProfilePhoto, int64, error) {
+	query := db.WithContext(ctx).
+		Model(&entities.UserProfilePhoto{}).
+		Where("is_active = ?", true)
+	if status != "" {
+		query = query.Where("moderation_status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var photos []entities.UserProfilePhoto
+	if err := query.
+		Preload("User").
+		Order("created_on ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&photos).Error; err != nil {
+		return nil, 0, err
+	}
+	return photos, total, nil
+}
+
+// UpdateModerationStatus only touches active photos, so a decision on a photo the
+// user replaced in the meantime has no effect
+func (r *userProfilePhotoRepository) UpdateModerationStatus(ctx context.Context, db *gorm.DB, ids []int, status string, reason *string, moderatedBy string) (int64, error) {
+	result := db.WithContext(ctx).
+		Model(&entities.UserProfilePhoto{}).
+		Where("id IN ? AND is_active = ?", ids, true).
+		Updates(map[string]interface{}{
+			"moderation_status": status,
+			"moderation_reason": reason,
+			"moderated_by":      moderatedBy,
+			"moderated_on":      time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -18,6 +18,7 @@ type UserQuestionAnswerRepository interface {
 	Update(ctx context.Context, tx *gorm.DB, answer *entities.UserQuestionAnswer) error
 	Delete(ctx context.Context, tx *gorm.DB, id int) error
 	DeleteByUserID(ctx context.Context, tx *gorm.DB, userID string) error
+	DeleteByUserAndQuestionIDs(ctx context.Context, tx *gorm.DB, userID string, questionIDs []int) error
 	FindAllByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]entities.UserQuestionAnswer, error)
 }
 
@@ -97,6 +98,12 @@ func (r *userQuestionAnswerRepository) DeleteByUserID(ctx context.Context, tx *g
 	return tx.Model(&entities.UserQuestionAnswer{}).Where("user_id = ?", userID).Update("is_deleted", true).Error
 }
 
+func (r *userQuestionAnswerRepository) DeleteByUserAndQuestionIDs(ctx context.Context, tx *gorm.DB, userID string, questionIDs []int) error {
+	return tx.Model(&entities.UserQuestionAnswer{}).
+		Where("user_id = ? AND question_master_id IN ? AND is_deleted = false", userID, questionIDs).
+		Update("is_deleted", true).Error
+}
+
 func (r *userQuestionAnswerRepository) FindAllByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]entities.UserQuestionAnswer, error) {
 	var answers []entities.UserQuestionAnswer
 	if err := tx.Where("user_id = ? AND is_deleted = false", userID).Find(&answers).Error; err != nil {
@@ -7,10 +7,64 @@ import (
 	"github.com/Infinite-Locus-Product/thums_up_backend/middlewares"
 )
 
-func SetupAdminRoutes(api *gin.RouterGroup, winnerHandler *handlers.WinnerHandler) {
+func SetupAdminRoutes(
+	api *gin.RouterGroup,
+	winnerHandler *handlers.WinnerHandler,
+	contestWeekHandler *handlers.ContestWeekHandler,
+	moderationHandler *handlers.ModerationHandler,
+	avatarHandler *handlers.AvatarHandler,
+	profileHandler *handlers.ProfileHandler,
+	questionHandler *handlers.QuestionHandler,
+	languageHandler *handlers.LanguageHandler,
+	translationHandler *handlers.TranslationHandler,
+	analyticsHandler *handlers.QuestionAnalyticsHandler,
+) {
 	admin := api.Group("/admin")
 	admin.Use(middlewares.APIKeyMiddleware())
 	{
 		admin.POST("/winners/select", winnerHandler.SelectWinners)
+		admin.PUT("/contest-weeks/:weekNumber/rules", contestWeekHandler.UpsertWeekRules)
+		admin.GET("/thunder-seat/moderation", moderationHandler.GetModerationQueue)
+		admin.POST("/thunder-seat/moderation", moderationHandler.ModerateSubmissions)
+
+		admin.GET("/avatars", avatarHandler.ListAdminAvatars)
+		admin.POST("/avatars", avatarHandler.CreateAvatar)
+		admin.PUT("/avatars/order", avatarHandler.ReorderAvatars)
+		admin.PATCH("/avatars/:avatarId", avatarHandler.UpdateAvatar)
+		admin.DELETE("/avatars/:avatarId", avatarHandler.DeleteAvatar)
+		admin.POST("/avatars/:avatarId/publish", avatarHandler.PublishAvatar)
+		admin.POST("/avatars/:avatarId/unpublish", avatarHandler.UnpublishAvatar)
+
+		admin.GET("/profile-photos/moderation", profileHandler.GetProfilePhotoQueue)
+		admin.POST("/profile-photos/moderation", profileHandler.ModerateProfilePhotos)
+
+		admin.POST("/questions", questionHandler.CreateQuestionDraft)
+		admin.POST("/questions/bulk", questionHandler.CreateQuestions)
+		admin.PUT("/questions/:questionId/schedule", questionHandler.ScheduleQuestion)
+		admin.POST("/questions/:questionId/unpublish", questionHandler.UnpublishQuestion)
+		admin.GET("/questions/:questionId/versions", questionHandler.GetQuestionVersions)
+		admin.POST("/questions/:questionId/versions", questionHandler.SaveQuestionDraft)
+		admin.GET("/questions/:questionId/versions/diff", questionHandler.DiffQuestionVersions)
+		admin.POST("/questions/:questionId/versions/:version/publish", questionHandler.PublishQuestionVersion)
+		admin.PUT("/questions/:questionId/conditions", questionHandler.SetQuestionConditions)
+		admin.GET("/question-sections", questionHandler.ListQuestionSections)
+		admin.POST("/question-sections", questionHandler.CreateQuestionSection)
+		admin.PATCH("/question-sections/:sectionId", questionHandler.UpdateQuestionSection)
+		admin.GET("/question-submissions", questionHandler.GetQuestionSubmissionQueue)
+		admin.POST("/question-submissions/:submissionId/approve", questionHandler.ApproveQuestionSubmission)
+		admin.POST("/question-submissions/:submissionId/reject", questionHandler.RejectQuestionSubmission)
+
+		admin.POST("/translations/reload", languageHandler.ReloadTranslations)
+		admin.GET("/translations/coverage", translationHandler.GetTranslationCoverage)
+		admin.GET("/translations/export", translationHandler.ExportTranslations)
+		admin.POST("/translations/import", translationHandler.ImportTranslations)
+		admin.GET("/questions/:questionId/translations", translationHandler.GetQuestionTranslations)
+		admin.PUT("/questions/:questionId/translations/:languageId", translationHandler.SetQuestionTranslation)
+		admin.DELETE("/questions/:questionId/translations/:languageId", translationHandler.DeleteQuestionTranslation)
+		admin.PUT("/options/:optionId/translations/:languageId", translationHandler.SetOptionTranslation)
+		admin.DELETE("/options/:optionId/translations/:languageId", translationHandler.DeleteOptionTranslation)
+
+		admin.GET("/analytics/questions", analyticsHandler.GetQuestionAnalytics)
+		admin.POST("/analytics/questions/refresh", analyticsHandler.RefreshQuestionAnalytics)
 	}
 }
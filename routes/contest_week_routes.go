@@ -14,6 +14,7 @@ func SetupContestWeekRoutes(api *gin.RouterGroup, db *gorm.DB, userRepo reposito
 	{
 		contestWeeks.GET("", contestWeekHandler.GetAllContestWeeks)
 		contestWeeks.GET("/active", contestWeekHandler.GetActiveWeek)
+		contestWeeks.GET("/timeline", contestWeekHandler.GetTimeline)
 		contestWeeks.GET("/:weekNumber", contestWeekHandler.GetContestWeekByNumber)
 
 		authRequired := contestWeeks.Group("")
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/handlers"
+)
+
+func SetupLanguageRoutes(api *gin.RouterGroup, languageHandler *handlers.LanguageHandler) {
+	api.GET("/languages", languageHandler.GetLanguages)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/handlers"
+	"github.com/Infinite-Locus-Product/thums_up_backend/middlewares"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+)
+
+func SetupLeaderboardRoutes(api *gin.RouterGroup, db *gorm.DB, userRepo repository.UserRepository, leaderboardHandler *handlers.LeaderboardHandler) {
+	leaderboard := api.Group("/leaderboard")
+	leaderboard.Use(middlewares.AuthMiddleware(db, userRepo))
+	{
+		leaderboard.GET("", leaderboardHandler.GetLeaderboard)
+	}
+}
@@ -16,6 +16,7 @@ func SetupProfileRoutes(
 	profileHandler *handlers.ProfileHandler,
 	addressHandler *handlers.AddressHandler,
 	questionHandler *handlers.QuestionHandler,
+	pointsHandler *handlers.PointsHandler,
 ) {
 	profileGroup := api.Group("/profile")
 	profileGroup.Use(middlewares.AuthMiddleware(db, userRepo))
@@ -23,6 +24,12 @@ func SetupProfileRoutes(
 		profileGroup.GET("", profileHandler.GetProfile)
 		profileGroup.PATCH("", profileHandler.UpdateProfile)
 
+		profileGroup.GET("/photo", profileHandler.GetProfilePhoto)
+		profileGroup.POST("/photo", profileHandler.UploadProfilePhoto)
+		profileGroup.DELETE("/photo", profileHandler.DeleteProfilePhoto)
+
+		profileGroup.GET("/points", pointsHandler.GetPoints)
+
 		profileGroup.POST("/address", addressHandler.AddAddress)
 		profileGroup.GET("/address", addressHandler.GetAddresses)
 		profileGroup.PUT("/address/:addressId", addressHandler.UpdateAddress)
@@ -32,6 +39,5 @@ func SetupProfileRoutes(
 		profileGroup.GET("/questions", questionHandler.GetQuestions)
 		profileGroup.POST("/questions/text", questionHandler.GetQuestionByID)
 		profileGroup.POST("/questions", questionHandler.AnswerQuestions)
-		profileGroup.POST("/questions/create", questionHandler.CreateQuestions)
 	}
 }
@@ -23,6 +23,7 @@ func SetupQuestionRoutes(
 		questionsAuth.Use(middlewares.AuthMiddleware(db, userRepo))
 		{
 			questionsAuth.POST("", questionHandler.SubmitQuestion)
+			questionsAuth.GET("/submissions", questionHandler.GetMyQuestionSubmissions)
 		}
 	}
 }
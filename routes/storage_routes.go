@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupStorageRoutes serves the public and signed URLs of the local and in-memory
+// storage drivers under mountPath. Signatures are checked by the driver itself.
+func SetupStorageRoutes(router *gin.Engine, mountPath string, storageHandler http.Handler) {
+	handler := gin.WrapH(http.StripPrefix(mountPath, storageHandler))
+
+	router.GET(mountPath+"/*objectPath", handler)
+	router.HEAD(mountPath+"/*objectPath", handler)
+	router.PUT(mountPath+"/*objectPath", handler)
+}
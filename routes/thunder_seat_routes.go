@@ -14,6 +14,7 @@ func SetupThunderSeatRoutes(
 	db *gorm.DB,
 	userRepo repository.UserRepository,
 	thunderSeatHandler *handlers.ThunderSeatHandler,
+	moderationHandler *handlers.ModerationHandler,
 ) {
 	thunderSeat := api.Group("/thunder-seat")
 	{
@@ -24,6 +25,11 @@ func SetupThunderSeatRoutes(
 		{
 			thunderSeatAuth.GET("/submissions", thunderSeatHandler.GetUserSubmissions)
 			thunderSeatAuth.POST("", thunderSeatHandler.SubmitAnswer)
+			thunderSeatAuth.POST("/uploads", thunderSeatHandler.CreateUploadSession)
+			thunderSeatAuth.POST("/uploads/:sessionId/finalize", thunderSeatHandler.FinalizeUploadSession)
+			thunderSeatAuth.PUT("/:id", thunderSeatHandler.UpdateSubmission)
+			thunderSeatAuth.DELETE("/:id", thunderSeatHandler.WithdrawSubmission)
+			thunderSeatAuth.POST("/:id/report", moderationHandler.ReportSubmission)
 		}
 	}
 }
@@ -34,6 +34,7 @@ type authService struct {
 	otpRepo          repository.OTPRepository
 	refreshTokenRepo repository.RefreshTokenRepository
 	loginCountRepo   repository.LoginCountRepository
+	pointsService    PointsService
 	infobipClient    *vendors.InfobipClient
 	cfg              *config.Config
 }
@@ -44,6 +45,7 @@ func NewAuthService(
 	otpRepo repository.OTPRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
 	loginCountRepo repository.LoginCountRepository,
+	pointsService PointsService,
 	infobipClient *vendors.InfobipClient,
 ) AuthService {
 	return &authService{
@@ -52,6 +54,7 @@ func NewAuthService(
 		otpRepo:          otpRepo,
 		refreshTokenRepo: refreshTokenRepo,
 		loginCountRepo:   loginCountRepo,
+		pointsService:    pointsService,
 		infobipClient:    infobipClient,
 		cfg:              config.GetConfig(),
 	}
@@ -253,15 +256,23 @@ func (s *authService) SignUp(ctx context.Context, req dtos.SignUpRequest) (*dtos
 		IsVerified:   false,
 	}
 
+	var referrer *entities.User
 	if req.ReferralCode != nil {
-		_, err := s.userRepo.FindByReferralCode(ctx, s.txnManager.GetDB(), *req.ReferralCode)
+		referrer, err = s.userRepo.FindByReferralCode(ctx, s.txnManager.GetDB(), *req.ReferralCode)
 		if err != nil {
 			log.WithError(err).Warn("Invalid referral code provided")
 		}
 	}
 
 	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
-		return s.userRepo.Create(ctx, tx, user)
+		if err := s.userRepo.Create(ctx, tx, user); err != nil {
+			return err
+		}
+		if referrer == nil {
+			return nil
+		}
+		_, err := s.pointsService.Award(ctx, tx, referralAward(referrer.ID, user.ID))
+		return err
 	})
 	if err != nil {
 		log.WithError(err).Error("Failed to create user")
@@ -411,14 +422,40 @@ func (s *authService) createOrIncrementLoginCount(ctx context.Context, tx *gorm.
 				Count:       1,
 				LastLogin:   time.Now(),
 			}
+			newLoginCount.RecordStreakDay(newLoginCount.LastLogin)
 			return s.loginCountRepo.Create(ctx, tx, newLoginCount)
 		}
 		return err
 	}
 
+	now := time.Now()
 	loginCount.Count++
-	loginCount.LastLogin = time.Now()
-	return s.loginCountRepo.Update(ctx, tx, loginCount)
+	loginCount.LastLogin = now
+	newDay := loginCount.RecordStreakDay(now)
+	if err := s.loginCountRepo.Update(ctx, tx, loginCount); err != nil {
+		return err
+	}
+	if !newDay {
+		return nil
+	}
+	return s.awardLoginStreak(ctx, tx, loginCount)
+}
+
+// awardLoginStreak credits a completed login streak in a savepoint, so a failed
+// award never fails the login itself
+func (s *authService) awardLoginStreak(ctx context.Context, tx *gorm.DB, loginCount *entities.LoginCount) error {
+	award, ok := loginStreakAward(loginCount)
+	if !ok {
+		return nil
+	}
+	err := tx.Transaction(func(savepoint *gorm.DB) error {
+		_, err := s.pointsService.Award(ctx, savepoint, award)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).WithField("user_id", loginCount.UserID).Warn("Failed to award login streak points")
+	}
+	return nil
 }
 
 func (s *authService) GetLoginCount(ctx context.Context, userID string) (*dtos.LoginCountResponse, error) {
@@ -3,7 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
-	"mime/multipart"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -11,35 +11,50 @@ import (
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
 	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
 type AvatarService interface {
-	CreateAvatar(ctx context.Context, req dtos.CreateAvatarRequestDTO, imageFile *multipart.FileHeader, createdBy string) (*dtos.AvatarResponseDTO, error)
-	GetAllAvatars(ctx context.Context, isPublished *bool) ([]dtos.AvatarResponseDTO, error)
+	CreateAvatar(ctx context.Context, req dtos.CreateAvatarRequestDTO, imageFile *utils.ValidatedFile, createdBy string) (*dtos.AvatarResponseDTO, error)
+	GetAllAvatars(ctx context.Context, req dtos.AvatarListRequestDTO) ([]dtos.AvatarResponseDTO, error)
 	GetAvatarByID(ctx context.Context, avatarID int) (*dtos.AvatarResponseDTO, error)
+	ListAdminAvatars(ctx context.Context, req dtos.AvatarListRequestDTO) ([]dtos.AvatarResponseDTO, error)
+	UpdateAvatar(ctx context.Context, avatarID int, req dtos.UpdateAvatarRequestDTO, modifiedBy string) (*dtos.AvatarResponseDTO, error)
+	DeleteAvatar(ctx context.Context, avatarID int, deletedBy string) error
+	SetPublished(ctx context.Context, avatarID int, published bool, modifiedBy string) (*dtos.AvatarResponseDTO, error)
+	ReorderAvatars(ctx context.Context, req dtos.ReorderAvatarsRequestDTO, modifiedBy string) ([]dtos.AvatarResponseDTO, error)
 }
 
 type avatarService struct {
-	txnManager *utils.TransactionManager
-	avatarRepo repository.GenericRepository[entities.Avatar]
-	gcsService utils.GCSService
+	txnManager     *utils.TransactionManager
+	avatarRepo     repository.AvatarRepository
+	mediaAssetRepo repository.MediaAssetRepository
+	gcsService     utils.GCSService
+	mediaResolver  MediaURLResolver
+	mediaPipeline  MediaPipelineService
 }
 
 func NewAvatarService(
 	txnManager *utils.TransactionManager,
-	avatarRepo repository.GenericRepository[entities.Avatar],
+	avatarRepo repository.AvatarRepository,
+	mediaAssetRepo repository.MediaAssetRepository,
 	gcsService utils.GCSService,
+	mediaResolver MediaURLResolver,
+	mediaPipeline MediaPipelineService,
 ) AvatarService {
 	return &avatarService{
-		txnManager: txnManager,
-		avatarRepo: avatarRepo,
-		gcsService: gcsService,
+		txnManager:     txnManager,
+		avatarRepo:     avatarRepo,
+		mediaAssetRepo: mediaAssetRepo,
+		gcsService:     gcsService,
+		mediaResolver:  mediaResolver,
+		mediaPipeline:  mediaPipeline,
 	}
 }
 
-func (s *avatarService) CreateAvatar(ctx context.Context, req dtos.CreateAvatarRequestDTO, imageFile *multipart.FileHeader, createdBy string) (*dtos.AvatarResponseDTO, error) {
+func (s *avatarService) CreateAvatar(ctx context.Context, req dtos.CreateAvatarRequestDTO, imageFile *utils.ValidatedFile, createdBy string) (*dtos.AvatarResponseDTO, error) {
 	if s.gcsService == nil {
 		return nil, fmt.Errorf("GCS service is not initialized")
 	}
@@ -52,22 +67,48 @@ func (s *avatarService) CreateAvatar(ctx context.Context, req dtos.CreateAvatarR
 
 	// Upload image file to GCS
 	folderPath := fmt.Sprintf("avatars/%s", createdBy)
-	imageURL, imageKey, err := s.gcsService.UploadFile(ctx, imageFile, folderPath)
+	imageURL, imageKey, err := utils.UploadValidatedFile(ctx, s.gcsService, imageFile, folderPath)
 	if err != nil {
 		log.WithError(err).Error("Failed to upload avatar image to GCS")
 		s.txnManager.AbortTxn(tx)
 		return nil, fmt.Errorf("failed to upload avatar image: %w", err)
 	}
 
+	asset := newMediaAsset(
+		fmt.Sprintf("%s/%s", folderPath, imageKey),
+		"image",
+		imageFile.ContentType,
+		imageFile.Size,
+		createdBy,
+	)
+	if err := s.mediaAssetRepo.Create(ctx, tx, asset); err != nil {
+		s.txnManager.AbortTxn(tx)
+		if deleteErr := s.gcsService.DeleteFile(ctx, imageURL); deleteErr != nil {
+			log.WithError(deleteErr).Error("Failed to cleanup uploaded avatar image after database error")
+		}
+		return nil, fmt.Errorf("failed to create avatar media asset: %w", err)
+	}
+
+	// New avatars go to the end of the picker unless placed explicitly
+	displayOrder := 0
+	if req.DisplayOrder != nil {
+		displayOrder = *req.DisplayOrder
+	} else if maxOrder, err := s.avatarRepo.MaxDisplayOrder(ctx, tx); err == nil {
+		displayOrder = maxOrder + 1
+	}
+
 	now := time.Now()
 	avatar := &entities.Avatar{
-		Name:        req.Name,
-		ImageKey:    imageKey,
-		IsPublished: req.IsPublished,
-		IsActive:    true,
-		IsDeleted:   false,
-		CreatedBy:   createdBy,
-		CreatedOn:   now,
+		Name:         req.Name,
+		ImageKey:     imageKey,
+		MediaAssetID: &asset.ID,
+		DisplayOrder: displayOrder,
+		Category:     normalizeAvatarCategory(req.Category),
+		IsPublished:  req.IsPublished,
+		IsActive:     true,
+		IsDeleted:    false,
+		CreatedBy:    createdBy,
+		CreatedOn:    now,
 	}
 
 	if req.IsPublished {
@@ -84,66 +125,60 @@ func (s *avatarService) CreateAvatar(ctx context.Context, req dtos.CreateAvatarR
 		return nil, fmt.Errorf("failed to create avatar: %w", err)
 	}
 
-	response := &dtos.AvatarResponseDTO{
-		ID:             avatar.ID,
-		Name:           avatar.Name,
-		ImageURL:       imageURL,
-		IsPublished:    avatar.IsPublished,
-		PublishedBy:    avatar.PublishedBy,
-		PublishedOn:    avatar.PublishedOn,
-		IsActive:       avatar.IsActive,
-		CreatedOn:      avatar.CreatedOn,
-		LastModifiedOn: avatar.LastModifiedOn,
+	tags := normalizeAvatarTags(req.Tags)
+	if err := s.avatarRepo.ReplaceTags(ctx, tx, avatar.ID, tags); err != nil {
+		s.txnManager.AbortTxn(tx)
+		if deleteErr := s.gcsService.DeleteFile(ctx, imageURL); deleteErr != nil {
+			log.WithError(deleteErr).Error("Failed to cleanup uploaded avatar image after database error")
+		}
+		return nil, fmt.Errorf("failed to save avatar tags: %w", err)
+	}
+	for _, tag := range tags {
+		avatar.Tags = append(avatar.Tags, entities.AvatarTag{AvatarID: avatar.ID, Tag: tag})
 	}
 
 	s.txnManager.CommitTxn(tx)
-	return response, nil
+	s.mediaPipeline.EnqueueAsset(asset.ID)
+	return s.toAvatarResponse(ctx, avatar), nil
 }
 
-func (s *avatarService) GetAllAvatars(ctx context.Context, isPublished *bool) ([]dtos.AvatarResponseDTO, error) {
-	tx, err := s.txnManager.StartTxn()
-	if err != nil {
-		return nil, err
-	}
-	defer s.txnManager.RollbackOnPanic(tx)
+// GetAllAvatars lists the avatars users can see in the picker: active and not deleted
+func (s *avatarService) GetAllAvatars(ctx context.Context, req dtos.AvatarListRequestDTO) ([]dtos.AvatarResponseDTO, error) {
+	isActive := true
+	return s.listAvatars(ctx, repository.AvatarFilter{
+		IsPublished: req.IsPublished,
+		IsActive:    &isActive,
+		Category:    normalizeAvatarCategory(req.Category),
+		Tag:         normalizeAvatarTag(req.Tag),
+	})
+}
 
-	conditions := map[string]interface{}{
-		"is_deleted": false,
-		"is_active":  true,
-	}
+// ListAdminAvatars also lists inactive avatars and, when asked, deleted ones
+func (s *avatarService) ListAdminAvatars(ctx context.Context, req dtos.AvatarListRequestDTO) ([]dtos.AvatarResponseDTO, error) {
+	return s.listAvatars(ctx, repository.AvatarFilter{
+		IsPublished:    req.IsPublished,
+		Category:       normalizeAvatarCategory(req.Category),
+		Tag:            normalizeAvatarTag(req.Tag),
+		IncludeDeleted: req.IncludeDeleted,
+	})
+}
 
-	if isPublished != nil {
-		conditions["is_published"] = *isPublished
+func (s *avatarService) listAvatars(ctx context.Context, filter repository.AvatarFilter) ([]dtos.AvatarResponseDTO, error) {
+	avatars, err := s.avatarRepo.FindFiltered(ctx, s.txnManager.GetDB(), filter)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to fetch avatars", err)
 	}
 
-	avatars, err := s.avatarRepo.FindWithConditions(ctx, tx, conditions)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to fetch avatars: %w", err)
+	loaded := make([]*entities.Avatar, len(avatars))
+	for i := range avatars {
+		loaded[i] = &avatars[i]
 	}
+	images := s.mediaResolver.AvatarImages(ctx, loaded)
 
 	response := make([]dtos.AvatarResponseDTO, 0, len(avatars))
-	for _, avatar := range avatars {
-		var imageURL string
-		if s.gcsService != nil {
-			// Reconstruct full path: avatars/{userID}/{filename}
-			fullPath := fmt.Sprintf("avatars/%s/%s", avatar.CreatedBy, avatar.ImageKey)
-			imageURL = s.gcsService.GetPublicURL(fullPath)
-		}
-		response = append(response, dtos.AvatarResponseDTO{
-			ID:             avatar.ID,
-			Name:           avatar.Name,
-			ImageURL:       imageURL,
-			IsPublished:    avatar.IsPublished,
-			PublishedBy:    avatar.PublishedBy,
-			PublishedOn:    avatar.PublishedOn,
-			IsActive:       avatar.IsActive,
-			CreatedOn:      avatar.CreatedOn,
-			LastModifiedOn: avatar.LastModifiedOn,
-		})
+	for _, avatar := range loaded {
+		response = append(response, *s.buildAvatarResponse(avatar, images[avatar.ID]))
 	}
-
-	s.txnManager.CommitTxn(tx)
 	return response, nil
 }
 
@@ -154,7 +189,7 @@ func (s *avatarService) GetAvatarByID(ctx context.Context, avatarID int) (*dtos.
 	}
 	defer s.txnManager.RollbackOnPanic(tx)
 
-	avatar, err := s.avatarRepo.FindByID(ctx, tx, avatarID)
+	avatar, err := s.avatarRepo.FindWithTags(ctx, tx, avatarID)
 	if err != nil {
 		s.txnManager.AbortTxn(tx)
 		return nil, fmt.Errorf("failed to fetch avatar: %w", err)
@@ -170,24 +205,248 @@ func (s *avatarService) GetAvatarByID(ctx context.Context, avatarID int) (*dtos.
 		return nil, gorm.ErrRecordNotFound
 	}
 
+	s.txnManager.CommitTxn(tx)
+	return s.toAvatarResponse(ctx, avatar), nil
+}
+
+func (s *avatarService) UpdateAvatar(ctx context.Context, avatarID int, req dtos.UpdateAvatarRequestDTO, modifiedBy string) (*dtos.AvatarResponseDTO, error) {
+	var avatar *entities.Avatar
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.findManagedAvatar(ctx, tx, avatarID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		fields := map[string]interface{}{
+			"last_modified_by": modifiedBy,
+			"last_modified_on": now,
+		}
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if name == "" {
+				return errors.NewBadRequestError("Avatar name cannot be empty", nil)
+			}
+			fields["name"] = name
+		}
+		if req.Category != nil {
+			fields["category"] = normalizeAvatarCategory(req.Category)
+		}
+		if req.DisplayOrder != nil {
+			fields["display_order"] = *req.DisplayOrder
+		}
+		if req.IsActive != nil {
+			fields["is_active"] = *req.IsActive
+		}
+
+		if err := s.avatarRepo.UpdateFields(ctx, tx, avatarID, fields); err != nil {
+			return errors.NewInternalServerError("Failed to update avatar", err)
+		}
+		if req.Tags != nil {
+			if err := s.avatarRepo.ReplaceTags(ctx, tx, avatarID, normalizeAvatarTags(*req.Tags)); err != nil {
+				return errors.NewInternalServerError("Failed to update avatar tags", err)
+			}
+		}
+
+		updated, err := s.avatarRepo.FindWithTags(ctx, tx, avatarID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to fetch avatar", err)
+		}
+		avatar = updated
+		return nil
+	})
+	s.mediaResolver.ForgetAvatar(avatarID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAvatarResponse(ctx, avatar), nil
+}
+
+// DeleteAvatar hides the avatar from the picker and admin listings. Users who
+// already chose it keep it, so the row and image stay in place.
+func (s *avatarService) DeleteAvatar(ctx context.Context, avatarID int, deletedBy string) error {
+	defer s.mediaResolver.ForgetAvatar(avatarID)
+	return s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.findManagedAvatar(ctx, tx, avatarID); err != nil {
+			return err
+		}
+
+		err := s.avatarRepo.UpdateFields(ctx, tx, avatarID, map[string]interface{}{
+			"is_deleted":       true,
+			"is_published":     false,
+			"last_modified_by": deletedBy,
+			"last_modified_on": time.Now(),
+		})
+		if err != nil {
+			return errors.NewInternalServerError("Failed to delete avatar", err)
+		}
+		return nil
+	})
+}
+
+// SetPublished publishes or unpublishes an avatar. Publishing records who
+// published it and when; unpublishing clears both.
+func (s *avatarService) SetPublished(ctx context.Context, avatarID int, published bool, modifiedBy string) (*dtos.AvatarResponseDTO, error) {
+	var avatar *entities.Avatar
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		existing, err := s.findManagedAvatar(ctx, tx, avatarID)
+		if err != nil {
+			return err
+		}
+
+		if existing.IsPublished != published {
+			now := time.Now()
+			fields := map[string]interface{}{
+				"is_published":     published,
+				"published_by":     nil,
+				"published_on":     nil,
+				"last_modified_by": modifiedBy,
+				"last_modified_on": now,
+			}
+			if published {
+				fields["published_by"] = modifiedBy
+				fields["published_on"] = now
+			}
+			if err := s.avatarRepo.UpdateFields(ctx, tx, avatarID, fields); err != nil {
+				return errors.NewInternalServerError("Failed to update avatar", err)
+			}
+		}
+
+		updated, err := s.avatarRepo.FindWithTags(ctx, tx, avatarID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to fetch avatar", err)
+		}
+		avatar = updated
+		return nil
+	})
+	s.mediaResolver.ForgetAvatar(avatarID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAvatarResponse(ctx, avatar), nil
+}
+
+// ReorderAvatars places the listed avatars first, in the given order. Avatars
+// not listed keep their relative order after them.
+func (s *avatarService) ReorderAvatars(ctx context.Context, req dtos.ReorderAvatarsRequestDTO, modifiedBy string) ([]dtos.AvatarResponseDTO, error) {
+	seen := make(map[int]bool, len(req.AvatarIDs))
+	for _, id := range req.AvatarIDs {
+		if seen[id] {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Avatar %d is listed more than once", id), nil)
+		}
+		seen[id] = true
+	}
+
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		avatars, err := s.avatarRepo.FindFiltered(ctx, tx, repository.AvatarFilter{})
+		if err != nil {
+			return errors.NewInternalServerError("Failed to fetch avatars", err)
+		}
+
+		known := make(map[int]bool, len(avatars))
+		for _, avatar := range avatars {
+			known[avatar.ID] = true
+		}
+		ordered := make([]int, 0, len(avatars))
+		for _, id := range req.AvatarIDs {
+			if !known[id] {
+				return errors.NewNotFoundError(fmt.Sprintf("Avatar %d not found", id), nil)
+			}
+			ordered = append(ordered, id)
+		}
+		for _, avatar := range avatars {
+			if !seen[avatar.ID] {
+				ordered = append(ordered, avatar.ID)
+			}
+		}
+
+		if err := s.avatarRepo.UpdateDisplayOrder(ctx, tx, ordered); err != nil {
+			return errors.NewInternalServerError("Failed to reorder avatars", err)
+		}
+		log.WithFields(log.Fields{"avatars": len(ordered), "modified_by": modifiedBy}).Info("Reordered avatars")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListAdminAvatars(ctx, dtos.AvatarListRequestDTO{})
+}
+
+// findManagedAvatar returns an avatar admins may change; deleted avatars are not found
+func (s *avatarService) findManagedAvatar(ctx context.Context, tx *gorm.DB, avatarID int) (*entities.Avatar, error) {
+	avatar, err := s.avatarRepo.FindByID(ctx, tx, avatarID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to fetch avatar", err)
+	}
+	if avatar == nil || avatar.IsDeleted {
+		return nil, errors.NewNotFoundError("Avatar not found", nil)
+	}
+	return avatar, nil
+}
+
+func (s *avatarService) toAvatarResponse(ctx context.Context, avatar *entities.Avatar) *dtos.AvatarResponseDTO {
+	images := s.mediaResolver.AvatarImages(ctx, []*entities.Avatar{avatar})
+	return s.buildAvatarResponse(avatar, images[avatar.ID])
+}
+
+// buildAvatarResponse keeps ImageURL pointing at the avatar's own image, even for
+// deleted avatars whose variants resolve to the default avatar
+func (s *avatarService) buildAvatarResponse(avatar *entities.Avatar, variants dtos.ImageVariants) *dtos.AvatarResponseDTO {
 	var imageURL string
 	if s.gcsService != nil {
-		fullPath := fmt.Sprintf("avatars/%s/%s", avatar.CreatedBy, avatar.ImageKey)
-		imageURL = s.gcsService.GetPublicURL(fullPath)
+		imageURL = s.gcsService.GetPublicURL(avatar.ObjectPath())
+	}
+
+	tags := make([]string, len(avatar.Tags))
+	for i, tag := range avatar.Tags {
+		tags[i] = tag.Tag
 	}
 
-	response := &dtos.AvatarResponseDTO{
+	return &dtos.AvatarResponseDTO{
 		ID:             avatar.ID,
 		Name:           avatar.Name,
 		ImageURL:       imageURL,
+		Variants:       variants,
+		DisplayOrder:   avatar.DisplayOrder,
+		Category:       avatar.Category,
+		Tags:           tags,
 		IsPublished:    avatar.IsPublished,
 		PublishedBy:    avatar.PublishedBy,
 		PublishedOn:    avatar.PublishedOn,
 		IsActive:       avatar.IsActive,
+		IsDeleted:      avatar.IsDeleted,
 		CreatedOn:      avatar.CreatedOn,
 		LastModifiedOn: avatar.LastModifiedOn,
 	}
+}
 
-	s.txnManager.CommitTxn(tx)
-	return response, nil
+// normalizeAvatarCategory lower-cases the category; blank means none
+func normalizeAvatarCategory(category *string) *string {
+	if category == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*category))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
+
+func normalizeAvatarTag(tag *string) *string {
+	return normalizeAvatarCategory(tag)
+}
+
+// normalizeAvatarTags lower-cases tags and drops blanks and duplicates
+func normalizeAvatarTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
 }
@@ -2,14 +2,18 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/cache"
 	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
@@ -20,20 +24,29 @@ type ContestWeekService interface {
 	GetContestWeekByNumber(ctx context.Context, weekNumber int) (*dtos.ContestWeekResponse, error)
 	ActivateWeek(ctx context.Context, weekNumber int) (*dtos.ContestWeekResponse, error)
 	GetActiveWeek(ctx context.Context) (*dtos.ContestWeekResponse, error)
+	GetTimeline(ctx context.Context) (*dtos.ContestWeekTimelineResponse, error)
+	UpsertWeekRules(ctx context.Context, weekNumber int, req dtos.ContestWeekRulesRequest, updatedBy string) (*dtos.ContestWeekRulesResponse, error)
 }
 
+const timelineCacheKey = "all"
+
 type contestWeekService struct {
 	txnManager      *utils.TransactionManager
 	contestWeekRepo repository.ContestWeekRepository
+	ruleRepo        repository.ContestWeekRuleRepository
+	timelineCache   *cache.TTLCache[string, []entities.ContestWeek]
 }
 
 func NewContestWeekService(
 	txnManager *utils.TransactionManager,
 	contestWeekRepo repository.ContestWeekRepository,
+	ruleRepo repository.ContestWeekRuleRepository,
 ) ContestWeekService {
 	return &contestWeekService{
 		txnManager:      txnManager,
 		contestWeekRepo: contestWeekRepo,
+		ruleRepo:        ruleRepo,
+		timelineCache:   cache.NewTTLCache[string, []entities.ContestWeek](constants.CONTEST_TIMELINE_CACHE_TTL),
 	}
 }
 
@@ -63,17 +76,50 @@ func (s *contestWeekService) CreateContestWeek(ctx context.Context, req dtos.Con
 	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, time.UTC)
 
+	var drawDate *time.Time
+	if req.DrawDate != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.DrawDate)
+		if err != nil {
+			return nil, errors.NewBadRequestError("Invalid draw date format. Use RFC3339", err)
+		}
+		if parsed.Before(endDate) {
+			return nil, errors.NewBadRequestError("Draw date must be after end date", nil)
+		}
+		drawDate = &parsed
+	}
+
+	var resultsDate *time.Time
+	if req.ResultsDate != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.ResultsDate)
+		if err != nil {
+			return nil, errors.NewBadRequestError("Invalid results date format. Use RFC3339", err)
+		}
+		if parsed.Before(endDate) {
+			return nil, errors.NewBadRequestError("Results date must be after end date and draw date", nil)
+		}
+		resultsDate = &parsed
+	}
+
 	now := time.Now()
 	contestWeek := &entities.ContestWeek{
 		WeekNumber:  req.WeekNumber,
 		StartDate:   startDate,
 		EndDate:     endDate,
+		DrawDate:    drawDate,
+		ResultsDate: resultsDate,
 		WinnerCount: req.WinnerCount,
 		IsActive:    false,
 		CreatedBy:   createdBy,
 		CreatedOn:   now,
 	}
 
+	// Either date may be left to its default delay after the week closes, so
+	// compare the effective times rather than only the ones sent
+	drawTime := contestWeek.DrawTime(constants.DEFAULT_DRAW_DELAY)
+	if contestWeek.ResultsTime(constants.DEFAULT_RESULTS_DELAY).Before(drawTime) {
+		return nil, errors.NewBadRequestError("Results date must be after end date and draw date", nil)
+	}
+
 	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
 		return s.contestWeekRepo.Create(ctx, tx, contestWeek)
 	})
@@ -81,6 +127,7 @@ func (s *contestWeekService) CreateContestWeek(ctx context.Context, req dtos.Con
 		log.WithError(err).Error("Failed to create contest week")
 		return nil, errors.NewInternalServerError("Failed to create contest week", err)
 	}
+	s.timelineCache.Clear()
 
 	return &dtos.ContestWeekResponse{
 		ID:          contestWeek.ID,
@@ -157,6 +204,7 @@ func (s *contestWeekService) ActivateWeek(ctx context.Context, weekNumber int) (
 		log.WithError(err).Error("Failed to activate contest week")
 		return nil, errors.NewInternalServerError("Failed to activate contest week", err)
 	}
+	s.timelineCache.Clear()
 
 	return &dtos.ContestWeekResponse{
 		ID:          week.ID,
@@ -188,3 +236,144 @@ func (s *contestWeekService) GetActiveWeek(ctx context.Context) (*dtos.ContestWe
 		CreatedOn:   week.CreatedOn.Format(time.RFC3339),
 	}, nil
 }
+
+func (s *contestWeekService) GetTimeline(ctx context.Context) (*dtos.ContestWeekTimelineResponse, error) {
+	weeks, ok := s.timelineCache.Get(timelineCacheKey)
+	if !ok {
+		var err error
+		weeks, err = s.contestWeekRepo.FindAll(ctx, s.txnManager.GetDB())
+		if err != nil {
+			return nil, errors.NewInternalServerError("Failed to get contest timeline", err)
+		}
+		s.timelineCache.Set(timelineCacheKey, weeks)
+	}
+
+	now := time.Now().UTC()
+	items := make([]dtos.ContestWeekTimelineItem, len(weeks))
+	for i, week := range weeks {
+		drawTime := week.DrawTime(constants.DEFAULT_DRAW_DELAY)
+		resultsTime := week.ResultsTime(constants.DEFAULT_RESULTS_DELAY)
+
+		items[i] = dtos.ContestWeekTimelineItem{
+			WeekNumber:        week.WeekNumber,
+			Status:            contestWeekStatus(now, week.StartDate, week.EndDate, resultsTime),
+			IsActive:          week.IsActive,
+			StartTime:         week.StartDate.UTC().Format(time.RFC3339),
+			EndTime:           week.EndDate.UTC().Format(time.RFC3339),
+			DrawTime:          drawTime.UTC().Format(time.RFC3339),
+			ResultsTime:       resultsTime.UTC().Format(time.RFC3339),
+			SecondsUntilOpen:  secondsUntil(now, week.StartDate),
+			SecondsUntilClose: secondsUntil(now, week.EndDate),
+			WinnerCount:       week.WinnerCount,
+		}
+	}
+
+	return &dtos.ContestWeekTimelineResponse{
+		ServerTime: now.Format(time.RFC3339),
+		Weeks:      items,
+	}, nil
+}
+
+func (s *contestWeekService) UpsertWeekRules(ctx context.Context, weekNumber int, req dtos.ContestWeekRulesRequest, updatedBy string) (*dtos.ContestWeekRulesResponse, error) {
+	week, err := s.contestWeekRepo.FindByWeekNumber(ctx, s.txnManager.GetDB(), weekNumber)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get contest week", err)
+	}
+	if week == nil {
+		return nil, errors.NewNotFoundError("Contest week not found", nil)
+	}
+
+	seenLanguages := make(map[int]bool, len(req.Prompts))
+	for _, prompt := range req.Prompts {
+		if seenLanguages[prompt.LanguageID] {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Duplicate prompt for language %d", prompt.LanguageID), nil)
+		}
+		seenLanguages[prompt.LanguageID] = true
+	}
+
+	rule, err := s.ruleRepo.FindByWeekNumber(ctx, s.txnManager.GetDB(), weekNumber)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get contest week rules", err)
+	}
+
+	now := time.Now()
+	if rule == nil {
+		rule = &entities.ContestWeekRule{
+			WeekNumber: weekNumber,
+			CreatedBy:  updatedBy,
+			CreatedOn:  now,
+		}
+	}
+	rule.MediaRequired = req.MediaRequired
+	rule.AllowedMediaKinds = strings.Join(req.AllowedMediaKinds, ",")
+	rule.MaxAnswerLength = req.MaxAnswerLength
+	rule.MaxSubmissionsPerUser = req.MaxSubmissionsPerUser
+	rule.SharingFieldsRequired = req.SharingFieldsRequired
+	rule.UpdatedBy = updatedBy
+	rule.UpdatedOn = now
+	rule.Prompts = nil
+
+	prompts := make([]entities.ContestWeekPrompt, len(req.Prompts))
+	for i, prompt := range req.Prompts {
+		prompts[i] = entities.ContestWeekPrompt{
+			WeekNumber: weekNumber,
+			LanguageID: prompt.LanguageID,
+			PromptText: prompt.PromptText,
+			CreatedBy:  updatedBy,
+			CreatedOn:  now,
+		}
+	}
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if rule.ID == 0 {
+			if err := s.ruleRepo.Create(ctx, tx, rule); err != nil {
+				return err
+			}
+		} else if err := s.ruleRepo.Update(ctx, tx, rule); err != nil {
+			return err
+		}
+		return s.ruleRepo.ReplacePrompts(ctx, tx, weekNumber, prompts)
+	})
+	if err != nil {
+		log.WithError(err).WithField("week_number", weekNumber).Error("Failed to save contest week rules")
+		return nil, errors.NewInternalServerError("Failed to save contest week rules", err)
+	}
+
+	promptResponses := make([]dtos.ContestWeekPromptResponse, len(prompts))
+	for i, prompt := range prompts {
+		promptResponses[i] = dtos.ContestWeekPromptResponse{
+			LanguageID: prompt.LanguageID,
+			PromptText: prompt.PromptText,
+		}
+	}
+
+	return &dtos.ContestWeekRulesResponse{
+		WeekNumber:            rule.WeekNumber,
+		MediaRequired:         rule.MediaRequired,
+		AllowedMediaKinds:     rule.MediaKinds(),
+		MaxAnswerLength:       rule.MaxAnswerLength,
+		MaxSubmissionsPerUser: rule.MaxSubmissionsPerUser,
+		SharingFieldsRequired: rule.SharingFieldsRequired,
+		Prompts:               promptResponses,
+	}, nil
+}
+
+func contestWeekStatus(now, start, end, results time.Time) string {
+	switch {
+	case now.Before(start):
+		return constants.CONTEST_WEEK_STATUS_UPCOMING
+	case !now.After(end):
+		return constants.CONTEST_WEEK_STATUS_OPEN
+	case now.Before(results):
+		return constants.CONTEST_WEEK_STATUS_CLOSED
+	default:
+		return constants.CONTEST_WEEK_STATUS_RESULTS_ANNOUNCED
+	}
+}
+
+func secondsUntil(now, target time.Time) int64 {
+	if !target.After(now) {
+		return 0
+	}
+	return int64(target.Sub(now).Seconds())
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// LeaderboardService ranks users by points per contest week and all time. Totals
+// are kept up to date as points are credited, so reading a leaderboard is a plain
+// indexed query.
+type LeaderboardService interface {
+	// ActiveWeekNumber returns the contest week whose leaderboard newly credited
+	// points count toward, or nil when no week is active
+	ActiveWeekNumber(ctx context.Context, tx *gorm.DB) (*int, error)
+	// RecordPoints adds points to the all-time leaderboard and, when weekNumber is
+	// set, to that week's, inside the transaction that wrote them to the ledger.
+	// Reversals pass negative points and the week the original award counted toward.
+	RecordPoints(ctx context.Context, tx *gorm.DB, userID string, points int, weekNumber *int, earnedOn time.Time) error
+	GetLeaderboard(ctx context.Context, user *entities.User, req dtos.LeaderboardRequest) (*dtos.LeaderboardResponse, error)
+}
+
+type leaderboardService struct {
+	txnManager      *utils.TransactionManager
+	leaderboardRepo repository.LeaderboardRepository
+	contestWeekRepo repository.ContestWeekRepository
+	mediaResolver   MediaURLResolver
+}
+
+func NewLeaderboardService(
+	txnManager *utils.TransactionManager,
+	leaderboardRepo repository.LeaderboardRepository,
+	contestWeekRepo repository.ContestWeekRepository,
+	mediaResolver MediaURLResolver,
+) LeaderboardService {
+	return &leaderboardService{
+		txnManager:      txnManager,
+		leaderboardRepo: leaderboardRepo,
+		contestWeekRepo: contestWeekRepo,
+		mediaResolver:   mediaResolver,
+	}
+}
+
+func (s *leaderboardService) ActiveWeekNumber(ctx context.Context, tx *gorm.DB) (*int, error) {
+	week, err := s.contestWeekRepo.FindActiveWeek(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active contest week: %w", err)
+	}
+	if week == nil {
+		return nil, nil
+	}
+	return &week.WeekNumber, nil
+}
+
+func (s *leaderboardService) RecordPoints(ctx context.Context, tx *gorm.DB, userID string, points int, weekNumber *int, earnedOn time.Time) error {
+	if err := s.leaderboardRepo.AddPoints(ctx, tx, entities.LeaderboardAllTime, userID, points, earnedOn); err != nil {
+		return fmt.Errorf("failed to update all-time leaderboard: %w", err)
+	}
+	if weekNumber == nil {
+		return nil
+	}
+	if err := s.leaderboardRepo.AddPoints(ctx, tx, *weekNumber, userID, points, earnedOn); err != nil {
+		return fmt.Errorf("failed to update week %d leaderboard: %w", *weekNumber, err)
+	}
+	return nil
+}
+
+func (s *leaderboardService) GetLeaderboard(ctx context.Context, user *entities.User, req dtos.LeaderboardRequest) (*dtos.LeaderboardResponse, error) {
+	db := s.txnManager.GetDB()
+
+	scope := req.Scope
+	if scope == "" {
+		scope = dtos.LeaderboardScopeWeek
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = constants.LEADERBOARD_DEFAULT_LIMIT
+	}
+
+	response := &dtos.LeaderboardResponse{Scope: scope}
+	weekNumber := entities.LeaderboardAllTime
+	if scope == dtos.LeaderboardScopeWeek {
+		var week *entities.ContestWeek
+		var err error
+		if req.Week != 0 {
+			week, err = s.contestWeekRepo.FindByWeekNumber(ctx, db, req.Week)
+		} else {
+			week, err = s.contestWeekRepo.FindActiveWeek(ctx, db)
+		}
+		if err != nil {
+			return nil, errors.NewInternalServerError("Failed to get contest week", err)
+		}
+		if week == nil {
+			return nil, errors.NewNotFoundError("Contest week not found", nil)
+		}
+		weekNumber = week.WeekNumber
+		response.WeekNumber = &weekNumber
+	}
+
+	entries, total, err := s.leaderboardRepo.FindPage(ctx, db, weekNumber, limit, req.Offset)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get leaderboard", err)
+	}
+
+	users := make([]*entities.User, 0, len(entries))
+	for i := range entries {
+		if entries[i].User != nil {
+			users = append(users, entries[i].User)
+		}
+	}
+	images := s.mediaResolver.UserImages(ctx, users)
+
+	response.Entries = make([]dtos.LeaderboardEntryDTO, len(entries))
+	for i, entry := range entries {
+		item := dtos.LeaderboardEntryDTO{
+			Rank:   req.Offset + i + 1,
+			Points: entry.Points,
+			IsMe:   entry.UserID == user.ID,
+		}
+		if entry.User != nil && entry.User.Name != nil {
+			item.Name = utils.MaskName(*entry.User.Name)
+		}
+		if variants, ok := images[entry.UserID]; ok {
+			item.AvatarVariants = &variants
+		}
+		response.Entries[i] = item
+	}
+
+	me, err := s.standing(ctx, db, weekNumber, user)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get leaderboard rank", err)
+	}
+	response.Me = *me
+
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	response.Meta = dtos.PaginationMeta{
+		Page:       (req.Offset / limit) + 1,
+		PageSize:   limit,
+		TotalPages: totalPages,
+		TotalCount: total,
+	}
+	return response, nil
+}
+
+// standing returns the user's points and, unless they opted out, their rank
+func (s *leaderboardService) standing(ctx context.Context, db *gorm.DB, weekNumber int, user *entities.User) (*dtos.LeaderboardMeDTO, error) {
+	me := &dtos.LeaderboardMeDTO{OptedOut: user.LeaderboardOptOut}
+
+	entry, err := s.leaderboardRepo.FindByUserID(ctx, db, weekNumber, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return me, nil
+	}
+	me.Points = entry.Points
+	if user.LeaderboardOptOut {
+		return me, nil
+	}
+
+	ahead, err := s.leaderboardRepo.CountAhead(ctx, db, entry)
+	if err != nil {
+		return nil, err
+	}
+	rank := int(ahead) + 1
+	me.Rank = &rank
+	return me, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/cache"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/i18n"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+const catalogCacheKey = "catalog"
+
+// LocalizationService serves translations from the language tables. They are
+// cached in memory and reloaded every constants.TRANSLATION_CACHE_TTL or on
+// demand; when the tables cannot be read the last catalog keeps being served.
+type LocalizationService interface {
+	// ResolveLanguage picks the language a request is served in: the requested
+	// language, then the user's preference, then Accept-Language, then the default.
+	// A requested language is always honoured since translated content falls back
+	// item by item; an unknown preference is skipped.
+	ResolveLanguage(ctx context.Context, requestedID int, user *entities.User, acceptLanguage string) int
+	// IsLanguage reports whether languageID is an active language
+	IsLanguage(ctx context.Context, languageID int) bool
+	// Translate returns key in the language, falling back to the default language
+	// and then to the key itself
+	Translate(ctx context.Context, languageID int, key string) string
+	// RenderTemplate fills the title and body of a notification template in the
+	// language, reporting false when the template has no translations
+	RenderTemplate(ctx context.Context, languageID int, template string, params map[string]string) (string, string, bool)
+	GetLanguages(ctx context.Context) []dtos.LanguageDTO
+	Reload(ctx context.Context) (*dtos.TranslationReloadResponse, error)
+}
+
+type localizationService struct {
+	txnManager   *utils.TransactionManager
+	languageRepo repository.LanguageRepository
+	catalogCache *cache.TTLCache[string, *i18n.Catalog]
+
+	mu   sync.Mutex
+	last *i18n.Catalog
+}
+
+func NewLocalizationService(
+	txnManager *utils.TransactionManager,
+	languageRepo repository.LanguageRepository,
+) LocalizationService {
+	return &localizationService{
+		txnManager:   txnManager,
+		languageRepo: languageRepo,
+		catalogCache: cache.NewTTLCache[string, *i18n.Catalog](constants.TRANSLATION_CACHE_TTL),
+	}
+}
+
+func (s *localizationService) ResolveLanguage(ctx context.Context, requestedID int, user *entities.User, acceptLanguage string) int {
+	if requestedID > 0 {
+		return requestedID
+	}
+	catalog := s.catalog(ctx)
+	if user != nil && user.PreferredLanguageID != nil && catalog.HasLanguage(*user.PreferredLanguageID) {
+		return *user.PreferredLanguageID
+	}
+	if languageID, ok := catalog.Match(acceptLanguage); ok {
+		return languageID
+	}
+	return catalog.DefaultLanguageID()
+}
+
+func (s *localizationService) IsLanguage(ctx context.Context, languageID int) bool {
+	return s.catalog(ctx).HasLanguage(languageID)
+}
+
+func (s *localizationService) Translate(ctx context.Context, languageID int, key string) string {
+	return s.catalog(ctx).Translate(languageID, key)
+}
+
+func (s *localizationService) RenderTemplate(ctx context.Context, languageID int, template string, params map[string]string) (string, string, bool) {
+	catalog := s.catalog(ctx)
+	titleKey, bodyKey := template+".title", template+".body"
+	for _, key := range []string{titleKey, bodyKey} {
+		if _, ok := catalog.Lookup(languageID, key); ok {
+			continue
+		}
+		if _, ok := catalog.Lookup(catalog.DefaultLanguageID(), key); !ok {
+			return "", "", false
+		}
+	}
+	title := catalog.Translate(languageID, titleKey)
+	body := catalog.Translate(languageID, bodyKey)
+	return i18n.Format(title, params), i18n.Format(body, params), true
+}
+
+func (s *localizationService) GetLanguages(ctx context.Context) []dtos.LanguageDTO {
+	languages := s.catalog(ctx).Languages()
+	response := make([]dtos.LanguageDTO, len(languages))
+	for i, language := range languages {
+		response[i] = dtos.LanguageDTO{
+			ID:   language.ID,
+			Name: language.Name,
+			Code: language.Code,
+		}
+	}
+	return response
+}
+
+func (s *localizationService) Reload(ctx context.Context) (*dtos.TranslationReloadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	catalog, err := s.load(ctx)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to reload translations", err)
+	}
+	return &dtos.TranslationReloadResponse{
+		Languages:    len(catalog.Languages()),
+		Translations: catalog.Size(),
+	}, nil
+}
+
+// catalog returns the cached catalog, loading it when it has expired
+func (s *localizationService) catalog(ctx context.Context) *i18n.Catalog {
+	if catalog, ok := s.catalogCache.Get(catalogCacheKey); ok {
+		return catalog
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if catalog, ok := s.catalogCache.Get(catalogCacheKey); ok {
+		return catalog
+	}
+
+	catalog, err := s.load(ctx)
+	if err == nil {
+		return catalog
+	}
+	log.WithError(err).Error("Failed to load translations")
+	fallback := s.last
+	if fallback == nil {
+		fallback = i18n.NewCatalog(constants.DEFAULT_LANGUAGE_ID, nil)
+	}
+	// Retry sooner than a full TTL, but not on every request
+	s.catalogCache.SetWithTTL(catalogCacheKey, fallback, constants.TRANSLATION_RELOAD_RETRY)
+	return fallback
+}
+
+// load reads the language tables into a new catalog and caches it. Callers hold s.mu.
+func (s *localizationService) load(ctx context.Context) (*i18n.Catalog, error) {
+	db := s.txnManager.GetDB()
+	languages, err := s.languageRepo.FindActiveLanguages(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.languageRepo.FindActiveKeyValues(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogLanguages := make([]i18n.Language, len(languages))
+	for i, language := range languages {
+		catalogLanguages[i] = i18n.Language{ID: language.ID, Name: language.Name}
+		if language.LanguageCode != nil {
+			catalogLanguages[i].Code = *language.LanguageCode
+		}
+	}
+	catalog := i18n.NewCatalog(constants.DEFAULT_LANGUAGE_ID, catalogLanguages)
+	for _, value := range values {
+		catalog.Add(value.LanguageMasterID, value.LanguageKey.Name, value.KeyValue)
+	}
+
+	s.catalogCache.Set(catalogCacheKey, catalog)
+	s.last = catalog
+	log.WithFields(log.Fields{
+		"languages":    len(catalogLanguages),
+		"translations": catalog.Size(),
+	}).Info("Translations loaded")
+	return catalog, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/media"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/queue"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type MediaPipelineService interface {
+	EnqueueAsset(assetID int)
+	ProcessAsset(ctx context.Context, assetID int) error
+}
+
+type mediaPipelineService struct {
+	txnManager     *utils.TransactionManager
+	mediaAssetRepo repository.MediaAssetRepository
+	gcsService     utils.GCSService
+	workerPool     *queue.WorkerPool
+}
+
+func NewMediaPipelineService(
+	txnManager *utils.TransactionManager,
+	mediaAssetRepo repository.MediaAssetRepository,
+	gcsService utils.GCSService,
+	workerPool *queue.WorkerPool,
+) MediaPipelineService {
+	return &mediaPipelineService{
+		txnManager:     txnManager,
+		mediaAssetRepo: mediaAssetRepo,
+		gcsService:     gcsService,
+		workerPool:     workerPool,
+	}
+}
+
+// newMediaAsset builds the pending record for an uploaded original
+func newMediaAsset(objectPath, kind, declaredContentType string, size int64, createdBy string) *entities.MediaAsset {
+	asset := &entities.MediaAsset{
+		Rendition:  entities.MediaRenditionOriginal,
+		ObjectPath: objectPath,
+		Kind:       kind,
+		Size:       size,
+		Status:     entities.MediaAssetStatusPending,
+		CreatedBy:  createdBy,
+	}
+	if declaredContentType != "" {
+		asset.DeclaredContentType = &declaredContentType
+	}
+	return asset
+}
+
+// EnqueueAsset processes the asset on the worker pool. Assets that cannot be queued
+// stay pending and are served as uploaded, without renditions.
+func (s *mediaPipelineService) EnqueueAsset(assetID int) {
+	if s.workerPool == nil {
+		return
+	}
+
+	task := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, constants.MEDIA_PIPELINE_TIMEOUT)
+		defer cancel()
+		return s.ProcessAsset(ctx, assetID)
+	}
+
+	if err := s.workerPool.Submit(task); err != nil {
+		log.WithError(err).WithField("media_asset_id", assetID).Warn("Failed to queue media asset for processing")
+	}
+}
+
+// ProcessAsset sniffs the stored original, records its real content type, duration
+// and dimensions, and generates its renditions next to it in storage
+func (s *mediaPipelineService) ProcessAsset(ctx context.Context, assetID int) error {
+	asset, err := s.mediaAssetRepo.FindByID(ctx, s.txnManager.GetDB(), assetID)
+	if err != nil {
+		return fmt.Errorf("failed to get media asset %d: %w", assetID, err)
+	}
+	if asset == nil || asset.ParentID != nil {
+		return nil
+	}
+
+	logger := log.WithFields(log.Fields{
+		"media_asset_id": asset.ID,
+		"object_path":    asset.ObjectPath,
+	})
+
+	info, renditions, err := s.process(ctx, asset)
+	if err != nil {
+		logger.WithError(err).Warn("Media processing failed")
+		if updateErr := s.mediaAssetRepo.UpdateFields(ctx, s.txnManager.GetDB(), asset.ID, map[string]interface{}{
+			"status":         entities.MediaAssetStatusFailed,
+			"failure_reason": err.Error(),
+			"processed_on":   time.Now(),
+		}); updateErr != nil {
+			logger.WithError(updateErr).Error("Failed to mark media asset as failed")
+		}
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"content_type":   info.ContentType,
+		"status":         entities.MediaAssetStatusReady,
+		"failure_reason": nil,
+		"processed_on":   time.Now(),
+	}
+	if info.Kind != "unknown" {
+		fields["kind"] = info.Kind
+	}
+	if info.Duration > 0 {
+		fields["duration_ms"] = info.Duration.Milliseconds()
+	}
+	if info.Width > 0 && info.Height > 0 {
+		fields["width"] = info.Width
+		fields["height"] = info.Height
+	}
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.mediaAssetRepo.UpdateFields(ctx, tx, asset.ID, fields); err != nil {
+			return err
+		}
+		return s.mediaAssetRepo.ReplaceRenditions(ctx, tx, asset.ID, renditions)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to save media processing results")
+		return err
+	}
+
+	logger.WithFields(log.Fields{
+		"content_type": info.ContentType,
+		"renditions":   len(renditions),
+	}).Debug("Media asset processed")
+	return nil
+}
+
+func (s *mediaPipelineService) process(ctx context.Context, asset *entities.MediaAsset) (*media.Info, []entities.MediaAsset, error) {
+	// Large audio files are only sniffed from their header to keep workers' memory bounded
+	readLength := int64(-1)
+	if asset.Size > constants.MEDIA_PIPELINE_MAX_IN_MEMORY {
+		readLength = media.HeaderSize
+	}
+	data, err := s.gcsService.ReadObjectRange(ctx, asset.ObjectPath, 0, readLength)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read original: %w", err)
+	}
+
+	info, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var renditions []entities.MediaAsset
+	switch info.Kind {
+	case "image":
+		img, err := media.Decode(data)
+		if err != nil {
+			// Formats without a standard library decoder (webp, svg) are served without a thumbnail
+			log.WithError(err).WithField("media_asset_id", asset.ID).Debug("Skipping thumbnail for undecodable image")
+			break
+		}
+		rendition, err := s.storeRendition(ctx, asset, entities.MediaRenditionThumbnail, media.Thumbnail(img, constants.MEDIA_THUMBNAIL_SIZE))
+		if err != nil {
+			return nil, nil, err
+		}
+		renditions = append(renditions, *rendition)
+	case "video":
+		width, height := media.FitWithin(info.Width, info.Height, constants.MEDIA_POSTER_SIZE)
+		rendition, err := s.storeRendition(ctx, asset, entities.MediaRenditionPoster, media.PosterPlaceholder(width, height))
+		if err != nil {
+			return nil, nil, err
+		}
+		renditions = append(renditions, *rendition)
+	}
+
+	return info, renditions, nil
+}
+
+func (s *mediaPipelineService) storeRendition(ctx context.Context, asset *entities.MediaAsset, name string, img image.Image) (*entities.MediaAsset, error) {
+	data, err := media.EncodeJPEG(img)
+	if err != nil {
+		return nil, err
+	}
+
+	objectPath := renditionPath(asset.ObjectPath, name)
+	if _, _, err := s.gcsService.UploadFileFromBytes(ctx, data, objectPath, "image/jpeg"); err != nil {
+		return nil, fmt.Errorf("failed to store %s rendition: %w", name, err)
+	}
+
+	contentType := "image/jpeg"
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	now := time.Now()
+	return &entities.MediaAsset{
+		Rendition:   name,
+		ObjectPath:  objectPath,
+		Kind:        "image",
+		ContentType: &contentType,
+		Size:        int64(len(data)),
+		Width:       &width,
+		Height:      &height,
+		Status:      entities.MediaAssetStatusReady,
+		CreatedBy:   asset.CreatedBy,
+		ProcessedOn: &now,
+	}, nil
+}
+
+// renditionPath stores a rendition next to its original, e.g. a/b/clip.mp4 -> a/b/clip_poster.jpg
+func renditionPath(objectPath, name string) string {
+	base := strings.TrimSuffix(objectPath, path.Ext(objectPath))
+	return fmt.Sprintf("%s_%s.jpg", base, name)
+}
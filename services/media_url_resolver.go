@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/cache"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// MediaURLResolver turns stored media into the URLs responses carry. It knows
+// where each kind of asset lives in storage, so services never build object
+// paths themselves, and it resolves avatars in batches so listings cost a fixed
+// number of queries however many users they show.
+type MediaURLResolver interface {
+	// AvatarImages returns the variants of each avatar keyed by avatar ID.
+	// Deleted avatars resolve to the default avatar.
+	AvatarImages(ctx context.Context, avatars []*entities.Avatar) map[int]dtos.ImageVariants
+	// AvatarImagesByID is AvatarImages for avatars not loaded yet; IDs that no
+	// longer exist resolve to the default avatar
+	AvatarImagesByID(ctx context.Context, avatarIDs []int) map[int]dtos.ImageVariants
+	DefaultAvatar() dtos.ImageVariants
+	// ProfilePhotoImages returns the variants of a custom profile photo whatever
+	// its moderation status; callers decide who may see it
+	ProfilePhotoImages(ctx context.Context, photo *entities.UserProfilePhoto) dtos.ImageVariants
+	// UserImages returns the picture shown for each user to other users, keyed by
+	// user ID: the approved profile photo, else the chosen avatar. Users with
+	// neither are left out.
+	UserImages(ctx context.Context, users []*entities.User) map[string]dtos.ImageVariants
+	// SubmissionMediaURL returns a signed URL for a submission's media
+	SubmissionMediaURL(ctx context.Context, thunderSeat *entities.ThunderSeat) *string
+	// QRCodeURL returns a signed URL for a winner's QR code
+	QRCodeURL(ctx context.Context, winner *entities.ThunderSeatWinner) *string
+	// ForgetAvatar drops cached variants after an avatar changed
+	ForgetAvatar(avatarID int)
+}
+
+type mediaURLResolver struct {
+	txnManager     *utils.TransactionManager
+	avatarRepo     repository.AvatarRepository
+	mediaAssetRepo repository.MediaAssetRepository
+	photoRepo      repository.UserProfilePhotoRepository
+	urlResolver    *utils.ObjectURLResolver
+	avatarCache    *cache.TTLCache[int, dtos.ImageVariants]
+}
+
+func NewMediaURLResolver(
+	txnManager *utils.TransactionManager,
+	avatarRepo repository.AvatarRepository,
+	mediaAssetRepo repository.MediaAssetRepository,
+	photoRepo repository.UserProfilePhotoRepository,
+	urlResolver *utils.ObjectURLResolver,
+) MediaURLResolver {
+	return &mediaURLResolver{
+		txnManager:     txnManager,
+		avatarRepo:     avatarRepo,
+		mediaAssetRepo: mediaAssetRepo,
+		photoRepo:      photoRepo,
+		urlResolver:    urlResolver,
+		avatarCache:    cache.NewTTLCache[int, dtos.ImageVariants](constants.AVATAR_IMAGES_CACHE_TTL),
+	}
+}
+
+func (r *mediaURLResolver) AvatarImages(ctx context.Context, avatars []*entities.Avatar) map[int]dtos.ImageVariants {
+	images := make(map[int]dtos.ImageVariants, len(avatars))
+	var uncached []*entities.Avatar
+	for _, avatar := range avatars {
+		if avatar == nil {
+			continue
+		}
+		if _, done := images[avatar.ID]; done {
+			continue
+		}
+		if avatar.IsDeleted {
+			images[avatar.ID] = r.DefaultAvatar()
+			continue
+		}
+		if variants, ok := r.avatarCache.Get(avatar.ID); ok {
+			images[avatar.ID] = variants
+			continue
+		}
+		images[avatar.ID] = dtos.ImageVariants{}
+		uncached = append(uncached, avatar)
+	}
+	if len(uncached) == 0 {
+		return images
+	}
+
+	thumbnails := r.loadThumbnails(ctx, uncached)
+	for _, avatar := range uncached {
+		full := r.publicURL(ctx, avatar.ObjectPath())
+		variants := dtos.ImageVariants{Thumb: full, Full: full}
+		if avatar.MediaAssetID != nil {
+			if thumbnail, ok := thumbnails[*avatar.MediaAssetID]; ok {
+				variants.Thumb = r.publicURL(ctx, thumbnail)
+			}
+		}
+		images[avatar.ID] = variants
+		r.avatarCache.Set(avatar.ID, variants)
+	}
+	return images
+}
+
+func (r *mediaURLResolver) AvatarImagesByID(ctx context.Context, avatarIDs []int) map[int]dtos.ImageVariants {
+	images := make(map[int]dtos.ImageVariants, len(avatarIDs))
+	var missing []int
+	for _, id := range avatarIDs {
+		if _, done := images[id]; done {
+			continue
+		}
+		if variants, ok := r.avatarCache.Get(id); ok {
+			images[id] = variants
+			continue
+		}
+		images[id] = r.DefaultAvatar()
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return images
+	}
+
+	avatars, err := r.avatarRepo.FindByCondition(ctx, r.txnManager.GetDB(), "id IN ?", missing)
+	if err != nil {
+		log.WithError(err).Warn("Failed to load avatars, using the default avatar")
+		return images
+	}
+	loaded := make([]*entities.Avatar, len(avatars))
+	for i := range avatars {
+		loaded[i] = &avatars[i]
+	}
+	for id, variants := range r.AvatarImages(ctx, loaded) {
+		images[id] = variants
+	}
+	return images
+}
+
+func (r *mediaURLResolver) DefaultAvatar() dtos.ImageVariants {
+	url := r.publicURL(context.Background(), constants.DEFAULT_AVATAR_OBJECT_PATH)
+	return dtos.ImageVariants{Thumb: url, Full: url}
+}
+
+func (r *mediaURLResolver) ProfilePhotoImages(ctx context.Context, photo *entities.UserProfilePhoto) dtos.ImageVariants {
+	return dtos.ImageVariants{
+		Thumb: r.publicURL(ctx, photo.ThumbPath),
+		Full:  r.publicURL(ctx, photo.FullPath),
+	}
+}
+
+func (r *mediaURLResolver) UserImages(ctx context.Context, users []*entities.User) map[string]dtos.ImageVariants {
+	images := make(map[string]dtos.ImageVariants, len(users))
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		if user != nil && user.ID != "" {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return images
+	}
+
+	photos, err := r.photoRepo.FindApprovedByUserIDs(ctx, r.txnManager.GetDB(), userIDs)
+	if err != nil {
+		// Avatars are still a valid picture, so a failed lookup only hides photos
+		log.WithError(err).Warn("Failed to load profile photos, using avatars")
+	}
+	for i := range photos {
+		images[photos[i].UserID] = r.ProfilePhotoImages(ctx, &photos[i])
+	}
+
+	var avatars []*entities.Avatar
+	for _, user := range users {
+		if user == nil || user.Avatar == nil {
+			continue
+		}
+		if _, ok := images[user.ID]; !ok {
+			avatars = append(avatars, user.Avatar)
+		}
+	}
+	avatarImages := r.AvatarImages(ctx, avatars)
+	for _, user := range users {
+		if user == nil || user.Avatar == nil {
+			continue
+		}
+		if _, ok := images[user.ID]; ok {
+			continue
+		}
+		if variants, ok := avatarImages[user.Avatar.ID]; ok {
+			images[user.ID] = variants
+		}
+	}
+	return images
+}
+
+func (r *mediaURLResolver) SubmissionMediaURL(ctx context.Context, thunderSeat *entities.ThunderSeat) *string {
+	if objectPath := thunderSeat.MediaObjectPath(); objectPath != "" {
+		return r.urlResolver.Resolve(ctx, objectPath)
+	}
+	// Rows written before media keys were recorded only have the URL
+	if thunderSeat.MediaURL != nil {
+		return r.urlResolver.ResolveStored(ctx, *thunderSeat.MediaURL)
+	}
+	return nil
+}
+
+func (r *mediaURLResolver) QRCodeURL(ctx context.Context, winner *entities.ThunderSeatWinner) *string {
+	return r.urlResolver.Resolve(ctx, winner.QRCode)
+}
+
+func (r *mediaURLResolver) ForgetAvatar(avatarID int) {
+	r.avatarCache.Delete(avatarID)
+}
+
+// loadThumbnails returns thumbnail object paths keyed by the avatar's media asset ID
+func (r *mediaURLResolver) loadThumbnails(ctx context.Context, avatars []*entities.Avatar) map[int]string {
+	assetIDs := make([]int, 0, len(avatars))
+	for _, avatar := range avatars {
+		if avatar.MediaAssetID != nil {
+			assetIDs = append(assetIDs, *avatar.MediaAssetID)
+		}
+	}
+
+	renditions, err := r.mediaAssetRepo.FindReadyRenditions(ctx, r.txnManager.GetDB(), assetIDs, entities.MediaRenditionThumbnail)
+	if err != nil {
+		// Full size images still work, so a failed lookup only costs bandwidth
+		log.WithError(err).Warn("Failed to load avatar thumbnails")
+		return nil
+	}
+
+	thumbnails := make(map[int]string, len(renditions))
+	for _, rendition := range renditions {
+		if rendition.ParentID != nil {
+			thumbnails[*rendition.ParentID] = rendition.ObjectPath
+		}
+	}
+	return thumbnails
+}
+
+func (r *mediaURLResolver) publicURL(ctx context.Context, objectPath string) string {
+	if url := r.urlResolver.Resolve(ctx, objectPath); url != nil {
+		return *url
+	}
+	return ""
+}
+
+// avatarSummary returns the avatar fields of a user summary from images batch
+// resolved by UserImages. The avatar name is kept even when a profile photo is shown.
+func avatarSummary(user *entities.User, images map[string]dtos.ImageVariants) (*string, *string, *dtos.ImageVariants) {
+	if user == nil {
+		return nil, nil, nil
+	}
+	var avatarName *string
+	if user.Avatar != nil {
+		avatarName = &user.Avatar.Name
+	}
+	variants, ok := images[user.ID]
+	if !ok {
+		return nil, avatarName, nil
+	}
+	return &variants.Full, avatarName, &variants
+}
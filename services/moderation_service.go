@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/queue"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type ModerationService interface {
+	GetModerationQueue(ctx context.Context, req dtos.ModerationQueueRequest) ([]dtos.ModerationQueueItem, int64, error)
+	ModerateSubmissions(ctx context.Context, req dtos.ModerateSubmissionsRequest, moderatedBy string) (*dtos.ModerateSubmissionsResponse, error)
+	ReportSubmission(ctx context.Context, submissionID int, userID string, req dtos.ReportSubmissionRequest) error
+}
+
+type moderationService struct {
+	txnManager          *utils.TransactionManager
+	thunderSeatRepo     repository.ThunderSeatRepository
+	reportRepo          repository.ThunderSeatReportRepository
+	gcsService          utils.GCSService
+	pointsService       PointsService
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	workerPool          *queue.WorkerPool
+}
+
+func NewModerationService(
+	txnManager *utils.TransactionManager,
+	thunderSeatRepo repository.ThunderSeatRepository,
+	reportRepo repository.ThunderSeatReportRepository,
+	gcsService utils.GCSService,
+	pointsService PointsService,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	workerPool *queue.WorkerPool,
+) ModerationService {
+	return &moderationService{
+		txnManager:          txnManager,
+		thunderSeatRepo:     thunderSeatRepo,
+		reportRepo:          reportRepo,
+		gcsService:          gcsService,
+		pointsService:       pointsService,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		workerPool:          workerPool,
+	}
+}
+
+func (s *moderationService) GetModerationQueue(ctx context.Context, req dtos.ModerationQueueRequest) ([]dtos.ModerationQueueItem, int64, error) {
+	filter := repository.ModerationQueueFilter{
+		Status:       req.Status,
+		WeekNumber:   req.WeekNumber,
+		MediaType:    req.MediaType,
+		ReportedOnly: req.Reported,
+	}
+
+	entries, total, err := s.thunderSeatRepo.FindForModeration(ctx, s.txnManager.GetDB(), filter, req.Limit, req.Offset)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("Failed to get moderation queue", err)
+	}
+
+	items := make([]dtos.ModerationQueueItem, len(entries))
+	for i, entry := range entries {
+		var signedURL *string
+		if objectPath := entry.MediaObjectPath(); objectPath != "" {
+			url, err := s.gcsService.GetFileSignedURL(ctx, objectPath, constants.MODERATION_SIGNED_URL_EXPIRY)
+			if err != nil {
+				log.WithError(err).WithField("submission_id", entry.ID).Warn("Failed to sign media URL for moderation queue")
+			} else {
+				signedURL = &url
+			}
+		}
+
+		items[i] = dtos.ModerationQueueItem{
+			ID:               entry.ID,
+			UserID:           entry.UserID,
+			Name:             entry.User.Name,
+			WeekNumber:       entry.WeekNumber,
+			Answer:           entry.Answer,
+			MediaType:        entry.MediaType,
+			SignedMediaURL:   signedURL,
+			ModerationStatus: entry.ModerationStatus,
+			ModerationReason: entry.ModerationReason,
+			ReportCount:      entry.ReportCount,
+			CreatedOn:        entry.CreatedOn.Format(time.RFC3339),
+		}
+	}
+
+	return items, total, nil
+}
+
+func (s *moderationService) ModerateSubmissions(ctx context.Context, req dtos.ModerateSubmissionsRequest, moderatedBy string) (*dtos.ModerateSubmissionsResponse, error) {
+	status := entities.ModerationStatusApproved
+	if req.Action == "reject" {
+		status = entities.ModerationStatusRejected
+		if req.Reason == nil || *req.Reason == "" {
+			return nil, errors.NewBadRequestError("A reason is required when rejecting submissions", nil)
+		}
+	}
+
+	var updated int64
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		updated, err = s.thunderSeatRepo.UpdateModerationStatus(ctx, tx, req.IDs, status, req.Reason, moderatedBy)
+		if err != nil || status != entities.ModerationStatusRejected {
+			return err
+		}
+		return s.forfeitRejectedEntryPoints(ctx, tx, req.IDs)
+	})
+	if err != nil {
+		log.WithError(err).WithField("ids", req.IDs).Error("Failed to moderate submissions")
+		return nil, errors.NewInternalServerError("Failed to moderate submissions", err)
+	}
+
+	log.WithFields(log.Fields{
+		"status":       status,
+		"requested":    len(req.IDs),
+		"updated":      updated,
+		"moderated_by": moderatedBy,
+	}).Info("Thunder seat submissions moderated")
+
+	s.notifyEntrants(req.IDs, status)
+
+	return &dtos.ModerateSubmissionsResponse{
+		Status:  status,
+		Updated: updated,
+	}, nil
+}
+
+// forfeitRejectedEntryPoints takes back the week's entry points from each user whose
+// rejected submissions leave them with no live entry in that week
+func (s *moderationService) forfeitRejectedEntryPoints(ctx context.Context, tx *gorm.DB, ids []int) error {
+	rejected, err := s.thunderSeatRepo.FindByCondition(ctx, tx, "id IN ?", ids)
+	if err != nil {
+		return err
+	}
+
+	type userWeek struct {
+		userID     string
+		weekNumber int
+	}
+	seen := make(map[userWeek]bool, len(rejected))
+	for _, entry := range rejected {
+		key := userWeek{userID: entry.UserID, weekNumber: entry.WeekNumber}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := forfeitThunderSeatEntryPoints(ctx, tx, s.pointsService, s.thunderSeatRepo, entry.UserID, entry.WeekNumber); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyEntrants tells the owners of moderated submissions about the decision in
+// their language. It runs in the background so a failed push never fails moderation.
+func (s *moderationService) notifyEntrants(ids []int, status string) {
+	if s.notificationService == nil || s.workerPool == nil {
+		return
+	}
+
+	template := constants.NOTIFICATION_TEMPLATE_SUBMISSION_APPROVED
+	if status == entities.ModerationStatusRejected {
+		template = constants.NOTIFICATION_TEMPLATE_SUBMISSION_REJECTED
+	}
+
+	task := func(ctx context.Context) error {
+		db := s.txnManager.GetDB()
+		entries, err := s.thunderSeatRepo.FindByCondition(ctx, db, "id IN ? AND moderation_status = ? AND withdrawn_on IS NULL", ids, status)
+		if err != nil || len(entries) == 0 {
+			return err
+		}
+
+		userIDs := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			userIDs = append(userIDs, entry.UserID)
+		}
+		users, err := s.userRepo.FindByCondition(ctx, db, "id IN ?", userIDs)
+		if err != nil {
+			return err
+		}
+		usersByID := make(map[string]*entities.User, len(users))
+		for i := range users {
+			usersByID[users[i].ID] = &users[i]
+		}
+
+		for _, entry := range entries {
+			user, ok := usersByID[entry.UserID]
+			if !ok {
+				continue
+			}
+			params := map[string]string{"week": strconv.Itoa(entry.WeekNumber)}
+			data := map[string]string{
+				"type":          template,
+				"submission_id": strconv.Itoa(entry.ID),
+			}
+			if err := s.notificationService.SendLocalizedNotification(ctx, user, template, params, data); err != nil {
+				log.WithError(err).WithField("submission_id", entry.ID).Warn("Failed to notify entrant of moderation decision")
+			}
+		}
+		return nil
+	}
+
+	if err := s.workerPool.Submit(task); err != nil {
+		log.WithError(err).Warn("Failed to submit moderation notification task to worker pool")
+	}
+}
+
+func (s *moderationService) ReportSubmission(ctx context.Context, submissionID int, userID string, req dtos.ReportSubmissionRequest) error {
+	thunderSeat, err := s.thunderSeatRepo.FindByID(ctx, s.txnManager.GetDB(), submissionID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to get submission", err)
+	}
+	if thunderSeat == nil || thunderSeat.WithdrawnOn != nil {
+		return errors.NewNotFoundError("Submission not found", nil)
+	}
+	if thunderSeat.UserID == userID {
+		return errors.NewBadRequestError("You cannot report your own submission", nil)
+	}
+
+	report := &entities.ThunderSeatReport{
+		ThunderSeatID: submissionID,
+		ReportedBy:    userID,
+		Reason:        req.Reason,
+		Details:       req.Details,
+		CreatedOn:     time.Now(),
+	}
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.reportRepo.Create(ctx, tx, report); err != nil {
+			return err
+		}
+		return s.thunderSeatRepo.IncrementReportCount(ctx, tx, submissionID, constants.THUNDER_SEAT_REPORT_FLAG_THRESHOLD)
+	})
+	if err != nil {
+		var dupErr *errors.DuplicateError
+		if stderrors.As(err, &dupErr) {
+			return errors.NewConflictError("You have already reported this submission", err)
+		}
+		log.WithError(err).WithField("submission_id", submissionID).Error("Failed to report submission")
+		return errors.NewInternalServerError("Failed to report submission", err)
+	}
+
+	return nil
+}
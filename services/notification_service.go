@@ -3,34 +3,43 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"cloud.google.com/go/pubsub"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/config"
 	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/vendors"
 )
 
 type NotificationService interface {
 	SendNotification(ctx context.Context, token string, title string, body string, data map[string]string) error
+	// SendLocalizedNotification sends a notification template to the user's device
+	// in the user's language, filling its {name} placeholders from params. Users
+	// without a device token are skipped.
+	SendLocalizedNotification(ctx context.Context, user *entities.User, template string, params map[string]string, data map[string]string) error
 	PublishNotifyMeMessage(ctx context.Context, phoneNumber string, email string) error
 }
 
 type notificationService struct {
-	firebaseClient *vendors.FirebaseClient
-	pubsubClient   *pubsub.Client
-	cfg            *config.Config
+	firebaseClient      *vendors.FirebaseClient
+	pubsubClient        *pubsub.Client
+	localizationService LocalizationService
+	cfg                 *config.Config
 }
 
 func NewNotificationService(
 	firebaseClient *vendors.FirebaseClient,
 	pubsubClient *pubsub.Client,
+	localizationService LocalizationService,
 ) NotificationService {
 	return &notificationService{
-		firebaseClient: firebaseClient,
-		pubsubClient:   pubsubClient,
-		cfg:            config.GetConfig(),
+		firebaseClient:      firebaseClient,
+		pubsubClient:        pubsubClient,
+		localizationService: localizationService,
+		cfg:                 config.GetConfig(),
 	}
 }
 
@@ -49,6 +58,20 @@ func (s *notificationService) SendNotification(ctx context.Context, token string
 	return nil
 }
 
+func (s *notificationService) SendLocalizedNotification(ctx context.Context, user *entities.User, template string, params map[string]string, data map[string]string) error {
+	if user.DeviceToken == nil || *user.DeviceToken == "" {
+		return nil
+	}
+
+	languageID := s.localizationService.ResolveLanguage(ctx, 0, user, "")
+	title, body, ok := s.localizationService.RenderTemplate(ctx, languageID, template, params)
+	if !ok {
+		return fmt.Errorf("notification template %q has no translations", template)
+	}
+
+	return s.SendNotification(ctx, *user.DeviceToken, title, body, data)
+}
+
 func (s *notificationService) PublishNotifyMeMessage(ctx context.Context, phoneNumber string, email string) error {
 	if s.pubsubClient == nil {
 		log.Warn("PubSub client not initialized")
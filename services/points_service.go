@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// PointAward is a credit to the points ledger. SourceType and SourceID identify
+// what earned it; the same pair is only ever credited once per user.
+type PointAward struct {
+	UserID      string
+	SourceType  string
+	SourceID    string
+	Amount      int
+	Description string
+}
+
+// PointsService keeps the points ledger. Awards and reversals run inside the
+// caller's transaction so points are credited or taken back exactly when the event
+// that caused it is stored.
+type PointsService interface {
+	// Award credits the award unless it was credited before, reporting whether it was
+	Award(ctx context.Context, tx *gorm.DB, award PointAward) (bool, error)
+	// Reverse takes back what was credited for the award's source with a compensating
+	// entry, reporting whether anything was taken back. The award's amount is ignored;
+	// the credited amount is reversed. A reversed source is not credited again.
+	Reverse(ctx context.Context, tx *gorm.DB, award PointAward) (bool, error)
+	GetPoints(ctx context.Context, userID string, req dtos.PointsHistoryRequest) (*dtos.PointsResponse, error)
+}
+
+type pointsService struct {
+	txnManager         *utils.TransactionManager
+	pointsRepo         repository.PointTransactionRepository
+	leaderboardService LeaderboardService
+}
+
+func NewPointsService(
+	txnManager *utils.TransactionManager,
+	pointsRepo repository.PointTransactionRepository,
+	leaderboardService LeaderboardService,
+) PointsService {
+	return &pointsService{
+		txnManager:         txnManager,
+		pointsRepo:         pointsRepo,
+		leaderboardService: leaderboardService,
+	}
+}
+
+func (s *pointsService) Award(ctx context.Context, tx *gorm.DB, award PointAward) (bool, error) {
+	if award.Amount <= 0 {
+		return false, nil
+	}
+
+	balance, err := s.pointsRepo.LockBalance(ctx, tx, award.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock points balance: %w", err)
+	}
+	weekNumber, err := s.leaderboardService.ActiveWeekNumber(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+
+	entry := &entities.PointTransaction{
+		UserID:       award.UserID,
+		SourceType:   award.SourceType,
+		SourceID:     award.SourceID,
+		Amount:       award.Amount,
+		BalanceAfter: balance + award.Amount,
+		Description:  award.Description,
+		WeekNumber:   weekNumber,
+	}
+	credited, err := s.pointsRepo.Append(ctx, tx, entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to append points transaction: %w", err)
+	}
+	if !credited {
+		return false, nil
+	}
+
+	if err := s.leaderboardService.RecordPoints(ctx, tx, award.UserID, award.Amount, weekNumber, entry.CreatedOn); err != nil {
+		return false, err
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":       award.UserID,
+		"source_type":   award.SourceType,
+		"source_id":     award.SourceID,
+		"amount":        award.Amount,
+		"balance_after": entry.BalanceAfter,
+	}).Info("Points awarded")
+	return true, nil
+}
+
+func (s *pointsService) Reverse(ctx context.Context, tx *gorm.DB, award PointAward) (bool, error) {
+	balance, err := s.pointsRepo.LockBalance(ctx, tx, award.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock points balance: %w", err)
+	}
+
+	credit, err := s.pointsRepo.FindBySource(ctx, tx, award.UserID, award.SourceType, award.SourceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find points transaction: %w", err)
+	}
+	if credit == nil || credit.Amount <= 0 {
+		return false, nil
+	}
+
+	// The reversal counts against the leaderboard week the award counted toward,
+	// even when another week is active by now
+	entry := &entities.PointTransaction{
+		UserID:       award.UserID,
+		SourceType:   entities.PointReversalSourceType(award.SourceType),
+		SourceID:     award.SourceID,
+		Amount:       -credit.Amount,
+		BalanceAfter: balance - credit.Amount,
+		Description:  award.Description,
+		WeekNumber:   credit.WeekNumber,
+	}
+	reversed, err := s.pointsRepo.Append(ctx, tx, entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to append points transaction: %w", err)
+	}
+	if !reversed {
+		return false, nil
+	}
+
+	if err := s.leaderboardService.RecordPoints(ctx, tx, award.UserID, entry.Amount, credit.WeekNumber, entry.CreatedOn); err != nil {
+		return false, err
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":       award.UserID,
+		"source_type":   award.SourceType,
+		"source_id":     award.SourceID,
+		"amount":        entry.Amount,
+		"balance_after": entry.BalanceAfter,
+	}).Info("Points reversed")
+	return true, nil
+}
+
+func (s *pointsService) GetPoints(ctx context.Context, userID string, req dtos.PointsHistoryRequest) (*dtos.PointsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = constants.POINTS_HISTORY_DEFAULT_LIMIT
+	}
+
+	db := s.txnManager.GetDB()
+	balance, err := s.pointsRepo.Balance(ctx, db, userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get points balance", err)
+	}
+	entries, total, err := s.pointsRepo.FindByUserID(ctx, db, userID, limit, req.Offset)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get points history", err)
+	}
+
+	transactions := make([]dtos.PointTransactionDTO, len(entries))
+	for i, entry := range entries {
+		transactions[i] = dtos.PointTransactionDTO{
+			ID:           entry.ID,
+			SourceType:   entry.SourceType,
+			SourceID:     entry.SourceID,
+			Amount:       entry.Amount,
+			BalanceAfter: entry.BalanceAfter,
+			Description:  entry.Description,
+			CreatedOn:    entry.CreatedOn.Format(time.RFC3339),
+		}
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return &dtos.PointsResponse{
+		Balance:      balance,
+		Transactions: transactions,
+		Meta: dtos.PaginationMeta{
+			Page:       (req.Offset / limit) + 1,
+			PageSize:   limit,
+			TotalPages: totalPages,
+			TotalCount: total,
+		},
+	}, nil
+}
+
+// profileQuestionAward pays a question's points the first time it is answered;
+// changing the answer later earns nothing
+func profileQuestionAward(userID string, question *entities.QuestionMaster) PointAward {
+	return PointAward{
+		UserID:      userID,
+		SourceType:  entities.PointSourceProfileQuestion,
+		SourceID:    strconv.Itoa(question.ID),
+		Amount:      question.QuesPoint,
+		Description: "Answered a profile question",
+	}
+}
+
+// thunderSeatEntryAward pays once per contest week however many entries are made
+func thunderSeatEntryAward(userID string, weekNumber int) PointAward {
+	return PointAward{
+		UserID:      userID,
+		SourceType:  entities.PointSourceThunderSeat,
+		SourceID:    fmt.Sprintf("week-%d", weekNumber),
+		Amount:      constants.POINTS_THUNDER_SEAT_ENTRY,
+		Description: fmt.Sprintf("Entered Thunder Seat week %d", weekNumber),
+	}
+}
+
+// forfeitThunderSeatEntryPoints takes back a week's entry points once the user has no
+// entry left in that week that is neither withdrawn nor rejected
+func forfeitThunderSeatEntryPoints(ctx context.Context, tx *gorm.DB, pointsService PointsService, thunderSeatRepo repository.ThunderSeatRepository, userID string, weekNumber int) error {
+	live, err := thunderSeatRepo.CountLiveByUserAndWeek(ctx, tx, userID, weekNumber)
+	if err != nil {
+		return err
+	}
+	if live > 0 {
+		return nil
+	}
+
+	award := thunderSeatEntryAward(userID, weekNumber)
+	award.Description = fmt.Sprintf("Thunder Seat week %d entry withdrawn or rejected", weekNumber)
+	_, err = pointsService.Reverse(ctx, tx, award)
+	return err
+}
+
+// referralAward pays the referrer once per user who signed up with their code
+func referralAward(referrerID, referredUserID string) PointAward {
+	return PointAward{
+		UserID:      referrerID,
+		SourceType:  entities.PointSourceReferral,
+		SourceID:    referredUserID,
+		Amount:      constants.POINTS_REFERRAL,
+		Description: "Referred a new user",
+	}
+}
+
+// loginStreakAward pays each time the streak reaches a multiple of
+// constants.LOGIN_STREAK_DAYS, keyed by the day it was reached
+func loginStreakAward(loginCount *entities.LoginCount) (PointAward, bool) {
+	if loginCount.StreakDate == nil || loginCount.StreakDays == 0 || loginCount.StreakDays%constants.LOGIN_STREAK_DAYS != 0 {
+		return PointAward{}, false
+	}
+	return PointAward{
+		UserID:      loginCount.UserID,
+		SourceType:  entities.PointSourceLoginStreak,
+		SourceID:    loginCount.StreakDate.Format("2006-01-02"),
+		Amount:      constants.POINTS_LOGIN_STREAK,
+		Description: fmt.Sprintf("Logged in %d days in a row", loginCount.StreakDays),
+	}, true
+}
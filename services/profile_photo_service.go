@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/media"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// ProfilePhotoService manages custom profile photos. Owners see their photo as
+// soon as it is uploaded; everyone else sees the chosen avatar until a moderator
+// approves it.
+type ProfilePhotoService interface {
+	UploadProfilePhoto(ctx context.Context, userID string, file *utils.ValidatedFile) (*dtos.ProfilePhotoDTO, error)
+	GetProfilePhoto(ctx context.Context, userID string) (*dtos.ProfilePhotoDTO, error)
+	DeleteProfilePhoto(ctx context.Context, userID string) error
+	GetModerationQueue(ctx context.Context, req dtos.ProfilePhotoQueueRequest) ([]dtos.ProfilePhotoQueueItem, int64, error)
+	ModerateProfilePhotos(ctx context.Context, req dtos.ModerateProfilePhotosRequest, moderatedBy string) (*dtos.ModerateSubmissionsResponse, error)
+}
+
+type profilePhotoService struct {
+	txnManager    *utils.TransactionManager
+	photoRepo     repository.UserProfilePhotoRepository
+	gcsService    utils.GCSService
+	mediaResolver MediaURLResolver
+}
+
+func NewProfilePhotoService(
+	txnManager *utils.TransactionManager,
+	photoRepo repository.UserProfilePhotoRepository,
+	gcsService utils.GCSService,
+	mediaResolver MediaURLResolver,
+) ProfilePhotoService {
+	return &profilePhotoService{
+		txnManager:    txnManager,
+		photoRepo:     photoRepo,
+		gcsService:    gcsService,
+		mediaResolver: mediaResolver,
+	}
+}
+
+// UploadProfilePhoto crops the image square, stores it in the standard sizes and
+// replaces the user's previous photo. Only the re-encoded sizes are stored, so
+// no metadata of the original upload is kept.
+func (s *profilePhotoService) UploadProfilePhoto(ctx context.Context, userID string, file *utils.ValidatedFile) (*dtos.ProfilePhotoDTO, error) {
+	full, thumb, err := profilePhotoSizes(file)
+	if err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("%s/%s/%s", constants.PROFILE_PHOTO_FOLDER, userID, uuid.New().String())
+	photo := &entities.UserProfilePhoto{
+		UserID:           userID,
+		FullPath:         base + "_full.jpg",
+		ThumbPath:        base + "_thumb.jpg",
+		IsActive:         true,
+		ModerationStatus: entities.ModerationStatusPending,
+	}
+
+	if _, _, err := s.gcsService.UploadFileFromBytes(ctx, full, photo.FullPath, "image/jpeg"); err != nil {
+		return nil, errors.NewInternalServerError("Failed to upload profile photo", err)
+	}
+	if _, _, err := s.gcsService.UploadFileFromBytes(ctx, thumb, photo.ThumbPath, "image/jpeg"); err != nil {
+		s.cleanup(ctx, photo)
+		return nil, errors.NewInternalServerError("Failed to upload profile photo", err)
+	}
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.photoRepo.DeactivateByUserID(ctx, tx, userID); err != nil {
+			return err
+		}
+		return s.photoRepo.Create(ctx, tx, photo)
+	})
+	if err != nil {
+		s.cleanup(ctx, photo)
+		log.WithError(err).WithField("user_id", userID).Error("Failed to save profile photo")
+		return nil, errors.NewInternalServerError("Failed to save profile photo", err)
+	}
+
+	log.WithFields(log.Fields{
+		"user_id":          userID,
+		"profile_photo_id": photo.ID,
+	}).Info("Profile photo uploaded for moderation")
+
+	return s.toProfilePhotoDTO(ctx, photo), nil
+}
+
+func (s *profilePhotoService) GetProfilePhoto(ctx context.Context, userID string) (*dtos.ProfilePhotoDTO, error) {
+	photo, err := s.photoRepo.FindActiveByUserID(ctx, s.txnManager.GetDB(), userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get profile photo", err)
+	}
+	if photo == nil {
+		return nil, nil
+	}
+	return s.toProfilePhotoDTO(ctx, photo), nil
+}
+
+// DeleteProfilePhoto retires the active photo; its objects are removed by storage
+// garbage collection once nothing references them
+func (s *profilePhotoService) DeleteProfilePhoto(ctx context.Context, userID string) error {
+	var removed int64
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		removed, err = s.photoRepo.DeactivateByUserID(ctx, tx, userID)
+		return err
+	})
+	if err != nil {
+		return errors.NewInternalServerError("Failed to remove profile photo", err)
+	}
+	if removed == 0 {
+		return errors.NewNotFoundError("No profile photo to remove", nil)
+	}
+	return nil
+}
+
+func (s *profilePhotoService) GetModerationQueue(ctx context.Context, req dtos.ProfilePhotoQueueRequest) ([]dtos.ProfilePhotoQueueItem, int64, error) {
+	status := req.Status
+	if status == "" {
+		status = entities.ModerationStatusPending
+	}
+
+	photos, total, err := s.photoRepo.FindForModeration(ctx, s.txnManager.GetDB(), status, req.Limit, req.Offset)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("Failed to get profile photo moderation queue", err)
+	}
+
+	items := make([]dtos.ProfilePhotoQueueItem, len(photos))
+	for i, photo := range photos {
+		var name *string
+		if photo.User != nil {
+			name = photo.User.Name
+		}
+		items[i] = dtos.ProfilePhotoQueueItem{
+			ID:               photo.ID,
+			UserID:           photo.UserID,
+			Name:             name,
+			Variants:         s.mediaResolver.ProfilePhotoImages(ctx, &photo),
+			ModerationStatus: photo.ModerationStatus,
+			ModerationReason: photo.ModerationReason,
+			CreatedOn:        photo.CreatedOn.Format(time.RFC3339),
+		}
+	}
+	return items, total, nil
+}
+
+func (s *profilePhotoService) ModerateProfilePhotos(ctx context.Context, req dtos.ModerateProfilePhotosRequest, moderatedBy string) (*dtos.ModerateSubmissionsResponse, error) {
+	status := entities.ModerationStatusApproved
+	if req.Action == "reject" {
+		status = entities.ModerationStatusRejected
+		if req.Reason == nil || *req.Reason == "" {
+			return nil, errors.NewBadRequestError("A reason is required when rejecting profile photos", nil)
+		}
+	}
+
+	var updated int64
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		updated, err = s.photoRepo.UpdateModerationStatus(ctx, tx, req.IDs, status, req.Reason, moderatedBy)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).WithField("ids", req.IDs).Error("Failed to moderate profile photos")
+		return nil, errors.NewInternalServerError("Failed to moderate profile photos", err)
+	}
+
+	log.WithFields(log.Fields{
+		"status":       status,
+		"requested":    len(req.IDs),
+		"updated":      updated,
+		"moderated_by": moderatedBy,
+	}).Info("Profile photos moderated")
+
+	return &dtos.ModerateSubmissionsResponse{
+		Status:  status,
+		Updated: updated,
+	}, nil
+}
+
+func (s *profilePhotoService) toProfilePhotoDTO(ctx context.Context, photo *entities.UserProfilePhoto) *dtos.ProfilePhotoDTO {
+	return &dtos.ProfilePhotoDTO{
+		ID:               photo.ID,
+		ModerationStatus: photo.ModerationStatus,
+		ModerationReason: photo.ModerationReason,
+		Variants:         s.mediaResolver.ProfilePhotoImages(ctx, photo),
+		CreatedOn:        photo.CreatedOn.Format(time.RFC3339),
+	}
+}
+
+// cleanup removes objects uploaded for a photo that was not saved
+func (s *profilePhotoService) cleanup(ctx context.Context, photo *entities.UserProfilePhoto) {
+	for _, objectPath := range []string{photo.FullPath, photo.ThumbPath} {
+		if err := s.gcsService.DeleteFile(ctx, s.gcsService.GetPublicURL(objectPath)); err != nil {
+			log.WithError(err).WithField("object_path", objectPath).Warn("Failed to cleanup profile photo object")
+		}
+	}
+}
+
+// profilePhotoSizes decodes the upload and encodes the full and thumbnail sizes
+func profilePhotoSizes(file *utils.ValidatedFile) ([]byte, []byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to read profile photo", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to read profile photo", err)
+	}
+
+	img, err := media.Decode(data)
+	if err != nil {
+		return nil, nil, errors.NewBadRequestError("Profile photo must be a JPEG, PNG or GIF image", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() < constants.PROFILE_PHOTO_MIN_SIZE || bounds.Dy() < constants.PROFILE_PHOTO_MIN_SIZE {
+		return nil, nil, errors.NewBadRequestError(fmt.Sprintf("Profile photo must be at least %dx%d pixels", constants.PROFILE_PHOTO_MIN_SIZE, constants.PROFILE_PHOTO_MIN_SIZE), nil)
+	}
+
+	full, err := media.EncodeJPEG(media.SquareThumbnail(img, constants.PROFILE_PHOTO_FULL_SIZE))
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to resize profile photo", err)
+	}
+	thumb, err := media.EncodeJPEG(media.SquareThumbnail(img, constants.PROFILE_PHOTO_THUMB_SIZE))
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to resize profile photo", err)
+	}
+	return full, thumb, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// QuestionAnalyticsService reports how users answered questions, by user
+// segment. Reports read counts materialized by Refresh, which the
+// question-analytics command runs periodically, so they never scan the answers
+// table and lag the answers by up to one refresh.
+type QuestionAnalyticsService interface {
+	GetQuestionAnalytics(ctx context.Context, req dtos.QuestionAnalyticsRequest) (*dtos.QuestionAnalyticsResponse, error)
+	Refresh(ctx context.Context) (*dtos.QuestionAnalyticsRefreshResponse, error)
+}
+
+type questionAnalyticsService struct {
+	txnManager            *utils.TransactionManager
+	questionAnalyticsRepo repository.QuestionAnalyticsRepository
+	questionRepo          repository.QuestionRepository
+	optionMasterRepo      repository.OptionMasterRepository
+}
+
+func NewQuestionAnalyticsService(
+	txnManager *utils.TransactionManager,
+	questionAnalyticsRepo repository.QuestionAnalyticsRepository,
+	questionRepo repository.QuestionRepository,
+	optionMasterRepo repository.OptionMasterRepository,
+) QuestionAnalyticsService {
+	return &questionAnalyticsService{
+		txnManager:            txnManager,
+		questionAnalyticsRepo: questionAnalyticsRepo,
+		questionRepo:          questionRepo,
+		optionMasterRepo:      optionMasterRepo,
+	}
+}
+
+func (s *questionAnalyticsService) GetQuestionAnalytics(ctx context.Context, req dtos.QuestionAnalyticsRequest) (*dtos.QuestionAnalyticsResponse, error) {
+	if req.CohortFrom != "" && req.CohortTo != "" && req.CohortFrom > req.CohortTo {
+		return nil, errors.NewBadRequestError("cohort_from must not be after cohort_to", nil)
+	}
+
+	db := s.txnManager.GetDB()
+	refreshedOn, err := s.questionAnalyticsRepo.LastRefreshedOn(ctx, db)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question analytics", err)
+	}
+	response := &dtos.QuestionAnalyticsResponse{Questions: []dtos.QuestionAnalyticsDTO{}}
+	if refreshedOn == nil {
+		return response, nil
+	}
+	response.RefreshedOn = refreshedOn.Format(time.RFC3339)
+
+	totals, err := s.questionAnalyticsRepo.SumStats(ctx, db, repository.QuestionAnswerStatFilter{
+		QuestionID:  req.QuestionID,
+		StateID:     req.StateID,
+		CohortFrom:  req.CohortFrom,
+		CohortTo:    req.CohortTo,
+		Participant: req.Participated,
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question analytics", err)
+	}
+	if len(totals) == 0 {
+		return response, nil
+	}
+
+	byQuestion := make(map[int][]repository.QuestionAnswerTotal)
+	var ids []int
+	for _, total := range totals {
+		if _, ok := byQuestion[total.QuestionMasterID]; !ok {
+			ids = append(ids, total.QuestionMasterID)
+		}
+		byQuestion[total.QuestionMasterID] = append(byQuestion[total.QuestionMasterID], total)
+	}
+	questions, options, err := s.analyzedQuestions(ctx, db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, question := range questions {
+		response.Questions = append(response.Questions, questionAnalytics(&question, options[question.ID], byQuestion[question.ID]))
+	}
+	return response, nil
+}
+
+func (s *questionAnalyticsService) Refresh(ctx context.Context) (*dtos.QuestionAnalyticsRefreshResponse, error) {
+	start := time.Now()
+	var rows int64
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		rows, err = s.questionAnalyticsRepo.Refresh(ctx, tx, start)
+		return err
+	})
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to refresh question analytics", err)
+	}
+
+	duration := time.Since(start)
+	log.WithFields(log.Fields{
+		"rows":        rows,
+		"duration_ms": duration.Milliseconds(),
+	}).Info("Question analytics refreshed")
+	return &dtos.QuestionAnalyticsRefreshResponse{
+		RefreshedOn: start.Format(time.RFC3339),
+		Rows:        rows,
+		DurationMs:  duration.Milliseconds(),
+	}, nil
+}
+
+// analyzedQuestions loads the questions with stats, deleted ones included since
+// their answers still count, with their options keyed by question ID
+func (s *questionAnalyticsService) analyzedQuestions(ctx context.Context, db *gorm.DB, ids []int) ([]entities.QuestionMaster, map[int][]entities.OptionMaster, error) {
+	questions, err := s.questionRepo.FindByCondition(ctx, db, "id IN ?", ids)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get questions", err)
+	}
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].ID < questions[j].ID
+	})
+
+	options, err := s.optionMasterRepo.FindByQuestionIDs(ctx, db, ids)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get question options", err)
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].DisplayOrder != options[j].DisplayOrder {
+			return options[i].DisplayOrder < options[j].DisplayOrder
+		}
+		return options[i].ID < options[j].ID
+	})
+	byQuestion := make(map[int][]entities.OptionMaster, len(ids))
+	for _, option := range options {
+		byQuestion[option.QuestionMasterID] = append(byQuestion[option.QuestionMasterID], option)
+	}
+	return questions, byQuestion, nil
+}
+
+// questionAnalytics lays out a question's totals. Current options and the
+// question's rating range are listed even when nobody picked them; options and
+// values that were picked before the question changed are listed as well.
+func questionAnalytics(question *entities.QuestionMaster, options []entities.OptionMaster, totals []repository.QuestionAnswerTotal) dtos.QuestionAnalyticsDTO {
+	analytics := dtos.QuestionAnalyticsDTO{
+		QuestionID:   question.ID,
+		QuestionText: question.QuestionText,
+		QuestionType: question.Type(),
+	}
+	optionUsers := make(map[int]int)
+	ratingUsers := make(map[int]int)
+	for _, total := range totals {
+		switch {
+		case total.OptionID != nil:
+			optionUsers[*total.OptionID] = total.Users
+		case total.RatingValue != nil:
+			ratingUsers[*total.RatingValue] = total.Users
+		default:
+			analytics.Respondents = total.Users
+		}
+	}
+
+	for _, option := range options {
+		users, picked := optionUsers[option.ID]
+		if !picked && (option.IsDeleted || !option.IsActive || !question.IsChoice()) {
+			continue
+		}
+		analytics.Options = append(analytics.Options, dtos.AnswerCountDTO{
+			OptionID:   option.ID,
+			OptionText: option.OptionText,
+			Users:      users,
+			Percent:    answerPercent(users, analytics.Respondents),
+		})
+	}
+
+	if question.Type() == entities.QuestionTypeRating {
+		minValue, maxValue := entities.DefaultRatingMin, entities.DefaultRatingMax
+		if question.MinValue != nil {
+			minValue = *question.MinValue
+		}
+		if question.MaxValue != nil {
+			maxValue = *question.MaxValue
+		}
+		for value := minValue; value <= maxValue; value++ {
+			if _, ok := ratingUsers[value]; !ok {
+				ratingUsers[value] = 0
+			}
+		}
+	}
+	values := make([]int, 0, len(ratingUsers))
+	for value := range ratingUsers {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	for _, value := range values {
+		value := value
+		analytics.Ratings = append(analytics.Ratings, dtos.AnswerCountDTO{
+			Value:   &value,
+			Users:   ratingUsers[value],
+			Percent: answerPercent(ratingUsers[value], analytics.Respondents),
+		})
+	}
+	return analytics
+}
+
+// answerPercent is users as a percentage of respondents, to one decimal place
+func answerPercent(users, respondents int) float64 {
+	if respondents == 0 {
+		return 0
+	}
+	return math.Round(float64(users)*1000/float64(respondents)) / 10
+}
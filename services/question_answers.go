@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+const answerDateLayout = "2006-01-02"
+
+// answeredQuestion is a validated answer together with the question it answers
+type answeredQuestion struct {
+	question *entities.QuestionMaster
+	answer   dtos.AnswerQuestionsRequestDTO
+	// index is the answer's position in the request, used for field errors
+	index int
+}
+
+// validateAnswer checks an answer against its question's type and active options,
+// recording failures under answers[index]
+func validateAnswer(fieldErrors utils.FieldErrors, index int, answer dtos.AnswerQuestionsRequestDTO, question *entities.QuestionMaster, options []entities.OptionMaster) {
+	field := func(name string) string {
+		return fmt.Sprintf("answers[%d].%s", index, name)
+	}
+
+	activeOptions := make(map[int]bool, len(options))
+	for _, option := range options {
+		activeOptions[option.ID] = true
+	}
+
+	switch question.Type() {
+	case entities.QuestionTypeSingleChoice:
+		if answer.AnswerID == 0 {
+			fieldErrors.Add(field("answer_id"), "answer_id is required")
+		} else if !activeOptions[answer.AnswerID] {
+			fieldErrors.Add(field("answer_id"), "answer_id is not an available option of this question")
+		}
+	case entities.QuestionTypeMultiSelect:
+		seen := make(map[int]bool, len(answer.AnswerIDs))
+		for _, optionID := range answer.AnswerIDs {
+			if seen[optionID] {
+				fieldErrors.Add(field("answer_ids"), fmt.Sprintf("answer_ids contains %d more than once", optionID))
+				continue
+			}
+			seen[optionID] = true
+			if !activeOptions[optionID] {
+				fieldErrors.Add(field("answer_ids"), fmt.Sprintf("%d is not an available option of this question", optionID))
+			}
+		}
+		minSelections, maxSelections := question.SelectionRange(len(options))
+		if len(seen) < minSelections || len(seen) > maxSelections {
+			fieldErrors.Add(field("answer_ids"), fmt.Sprintf("answer_ids must select between %d and %d options", minSelections, maxSelections))
+		}
+	case entities.QuestionTypeText:
+		text := ""
+		if answer.Text != nil {
+			text = strings.TrimSpace(*answer.Text)
+		}
+		if text == "" {
+			fieldErrors.Add(field("text"), "text is required")
+		} else if maxLength := question.TextMaxLength(); utf8.RuneCountInString(text) > maxLength {
+			fieldErrors.Add(field("text"), fmt.Sprintf("text must be at most %d characters", maxLength))
+		}
+	case entities.QuestionTypeRating:
+		minValue, maxValue := question.RatingRange()
+		if answer.Rating == nil {
+			fieldErrors.Add(field("rating"), "rating is required")
+		} else if *answer.Rating < minValue || *answer.Rating > maxValue {
+			fieldErrors.Add(field("rating"), fmt.Sprintf("rating must be between %d and %d", minValue, maxValue))
+		}
+	case entities.QuestionTypeDate:
+		if answer.Date == nil || *answer.Date == "" {
+			fieldErrors.Add(field("date"), "date is required")
+		} else if _, err := time.Parse(answerDateLayout, *answer.Date); err != nil {
+			fieldErrors.Add(field("date"), "date must be a date in YYYY-MM-DD format")
+		}
+	default:
+		fieldErrors.Add(field("question_id"), "question has an unsupported type")
+	}
+}
+
+// answerRows converts a validated answer into the rows stored for its question type
+func answerRows(userID string, answered answeredQuestion, now time.Time) []entities.UserQuestionAnswer {
+	base := entities.UserQuestionAnswer{
+		UserID:            userID,
+		QuestionMasterID:  answered.question.ID,
+		QuestionVersionID: answered.question.PublishedVersionID,
+		SelectedAnswer:    true,
+		IsActive:          true,
+		IsDeleted:         false,
+		CreatedBy:         userID,
+		CreatedOn:         now,
+	}
+	answer := answered.answer
+
+	switch answered.question.Type() {
+	case entities.QuestionTypeSingleChoice:
+		row := base
+		row.OptionID = &answer.AnswerID
+		return []entities.UserQuestionAnswer{row}
+	case entities.QuestionTypeMultiSelect:
+		rows := make([]entities.UserQuestionAnswer, len(answer.AnswerIDs))
+		for i := range answer.AnswerIDs {
+			rows[i] = base
+			rows[i].OptionID = &answer.AnswerIDs[i]
+		}
+		return rows
+	case entities.QuestionTypeText:
+		text := strings.TrimSpace(*answer.Text)
+		base.TextAnswer = &text
+	case entities.QuestionTypeRating:
+		base.RatingAnswer = answer.Rating
+	case entities.QuestionTypeDate:
+		date, _ := time.Parse(answerDateLayout, *answer.Date)
+		base.DateAnswer = &date
+	}
+	return []entities.UserQuestionAnswer{base}
+}
+
+// setQuestionType copies the question type and its limits into a response
+func setQuestionType(response *dtos.QuestionResponseDTO, question *entities.QuestionMaster) {
+	response.QuestionType = question.Type()
+	response.MinSelections = question.MinSelections
+	response.MaxSelections = question.MaxSelections
+	response.MinValue = question.MinValue
+	response.MaxValue = question.MaxValue
+	response.MaxLength = question.MaxLength
+}
+
+// setUserAnswer fills a response with the user's stored answer to its question
+func setUserAnswer(response *dtos.QuestionResponseDTO, answers []entities.UserQuestionAnswer) {
+	for _, answer := range answers {
+		switch {
+		case answer.OptionID != nil:
+			if response.SelectedOption == nil {
+				response.SelectedOption = answer.OptionID
+			}
+			response.SelectedOptions = append(response.SelectedOptions, *answer.OptionID)
+		case answer.TextAnswer != nil:
+			response.TextAnswer = answer.TextAnswer
+		case answer.RatingAnswer != nil:
+			response.RatingAnswer = answer.RatingAnswer
+		case answer.DateAnswer != nil:
+			date := answer.DateAnswer.Format(answerDateLayout)
+			response.DateAnswer = &date
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/cache"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// QuestionCatalog keeps the profile questionnaire of each language in memory so
+// the questions page is served without reading question content per request.
+// Every change to questions, options, sections, conditions or translations
+// invalidates it; cached languages also expire after
+// constants.QUESTION_CATALOG_CACHE_TTL so other instances catch up.
+type QuestionCatalog interface {
+	// Invalidate starts a new catalog version; languages cached for an older one
+	// are rebuilt on their next read
+	Invalidate()
+	get(ctx context.Context, languageID int) (*questionCatalog, error)
+	localize(ctx context.Context, db *gorm.DB, questions []entities.QuestionMaster, languageID int) (*questionContent, error)
+}
+
+// questionCatalog is the profile questionnaire in one language as of a catalog
+// version. Questions are kept whatever their publish window, which is checked
+// when the catalog is read. It is never modified once built.
+type questionCatalog struct {
+	version    uint64
+	languageID int
+	questions  []entities.QuestionMaster
+	content    *questionContent
+	sections   []entities.QuestionSection
+	conditions []entities.QuestionCondition
+}
+
+// questionContent is the text and active options of questions in one language,
+// falling back to the question's own text where there is no translation
+type questionContent struct {
+	texts   map[int]string
+	options map[int][]dtos.OptionDTO
+}
+
+type questionCatalogStore struct {
+	txnManager                 *utils.TransactionManager
+	questionMasterRepo         repository.QuestionRepository
+	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository
+	optionMasterRepo           repository.OptionMasterRepository
+	optionMasterLanguageRepo   repository.OptionMasterLanguageRepository
+	questionSectionRepo        repository.QuestionSectionRepository
+	questionConditionRepo      repository.QuestionConditionRepository
+	catalogs                   *cache.TTLCache[int, *questionCatalog]
+	version                    atomic.Uint64
+	buildMu                    sync.Mutex
+}
+
+func NewQuestionCatalog(
+	txnManager *utils.TransactionManager,
+	questionMasterRepo repository.QuestionRepository,
+	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository,
+	optionMasterRepo repository.OptionMasterRepository,
+	optionMasterLanguageRepo repository.OptionMasterLanguageRepository,
+	questionSectionRepo repository.QuestionSectionRepository,
+	questionConditionRepo repository.QuestionConditionRepository,
+) QuestionCatalog {
+	return &questionCatalogStore{
+		txnManager:                 txnManager,
+		questionMasterRepo:         questionMasterRepo,
+		questionMasterLanguageRepo: questionMasterLanguageRepo,
+		optionMasterRepo:           optionMasterRepo,
+		optionMasterLanguageRepo:   optionMasterLanguageRepo,
+		questionSectionRepo:        questionSectionRepo,
+		questionConditionRepo:      questionConditionRepo,
+		catalogs:                   cache.NewTTLCache[int, *questionCatalog](constants.QUESTION_CATALOG_CACHE_TTL),
+	}
+}
+
+func (s *questionCatalogStore) Invalidate() {
+	s.version.Add(1)
+	s.catalogs.Clear()
+}
+
+func (s *questionCatalogStore) get(ctx context.Context, languageID int) (*questionCatalog, error) {
+	if catalog, ok := s.catalogs.Get(languageID); ok && catalog.version == s.version.Load() {
+		return catalog, nil
+	}
+
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	// Taken before reading, so a change made while building leaves this catalog
+	// already stale
+	version := s.version.Load()
+	if catalog, ok := s.catalogs.Get(languageID); ok && catalog.version == version {
+		return catalog, nil
+	}
+
+	catalog, err := s.build(ctx, languageID, version)
+	if err != nil {
+		return nil, err
+	}
+	s.catalogs.Set(languageID, catalog)
+	log.WithFields(log.Fields{
+		"language_id": languageID,
+		"version":     version,
+		"questions":   len(catalog.questions),
+	}).Debug("Question catalog built")
+	return catalog, nil
+}
+
+func (s *questionCatalogStore) build(ctx context.Context, languageID int, version uint64) (*questionCatalog, error) {
+	db := s.txnManager.GetDB()
+	questions, err := s.questionMasterRepo.FindPublishedProfileQuestions(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	content, err := s.localize(ctx, db, questions, languageID)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := s.questionSectionRepo.FindOrdered(ctx, db, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question sections: %w", err)
+	}
+	conditions, err := s.questionConditionRepo.FindByQuestionIDs(ctx, db, questionIDs(questions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question conditions: %w", err)
+	}
+
+	return &questionCatalog{
+		version:    version,
+		languageID: languageID,
+		questions:  questions,
+		content:    content,
+		sections:   sections,
+		conditions: conditions,
+	}, nil
+}
+
+// localize loads the questions' text and active options in the language with one
+// query per table
+func (s *questionCatalogStore) localize(ctx context.Context, db *gorm.DB, questions []entities.QuestionMaster, languageID int) (*questionContent, error) {
+	ids := questionIDs(questions)
+	content := &questionContent{
+		texts:   make(map[int]string, len(questions)),
+		options: make(map[int][]dtos.OptionDTO, len(questions)),
+	}
+	for _, question := range questions {
+		content.texts[question.ID] = question.QuestionText
+	}
+
+	questionLanguages, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDsAndLanguageID(ctx, db, ids, languageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question language texts: %w", err)
+	}
+	for _, questionLanguage := range questionLanguages {
+		content.texts[questionLanguage.QuestionMasterID] = questionLanguage.QuestionText
+	}
+
+	options, err := s.optionMasterRepo.FindActiveByQuestionIDs(ctx, db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get options: %w", err)
+	}
+	optionIDs := make([]int, len(options))
+	for i, option := range options {
+		optionIDs[i] = option.ID
+	}
+	optionTexts := make(map[int]string, len(options))
+	if len(optionIDs) > 0 {
+		optionLanguages, err := s.optionMasterLanguageRepo.FindByOptionMasterIDsAndLanguageID(ctx, db, optionIDs, languageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get option language texts: %w", err)
+		}
+		for _, optionLanguage := range optionLanguages {
+			optionTexts[optionLanguage.OptionMasterID] = optionLanguage.OptionText
+		}
+	}
+
+	for _, option := range options {
+		text, ok := optionTexts[option.ID]
+		if !ok {
+			text = option.OptionText
+		}
+		content.options[option.QuestionMasterID] = append(content.options[option.QuestionMasterID], dtos.OptionDTO{
+			ID:           option.ID,
+			OptionText:   text,
+			DisplayOrder: option.DisplayOrder,
+		})
+	}
+	return content, nil
+}
+
+// questionnaire returns the catalog's questions that are live at now, ordered
+// and ready to be evaluated against a user's answers
+func (c *questionCatalog) questionnaire(now time.Time) *questionnaire {
+	live := make([]entities.QuestionMaster, 0, len(c.questions))
+	for _, question := range c.questions {
+		if question.IsLive(now) {
+			live = append(live, question)
+		}
+	}
+	return newQuestionnaire(live, c.sections, c.conditions)
+}
+
+// response builds the question as users see it in the catalog's language
+func (c *questionContent) response(question *entities.QuestionMaster, languageID int) dtos.QuestionResponseDTO {
+	response := dtos.QuestionResponseDTO{
+		ID:           question.ID,
+		QuestionText: c.texts[question.ID],
+		LanguageID:   languageID,
+		Options:      c.options[question.ID],
+		SectionID:    question.SectionID,
+		DisplayOrder: question.DisplayOrder,
+	}
+	setQuestionType(&response, question)
+	return response
+}
+
+func questionIDs(questions []entities.QuestionMaster) []int {
+	ids := make([]int, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+	}
+	return ids
+}
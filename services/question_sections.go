@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+)
+
+func (s *questionService) ListQuestionSections(ctx context.Context) ([]dtos.QuestionSectionResponseDTO, error) {
+	sections, err := s.questionSectionRepo.FindOrdered(ctx, s.txnManager.GetDB(), false)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question sections", err)
+	}
+
+	response := make([]dtos.QuestionSectionResponseDTO, len(sections))
+	for i := range sections {
+		response[i] = toQuestionSectionDTO(&sections[i])
+	}
+	return response, nil
+}
+
+func (s *questionService) CreateQuestionSection(ctx context.Context, req dtos.QuestionSectionRequestDTO, createdBy string) (*dtos.QuestionSectionResponseDTO, error) {
+	section := &entities.QuestionSection{
+		Title:        req.Title,
+		DisplayOrder: req.DisplayOrder,
+		IsActive:     req.IsActive == nil || *req.IsActive,
+		CreatedBy:    createdBy,
+		CreatedOn:    time.Now(),
+	}
+	if err := s.questionSectionRepo.Create(ctx, s.txnManager.GetDB(), section); err != nil {
+		return nil, errors.NewInternalServerError("Failed to create question section", err)
+	}
+	s.questionCatalog.Invalidate()
+
+	response := toQuestionSectionDTO(section)
+	return &response, nil
+}
+
+// UpdateQuestionSection renames, reorders or deactivates a section. Questions in
+// an inactive section are left out of the questionnaire.
+func (s *questionService) UpdateQuestionSection(ctx context.Context, sectionID int, req dtos.UpdateQuestionSectionRequestDTO, modifiedBy string) (*dtos.QuestionSectionResponseDTO, error) {
+	db := s.txnManager.GetDB()
+	section, err := s.questionSectionRepo.FindByID(ctx, db, sectionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question section", err)
+	}
+	if section == nil || section.IsDeleted {
+		return nil, errors.NewNotFoundError("Question section not found", nil)
+	}
+
+	if req.Title != nil {
+		section.Title = *req.Title
+	}
+	if req.DisplayOrder != nil {
+		section.DisplayOrder = *req.DisplayOrder
+	}
+	if req.IsActive != nil {
+		section.IsActive = *req.IsActive
+	}
+	now := time.Now()
+	section.LastModifiedBy = &modifiedBy
+	section.LastModifiedOn = &now
+	if err := s.questionSectionRepo.Update(ctx, db, section); err != nil {
+		return nil, errors.NewInternalServerError("Failed to update question section", err)
+	}
+	s.questionCatalog.Invalidate()
+
+	response := toQuestionSectionDTO(section)
+	return &response, nil
+}
+
+// SetQuestionConditions replaces the conditions under which a question is shown.
+// An empty list shows the question to everyone.
+func (s *questionService) SetQuestionConditions(ctx context.Context, questionID int, req dtos.SetQuestionConditionsRequestDTO, createdBy string) ([]dtos.QuestionConditionDTO, error) {
+	var saved []entities.QuestionCondition
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if _, err := s.lockQuestion(ctx, tx, questionID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		conditions := make([]entities.QuestionCondition, len(req.Conditions))
+		for i, condition := range req.Conditions {
+			if err := s.validateQuestionCondition(ctx, tx, questionID, condition); err != nil {
+				return errors.NewBadRequestError(fmt.Sprintf("Invalid condition at index %d: %s", i, err.Error()), nil)
+			}
+			conditions[i] = entities.QuestionCondition{
+				DependsOnQuestionID: condition.DependsOnQuestionID,
+				Operator:            condition.Operator,
+				CreatedBy:           createdBy,
+				CreatedOn:           now,
+			}
+			switch condition.Operator {
+			case entities.ConditionAnyOf, entities.ConditionNoneOf:
+				conditions[i].OptionIDs = condition.OptionIDs
+			case entities.ConditionBetween:
+				conditions[i].MinValue = condition.MinValue
+				conditions[i].MaxValue = condition.MaxValue
+			}
+		}
+
+		existing, err := s.questionConditionRepo.FindAll(ctx, tx)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get question conditions", err)
+		}
+		if conditionsCycle(questionID, conditions, existing) {
+			return errors.NewBadRequestError("Conditions would make the question depend on itself", nil)
+		}
+
+		if err := s.questionConditionRepo.ReplaceForQuestion(ctx, tx, questionID, conditions); err != nil {
+			return errors.NewInternalServerError("Failed to save question conditions", err)
+		}
+		saved = conditions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+
+	response := make([]dtos.QuestionConditionDTO, len(saved))
+	for i, condition := range saved {
+		response[i] = dtos.QuestionConditionDTO{
+			DependsOnQuestionID: condition.DependsOnQuestionID,
+			Operator:            condition.Operator,
+			OptionIDs:           condition.OptionIDs,
+			MinValue:            condition.MinValue,
+			MaxValue:            condition.MaxValue,
+		}
+	}
+	return response, nil
+}
+
+// validateQuestionCondition checks that a condition refers to another question
+// and fits that question's type
+func (s *questionService) validateQuestionCondition(ctx context.Context, tx *gorm.DB, questionID int, condition dtos.QuestionConditionDTO) error {
+	if condition.DependsOnQuestionID == questionID {
+		return fmt.Errorf("a question cannot depend on itself")
+	}
+	dependsOn, err := s.questionRepo.FindByIDTx(ctx, tx, condition.DependsOnQuestionID)
+	if err != nil {
+		return err
+	}
+	if dependsOn == nil || dependsOn.IsDeleted {
+		return fmt.Errorf("question %d not found", condition.DependsOnQuestionID)
+	}
+
+	switch condition.Operator {
+	case entities.ConditionAnyOf, entities.ConditionNoneOf:
+		if !dependsOn.IsChoice() {
+			return fmt.Errorf("%s needs a choice question", condition.Operator)
+		}
+		if len(condition.OptionIDs) == 0 {
+			return fmt.Errorf("option_ids is required for %s", condition.Operator)
+		}
+		options, err := s.optionMasterRepo.FindByQuestionID(ctx, tx, dependsOn.ID)
+		if err != nil {
+			return err
+		}
+		owned := make(map[int]bool, len(options))
+		for _, option := range options {
+			owned[option.ID] = true
+		}
+		for _, optionID := range condition.OptionIDs {
+			if !owned[optionID] {
+				return fmt.Errorf("option %d does not belong to question %d", optionID, dependsOn.ID)
+			}
+		}
+	case entities.ConditionBetween:
+		if dependsOn.Type() != entities.QuestionTypeRating {
+			return fmt.Errorf("between needs a rating question")
+		}
+		if condition.MinValue == nil && condition.MaxValue == nil {
+			return fmt.Errorf("min_value or max_value is required for between")
+		}
+		if condition.MinValue != nil && condition.MaxValue != nil && *condition.MinValue > *condition.MaxValue {
+			return fmt.Errorf("min_value must not exceed max_value")
+		}
+	}
+	return nil
+}
+
+// conditionsCycle reports whether giving questionID the conditions would let it
+// depend on itself through the conditions already stored for other questions
+func conditionsCycle(questionID int, conditions, existing []entities.QuestionCondition) bool {
+	dependsOn := make(map[int][]int)
+	for _, condition := range existing {
+		if condition.QuestionMasterID != questionID {
+			dependsOn[condition.QuestionMasterID] = append(dependsOn[condition.QuestionMasterID], condition.DependsOnQuestionID)
+		}
+	}
+
+	visited := make(map[int]bool)
+	pending := make([]int, 0, len(conditions))
+	for _, condition := range conditions {
+		pending = append(pending, condition.DependsOnQuestionID)
+	}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == questionID {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		pending = append(pending, dependsOn[current]...)
+	}
+	return false
+}
+
+// applyPlacement applies the request's active flag, section and display order.
+// They are not versioned and take effect straight away.
+func (s *questionService) applyPlacement(ctx context.Context, tx *gorm.DB, question *entities.QuestionMaster, req dtos.CreateQuestionDTO) error {
+	changed := false
+	if req.IsActive != nil && *req.IsActive != question.IsActive {
+		question.IsActive = *req.IsActive
+		changed = true
+	}
+	if req.SectionID != nil {
+		var sectionID *int
+		if *req.SectionID > 0 {
+			section, err := s.questionSectionRepo.FindByID(ctx, tx, *req.SectionID)
+			if err != nil {
+				return errors.NewInternalServerError("Failed to get question section", err)
+			}
+			if section == nil || section.IsDeleted {
+				return errors.NewBadRequestError(fmt.Sprintf("Question section %d not found", *req.SectionID), nil)
+			}
+			sectionID = &section.ID
+		}
+		question.SectionID = sectionID
+		changed = true
+	}
+	if req.DisplayOrder != nil {
+		question.DisplayOrder = *req.DisplayOrder
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := s.questionRepo.Update(ctx, tx, question); err != nil {
+		return errors.NewInternalServerError("Failed to update question", err)
+	}
+	return nil
+}
+
+func toQuestionSectionDTO(section *entities.QuestionSection) dtos.QuestionSectionResponseDTO {
+	return dtos.QuestionSectionResponseDTO{
+		ID:           section.ID,
+		Title:        section.Title,
+		DisplayOrder: section.DisplayOrder,
+		IsActive:     section.IsActive,
+		CreatedOn:    section.CreatedOn.Format(time.RFC3339),
+	}
+}
@@ -3,9 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
-	"time"
 
-	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
@@ -16,17 +14,37 @@ import (
 )
 
 type QuestionService interface {
-	SubmitQuestion(ctx context.Context, req dtos.QuestionSubmitRequest, userID string) (*dtos.QuestionResponse, error)
+	SubmitQuestion(ctx context.Context, req dtos.QuestionSubmitRequest, userID string) (*dtos.QuestionSubmissionDTO, error)
+	GetUserQuestionSubmissions(ctx context.Context, userID string, req dtos.QuestionSubmissionListRequest) ([]dtos.QuestionSubmissionDTO, int64, error)
+	GetQuestionSubmissionQueue(ctx context.Context, req dtos.QuestionSubmissionQueueRequest) ([]dtos.QuestionSubmissionQueueItem, int64, error)
+	ApproveQuestionSubmission(ctx context.Context, submissionID int, req dtos.CreateQuestionDTO, reviewedBy string) (*dtos.ApproveQuestionSubmissionResponse, error)
+	RejectQuestionSubmission(ctx context.Context, submissionID int, req dtos.RejectQuestionSubmissionRequest, reviewedBy string) (*dtos.QuestionSubmissionDTO, error)
 	GetActiveQuestions(ctx context.Context) ([]dtos.QuestionResponse, error)
 	GetQuestionsByLanguage(ctx context.Context, languageID int) ([]dtos.QuestionResponse, error)
 	CreateQuestions(ctx context.Context, userID string, req dtos.CreateQuestionsRequestDTO) error
+	SaveQuestionDraft(ctx context.Context, questionID int, req dtos.CreateQuestionDTO, savedBy string) (*dtos.QuestionVersionDTO, error)
+	GetQuestionVersions(ctx context.Context, questionID int) (*dtos.QuestionVersionsResponse, error)
+	PublishQuestionVersion(ctx context.Context, questionID, version int, req dtos.QuestionScheduleDTO, publishedBy string) (*dtos.QuestionVersionsResponse, error)
+	ScheduleQuestion(ctx context.Context, questionID int, req dtos.QuestionScheduleDTO, modifiedBy string) (*dtos.QuestionVersionsResponse, error)
+	UnpublishQuestion(ctx context.Context, questionID int, modifiedBy string) (*dtos.QuestionVersionsResponse, error)
+	DiffQuestionVersions(ctx context.Context, questionID int, req dtos.QuestionVersionDiffRequest) (*dtos.QuestionVersionDiffDTO, error)
+	ListQuestionSections(ctx context.Context) ([]dtos.QuestionSectionResponseDTO, error)
+	CreateQuestionSection(ctx context.Context, req dtos.QuestionSectionRequestDTO, createdBy string) (*dtos.QuestionSectionResponseDTO, error)
+	UpdateQuestionSection(ctx context.Context, sectionID int, req dtos.UpdateQuestionSectionRequestDTO, modifiedBy string) (*dtos.QuestionSectionResponseDTO, error)
+	SetQuestionConditions(ctx context.Context, questionID int, req dtos.SetQuestionConditionsRequestDTO, createdBy string) ([]dtos.QuestionConditionDTO, error)
 }
 
 type questionService struct {
-	txnManager         *utils.TransactionManager
-	questionRepo       repository.QuestionRepository
-	questionAnswerRepo repository.UserQuestionAnswerRepository
-	optionMasterRepo   repository.OptionMasterRepository
+	txnManager             *utils.TransactionManager
+	questionRepo           repository.QuestionRepository
+	questionAnswerRepo     repository.UserQuestionAnswerRepository
+	optionMasterRepo       repository.OptionMasterRepository
+	questionVersionRepo    repository.QuestionVersionRepository
+	questionSectionRepo    repository.QuestionSectionRepository
+	questionConditionRepo  repository.QuestionConditionRepository
+	questionSubmissionRepo repository.QuestionSubmissionRepository
+	localizationService    LocalizationService
+	questionCatalog        QuestionCatalog
 }
 
 func NewQuestionService(
@@ -34,43 +52,27 @@ func NewQuestionService(
 	questionRepo repository.QuestionRepository,
 	questionAnswerRepo repository.UserQuestionAnswerRepository,
 	optionMasterRepo repository.OptionMasterRepository,
+	questionVersionRepo repository.QuestionVersionRepository,
+	questionSectionRepo repository.QuestionSectionRepository,
+	questionConditionRepo repository.QuestionConditionRepository,
+	questionSubmissionRepo repository.QuestionSubmissionRepository,
+	localizationService LocalizationService,
+	questionCatalog QuestionCatalog,
 ) QuestionService {
 	return &questionService{
-		txnManager:         txnManager,
-		questionRepo:       questionRepo,
-		questionAnswerRepo: questionAnswerRepo,
-		optionMasterRepo:   optionMasterRepo,
+		txnManager:             txnManager,
+		questionRepo:           questionRepo,
+		questionAnswerRepo:     questionAnswerRepo,
+		optionMasterRepo:       optionMasterRepo,
+		questionVersionRepo:    questionVersionRepo,
+		questionSectionRepo:    questionSectionRepo,
+		questionConditionRepo:  questionConditionRepo,
+		questionSubmissionRepo: questionSubmissionRepo,
+		localizationService:    localizationService,
+		questionCatalog:        questionCatalog,
 	}
 }
 
-func (s *questionService) SubmitQuestion(ctx context.Context, req dtos.QuestionSubmitRequest, userID string) (*dtos.QuestionResponse, error) {
-	now := time.Now()
-	question := &entities.QuestionMaster{
-		QuestionText: req.QuestionText,
-		QuesPoint:    0, // Default value when not provided
-		LanguageID:   req.LanguageID,
-		IsActive:     true,
-		IsDeleted:    false,
-		CreatedBy:    userID,
-		CreatedOn:    now,
-	}
-
-	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
-		return s.questionRepo.Create(ctx, tx, question)
-	})
-	if err != nil {
-		log.WithError(err).Error("Failed to create question")
-		return nil, errors.NewInternalServerError("Failed to submit question", err)
-	}
-
-	return &dtos.QuestionResponse{
-		ID:           question.ID,
-		QuestionText: question.QuestionText,
-		LanguageID:   question.LanguageID,
-		IsActive:     question.IsActive,
-	}, nil
-}
-
 func (s *questionService) GetActiveQuestions(ctx context.Context) ([]dtos.QuestionResponse, error) {
 	questions, err := s.questionRepo.FindActiveQuestions(ctx, s.txnManager.GetDB(), 100, 0)
 	if err != nil {
@@ -109,106 +111,91 @@ func (s *questionService) GetQuestionsByLanguage(ctx context.Context, languageID
 	return responses, nil
 }
 
+// CreateQuestions saves each question as a new version and publishes it, unless
+// it is marked as a draft. Answers keep pointing at the version they were given
+// against, so editing a live question never changes the meaning of old answers.
 func (s *questionService) CreateQuestions(ctx context.Context, userID string, req dtos.CreateQuestionsRequestDTO) error {
-	tx, err := s.txnManager.StartTxn()
-	if err != nil {
-		return err
-	}
-	defer s.txnManager.RollbackOnPanic(tx)
-
-	for _, qDTO := range req.Questions {
-		var questionID int
-		isActive := true
-		if qDTO.IsActive != nil {
-			isActive = *qDTO.IsActive
+	for i, qDTO := range req.Questions {
+		if err := validateQuestionType(qDTO); err != nil {
+			return errors.NewBadRequestError(fmt.Sprintf("Invalid question at index %d: %s", i, err.Error()), nil)
+		}
+		schedule := dtos.QuestionScheduleDTO{PublishAt: qDTO.PublishAt, UnpublishAt: qDTO.UnpublishAt}
+		if err := validateQuestionSchedule(schedule); err != nil {
+			return errors.NewBadRequestError(fmt.Sprintf("Invalid question at index %d: publish_at must be before unpublish_at", i), nil)
 		}
+	}
 
-		if qDTO.ID != nil && *qDTO.ID > 0 {
-			q, err := s.questionRepo.FindByIDTx(ctx, tx, *qDTO.ID)
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		for _, qDTO := range req.Questions {
+			questionID := 0
+			if qDTO.ID != nil {
+				questionID = *qDTO.ID
+			}
+			question, err := s.findOrCreateQuestion(ctx, tx, questionID, qDTO, userID)
 			if err != nil {
-				s.txnManager.AbortTxn(tx)
-				return fmt.Errorf("failed to find question %d: %w", *qDTO.ID, err)
+				return err
 			}
-			if q == nil {
-				s.txnManager.AbortTxn(tx)
-				return fmt.Errorf("question with id %d not found", *qDTO.ID)
+
+			if err := s.applyPlacement(ctx, tx, question, qDTO); err != nil {
+				return err
 			}
-			q.QuestionText = qDTO.QuestionText
-			q.QuesPoint = qDTO.QuesPoint
-			q.LanguageID = qDTO.LanguageID
-			q.IsActive = isActive
-
-			now := time.Now()
-			q.LastModifiedOn = &now
-			q.LastModifiedBy = &userID
-
-			if err := s.questionRepo.Update(ctx, tx, q); err != nil {
-				s.txnManager.AbortTxn(tx)
-				return fmt.Errorf("failed to update question %d: %w", *qDTO.ID, err)
+
+			draft, err := s.saveDraft(ctx, tx, question, qDTO, userID)
+			if err != nil {
+				return err
 			}
-			questionID = q.ID
-		} else {
-			q := &entities.QuestionMaster{
-				QuestionText: qDTO.QuestionText,
-				QuesPoint:    qDTO.QuesPoint,
-				LanguageID:   qDTO.LanguageID,
-				IsActive:     isActive,
-				IsDeleted:    false,
-				CreatedOn:    time.Now(),
-				CreatedBy:    userID,
+			if qDTO.Draft {
+				continue
 			}
-			if err := s.questionRepo.Create(ctx, tx, q); err != nil {
-				s.txnManager.AbortTxn(tx)
-				return fmt.Errorf("failed to create question: %w", err)
+			schedule := dtos.QuestionScheduleDTO{PublishAt: qDTO.PublishAt, UnpublishAt: qDTO.UnpublishAt}
+			if err := s.publishVersion(ctx, tx, question, draft, schedule, userID); err != nil {
+				return err
 			}
-			questionID = q.ID
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.questionCatalog.Invalidate()
+	return nil
+}
 
-		for _, oDTO := range qDTO.Options {
-			optActive := true
-			if oDTO.IsActive != nil {
-				optActive = *oDTO.IsActive
-			}
-			if oDTO.ID != nil && *oDTO.ID > 0 {
-				opt, err := s.optionMasterRepo.FindByID(ctx, tx, *oDTO.ID)
-				if err != nil {
-					s.txnManager.AbortTxn(tx)
-					return fmt.Errorf("failed to find option %d: %w", *oDTO.ID, err)
-				}
-				if opt == nil {
-					s.txnManager.AbortTxn(tx)
-					return fmt.Errorf("option with id %d not found", *oDTO.ID)
-				}
-				opt.OptionText = oDTO.OptionText
-				opt.DisplayOrder = oDTO.DisplayOrder
-				opt.IsActive = optActive
-
-				now := time.Now()
-				opt.LastModifiedOn = &now
-				opt.LastModifiedBy = &userID
-
-				if err := s.optionMasterRepo.Update(ctx, tx, opt); err != nil {
-					s.txnManager.AbortTxn(tx)
-					return fmt.Errorf("failed to update option %d: %w", *oDTO.ID, err)
-				}
-			} else {
-				opt := &entities.OptionMaster{
-					QuestionMasterID: questionID,
-					OptionText:       oDTO.OptionText,
-					DisplayOrder:     oDTO.DisplayOrder,
-					IsActive:         optActive,
-					IsDeleted:        false,
-					CreatedOn:        time.Now(),
-					CreatedBy:        userID,
-				}
-				if err := s.optionMasterRepo.Create(ctx, tx, opt); err != nil {
-					s.txnManager.AbortTxn(tx)
-					return fmt.Errorf("failed to create option: %w", err)
-				}
-			}
+// validateQuestionType checks that the type's limits are consistent
+func validateQuestionType(q dtos.CreateQuestionDTO) error {
+	switch q.QuestionType {
+	case entities.QuestionTypeMultiSelect:
+		if q.MinSelections != nil && q.MaxSelections != nil && *q.MinSelections > *q.MaxSelections {
+			return fmt.Errorf("min_selections must not exceed max_selections")
+		}
+	case entities.QuestionTypeRating:
+		minValue, maxValue := entities.DefaultRatingMin, entities.DefaultRatingMax
+		if q.MinValue != nil {
+			minValue = *q.MinValue
+		}
+		if q.MaxValue != nil {
+			maxValue = *q.MaxValue
+		}
+		if minValue >= maxValue {
+			return fmt.Errorf("min_value must be less than max_value")
 		}
 	}
-
-	s.txnManager.CommitTxn(tx)
 	return nil
 }
+
+// applyQuestionType copies the type and the limits that apply to it, clearing the rest
+func applyQuestionType(q *entities.QuestionMaster, qDTO dtos.CreateQuestionDTO) {
+	q.QuestionType = qDTO.QuestionType
+	if q.QuestionType == "" {
+		q.QuestionType = entities.QuestionTypeSingleChoice
+	}
+	q.MinSelections, q.MaxSelections, q.MinValue, q.MaxValue, q.MaxLength = nil, nil, nil, nil, nil
+	switch q.QuestionType {
+	case entities.QuestionTypeMultiSelect:
+		q.MinSelections, q.MaxSelections = qDTO.MinSelections, qDTO.MaxSelections
+	case entities.QuestionTypeRating:
+		q.MinValue, q.MaxValue = qDTO.MinValue, qDTO.MaxValue
+	case entities.QuestionTypeText:
+		q.MaxLength = qDTO.MaxLength
+	}
+}
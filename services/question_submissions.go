@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+)
+
+// SubmitQuestion queues a user's question suggestion for review. Suggestions
+// never reach the question bank until an admin approves them.
+func (s *questionService) SubmitQuestion(ctx context.Context, req dtos.QuestionSubmitRequest, userID string) (*dtos.QuestionSubmissionDTO, error) {
+	text := strings.TrimSpace(req.QuestionText)
+	if text == "" {
+		return nil, errors.NewBadRequestError("Question text must not be blank", nil)
+	}
+	if !s.localizationService.IsLanguage(ctx, req.LanguageID) {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Language %d not found", req.LanguageID), nil)
+	}
+
+	pending, err := s.questionSubmissionRepo.CountPendingByUserID(ctx, s.txnManager.GetDB(), userID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to submit question", err)
+	}
+	if pending >= constants.MAX_PENDING_QUESTION_SUBMISSIONS {
+		return nil, errors.NewTooManyRequestsError(fmt.Sprintf("You can have at most %d questions waiting for review", constants.MAX_PENDING_QUESTION_SUBMISSIONS), nil)
+	}
+
+	submission := &entities.QuestionSubmission{
+		UserID:       userID,
+		QuestionText: text,
+		LanguageID:   req.LanguageID,
+		Status:       entities.QuestionSubmissionStatusPending,
+	}
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		return s.questionSubmissionRepo.Create(ctx, tx, submission)
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to create question submission")
+		return nil, errors.NewInternalServerError("Failed to submit question", err)
+	}
+
+	response := toQuestionSubmissionDTO(submission)
+	return &response, nil
+}
+
+func (s *questionService) GetUserQuestionSubmissions(ctx context.Context, userID string, req dtos.QuestionSubmissionListRequest) ([]dtos.QuestionSubmissionDTO, int64, error) {
+	submissions, total, err := s.questionSubmissionRepo.FindByUserID(ctx, s.txnManager.GetDB(), userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("Failed to get question submissions", err)
+	}
+
+	items := make([]dtos.QuestionSubmissionDTO, len(submissions))
+	for i := range submissions {
+		items[i] = toQuestionSubmissionDTO(&submissions[i])
+	}
+	return items, total, nil
+}
+
+func (s *questionService) GetQuestionSubmissionQueue(ctx context.Context, req dtos.QuestionSubmissionQueueRequest) ([]dtos.QuestionSubmissionQueueItem, int64, error) {
+	status := req.Status
+	if status == "" {
+		status = entities.QuestionSubmissionStatusPending
+	}
+
+	submissions, total, err := s.questionSubmissionRepo.FindForReview(ctx, s.txnManager.GetDB(), status, req.Limit, req.Offset)
+	if err != nil {
+		return nil, 0, errors.NewInternalServerError("Failed to get question submission queue", err)
+	}
+
+	items := make([]dtos.QuestionSubmissionQueueItem, len(submissions))
+	for i, submission := range submissions {
+		var name *string
+		if submission.User != nil {
+			name = submission.User.Name
+		}
+		items[i] = dtos.QuestionSubmissionQueueItem{
+			ID:           submission.ID,
+			UserID:       submission.UserID,
+			Name:         name,
+			QuestionText: submission.QuestionText,
+			LanguageID:   submission.LanguageID,
+			Status:       submission.Status,
+			ReviewReason: submission.ReviewReason,
+			ReviewedBy:   submission.ReviewedBy,
+			QuestionID:   submission.QuestionMasterID,
+			CreatedOn:    submission.CreatedOn.Format(time.RFC3339),
+			ReviewedOn:   formatReviewedOn(submission.ReviewedOn),
+		}
+	}
+	return items, total, nil
+}
+
+// ApproveQuestionSubmission promotes a pending submission into the question
+// bank as a new question with a draft version, which is published like any
+// other draft. The request may rework the suggestion; its text and language
+// default to the submitted ones, and a question without options defaults to a
+// text question. The ID, draft and schedule fields of the request are ignored.
+func (s *questionService) ApproveQuestionSubmission(ctx context.Context, submissionID int, req dtos.CreateQuestionDTO, reviewedBy string) (*dtos.ApproveQuestionSubmissionResponse, error) {
+	if req.QuestionType == "" && len(req.Options) == 0 {
+		req.QuestionType = entities.QuestionTypeText
+	}
+	if err := validateQuestionType(req); err != nil {
+		return nil, errors.NewBadRequestError(err.Error(), nil)
+	}
+	if req.LanguageID != 0 && !s.localizationService.IsLanguage(ctx, req.LanguageID) {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Language %d not found", req.LanguageID), nil)
+	}
+
+	var submission *entities.QuestionSubmission
+	var draft *entities.QuestionVersion
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		submission, err = s.lockPendingSubmission(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(req.QuestionText) == "" {
+			req.QuestionText = submission.QuestionText
+		}
+		if req.LanguageID == 0 {
+			req.LanguageID = submission.LanguageID
+		}
+		question, err := s.findOrCreateQuestion(ctx, tx, 0, req, reviewedBy)
+		if err != nil {
+			return err
+		}
+		if err := s.applyPlacement(ctx, tx, question, req); err != nil {
+			return err
+		}
+		draft, err = s.saveDraft(ctx, tx, question, req, reviewedBy)
+		if err != nil {
+			return err
+		}
+
+		submission.QuestionMasterID = &question.ID
+		return s.reviewSubmission(ctx, tx, submission, entities.QuestionSubmissionStatusApproved, nil, reviewedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+
+	return &dtos.ApproveQuestionSubmissionResponse{
+		Submission: toQuestionSubmissionDTO(submission),
+		Draft:      toQuestionVersionDTO(draft),
+	}, nil
+}
+
+func (s *questionService) RejectQuestionSubmission(ctx context.Context, submissionID int, req dtos.RejectQuestionSubmissionRequest, reviewedBy string) (*dtos.QuestionSubmissionDTO, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.NewBadRequestError("A reason is required when rejecting a question submission", nil)
+	}
+
+	var submission *entities.QuestionSubmission
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		submission, err = s.lockPendingSubmission(ctx, tx, submissionID)
+		if err != nil {
+			return err
+		}
+		return s.reviewSubmission(ctx, tx, submission, entities.QuestionSubmissionStatusRejected, &reason, reviewedBy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toQuestionSubmissionDTO(submission)
+	return &response, nil
+}
+
+// lockPendingSubmission locks a submission that has not been reviewed yet, so
+// two admins cannot both review it
+func (s *questionService) lockPendingSubmission(ctx context.Context, tx *gorm.DB, submissionID int) (*entities.QuestionSubmission, error) {
+	submission, err := s.questionSubmissionRepo.LockByID(ctx, tx, submissionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question submission", err)
+	}
+	if submission == nil {
+		return nil, errors.NewNotFoundError("Question submission not found", nil)
+	}
+	if submission.Status != entities.QuestionSubmissionStatusPending {
+		return nil, errors.NewConflictError(fmt.Sprintf("Question submission has already been %s", submission.Status), nil)
+	}
+	return submission, nil
+}
+
+func (s *questionService) reviewSubmission(ctx context.Context, tx *gorm.DB, submission *entities.QuestionSubmission, status string, reason *string, reviewedBy string) error {
+	now := time.Now()
+	submission.Status = status
+	submission.ReviewReason = reason
+	submission.ReviewedBy = &reviewedBy
+	submission.ReviewedOn = &now
+	if err := s.questionSubmissionRepo.Update(ctx, tx, submission); err != nil {
+		return errors.NewInternalServerError("Failed to update question submission", err)
+	}
+	return nil
+}
+
+func toQuestionSubmissionDTO(submission *entities.QuestionSubmission) dtos.QuestionSubmissionDTO {
+	return dtos.QuestionSubmissionDTO{
+		ID:           submission.ID,
+		QuestionText: submission.QuestionText,
+		LanguageID:   submission.LanguageID,
+		Status:       submission.Status,
+		ReviewReason: submission.ReviewReason,
+		QuestionID:   submission.QuestionMasterID,
+		CreatedOn:    submission.CreatedOn.Format(time.RFC3339),
+		ReviewedOn:   formatReviewedOn(submission.ReviewedOn),
+	}
+}
+
+func formatReviewedOn(reviewedOn *time.Time) string {
+	if reviewedOn == nil {
+		return ""
+	}
+	return reviewedOn.Format(time.RFC3339)
+}
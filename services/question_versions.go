@@ -0,0 +1,598 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+)
+
+// Option changes reported by a version diff
+const (
+	optionChangeAdded   = "added"
+	optionChangeRemoved = "removed"
+	optionChangeChanged = "changed"
+)
+
+// SaveQuestionDraft saves changes to a question as its draft version, replacing
+// any earlier draft. A question ID of 0 creates a new question that stays hidden
+// until its first version is published.
+func (s *questionService) SaveQuestionDraft(ctx context.Context, questionID int, req dtos.CreateQuestionDTO, savedBy string) (*dtos.QuestionVersionDTO, error) {
+	if err := validateQuestionType(req); err != nil {
+		return nil, errors.NewBadRequestError(err.Error(), nil)
+	}
+
+	var draft *entities.QuestionVersion
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		question, err := s.findOrCreateQuestion(ctx, tx, questionID, req, savedBy)
+		if err != nil {
+			return err
+		}
+		if err := s.applyPlacement(ctx, tx, question, req); err != nil {
+			return err
+		}
+		draft, err = s.saveDraft(ctx, tx, question, req, savedBy)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+
+	response := toQuestionVersionDTO(draft)
+	return &response, nil
+}
+
+func (s *questionService) GetQuestionVersions(ctx context.Context, questionID int) (*dtos.QuestionVersionsResponse, error) {
+	db := s.txnManager.GetDB()
+	question, err := s.questionRepo.FindByIDTx(ctx, db, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question", err)
+	}
+	if question == nil {
+		return nil, errors.NewNotFoundError("Question not found", nil)
+	}
+	return s.questionVersions(ctx, db, question)
+}
+
+// PublishQuestionVersion makes a draft version the live content of its question
+// and sets the window in which the question is shown
+func (s *questionService) PublishQuestionVersion(ctx context.Context, questionID, version int, req dtos.QuestionScheduleDTO, publishedBy string) (*dtos.QuestionVersionsResponse, error) {
+	if err := validateQuestionSchedule(req); err != nil {
+		return nil, err
+	}
+
+	var response *dtos.QuestionVersionsResponse
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		question, err := s.lockQuestion(ctx, tx, questionID)
+		if err != nil {
+			return err
+		}
+
+		draft, err := s.questionVersionRepo.FindByVersion(ctx, tx, questionID, version)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get question version", err)
+		}
+		if draft == nil {
+			return errors.NewNotFoundError("Question version not found", nil)
+		}
+		if draft.Status != entities.QuestionVersionStatusDraft {
+			return errors.NewConflictError("Only a draft version can be published", nil)
+		}
+
+		if err := s.publishVersion(ctx, tx, question, draft, req, publishedBy); err != nil {
+			return err
+		}
+		response, err = s.questionVersions(ctx, tx, question)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+	return response, nil
+}
+
+// ScheduleQuestion changes the window in which a published question is shown
+// without changing its content
+func (s *questionService) ScheduleQuestion(ctx context.Context, questionID int, req dtos.QuestionScheduleDTO, modifiedBy string) (*dtos.QuestionVersionsResponse, error) {
+	if err := validateQuestionSchedule(req); err != nil {
+		return nil, err
+	}
+
+	var response *dtos.QuestionVersionsResponse
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		question, err := s.lockQuestion(ctx, tx, questionID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		question.PublishAt = req.PublishAt
+		question.UnpublishAt = req.UnpublishAt
+		question.LastModifiedBy = &modifiedBy
+		question.LastModifiedOn = &now
+		if err := s.questionRepo.Update(ctx, tx, question); err != nil {
+			return errors.NewInternalServerError("Failed to schedule question", err)
+		}
+		response, err = s.questionVersions(ctx, tx, question)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+	return response, nil
+}
+
+// UnpublishQuestion hides a question from users straight away. Its versions and
+// the answers given to them are kept; publishing a new draft shows it again.
+func (s *questionService) UnpublishQuestion(ctx context.Context, questionID int, modifiedBy string) (*dtos.QuestionVersionsResponse, error) {
+	var response *dtos.QuestionVersionsResponse
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		question, err := s.lockQuestion(ctx, tx, questionID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		question.Status = entities.QuestionStatusDraft
+		question.LastModifiedBy = &modifiedBy
+		question.LastModifiedOn = &now
+		if err := s.questionRepo.Update(ctx, tx, question); err != nil {
+			return errors.NewInternalServerError("Failed to unpublish question", err)
+		}
+		response, err = s.questionVersions(ctx, tx, question)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+	return response, nil
+}
+
+// DiffQuestionVersions compares two versions of a question field by field, and
+// their options by option ID
+func (s *questionService) DiffQuestionVersions(ctx context.Context, questionID int, req dtos.QuestionVersionDiffRequest) (*dtos.QuestionVersionDiffDTO, error) {
+	versions, err := s.questionVersionRepo.FindByQuestionID(ctx, s.txnManager.GetDB(), questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question versions", err)
+	}
+	if len(versions) == 0 {
+		return nil, errors.NewNotFoundError("Question has no versions", nil)
+	}
+
+	byNumber := make(map[int]*entities.QuestionVersion, len(versions))
+	for i := range versions {
+		byNumber[versions[i].Version] = &versions[i]
+	}
+
+	toNumber := req.To
+	if toNumber == 0 {
+		toNumber = versions[0].Version
+	}
+	fromNumber := req.From
+	if fromNumber == 0 {
+		fromNumber = toNumber - 1
+	}
+
+	from, to := byNumber[fromNumber], byNumber[toNumber]
+	if from == nil || to == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Question version %d or %d not found", fromNumber, toNumber), nil)
+	}
+	return diffQuestionVersions(from, to), nil
+}
+
+// findOrCreateQuestion locks an existing question, or creates a hidden one whose
+// content is filled in when its first version is published
+func (s *questionService) findOrCreateQuestion(ctx context.Context, tx *gorm.DB, questionID int, req dtos.CreateQuestionDTO, createdBy string) (*entities.QuestionMaster, error) {
+	if questionID > 0 {
+		return s.lockQuestion(ctx, tx, questionID)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	question := &entities.QuestionMaster{
+		QuestionText: req.QuestionText,
+		QuesPoint:    req.QuesPoint,
+		LanguageID:   req.LanguageID,
+		IsActive:     isActive,
+		IsDeleted:    false,
+		Status:       entities.QuestionStatusDraft,
+		CreatedBy:    createdBy,
+		CreatedOn:    time.Now(),
+	}
+	applyQuestionType(question, req)
+	if err := s.questionRepo.Create(ctx, tx, question); err != nil {
+		return nil, errors.NewInternalServerError("Failed to create question", err)
+	}
+	return question, nil
+}
+
+func (s *questionService) lockQuestion(ctx context.Context, tx *gorm.DB, questionID int) (*entities.QuestionMaster, error) {
+	question, err := s.questionRepo.LockByID(ctx, tx, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question", err)
+	}
+	if question == nil {
+		return nil, errors.NewNotFoundError(fmt.Sprintf("Question %d not found", questionID), nil)
+	}
+	return question, nil
+}
+
+// saveDraft writes the request into the question's draft version, creating the
+// draft when there is none
+func (s *questionService) saveDraft(ctx context.Context, tx *gorm.DB, question *entities.QuestionMaster, req dtos.CreateQuestionDTO, savedBy string) (*entities.QuestionVersion, error) {
+	existing, err := s.optionMasterRepo.FindByQuestionID(ctx, tx, question.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question options", err)
+	}
+	owned := make(map[int]bool, len(existing))
+	for _, option := range existing {
+		owned[option.ID] = true
+	}
+
+	options := make([]entities.OptionVersion, len(req.Options))
+	for i, oDTO := range req.Options {
+		option := entities.OptionVersion{
+			OptionText:   oDTO.OptionText,
+			DisplayOrder: oDTO.DisplayOrder,
+			IsActive:     oDTO.IsActive == nil || *oDTO.IsActive,
+		}
+		if oDTO.ID != nil && *oDTO.ID > 0 {
+			if !owned[*oDTO.ID] {
+				return nil, errors.NewBadRequestError(fmt.Sprintf("Option %d does not belong to question %d", *oDTO.ID, question.ID), nil)
+			}
+			option.OptionMasterID = oDTO.ID
+		}
+		options[i] = option
+	}
+
+	if err := s.ensureBaseVersion(ctx, tx, question, existing); err != nil {
+		return nil, err
+	}
+
+	draft, err := s.questionVersionRepo.FindDraft(ctx, tx, question.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question draft", err)
+	}
+	if draft == nil {
+		latest, err := s.questionVersionRepo.LatestVersionNumber(ctx, tx, question.ID)
+		if err != nil {
+			return nil, errors.NewInternalServerError("Failed to get question versions", err)
+		}
+		draft = &entities.QuestionVersion{
+			QuestionMasterID: question.ID,
+			Version:          latest + 1,
+			Status:           entities.QuestionVersionStatusDraft,
+		}
+	}
+
+	content := entities.QuestionMaster{
+		QuestionText: req.QuestionText,
+		QuesPoint:    req.QuesPoint,
+		LanguageID:   req.LanguageID,
+	}
+	applyQuestionType(&content, req)
+	setVersionContent(draft, &content)
+	draft.CreatedBy = savedBy
+	draft.CreatedOn = time.Now()
+
+	if draft.ID == 0 {
+		draft.Options = options
+		if err := s.questionVersionRepo.Create(ctx, tx, draft); err != nil {
+			return nil, errors.NewInternalServerError("Failed to save question draft", err)
+		}
+		return draft, nil
+	}
+
+	if err := s.questionVersionRepo.Update(ctx, tx, draft); err != nil {
+		return nil, errors.NewInternalServerError("Failed to save question draft", err)
+	}
+	if err := s.questionVersionRepo.ReplaceOptions(ctx, tx, draft.ID, options); err != nil {
+		return nil, errors.NewInternalServerError("Failed to save question draft options", err)
+	}
+	draft.Options = options
+	return draft, nil
+}
+
+// ensureBaseVersion records the live content of a question published before
+// versioning existed as its first version, so drafts have something to diff against
+func (s *questionService) ensureBaseVersion(ctx context.Context, tx *gorm.DB, question *entities.QuestionMaster, options []entities.OptionMaster) error {
+	if question.PublishedVersionID != nil || question.Status != entities.QuestionStatusPublished {
+		return nil
+	}
+
+	latest, err := s.questionVersionRepo.LatestVersionNumber(ctx, tx, question.ID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to get question versions", err)
+	}
+
+	now := time.Now()
+	base := &entities.QuestionVersion{
+		QuestionMasterID: question.ID,
+		Version:          latest + 1,
+		Status:           entities.QuestionVersionStatusPublished,
+		CreatedBy:        question.CreatedBy,
+		CreatedOn:        question.CreatedOn,
+		PublishedBy:      &question.CreatedBy,
+		PublishedOn:      &now,
+	}
+	setVersionContent(base, question)
+	for _, option := range options {
+		optionID := option.ID
+		base.Options = append(base.Options, entities.OptionVersion{
+			OptionMasterID: &optionID,
+			OptionText:     option.OptionText,
+			DisplayOrder:   option.DisplayOrder,
+			IsActive:       option.IsActive,
+		})
+	}
+	if err := s.questionVersionRepo.Create(ctx, tx, base); err != nil {
+		return errors.NewInternalServerError("Failed to record question version", err)
+	}
+
+	question.PublishedVersionID = &base.ID
+	if err := s.questionRepo.Update(ctx, tx, question); err != nil {
+		return errors.NewInternalServerError("Failed to update question", err)
+	}
+	return nil
+}
+
+// publishVersion copies a draft's content to the live question and options.
+// Options left out of the draft are deactivated rather than deleted, because
+// earlier answers still point at them.
+func (s *questionService) publishVersion(ctx context.Context, tx *gorm.DB, question *entities.QuestionMaster, draft *entities.QuestionVersion, schedule dtos.QuestionScheduleDTO, publishedBy string) error {
+	existing, err := s.optionMasterRepo.FindByQuestionID(ctx, tx, question.ID)
+	if err != nil {
+		return errors.NewInternalServerError("Failed to get question options", err)
+	}
+	liveOptions := make(map[int]*entities.OptionMaster, len(existing))
+	for i := range existing {
+		liveOptions[existing[i].ID] = &existing[i]
+	}
+
+	now := time.Now()
+	kept := make(map[int]bool, len(draft.Options))
+	for i := range draft.Options {
+		optionVersion := &draft.Options[i]
+		if optionVersion.OptionMasterID != nil {
+			if option, ok := liveOptions[*optionVersion.OptionMasterID]; ok {
+				option.OptionText = optionVersion.OptionText
+				option.DisplayOrder = optionVersion.DisplayOrder
+				option.IsActive = optionVersion.IsActive
+				option.LastModifiedBy = &publishedBy
+				option.LastModifiedOn = &now
+				if err := s.optionMasterRepo.Update(ctx, tx, option); err != nil {
+					return errors.NewInternalServerError("Failed to update option", err)
+				}
+				kept[option.ID] = true
+				continue
+			}
+		}
+
+		option := &entities.OptionMaster{
+			QuestionMasterID: question.ID,
+			OptionText:       optionVersion.OptionText,
+			DisplayOrder:     optionVersion.DisplayOrder,
+			IsActive:         optionVersion.IsActive,
+			IsDeleted:        false,
+			CreatedBy:        publishedBy,
+			CreatedOn:        now,
+		}
+		if err := s.optionMasterRepo.Create(ctx, tx, option); err != nil {
+			return errors.NewInternalServerError("Failed to create option", err)
+		}
+		optionVersion.OptionMasterID = &option.ID
+		if err := s.questionVersionRepo.UpdateOption(ctx, tx, optionVersion); err != nil {
+			return errors.NewInternalServerError("Failed to update option version", err)
+		}
+		kept[option.ID] = true
+	}
+	for i := range existing {
+		option := &existing[i]
+		if kept[option.ID] || !option.IsActive {
+			continue
+		}
+		option.IsActive = false
+		option.LastModifiedBy = &publishedBy
+		option.LastModifiedOn = &now
+		if err := s.optionMasterRepo.Update(ctx, tx, option); err != nil {
+			return errors.NewInternalServerError("Failed to deactivate option", err)
+		}
+	}
+
+	question.QuestionText = draft.QuestionText
+	question.QuesPoint = draft.QuesPoint
+	question.LanguageID = draft.LanguageID
+	question.QuestionType = draft.QuestionType
+	question.MinSelections = draft.MinSelections
+	question.MaxSelections = draft.MaxSelections
+	question.MinValue = draft.MinValue
+	question.MaxValue = draft.MaxValue
+	question.MaxLength = draft.MaxLength
+	question.Status = entities.QuestionStatusPublished
+	question.PublishedVersionID = &draft.ID
+	question.PublishAt = schedule.PublishAt
+	question.UnpublishAt = schedule.UnpublishAt
+	question.LastModifiedBy = &publishedBy
+	question.LastModifiedOn = &now
+	if err := s.questionRepo.Update(ctx, tx, question); err != nil {
+		return errors.NewInternalServerError("Failed to publish question", err)
+	}
+
+	draft.Status = entities.QuestionVersionStatusPublished
+	draft.PublishedBy = &publishedBy
+	draft.PublishedOn = &now
+	if err := s.questionVersionRepo.Update(ctx, tx, draft); err != nil {
+		return errors.NewInternalServerError("Failed to publish question version", err)
+	}
+	if err := s.questionVersionRepo.SupersedePublished(ctx, tx, question.ID, draft.ID); err != nil {
+		return errors.NewInternalServerError("Failed to supersede question versions", err)
+	}
+
+	log.WithFields(log.Fields{
+		"question_id":  question.ID,
+		"version":      draft.Version,
+		"published_by": publishedBy,
+	}).Info("Question version published")
+	return nil
+}
+
+func (s *questionService) questionVersions(ctx context.Context, db *gorm.DB, question *entities.QuestionMaster) (*dtos.QuestionVersionsResponse, error) {
+	versions, err := s.questionVersionRepo.FindByQuestionID(ctx, db, question.ID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question versions", err)
+	}
+
+	response := &dtos.QuestionVersionsResponse{
+		QuestionID:  question.ID,
+		Status:      question.Status,
+		IsActive:    question.IsActive,
+		PublishAt:   question.PublishAt,
+		UnpublishAt: question.UnpublishAt,
+		Versions:    make([]dtos.QuestionVersionDTO, len(versions)),
+	}
+	for i := range versions {
+		response.Versions[i] = toQuestionVersionDTO(&versions[i])
+		if question.PublishedVersionID != nil && versions[i].ID == *question.PublishedVersionID {
+			response.PublishedVersion = &versions[i].Version
+		}
+	}
+	return response, nil
+}
+
+func validateQuestionSchedule(schedule dtos.QuestionScheduleDTO) error {
+	if schedule.PublishAt != nil && schedule.UnpublishAt != nil && !schedule.PublishAt.Before(*schedule.UnpublishAt) {
+		return errors.NewBadRequestError("publish_at must be before unpublish_at", nil)
+	}
+	return nil
+}
+
+// setVersionContent copies the versioned fields of a question into a version
+func setVersionContent(version *entities.QuestionVersion, question *entities.QuestionMaster) {
+	version.QuestionText = question.QuestionText
+	version.QuesPoint = question.QuesPoint
+	version.LanguageID = question.LanguageID
+	version.QuestionType = question.Type()
+	version.MinSelections = question.MinSelections
+	version.MaxSelections = question.MaxSelections
+	version.MinValue = question.MinValue
+	version.MaxValue = question.MaxValue
+	version.MaxLength = question.MaxLength
+}
+
+func toQuestionVersionDTO(version *entities.QuestionVersion) dtos.QuestionVersionDTO {
+	response := dtos.QuestionVersionDTO{
+		ID:            version.ID,
+		QuestionID:    version.QuestionMasterID,
+		Version:       version.Version,
+		Status:        version.Status,
+		QuestionText:  version.QuestionText,
+		QuesPoint:     version.QuesPoint,
+		LanguageID:    version.LanguageID,
+		QuestionType:  version.QuestionType,
+		MinSelections: version.MinSelections,
+		MaxSelections: version.MaxSelections,
+		MinValue:      version.MinValue,
+		MaxValue:      version.MaxValue,
+		MaxLength:     version.MaxLength,
+		Options:       make([]dtos.OptionVersionDTO, len(version.Options)),
+		CreatedBy:     version.CreatedBy,
+		CreatedOn:     version.CreatedOn.Format(time.RFC3339),
+		PublishedBy:   version.PublishedBy,
+	}
+	if version.PublishedOn != nil {
+		publishedOn := version.PublishedOn.Format(time.RFC3339)
+		response.PublishedOn = &publishedOn
+	}
+	for i, option := range version.Options {
+		response.Options[i] = dtos.OptionVersionDTO{
+			OptionID:     option.OptionMasterID,
+			OptionText:   option.OptionText,
+			DisplayOrder: option.DisplayOrder,
+			IsActive:     option.IsActive,
+		}
+	}
+	return response
+}
+
+// diffQuestionVersions lists what changed from one version to another. Options
+// are matched by option ID; options added in an unpublished draft have none and
+// are always reported as added.
+func diffQuestionVersions(from, to *entities.QuestionVersion) *dtos.QuestionVersionDiffDTO {
+	diff := &dtos.QuestionVersionDiffDTO{
+		QuestionID: to.QuestionMasterID,
+		From:       from.Version,
+		To:         to.Version,
+		Changes:    []dtos.FieldChangeDTO{},
+		Options:    []dtos.OptionChangeDTO{},
+	}
+
+	addChange(&diff.Changes, "question_text", from.QuestionText, to.QuestionText)
+	addChange(&diff.Changes, "ques_point", from.QuesPoint, to.QuesPoint)
+	addChange(&diff.Changes, "language_id", from.LanguageID, to.LanguageID)
+	addChange(&diff.Changes, "question_type", from.QuestionType, to.QuestionType)
+	addIntPtrChange(&diff.Changes, "min_selections", from.MinSelections, to.MinSelections)
+	addIntPtrChange(&diff.Changes, "max_selections", from.MaxSelections, to.MaxSelections)
+	addIntPtrChange(&diff.Changes, "min_value", from.MinValue, to.MinValue)
+	addIntPtrChange(&diff.Changes, "max_value", from.MaxValue, to.MaxValue)
+	addIntPtrChange(&diff.Changes, "max_length", from.MaxLength, to.MaxLength)
+
+	fromOptions := make(map[int]entities.OptionVersion, len(from.Options))
+	for _, option := range from.Options {
+		if option.OptionMasterID != nil {
+			fromOptions[*option.OptionMasterID] = option
+		}
+	}
+
+	matched := make(map[int]bool, len(to.Options))
+	for _, option := range to.Options {
+		if option.OptionMasterID == nil {
+			diff.Options = append(diff.Options, dtos.OptionChangeDTO{OptionText: option.OptionText, Change: optionChangeAdded})
+			continue
+		}
+		previous, ok := fromOptions[*option.OptionMasterID]
+		if !ok {
+			diff.Options = append(diff.Options, dtos.OptionChangeDTO{OptionID: option.OptionMasterID, OptionText: option.OptionText, Change: optionChangeAdded})
+			continue
+		}
+		matched[*option.OptionMasterID] = true
+
+		var changes []dtos.FieldChangeDTO
+		addChange(&changes, "option_text", previous.OptionText, option.OptionText)
+		addChange(&changes, "display_order", previous.DisplayOrder, option.DisplayOrder)
+		addChange(&changes, "is_active", previous.IsActive, option.IsActive)
+		if len(changes) > 0 {
+			diff.Options = append(diff.Options, dtos.OptionChangeDTO{OptionID: option.OptionMasterID, OptionText: option.OptionText, Change: optionChangeChanged, Changes: changes})
+		}
+	}
+	for _, option := range from.Options {
+		if option.OptionMasterID != nil && !matched[*option.OptionMasterID] {
+			diff.Options = append(diff.Options, dtos.OptionChangeDTO{OptionID: option.OptionMasterID, OptionText: option.OptionText, Change: optionChangeRemoved})
+		}
+	}
+	return diff
+}
+
+func addChange[T comparable](changes *[]dtos.FieldChangeDTO, field string, from, to T) {
+	if from != to {
+		*changes = append(*changes, dtos.FieldChangeDTO{Field: field, From: from, To: to})
+	}
+}
+
+func addIntPtrChange(changes *[]dtos.FieldChangeDTO, field string, from, to *int) {
+	if from == nil && to == nil || from != nil && to != nil && *from == *to {
+		return
+	}
+	*changes = append(*changes, dtos.FieldChangeDTO{Field: field, From: from, To: to})
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// questionnaire orders the live profile questions by section and display order
+// and decides which of them apply to a user given their answers
+type questionnaire struct {
+	questions  []entities.QuestionMaster
+	sections   []entities.QuestionSection
+	conditions map[int][]entities.QuestionCondition
+	// inactiveSection holds questions placed in a section that is inactive or deleted
+	inactiveSection map[int]bool
+}
+
+func newQuestionnaire(questions []entities.QuestionMaster, sections []entities.QuestionSection, conditions []entities.QuestionCondition) *questionnaire {
+	q := &questionnaire{
+		sections:        sections,
+		conditions:      make(map[int][]entities.QuestionCondition),
+		inactiveSection: make(map[int]bool),
+	}
+
+	sectionRank := make(map[int]int, len(sections))
+	for i, section := range sections {
+		sectionRank[section.ID] = i
+	}
+	rank := func(question entities.QuestionMaster) int {
+		if question.SectionID == nil {
+			return len(sections)
+		}
+		return sectionRank[*question.SectionID]
+	}
+
+	for _, question := range questions {
+		if question.SectionID != nil {
+			if _, ok := sectionRank[*question.SectionID]; !ok {
+				q.inactiveSection[question.ID] = true
+				continue
+			}
+		}
+		q.questions = append(q.questions, question)
+	}
+	sort.SliceStable(q.questions, func(i, j int) bool {
+		a, b := q.questions[i], q.questions[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.DisplayOrder != b.DisplayOrder {
+			return a.DisplayOrder < b.DisplayOrder
+		}
+		return a.ID < b.ID
+	})
+
+	for _, condition := range conditions {
+		q.conditions[condition.QuestionMasterID] = append(q.conditions[condition.QuestionMasterID], condition)
+	}
+	return q
+}
+
+// applicable returns the questions shown for the given answers, keyed by question
+// ID. A question is shown when all its conditions hold and the questions they
+// depend on are shown; conditions that form a cycle never hold.
+func (q *questionnaire) applicable(answers map[int][]entities.UserQuestionAnswer) map[int]bool {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	listed := make(map[int]bool, len(q.questions))
+	for _, question := range q.questions {
+		listed[question.ID] = true
+	}
+
+	shown := make(map[int]bool, len(q.questions))
+	state := make(map[int]int, len(q.questions))
+	var visit func(questionID int) bool
+	visit = func(questionID int) bool {
+		switch state[questionID] {
+		case visiting:
+			return false
+		case visited:
+			return shown[questionID]
+		}
+		state[questionID] = visiting
+
+		ok := listed[questionID]
+		for _, condition := range q.conditions[questionID] {
+			if !ok {
+				break
+			}
+			ok = visit(condition.DependsOnQuestionID) && condition.IsMet(answers[condition.DependsOnQuestionID])
+		}
+
+		state[questionID] = visited
+		shown[questionID] = ok
+		return ok
+	}
+
+	for _, question := range q.questions {
+		visit(question.ID)
+	}
+	return shown
+}
+
+// hides reports whether the question belongs to the questionnaire but is not
+// shown. Questions outside the questionnaire are never hidden by it.
+func (q *questionnaire) hides(questionID int, shown map[int]bool) bool {
+	if q.inactiveSection[questionID] {
+		return true
+	}
+	for _, question := range q.questions {
+		if question.ID == questionID {
+			return !shown[questionID]
+		}
+	}
+	return false
+}
+
+// summarize counts applicable and answered questions per section and overall,
+// and picks the first applicable question without an answer
+func (q *questionnaire) summarize(questions []dtos.QuestionResponseDTO) ([]dtos.QuestionnaireSectionDTO, *dtos.QuestionResponseDTO, dtos.QuestionnaireCompletionDTO) {
+	sections := make([]dtos.QuestionnaireSectionDTO, len(q.sections))
+	sectionIndex := make(map[int]int, len(q.sections))
+	for i, section := range q.sections {
+		sections[i] = dtos.QuestionnaireSectionDTO{
+			ID:           section.ID,
+			Title:        section.Title,
+			DisplayOrder: section.DisplayOrder,
+		}
+		sectionIndex[section.ID] = i
+	}
+
+	var next *dtos.QuestionResponseDTO
+	completion := dtos.QuestionnaireCompletionDTO{Total: len(questions)}
+	for i := range questions {
+		question := &questions[i]
+		if question.Answered {
+			completion.Answered++
+		} else if next == nil {
+			next = question
+		}
+		if question.SectionID == nil {
+			continue
+		}
+		if index, ok := sectionIndex[*question.SectionID]; ok {
+			sections[index].Total++
+			if question.Answered {
+				sections[index].Answered++
+			}
+		}
+	}
+
+	completion.Percent = 100
+	if completion.Total > 0 {
+		completion.Percent = completion.Answered * 100 / completion.Total
+	}
+	return sections, next, completion
+}
+
+// loadQuestionnaire reads the live profile questions with their active sections
+// and display conditions
+func (s *userService) loadQuestionnaire(ctx context.Context, db *gorm.DB) (*questionnaire, error) {
+	questions, err := s.questionMasterRepo.FindActive(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	sections, err := s.questionSectionRepo.FindOrdered(ctx, db, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question sections: %w", err)
+	}
+
+	conditions, err := s.questionConditionRepo.FindByQuestionIDs(ctx, db, questionIDs(questions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question conditions: %w", err)
+	}
+
+	return newQuestionnaire(questions, sections, conditions), nil
+}
+
+// checkApplicable records a field error for every answer to a question that is
+// not shown once the batch's own answers replace the user's stored ones, so a
+// question and the answer that unlocks it may be submitted together
+func (s *userService) checkApplicable(ctx context.Context, tx *gorm.DB, userID string, answered []answeredQuestion, now time.Time, fieldErrors utils.FieldErrors) error {
+	questionnaire, err := s.loadQuestionnaire(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.questionAnswerRepo.FindByUserID(ctx, tx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user answers: %w", err)
+	}
+	answers := make(map[int][]entities.UserQuestionAnswer)
+	for _, answer := range stored {
+		answers[answer.QuestionMasterID] = append(answers[answer.QuestionMasterID], answer)
+	}
+	for _, a := range answered {
+		answers[a.question.ID] = answerRows(userID, a, now)
+	}
+
+	shown := questionnaire.applicable(answers)
+	for _, a := range answered {
+		if questionnaire.hides(a.question.ID, shown) {
+			fieldErrors.Add(fmt.Sprintf("answers[%d].question_id", a.index), "question does not apply given your earlier answers")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/queue"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/screening"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type ScreeningService interface {
+	EnqueueSubmission(submission screening.Submission)
+	ScreenSubmission(ctx context.Context, submission screening.Submission) error
+}
+
+type screeningService struct {
+	txnManager      *utils.TransactionManager
+	thunderSeatRepo repository.ThunderSeatRepository
+	screeningRepo   repository.GenericRepository[entities.ThunderSeatScreening]
+	workerPool      *queue.WorkerPool
+	chain           *screening.Chain
+	pointsService   PointsService
+}
+
+func NewScreeningService(
+	txnManager *utils.TransactionManager,
+	thunderSeatRepo repository.ThunderSeatRepository,
+	screeningRepo repository.GenericRepository[entities.ThunderSeatScreening],
+	workerPool *queue.WorkerPool,
+	chain *screening.Chain,
+	pointsService PointsService,
+) ScreeningService {
+	return &screeningService{
+		txnManager:      txnManager,
+		thunderSeatRepo: thunderSeatRepo,
+		screeningRepo:   screeningRepo,
+		workerPool:      workerPool,
+		chain:           chain,
+		pointsService:   pointsService,
+	}
+}
+
+// NewLocalScreeningChain builds the rule-based screening chain. Adapters for external
+// moderation APIs are passed as extra screeners and run after the local checks.
+func NewLocalScreeningChain(txnManager *utils.TransactionManager, thunderSeatRepo repository.ThunderSeatRepository, extra ...screening.ContentScreener) *screening.Chain {
+	screeners := []screening.ContentScreener{
+		screening.NewProfanityCheck(screening.DefaultEnglishBlocklist, screening.DefaultHindiBlocklist),
+		screening.NewMediaSanityCheck(constants.SCREENING_MIN_MEDIA_DURATION, map[string]time.Duration{
+			"audio": constants.SCREENING_MAX_AUDIO_DURATION,
+			"video": constants.SCREENING_MAX_VIDEO_DURATION,
+		}),
+		screening.NewDuplicateMediaCheck(func(ctx context.Context, sha256 string, excludeID int) ([]int, error) {
+			return thunderSeatRepo.FindIDsByMediaHash(ctx, txnManager.GetDB(), sha256, excludeID)
+		}),
+	}
+	return screening.NewChain(append(screeners, extra...)...)
+}
+
+// EnqueueSubmission screens the submission on the worker pool. Submissions that cannot be
+// queued stay pending and are picked up by manual moderation.
+func (s *screeningService) EnqueueSubmission(submission screening.Submission) {
+	if s.workerPool == nil {
+		return
+	}
+
+	task := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, constants.SCREENING_TIMEOUT)
+		defer cancel()
+		return s.ScreenSubmission(ctx, submission)
+	}
+
+	if err := s.workerPool.Submit(task); err != nil {
+		log.WithError(err).WithField("submission_id", submission.ID).Warn("Failed to queue submission for screening")
+	}
+}
+
+func (s *screeningService) ScreenSubmission(ctx context.Context, submission screening.Submission) error {
+	result := s.chain.Run(ctx, submission)
+
+	findings, err := json.Marshal(result.Findings)
+	if err != nil {
+		return err
+	}
+
+	record := &entities.ThunderSeatScreening{
+		ThunderSeatID: submission.ID,
+		Verdict:       string(result.Verdict),
+		Findings:      string(findings),
+	}
+
+	status := moderationStatusForVerdict(result.Verdict, submission.Media)
+	var reason *string
+	if len(result.Findings) > 0 {
+		reasons := make([]string, len(result.Findings))
+		for i, finding := range result.Findings {
+			reasons[i] = finding.Reason
+		}
+		joined := strings.Join(reasons, "; ")
+		reason = &joined
+	}
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.screeningRepo.Create(ctx, tx, record); err != nil {
+			return err
+		}
+		if status == entities.ModerationStatusPending {
+			return nil
+		}
+		if err := s.thunderSeatRepo.UpdatePendingModerationStatus(ctx, tx, submission.ID, status, reason, constants.SYSTEM_USER_ID); err != nil {
+			return err
+		}
+		if status != entities.ModerationStatusRejected {
+			return nil
+		}
+		thunderSeat, err := s.thunderSeatRepo.FindByID(ctx, tx, submission.ID)
+		if err != nil || thunderSeat == nil {
+			return err
+		}
+		return forfeitThunderSeatEntryPoints(ctx, tx, s.pointsService, s.thunderSeatRepo, thunderSeat.UserID, thunderSeat.WeekNumber)
+	})
+	if err != nil {
+		log.WithError(err).WithField("submission_id", submission.ID).Error("Failed to save screening verdict")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"submission_id": submission.ID,
+		"verdict":       result.Verdict,
+		"findings":      len(result.Findings),
+	}).Info("Thunder seat submission screened")
+	return nil
+}
+
+// moderationStatusForVerdict maps a screening verdict to the moderation status it produces.
+// Media without a hash skipped the duplicate check, so a passing verdict leaves it pending.
+func moderationStatusForVerdict(verdict screening.Verdict, media *screening.Media) string {
+	switch verdict {
+	case screening.VerdictReject:
+		return entities.ModerationStatusRejected
+	case screening.VerdictReview:
+		return entities.ModerationStatusFlagged
+	default:
+		if media != nil && media.SHA256 == "" {
+			return entities.ModerationStatusPending
+		}
+		return entities.ModerationStatusApproved
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// StorageGCOptions controls a garbage collection run
+type StorageGCOptions struct {
+	// GracePeriod protects objects uploaded recently, whose rows may not be written yet
+	GracePeriod time.Duration
+	// DryRun only reports orphans without touching them
+	DryRun bool
+	// Quarantine moves orphans under constants.STORAGE_GC_QUARANTINE_PREFIX instead of deleting them
+	Quarantine bool
+}
+
+// StorageGCOrphan is an object no row references
+type StorageGCOrphan struct {
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	CreatedOn time.Time `json:"created_on"`
+	// Action is what happened to the object: reported, deleted, quarantined or failed
+	Action string `json:"action"`
+}
+
+// StorageGCClassReport summarizes one object class
+type StorageGCClassReport struct {
+	Class      string `json:"class"`
+	Prefix     string `json:"prefix"`
+	Scanned    int    `json:"scanned"`
+	Referenced int    `json:"referenced"`
+	TooRecent  int    `json:"too_recent"`
+	Orphaned   int    `json:"orphaned"`
+}
+
+// StorageGCReport is the outcome of a garbage collection run
+type StorageGCReport struct {
+	StartedOn      time.Time              `json:"started_on"`
+	FinishedOn     time.Time              `json:"finished_on"`
+	DryRun         bool                   `json:"dry_run"`
+	Quarantine     bool                   `json:"quarantine"`
+	GracePeriod    string                 `json:"grace_period"`
+	Classes        []StorageGCClassReport `json:"classes"`
+	Orphans        []StorageGCOrphan      `json:"orphans"`
+	Removed        int                    `json:"removed"`
+	Failed         int                    `json:"failed"`
+	BytesReclaimed int64                  `json:"bytes_reclaimed"`
+}
+
+const (
+	storageGCActionReported    = "reported"
+	storageGCActionDeleted     = "deleted"
+	storageGCActionQuarantined = "quarantined"
+	storageGCActionFailed      = "failed"
+)
+
+// StorageGCService removes objects left behind when an upload succeeded but the
+// row pointing at it was never written or was later removed
+type StorageGCService interface {
+	Run(ctx context.Context, opts StorageGCOptions) (*StorageGCReport, error)
+}
+
+type storageGCService struct {
+	txnManager    *utils.TransactionManager
+	referenceRepo repository.StorageReferenceRepository
+	gcsService    utils.GCSService
+}
+
+func NewStorageGCService(
+	txnManager *utils.TransactionManager,
+	referenceRepo repository.StorageReferenceRepository,
+	gcsService utils.GCSService,
+) StorageGCService {
+	return &storageGCService{
+		txnManager:    txnManager,
+		referenceRepo: referenceRepo,
+		gcsService:    gcsService,
+	}
+}
+
+func (s *storageGCService) Run(ctx context.Context, opts StorageGCOptions) (*StorageGCReport, error) {
+	if opts.GracePeriod < constants.STORAGE_GC_MIN_GRACE_PERIOD {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Grace period must be at least %s", constants.STORAGE_GC_MIN_GRACE_PERIOD), nil)
+	}
+
+	report := &StorageGCReport{
+		StartedOn:   time.Now(),
+		DryRun:      opts.DryRun,
+		Quarantine:  opts.Quarantine,
+		GracePeriod: opts.GracePeriod.String(),
+	}
+
+	// Objects are listed before references are loaded: an object uploaded in
+	// between is then either too recent or already referenced, never a false orphan
+	classObjects := make([][]utils.ObjectInfo, len(utils.ObjectClasses))
+	for i, class := range utils.ObjectClasses {
+		objects, err := s.gcsService.ListObjects(ctx, class.Prefix)
+		if err != nil {
+			return nil, errors.NewInternalServerError(fmt.Sprintf("Failed to list %s objects", class.Name), err)
+		}
+		classObjects[i] = objects
+	}
+
+	referenced, err := s.loadReferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := report.StartedOn.Add(-opts.GracePeriod)
+	for i, class := range utils.ObjectClasses {
+		classReport := StorageGCClassReport{Class: class.Name, Prefix: class.Prefix}
+		for _, object := range classObjects[i] {
+			classReport.Scanned++
+			switch {
+			case referenced[object.Path]:
+				classReport.Referenced++
+			case object.CreatedOn.After(cutoff):
+				classReport.TooRecent++
+			default:
+				classReport.Orphaned++
+				report.Orphans = append(report.Orphans, s.collect(ctx, object, opts, report))
+			}
+		}
+		report.Classes = append(report.Classes, classReport)
+	}
+
+	report.FinishedOn = time.Now()
+	log.WithFields(log.Fields{
+		"dry_run":         report.DryRun,
+		"quarantine":      report.Quarantine,
+		"orphans":         len(report.Orphans),
+		"removed":         report.Removed,
+		"failed":          report.Failed,
+		"bytes_reclaimed": report.BytesReclaimed,
+	}).Info("Storage garbage collection finished")
+	return report, nil
+}
+
+// storageGCPinnedObjects are referenced by code rather than by any row and are never collected
+var storageGCPinnedObjects = []string{
+	constants.DEFAULT_AVATAR_OBJECT_PATH,
+}
+
+// loadReferences returns the set of referenced object paths, with stored URLs
+// converted to paths
+func (s *storageGCService) loadReferences(ctx context.Context) (map[string]bool, error) {
+	stored, err := s.referenceRepo.FindReferencedObjects(ctx, s.txnManager.GetDB())
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to load object references", err)
+	}
+
+	referenced := make(map[string]bool, len(stored)+len(storageGCPinnedObjects))
+	for _, objectPath := range storageGCPinnedObjects {
+		referenced[objectPath] = true
+	}
+	for _, value := range stored {
+		if objectPath, ok := s.gcsService.ObjectPathFromURL(value); ok {
+			value = objectPath
+		}
+		referenced[strings.TrimPrefix(value, "/")] = true
+	}
+	return referenced, nil
+}
+
+// collect deletes or quarantines an orphan unless this is a dry run
+func (s *storageGCService) collect(ctx context.Context, object utils.ObjectInfo, opts StorageGCOptions, report *StorageGCReport) StorageGCOrphan {
+	orphan := StorageGCOrphan{
+		Path:      object.Path,
+		Size:      object.Size,
+		CreatedOn: object.CreatedOn,
+		Action:    storageGCActionReported,
+	}
+	if opts.DryRun {
+		return orphan
+	}
+
+	var err error
+	if opts.Quarantine {
+		err = s.quarantine(ctx, object)
+		orphan.Action = storageGCActionQuarantined
+	} else {
+		err = s.gcsService.DeleteFile(ctx, s.gcsService.GetPublicURL(object.Path))
+		orphan.Action = storageGCActionDeleted
+	}
+	if err != nil {
+		log.WithError(err).WithField("object_path", object.Path).Warn("Failed to collect orphaned object")
+		orphan.Action = storageGCActionFailed
+		report.Failed++
+		return orphan
+	}
+
+	report.Removed++
+	report.BytesReclaimed += object.Size
+	return orphan
+}
+
+// quarantine copies the object under the quarantine prefix, which no class
+// covers, and removes the original
+func (s *storageGCService) quarantine(ctx context.Context, object utils.ObjectInfo) error {
+	data, err := s.gcsService.ReadObjectRange(ctx, object.Path, 0, -1)
+	if err != nil {
+		return err
+	}
+	target := constants.STORAGE_GC_QUARANTINE_PREFIX + object.Path
+	if _, _, err := s.gcsService.UploadFileFromBytes(ctx, data, target, object.ContentType); err != nil {
+		return err
+	}
+	return s.gcsService.DeleteFile(ctx, s.gcsService.GetPublicURL(object.Path))
+}
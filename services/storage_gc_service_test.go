@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+type fakeStorageReferenceRepository struct {
+	paths []string
+}
+
+func (r *fakeStorageReferenceRepository) FindReferencedObjects(ctx context.Context, db *gorm.DB) ([]string, error) {
+	return r.paths, nil
+}
+
+// gcTestStorage lists objects with fixed creation times, which the in-memory
+// driver always sets to now
+type gcTestStorage struct {
+	utils.GCSService
+	objects []utils.ObjectInfo
+}
+
+func (s *gcTestStorage) ListObjects(ctx context.Context, prefix string) ([]utils.ObjectInfo, error) {
+	var objects []utils.ObjectInfo
+	for _, object := range s.objects {
+		if strings.HasPrefix(object.Path, prefix) {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+func TestStorageGC_NeverCollectsDefaultAvatar(t *testing.T) {
+	memory, err := utils.NewMemoryStorageService("http://localhost:8080/storage", "test-signing-key")
+	require.NoError(t, err)
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	storage := &gcTestStorage{
+		GCSService: memory,
+		objects: []utils.ObjectInfo{
+			{Path: constants.DEFAULT_AVATAR_OBJECT_PATH, Size: 10, CreatedOn: old},
+			{Path: "avatars/admin/orphan.png", Size: 20, CreatedOn: old},
+		},
+	}
+
+	gc := NewStorageGCService(utils.NewTransactionManager(nil), &fakeStorageReferenceRepository{}, storage)
+	report, err := gc.Run(context.Background(), StorageGCOptions{
+		GracePeriod: constants.STORAGE_GC_DEFAULT_GRACE_PERIOD,
+		DryRun:      true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, "avatars/admin/orphan.png", report.Orphans[0].Path)
+	for _, class := range report.Classes {
+		if class.Class == "avatar" {
+			assert.Equal(t, 1, class.Referenced, "the default avatar must count as referenced")
+		}
+	}
+}
@@ -2,125 +2,203 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
-	"mime/multipart"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
 	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/screening"
 	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
-// contains checks if a string contains a substring (case-insensitive)
-func contains(s, substr string) bool {
-	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
-}
-
 type ThunderSeatService interface {
-	SubmitAnswer(ctx context.Context, req dtos.ThunderSeatSubmitRequest, userID string, mediaFile *multipart.FileHeader) (*dtos.ThunderSeatResponse, error)
+	SubmitAnswer(ctx context.Context, req dtos.ThunderSeatSubmitRequest, userID string, mediaFile *utils.ValidatedFile, idempotencyKey string) (*dtos.ThunderSeatResponse, error)
 	GetUserSubmissions(ctx context.Context, userID string) ([]dtos.ThunderSeatResponse, error)
-	GetCurrentWeek(ctx context.Context) (*dtos.CurrentWeekResponse, error)
+	GetCurrentWeek(ctx context.Context, languageID int) (*dtos.CurrentWeekResponse, error)
+	UpdateSubmission(ctx context.Context, submissionID int, userID string, req dtos.ThunderSeatUpdateRequest, mediaFile *utils.ValidatedFile) (*dtos.ThunderSeatResponse, error)
+	WithdrawSubmission(ctx context.Context, submissionID int, userID string) error
+	CreateUploadSession(ctx context.Context, userID string, req dtos.UploadSessionRequest) (*dtos.UploadSessionResponse, error)
+	FinalizeUploadSession(ctx context.Context, sessionID string, userID string, req dtos.FinalizeUploadRequest, idempotencyKey string) (*dtos.ThunderSeatResponse, error)
 }
 
 type thunderSeatService struct {
-	txnManager      *utils.TransactionManager
-	thunderSeatRepo repository.ThunderSeatRepository
-	contestWeekRepo repository.ContestWeekRepository
-	userRepo        repository.UserRepository
-	gcsService      utils.GCSService
+	txnManager        *utils.TransactionManager
+	thunderSeatRepo   repository.ThunderSeatRepository
+	contestWeekRepo   repository.ContestWeekRepository
+	ruleRepo          repository.ContestWeekRuleRepository
+	revisionRepo      repository.GenericRepository[entities.ThunderSeatRevision]
+	uploadSessionRepo repository.UploadSessionRepository
+	mediaAssetRepo    repository.MediaAssetRepository
+	userRepo          repository.UserRepository
+	gcsService        utils.GCSService
+	mediaResolver     MediaURLResolver
+	filePolicy        *utils.FilePolicy
+	screeningService  ScreeningService
+	mediaPipeline     MediaPipelineService
+	pointsService     PointsService
 }
 
 func NewThunderSeatService(
 	txnManager *utils.TransactionManager,
 	thunderSeatRepo repository.ThunderSeatRepository,
 	contestWeekRepo repository.ContestWeekRepository,
+	ruleRepo repository.ContestWeekRuleRepository,
+	revisionRepo repository.GenericRepository[entities.ThunderSeatRevision],
+	uploadSessionRepo repository.UploadSessionRepository,
+	mediaAssetRepo repository.MediaAssetRepository,
 	userRepo repository.UserRepository,
 	gcsService utils.GCSService,
+	mediaResolver MediaURLResolver,
+	filePolicy *utils.FilePolicy,
+	screeningService ScreeningService,
+	mediaPipeline MediaPipelineService,
+	pointsService PointsService,
 ) ThunderSeatService {
 	return &thunderSeatService{
-		txnManager:      txnManager,
-		thunderSeatRepo: thunderSeatRepo,
-		contestWeekRepo: contestWeekRepo,
-		userRepo:        userRepo,
-		gcsService:      gcsService,
+		txnManager:        txnManager,
+		thunderSeatRepo:   thunderSeatRepo,
+		contestWeekRepo:   contestWeekRepo,
+		ruleRepo:          ruleRepo,
+		revisionRepo:      revisionRepo,
+		uploadSessionRepo: uploadSessionRepo,
+		mediaAssetRepo:    mediaAssetRepo,
+		userRepo:          userRepo,
+		gcsService:        gcsService,
+		mediaResolver:     mediaResolver,
+		filePolicy:        filePolicy,
+		screeningService:  screeningService,
+		mediaPipeline:     mediaPipeline,
+		pointsService:     pointsService,
 	}
 }
 
-func (s *thunderSeatService) SubmitAnswer(ctx context.Context, req dtos.ThunderSeatSubmitRequest, userID string, mediaFile *multipart.FileHeader) (*dtos.ThunderSeatResponse, error) {
-	activeWeek, err := s.contestWeekRepo.FindActiveWeek(ctx, s.txnManager.GetDB())
-	if err != nil {
-		log.WithError(err).Error("Failed to get active contest week from database")
-		return nil, errors.NewInternalServerError("Failed to get active contest week", err)
-	}
-	if activeWeek == nil {
-		log.Warn("No active contest week found when attempting to submit answer")
-		return nil, errors.NewBadRequestError("No active contest week found. Please check if a contest week is currently active.", nil)
+func (s *thunderSeatService) SubmitAnswer(ctx context.Context, req dtos.ThunderSeatSubmitRequest, userID string, mediaFile *utils.ValidatedFile, idempotencyKey string) (*dtos.ThunderSeatResponse, error) {
+	// A retried request with the same idempotency key returns the original submission
+	if idempotencyKey != "" {
+		existing, err := s.thunderSeatRepo.FindByIdempotencyKey(ctx, s.txnManager.GetDB(), userID, idempotencyKey)
+		if err != nil {
+			log.WithError(err).WithField("user_id", userID).Error("Failed to look up submission by idempotency key")
+			return nil, errors.NewInternalServerError("Failed to submit answer. Please try again later.", err)
+		}
+		if existing != nil {
+			return s.toThunderSeatResponse(ctx, existing), nil
+		}
 	}
 
-	now := time.Now()
-	// Include the full end date by checking if now is after end of day (23:59:59.999)
-	endOfDay := time.Date(activeWeek.EndDate.Year(), activeWeek.EndDate.Month(), activeWeek.EndDate.Day(), 23, 59, 59, 999999999, activeWeek.EndDate.Location())
-
-	if now.Before(activeWeek.StartDate) {
-		log.WithFields(log.Fields{
-			"now":         now,
-			"start_date":  activeWeek.StartDate,
-			"end_date":    activeWeek.EndDate,
-			"week_number": activeWeek.WeekNumber,
-		}).Warn("Submission attempted before contest week start date")
-		return nil, errors.NewBadRequestError(fmt.Sprintf("Submissions are not allowed before the contest week starts. Contest week %d starts on %s", activeWeek.WeekNumber, activeWeek.StartDate.Format("2006-01-02 15:04:05")), nil)
+	activeWeek, err := s.getOpenWeek(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if now.After(endOfDay) {
-		log.WithFields(log.Fields{
-			"now":         now,
-			"start_date":  activeWeek.StartDate,
-			"end_date":    activeWeek.EndDate,
-			"end_of_day":  endOfDay,
-			"week_number": activeWeek.WeekNumber,
-		}).Warn("Submission attempted after contest week end date")
-		return nil, errors.NewBadRequestError(fmt.Sprintf("Submissions are not allowed after the contest week ends. Contest week %d ended on %s", activeWeek.WeekNumber, activeWeek.EndDate.Format("2006-01-02")), nil)
+	rule, err := s.getWeekRule(ctx, activeWeek.WeekNumber)
+	if err != nil {
+		return nil, err
+	}
+	mediaType := ""
+	if mediaFile != nil {
+		mediaType = mediaFile.Kind
+	}
+	if err := validateSubmissionRules(rule, req, mediaType); err != nil {
+		return nil, err
 	}
 
-	thunderSeat := &entities.ThunderSeat{
-		UserID:     userID,
-		WeekNumber: activeWeek.WeekNumber,
-		Answer:     req.Answer,
-		CreatedBy:  userID,
-		CreatedOn:  now,
+	// Check the submission limit before uploading so rejected requests never leave orphaned media
+	if err := s.checkSubmissionLimit(ctx, userID, rule); err != nil {
+		return nil, err
 	}
 
 	// Upload media file to GCS if provided
+	var media *submissionMedia
 	if mediaFile != nil {
+		probedMedia := probeMediaFile(mediaFile)
+
 		folderPath := fmt.Sprintf("thunder-seat/%s/week-%d", userID, activeWeek.WeekNumber)
-		mediaURL, mediaKey, err := s.gcsService.UploadFile(ctx, mediaFile, folderPath)
+		mediaURL, mediaKey, err := utils.UploadValidatedFile(ctx, s.gcsService, mediaFile, folderPath)
 		if err != nil {
 			log.WithError(err).Error("Failed to upload media file to GCS")
 			return nil, errors.NewInternalServerError("Failed to upload media file", err)
 		}
 
-		mediaType := utils.GetMediaType(mediaFile)
-		thunderSeat.MediaURL = &mediaURL
-		thunderSeat.MediaKey = &mediaKey
-		thunderSeat.MediaType = &mediaType
+		media = &submissionMedia{
+			URL:             mediaURL,
+			Key:             mediaKey,
+			Path:            fmt.Sprintf("%s/%s", folderPath, mediaKey),
+			Type:            mediaType,
+			ContentType:     mediaFile.ContentType,
+			Size:            mediaFile.Size,
+			Probe:           probedMedia,
+			DeleteOnFailure: true,
+		}
+	}
+
+	return s.createSubmission(ctx, userID, activeWeek.WeekNumber, req, media, idempotencyKey, nil)
+}
+
+// createSubmission stores a new submission with already uploaded media. onCreated,
+// when set, runs inside the same transaction as the insert.
+func (s *thunderSeatService) createSubmission(
+	ctx context.Context,
+	userID string,
+	weekNumber int,
+	req dtos.ThunderSeatSubmitRequest,
+	media *submissionMedia,
+	idempotencyKey string,
+	onCreated func(tx *gorm.DB, thunderSeat *entities.ThunderSeat) error,
+) (*dtos.ThunderSeatResponse, error) {
+	submissionSeq, err := s.thunderSeatRepo.NextSubmissionSeq(ctx, s.txnManager.GetDB(), userID, weekNumber)
+	if err != nil {
+		log.WithError(err).WithField("user_id", userID).Error("Failed to get next submission sequence")
+		s.cleanupMedia(ctx, media)
+		return nil, errors.NewInternalServerError("Failed to submit answer. Please try again later.", err)
+	}
+
+	thunderSeat := &entities.ThunderSeat{
+		UserID:           userID,
+		WeekNumber:       weekNumber,
+		SubmissionSeq:    submissionSeq,
+		ModerationStatus: entities.ModerationStatusPending,
+		Answer:           req.Answer,
+		CreatedBy:        userID,
+		CreatedOn:        time.Now(),
 	}
+	if idempotencyKey != "" {
+		thunderSeat.IdempotencyKey = &idempotencyKey
+	}
+	media.applyTo(thunderSeat)
 
+	var asset *entities.MediaAsset
 	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		var err error
+		asset, err = s.createMediaAsset(ctx, tx, media, userID)
+		if err != nil {
+			return err
+		}
+		if asset != nil {
+			thunderSeat.MediaAssetID = &asset.ID
+		}
+
 		if err := s.thunderSeatRepo.Create(ctx, tx, thunderSeat); err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"user_id":     userID,
-				"week_number": activeWeek.WeekNumber,
+				"week_number": weekNumber,
 			}).Error("Failed to create thunder seat record in database")
 			return err
 		}
 
+		if _, err := s.pointsService.Award(ctx, tx, thunderSeatEntryAward(userID, weekNumber)); err != nil {
+			return err
+		}
+
 		if req.SharingPlatform != nil || req.PlatformUserName != nil {
 			userUUID, parseErr := uuid.Parse(userID)
 			if parseErr != nil {
@@ -153,38 +231,59 @@ func (s *thunderSeatService) SubmitAnswer(ctx context.Context, req dtos.ThunderS
 			}
 		}
 
+		if onCreated != nil {
+			return onCreated(tx, thunderSeat)
+		}
 		return nil
 	})
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"user_id":     userID,
-			"week_number": activeWeek.WeekNumber,
-			"has_media":   mediaFile != nil,
+			"week_number": weekNumber,
+			"has_media":   media != nil,
 		}).Error("Failed to submit thunder seat answer in transaction")
 
-		if thunderSeat.MediaURL != nil {
-			if deleteErr := s.gcsService.DeleteFile(ctx, *thunderSeat.MediaURL); deleteErr != nil {
-				log.WithError(deleteErr).WithField("media_url", *thunderSeat.MediaURL).Error("Failed to cleanup uploaded file after database error")
-			}
+		s.cleanupMedia(ctx, media)
+
+		if stderrors.Is(err, errUploadSessionNotPending) {
+			return nil, errors.NewConflictError("Upload session has already been finalized", err)
 		}
 
-		errStr := err.Error()
-		if contains(errStr, "duplicate") || contains(errStr, "unique constraint") || contains(errStr, "UNIQUE constraint") {
-			return nil, errors.NewBadRequestError("You have already submitted an answer for this contest week", err)
+		var dupErr *errors.DuplicateError
+		if stderrors.As(err, &dupErr) {
+			if dupErr.Constraint == entities.ThunderSeatIdempotencyKeyConstraint {
+				existing, findErr := s.thunderSeatRepo.FindByIdempotencyKey(ctx, s.txnManager.GetDB(), userID, idempotencyKey)
+				if findErr == nil && existing != nil {
+					return s.toThunderSeatResponse(ctx, existing), nil
+				}
+			}
+			return nil, errors.NewConflictError("You have already submitted an answer for this contest week", err)
 		}
 
 		return nil, errors.NewInternalServerError("Failed to submit answer. Please try again later.", err)
 	}
 
+	s.enqueueMediaAsset(asset)
+	s.screeningService.EnqueueSubmission(screening.Submission{
+		ID:    thunderSeat.ID,
+		Text:  thunderSeat.Answer,
+		Media: media.probe(),
+	})
+
+	return s.toThunderSeatResponse(ctx, thunderSeat), nil
+}
+
+func (s *thunderSeatService) toThunderSeatResponse(ctx context.Context, thunderSeat *entities.ThunderSeat) *dtos.ThunderSeatResponse {
 	return &dtos.ThunderSeatResponse{
-		ID:         thunderSeat.ID,
-		UserID:     thunderSeat.UserID,
-		WeekNumber: thunderSeat.WeekNumber,
-		Answer:     thunderSeat.Answer,
-		MediaURL:   thunderSeat.MediaURL,
-		MediaType:  thunderSeat.MediaType,
-		CreatedOn:  thunderSeat.CreatedOn.Format(time.RFC3339),
-	}, nil
+		ID:               thunderSeat.ID,
+		UserID:           thunderSeat.UserID,
+		WeekNumber:       thunderSeat.WeekNumber,
+		Answer:           thunderSeat.Answer,
+		MediaURL:         s.mediaResolver.SubmissionMediaURL(ctx, thunderSeat),
+		MediaType:        thunderSeat.MediaType,
+		ModerationStatus: thunderSeat.ModerationStatus,
+		CreatedOn:        thunderSeat.CreatedOn.Format(time.RFC3339),
+	}
 }
 
 func (s *thunderSeatService) GetUserSubmissions(ctx context.Context, userID string) ([]dtos.ThunderSeatResponse, error) {
@@ -193,37 +292,37 @@ func (s *thunderSeatService) GetUserSubmissions(ctx context.Context, userID stri
 		return nil, errors.NewInternalServerError("Failed to get user submissions", err)
 	}
 
+	users := make([]*entities.User, len(submissions))
+	for i := range submissions {
+		users[i] = &submissions[i].User
+	}
+	avatarImages := s.mediaResolver.UserImages(ctx, users)
+
 	responses := make([]dtos.ThunderSeatResponse, len(submissions))
 	for i, sub := range submissions {
-		var avatarURL *string
-		var avatarName *string
-		if sub.User.Avatar != nil {
-			// Reconstruct full path: avatars/{createdBy}/{imageKey}
-			fullPath := fmt.Sprintf("avatars/%s/%s", sub.User.Avatar.CreatedBy, sub.User.Avatar.ImageKey)
-			url := s.gcsService.GetPublicURL(fullPath)
-			avatarURL = &url
-			avatarName = &sub.User.Avatar.Name
-		}
+		avatarURL, avatarName, avatarVariants := avatarSummary(&sub.User, avatarImages)
 
 		responses[i] = dtos.ThunderSeatResponse{
-			ID:         sub.ID,
-			UserID:     sub.UserID,
-			WeekNumber: sub.WeekNumber,
-			Answer:     sub.Answer,
-			MediaURL:   sub.MediaURL,
-			MediaType:  sub.MediaType,
-			CreatedOn:  sub.CreatedOn.Format(time.RFC3339),
-			Name:       sub.User.Name,
-			Email:      sub.User.Email,
-			AvatarURL:  avatarURL,
-			AvatarName: avatarName,
+			ID:               sub.ID,
+			UserID:           sub.UserID,
+			WeekNumber:       sub.WeekNumber,
+			Answer:           sub.Answer,
+			MediaURL:         s.mediaResolver.SubmissionMediaURL(ctx, &sub),
+			MediaType:        sub.MediaType,
+			ModerationStatus: sub.ModerationStatus,
+			CreatedOn:        sub.CreatedOn.Format(time.RFC3339),
+			Name:             sub.User.Name,
+			Email:            sub.User.Email,
+			AvatarURL:        avatarURL,
+			AvatarName:       avatarName,
+			AvatarVariants:   avatarVariants,
 		}
 	}
 
 	return responses, nil
 }
 
-func (s *thunderSeatService) GetCurrentWeek(ctx context.Context) (*dtos.CurrentWeekResponse, error) {
+func (s *thunderSeatService) GetCurrentWeek(ctx context.Context, languageID int) (*dtos.CurrentWeekResponse, error) {
 	activeWeek, err := s.contestWeekRepo.FindActiveWeek(ctx, s.txnManager.GetDB())
 	if err != nil {
 		return nil, errors.NewInternalServerError("Failed to get active contest week", err)
@@ -232,11 +331,426 @@ func (s *thunderSeatService) GetCurrentWeek(ctx context.Context) (*dtos.CurrentW
 		return nil, errors.NewNotFoundError("No active contest week found", nil)
 	}
 
+	rule, err := s.getWeekRule(ctx, activeWeek.WeekNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := dtos.SubmissionRulesResponse{
+		MediaRequired:         rule.MediaRequired,
+		AllowedMediaKinds:     rule.MediaKinds(),
+		MaxAnswerLength:       rule.MaxAnswerLength,
+		MaxSubmissionsPerUser: rule.MaxSubmissionsPerUser,
+		SharingFieldsRequired: rule.SharingFieldsRequired,
+	}
+	if prompt := selectPrompt(rule.Prompts, languageID); prompt != nil {
+		rules.Prompt = &prompt.PromptText
+		rules.PromptLanguageID = &prompt.LanguageID
+	}
+
 	return &dtos.CurrentWeekResponse{
 		WeekNumber:  activeWeek.WeekNumber,
 		StartDate:   activeWeek.StartDate.Format("2006-01-02"),
 		EndDate:     activeWeek.EndDate.Format("2006-01-02"),
 		WinnerCount: activeWeek.WinnerCount,
 		IsActive:    activeWeek.IsActive,
+		Rules:       rules,
 	}, nil
 }
+
+func (s *thunderSeatService) UpdateSubmission(ctx context.Context, submissionID int, userID string, req dtos.ThunderSeatUpdateRequest, mediaFile *utils.ValidatedFile) (*dtos.ThunderSeatResponse, error) {
+	thunderSeat, err := s.getEditableSubmission(ctx, submissionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.getWeekRule(ctx, thunderSeat.WeekNumber)
+	if err != nil {
+		return nil, err
+	}
+	mediaType := ""
+	if mediaFile != nil {
+		mediaType = mediaFile.Kind
+	}
+	if err := validateSubmissionEdit(rule, req.Answer, mediaType); err != nil {
+		return nil, err
+	}
+
+	// Upload the replacement first so the submission never points at missing media
+	var media *submissionMedia
+	if mediaFile != nil {
+		probedMedia := probeMediaFile(mediaFile)
+
+		folderPath := fmt.Sprintf("thunder-seat/%s/week-%d", userID, thunderSeat.WeekNumber)
+		mediaURL, mediaKey, err := utils.UploadValidatedFile(ctx, s.gcsService, mediaFile, folderPath)
+		if err != nil {
+			log.WithError(err).Error("Failed to upload replacement media file to GCS")
+			return nil, errors.NewInternalServerError("Failed to upload media file", err)
+		}
+
+		media = &submissionMedia{
+			URL:             mediaURL,
+			Key:             mediaKey,
+			Path:            fmt.Sprintf("%s/%s", folderPath, mediaKey),
+			Type:            mediaType,
+			ContentType:     mediaFile.ContentType,
+			Size:            mediaFile.Size,
+			Probe:           probedMedia,
+			DeleteOnFailure: true,
+		}
+	}
+
+	return s.applySubmissionUpdate(ctx, thunderSeat, userID, req.Answer, media, nil)
+}
+
+// applySubmissionUpdate records a revision and applies the new answer and/or media.
+// onUpdated, when set, runs inside the same transaction as the update.
+func (s *thunderSeatService) applySubmissionUpdate(
+	ctx context.Context,
+	thunderSeat *entities.ThunderSeat,
+	userID string,
+	answer *string,
+	media *submissionMedia,
+	onUpdated func(tx *gorm.DB) error,
+) (*dtos.ThunderSeatResponse, error) {
+	// The revision keeps the previous media_url/media_key, so the replaced object is left
+	// in storage as part of the audit trail; storage-gc decides when it can go
+	revision := newThunderSeatRevision(thunderSeat, entities.ThunderSeatRevisionActionEdited, userID)
+
+	if media != nil {
+		thunderSeat.MediaHash = nil
+		media.applyTo(thunderSeat)
+	}
+	if answer != nil {
+		thunderSeat.Answer = *answer
+	}
+	now := time.Now()
+	thunderSeat.UpdatedOn = &now
+	// Edited content has to be reviewed again before it can be drawn
+	thunderSeat.ModerationStatus = entities.ModerationStatusPending
+
+	var asset *entities.MediaAsset
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.revisionRepo.Create(ctx, tx, revision); err != nil {
+			return err
+		}
+		var err error
+		asset, err = s.createMediaAsset(ctx, tx, media, userID)
+		if err != nil {
+			return err
+		}
+		if asset != nil {
+			thunderSeat.MediaAssetID = &asset.ID
+		}
+		if err := tx.WithContext(ctx).Model(thunderSeat).
+			Select("answer", "media_url", "media_key", "media_type", "media_hash", "media_asset_id", "moderation_status", "updated_on").
+			Updates(thunderSeat).Error; err != nil {
+			return err
+		}
+		if onUpdated != nil {
+			return onUpdated(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).WithField("submission_id", thunderSeat.ID).Error("Failed to update thunder seat submission")
+		s.cleanupMedia(ctx, media)
+		if stderrors.Is(err, errUploadSessionNotPending) {
+			return nil, errors.NewConflictError("Upload session has already been finalized", err)
+		}
+		return nil, errors.NewInternalServerError("Failed to update submission. Please try again later.", err)
+	}
+
+	s.enqueueMediaAsset(asset)
+	s.screeningService.EnqueueSubmission(screening.Submission{
+		ID:    thunderSeat.ID,
+		Text:  thunderSeat.Answer,
+		Media: media.probe(),
+	})
+
+	return s.toThunderSeatResponse(ctx, thunderSeat), nil
+}
+
+func (s *thunderSeatService) WithdrawSubmission(ctx context.Context, submissionID int, userID string) error {
+	thunderSeat, err := s.getEditableSubmission(ctx, submissionID, userID)
+	if err != nil {
+		return err
+	}
+
+	revision := newThunderSeatRevision(thunderSeat, entities.ThunderSeatRevisionActionWithdrawn, userID)
+	now := time.Now()
+
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.revisionRepo.Create(ctx, tx, revision); err != nil {
+			return err
+		}
+		if err := s.thunderSeatRepo.UpdateFields(ctx, tx, thunderSeat.ID, map[string]interface{}{
+			"withdrawn_on": now,
+			"updated_on":   now,
+		}); err != nil {
+			return err
+		}
+		return forfeitThunderSeatEntryPoints(ctx, tx, s.pointsService, s.thunderSeatRepo, userID, thunderSeat.WeekNumber)
+	})
+	if err != nil {
+		log.WithError(err).WithField("submission_id", submissionID).Error("Failed to withdraw thunder seat submission")
+		return errors.NewInternalServerError("Failed to withdraw submission. Please try again later.", err)
+	}
+
+	return nil
+}
+
+// getEditableSubmission loads a submission owned by the user whose contest week is still open
+func (s *thunderSeatService) getEditableSubmission(ctx context.Context, submissionID int, userID string) (*entities.ThunderSeat, error) {
+	thunderSeat, err := s.thunderSeatRepo.FindByID(ctx, s.txnManager.GetDB(), submissionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get submission", err)
+	}
+	if thunderSeat == nil || thunderSeat.UserID != userID || thunderSeat.WithdrawnOn != nil {
+		return nil, errors.NewNotFoundError("Submission not found", nil)
+	}
+
+	week, err := s.contestWeekRepo.FindByWeekNumber(ctx, s.txnManager.GetDB(), thunderSeat.WeekNumber)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get contest week", err)
+	}
+	if week == nil || !isWeekOpen(week, time.Now()) {
+		return nil, errors.NewForbiddenError("Submissions can only be changed while the contest week is open", nil)
+	}
+
+	return thunderSeat, nil
+}
+
+// getOpenWeek returns the active contest week, failing when submissions are not currently accepted
+func (s *thunderSeatService) getOpenWeek(ctx context.Context) (*entities.ContestWeek, error) {
+	activeWeek, err := s.contestWeekRepo.FindActiveWeek(ctx, s.txnManager.GetDB())
+	if err != nil {
+		log.WithError(err).Error("Failed to get active contest week from database")
+		return nil, errors.NewInternalServerError("Failed to get active contest week", err)
+	}
+	if activeWeek == nil {
+		log.Warn("No active contest week found when attempting to submit answer")
+		return nil, errors.NewBadRequestError("No active contest week found. Please check if a contest week is currently active.", nil)
+	}
+
+	now := time.Now()
+	// Include the full end date by checking if now is after end of day (23:59:59.999)
+	endOfDay := time.Date(activeWeek.EndDate.Year(), activeWeek.EndDate.Month(), activeWeek.EndDate.Day(), 23, 59, 59, 999999999, activeWeek.EndDate.Location())
+
+	if now.Before(activeWeek.StartDate) {
+		log.WithFields(log.Fields{
+			"now":         now,
+			"start_date":  activeWeek.StartDate,
+			"end_date":    activeWeek.EndDate,
+			"week_number": activeWeek.WeekNumber,
+		}).Warn("Submission attempted before contest week start date")
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Submissions are not allowed before the contest week starts. Contest week %d starts on %s", activeWeek.WeekNumber, activeWeek.StartDate.Format("2006-01-02 15:04:05")), nil)
+	}
+
+	if now.After(endOfDay) {
+		log.WithFields(log.Fields{
+			"now":         now,
+			"start_date":  activeWeek.StartDate,
+			"end_date":    activeWeek.EndDate,
+			"end_of_day":  endOfDay,
+			"week_number": activeWeek.WeekNumber,
+		}).Warn("Submission attempted after contest week end date")
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Submissions are not allowed after the contest week ends. Contest week %d ended on %s", activeWeek.WeekNumber, activeWeek.EndDate.Format("2006-01-02")), nil)
+	}
+
+	return activeWeek, nil
+}
+
+// checkSubmissionLimit fails with a conflict when the user has used up the week's submissions
+func (s *thunderSeatService) checkSubmissionLimit(ctx context.Context, userID string, rule *entities.ContestWeekRule) error {
+	submissionCount, err := s.thunderSeatRepo.CountByUserAndWeek(ctx, s.txnManager.GetDB(), userID, rule.WeekNumber)
+	if err != nil {
+		log.WithError(err).WithField("user_id", userID).Error("Failed to count user submissions for week")
+		return errors.NewInternalServerError("Failed to submit answer. Please try again later.", err)
+	}
+	if rule.MaxSubmissionsPerUser > 0 && submissionCount >= int64(rule.MaxSubmissionsPerUser) {
+		if rule.MaxSubmissionsPerUser == 1 {
+			return errors.NewConflictError("You have already submitted an answer for this contest week", nil)
+		}
+		return errors.NewConflictError(fmt.Sprintf("You can submit at most %d answers for this contest week", rule.MaxSubmissionsPerUser), nil)
+	}
+	return nil
+}
+
+// submissionMedia is media already in storage that is ready to be attached to a submission
+type submissionMedia struct {
+	URL         string
+	Key         string
+	Path        string // full object path in storage
+	Type        string
+	ContentType string // content type declared by the client
+	Size        int64
+	Probe       *screening.Media
+	// DeleteOnFailure removes the object when saving the submission fails. Direct
+	// uploads keep their object so the client can retry finalizing.
+	DeleteOnFailure bool
+}
+
+func (m *submissionMedia) applyTo(thunderSeat *entities.ThunderSeat) {
+	if m == nil {
+		return
+	}
+	thunderSeat.MediaURL = &m.URL
+	thunderSeat.MediaKey = &m.Key
+	thunderSeat.MediaType = &m.Type
+	if m.Probe != nil && m.Probe.SHA256 != "" {
+		thunderSeat.MediaHash = &m.Probe.SHA256
+	}
+}
+
+// probe returns what screening knows about the media. When probing failed it still
+// reports the declared kind and size, so screening can tell the media was not hashed.
+func (m *submissionMedia) probe() *screening.Media {
+	if m == nil {
+		return nil
+	}
+	if m.Probe == nil {
+		return &screening.Media{Kind: m.Type, ContentType: m.ContentType, Size: m.Size}
+	}
+	return m.Probe
+}
+
+// createMediaAsset records newly attached media for the processing pipeline
+func (s *thunderSeatService) createMediaAsset(ctx context.Context, tx *gorm.DB, media *submissionMedia, userID string) (*entities.MediaAsset, error) {
+	if media == nil {
+		return nil, nil
+	}
+	asset := newMediaAsset(media.Path, media.Type, media.ContentType, media.Size, userID)
+	if err := s.mediaAssetRepo.Create(ctx, tx, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (s *thunderSeatService) enqueueMediaAsset(asset *entities.MediaAsset) {
+	if asset != nil {
+		s.mediaPipeline.EnqueueAsset(asset.ID)
+	}
+}
+
+func (s *thunderSeatService) cleanupMedia(ctx context.Context, media *submissionMedia) {
+	if media == nil || !media.DeleteOnFailure {
+		return
+	}
+	if deleteErr := s.gcsService.DeleteFile(ctx, media.URL); deleteErr != nil {
+		log.WithError(deleteErr).WithField("media_url", media.URL).Error("Failed to cleanup uploaded file after database error")
+	}
+}
+
+// probeMediaFile captures the header, hash and duration of an upload for screening.
+// Probing is best effort; a failure only means screening runs without media details.
+func probeMediaFile(file *utils.ValidatedFile) *screening.Media {
+	src, err := file.Open()
+	if err != nil {
+		log.WithError(err).Warn("Failed to open media file for probing")
+		return nil
+	}
+	defer src.Close()
+
+	media, err := screening.ProbeMedia(src, file.Size, file.Kind, file.ContentType)
+	if err != nil {
+		log.WithError(err).Warn("Failed to probe media file")
+		return nil
+	}
+	return media
+}
+
+// isWeekOpen reports whether now falls between the week's start and the end of its last day
+func isWeekOpen(week *entities.ContestWeek, now time.Time) bool {
+	endOfDay := time.Date(week.EndDate.Year(), week.EndDate.Month(), week.EndDate.Day(), 23, 59, 59, 999999999, week.EndDate.Location())
+	return !now.Before(week.StartDate) && !now.After(endOfDay)
+}
+
+func newThunderSeatRevision(thunderSeat *entities.ThunderSeat, action string, userID string) *entities.ThunderSeatRevision {
+	return &entities.ThunderSeatRevision{
+		ThunderSeatID: thunderSeat.ID,
+		Action:        action,
+		Answer:        thunderSeat.Answer,
+		MediaURL:      thunderSeat.MediaURL,
+		MediaKey:      thunderSeat.MediaKey,
+		MediaType:     thunderSeat.MediaType,
+		CreatedBy:     userID,
+		CreatedOn:     time.Now(),
+	}
+}
+
+// getWeekRule returns the submission rules for a week, falling back to the
+// defaults when no rules have been configured
+func (s *thunderSeatService) getWeekRule(ctx context.Context, weekNumber int) (*entities.ContestWeekRule, error) {
+	rule, err := s.ruleRepo.FindByWeekNumber(ctx, s.txnManager.GetDB(), weekNumber)
+	if err != nil {
+		log.WithError(err).WithField("week_number", weekNumber).Error("Failed to get contest week rules")
+		return nil, errors.NewInternalServerError("Failed to get contest week rules", err)
+	}
+	if rule == nil {
+		return &entities.ContestWeekRule{
+			WeekNumber:            weekNumber,
+			MaxSubmissionsPerUser: constants.DEFAULT_MAX_SUBMISSIONS_PER_USER,
+		}, nil
+	}
+	return rule, nil
+}
+
+func validateSubmissionRules(rule *entities.ContestWeekRule, req dtos.ThunderSeatSubmitRequest, mediaType string) error {
+	if rule.MaxAnswerLength > 0 && utf8.RuneCountInString(req.Answer) > rule.MaxAnswerLength {
+		return errors.NewBadRequestError(fmt.Sprintf("Answer must not exceed %d characters", rule.MaxAnswerLength), nil)
+	}
+
+	if mediaType == "" && rule.MediaRequired {
+		return errors.NewBadRequestError("A media file is required for this week's submission", nil)
+	}
+	if mediaType != "" && !rule.AllowsMediaKind(mediaType) {
+		return mediaKindNotAllowedError(rule, mediaType)
+	}
+
+	if rule.SharingFieldsRequired && (req.SharingPlatform == nil || req.PlatformUserName == nil) {
+		return errors.NewBadRequestError("Sharing platform and platform user name are required for this week's submission", nil)
+	}
+
+	return nil
+}
+
+// validateSubmissionEdit checks an edit against the week's rules; an empty
+// mediaType means the media is not being replaced
+func validateSubmissionEdit(rule *entities.ContestWeekRule, answer *string, mediaType string) error {
+	if answer != nil && rule.MaxAnswerLength > 0 && utf8.RuneCountInString(*answer) > rule.MaxAnswerLength {
+		return errors.NewBadRequestError(fmt.Sprintf("Answer must not exceed %d characters", rule.MaxAnswerLength), nil)
+	}
+	if mediaType != "" && !rule.AllowsMediaKind(mediaType) {
+		return mediaKindNotAllowedError(rule, mediaType)
+	}
+	if answer == nil && mediaType == "" {
+		return errors.NewBadRequestError("Nothing to update. Provide a new description or media file", nil)
+	}
+	return nil
+}
+
+func mediaKindNotAllowedError(rule *entities.ContestWeekRule, mediaType string) error {
+	return errors.NewBadRequestError(fmt.Sprintf("Media type %s is not allowed this week. Allowed types: %s", mediaType, strings.Join(rule.MediaKinds(), ", ")), nil)
+}
+
+// selectPrompt picks the prompt for the requested language, falling back to
+// the default language and then to any configured prompt
+func selectPrompt(prompts []entities.ContestWeekPrompt, languageID int) *entities.ContestWeekPrompt {
+	if len(prompts) == 0 {
+		return nil
+	}
+	var fallback *entities.ContestWeekPrompt
+	for i := range prompts {
+		if prompts[i].LanguageID == languageID {
+			return &prompts[i]
+		}
+		if prompts[i].LanguageID == constants.DEFAULT_LANGUAGE_ID {
+			fallback = &prompts[i]
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return &prompts[0]
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/constants"
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/media"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/screening"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// errUploadSessionNotPending aborts a finalize transaction when another request
+// already finalized or failed the same upload session
+var errUploadSessionNotPending = stderrors.New("upload session is no longer pending")
+
+// CreateUploadSession issues a signed URL the client uploads media to directly.
+// With SubmissionID set the upload replaces the media of that submission,
+// otherwise finalizing it creates a new submission for the active week.
+func (s *thunderSeatService) CreateUploadSession(ctx context.Context, userID string, req dtos.UploadSessionRequest) (*dtos.UploadSessionResponse, error) {
+	contentType := normalizeContentType(req.ContentType)
+	mediaType := utils.GetMediaTypeFromContentType(contentType)
+	if mediaType == "unknown" {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Unsupported content type %s. Only audio, video and image files are allowed", req.ContentType), nil)
+	}
+	if maxSize := s.filePolicy.MaxSize(mediaType); req.Size > maxSize {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("%s file size exceeds maximum allowed size of %dMB", mediaType, maxSize/(1024*1024)), nil)
+	}
+	if req.MD5 != nil {
+		if checksum, err := base64.StdEncoding.DecodeString(*req.MD5); err != nil || len(checksum) != 16 {
+			return nil, errors.NewBadRequestError("md5 must be the base64 encoded MD5 digest of the file", err)
+		}
+	}
+
+	var weekNumber int
+	var submissionID *int
+	if req.SubmissionID != nil {
+		thunderSeat, err := s.getEditableSubmission(ctx, *req.SubmissionID, userID)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := s.getWeekRule(ctx, thunderSeat.WeekNumber)
+		if err != nil {
+			return nil, err
+		}
+		if !rule.AllowsMediaKind(mediaType) {
+			return nil, mediaKindNotAllowedError(rule, mediaType)
+		}
+		weekNumber = thunderSeat.WeekNumber
+		submissionID = &thunderSeat.ID
+	} else {
+		activeWeek, err := s.getOpenWeek(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := s.getWeekRule(ctx, activeWeek.WeekNumber)
+		if err != nil {
+			return nil, err
+		}
+		if !rule.AllowsMediaKind(mediaType) {
+			return nil, mediaKindNotAllowedError(rule, mediaType)
+		}
+		if err := s.checkSubmissionLimit(ctx, userID, rule); err != nil {
+			return nil, err
+		}
+		weekNumber = activeWeek.WeekNumber
+	}
+
+	mediaKey := uuid.New().String() + utils.ExtensionForContentType(contentType)
+	objectPath := entities.ThunderSeatMediaPath(userID, weekNumber, mediaKey)
+
+	// Storage refuses anything above the policy limit, so an abandoned oversized
+	// upload never lands in the bucket
+	maxSize := s.filePolicy.MaxUploadSize(objectPath, contentType)
+	upload, err := s.gcsService.GetSignedUploadURL(ctx, objectPath, contentType, maxSize, constants.UPLOAD_SESSION_EXPIRY)
+	if err != nil {
+		log.WithError(err).WithField("user_id", userID).Error("Failed to create signed upload URL")
+		return nil, errors.NewInternalServerError("Failed to start upload. Please try again later.", err)
+	}
+
+	session := &entities.UploadSession{
+		UserID:        userID,
+		WeekNumber:    weekNumber,
+		ThunderSeatID: submissionID,
+		ObjectPath:    objectPath,
+		MediaKey:      mediaKey,
+		MediaType:     mediaType,
+		ContentType:   contentType,
+		ExpectedSize:  req.Size,
+		ExpectedMD5:   req.MD5,
+		Status:        entities.UploadSessionStatusPending,
+		ExpiresOn:     upload.ExpiresAt,
+	}
+	err = s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		return s.uploadSessionRepo.Create(ctx, tx, session)
+	})
+	if err != nil {
+		log.WithError(err).WithField("user_id", userID).Error("Failed to create upload session")
+		return nil, errors.NewInternalServerError("Failed to start upload. Please try again later.", err)
+	}
+
+	return &dtos.UploadSessionResponse{
+		SessionID: session.ID,
+		UploadURL: upload.URL,
+		Method:    upload.Method,
+		Headers:   upload.Headers,
+		Resumable: upload.Resumable,
+		ExpiresAt: upload.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// FinalizeUploadSession verifies the uploaded object against what was declared
+// when the session was created and attaches it to the submission. Sessions can be
+// finalized after the signed URL expired as long as the contest week is still open,
+// so slow resumable uploads that started in time are not lost.
+func (s *thunderSeatService) FinalizeUploadSession(ctx context.Context, sessionID string, userID string, req dtos.FinalizeUploadRequest, idempotencyKey string) (*dtos.ThunderSeatResponse, error) {
+	session, err := s.uploadSessionRepo.FindByID(ctx, s.txnManager.GetDB(), sessionID)
+	if err != nil {
+		log.WithError(err).WithField("session_id", sessionID).Error("Failed to get upload session")
+		return nil, errors.NewInternalServerError("Failed to finalize upload", err)
+	}
+	if session == nil || session.UserID != userID {
+		return nil, errors.NewNotFoundError("Upload session not found", nil)
+	}
+
+	switch session.Status {
+	case entities.UploadSessionStatusFinalized:
+		// A retried finalize returns the submission the upload was attached to
+		if session.ThunderSeatID != nil {
+			thunderSeat, err := s.thunderSeatRepo.FindByID(ctx, s.txnManager.GetDB(), *session.ThunderSeatID)
+			if err != nil {
+				return nil, errors.NewInternalServerError("Failed to get submission", err)
+			}
+			if thunderSeat != nil && thunderSeat.WithdrawnOn == nil {
+				return s.toThunderSeatResponse(ctx, thunderSeat), nil
+			}
+		}
+		return nil, errors.NewConflictError("Upload session has already been finalized", nil)
+	case entities.UploadSessionStatusFailed:
+		reason := "the uploaded file was rejected"
+		if session.FailureReason != nil {
+			reason = *session.FailureReason
+		}
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Upload verification failed: %s. Start a new upload", reason), nil)
+	}
+
+	media, err := s.verifyUploadedObject(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	finalize := func(tx *gorm.DB, thunderSeatID int) error {
+		claimed, err := s.uploadSessionRepo.ClaimPending(ctx, tx, session.ID, entities.UploadSessionStatusFinalized, map[string]interface{}{
+			"thunder_seat_id": thunderSeatID,
+			"finalized_on":    time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return errUploadSessionNotPending
+		}
+		return nil
+	}
+
+	if session.ThunderSeatID != nil {
+		thunderSeat, err := s.getEditableSubmission(ctx, *session.ThunderSeatID, userID)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := s.getWeekRule(ctx, thunderSeat.WeekNumber)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateSubmissionEdit(rule, req.Answer, media.Type); err != nil {
+			return nil, err
+		}
+		return s.applySubmissionUpdate(ctx, thunderSeat, userID, req.Answer, media, func(tx *gorm.DB) error {
+			return finalize(tx, thunderSeat.ID)
+		})
+	}
+
+	activeWeek, err := s.getOpenWeek(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if activeWeek.WeekNumber != session.WeekNumber {
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Contest week %d has ended. Start a new upload for the current week", session.WeekNumber), nil)
+	}
+	rule, err := s.getWeekRule(ctx, activeWeek.WeekNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	submitReq := dtos.ThunderSeatSubmitRequest{
+		SharingPlatform:  req.SharingPlatform,
+		PlatformUserName: req.PlatformUserName,
+	}
+	if req.Answer != nil {
+		submitReq.Answer = *req.Answer
+	}
+	if err := validateSubmissionRules(rule, submitReq, media.Type); err != nil {
+		return nil, err
+	}
+	if err := s.checkSubmissionLimit(ctx, userID, rule); err != nil {
+		return nil, err
+	}
+
+	return s.createSubmission(ctx, userID, session.WeekNumber, submitReq, media, idempotencyKey, func(tx *gorm.DB, thunderSeat *entities.ThunderSeat) error {
+		return finalize(tx, thunderSeat.ID)
+	})
+}
+
+// verifyUploadedObject checks size, content type, checksum and magic bytes of the
+// uploaded object. A mismatch fails the session and deletes the object.
+func (s *thunderSeatService) verifyUploadedObject(ctx context.Context, session *entities.UploadSession) (*submissionMedia, error) {
+	info, err := s.gcsService.GetObjectInfo(ctx, session.ObjectPath)
+	if err != nil {
+		if stderrors.Is(err, utils.ErrObjectNotFound) {
+			return nil, errors.NewBadRequestError("The file has not been uploaded yet. Upload the file before finalizing", nil)
+		}
+		log.WithError(err).WithField("session_id", session.ID).Error("Failed to get uploaded object info")
+		return nil, errors.NewInternalServerError("Failed to finalize upload", err)
+	}
+
+	header, err := s.gcsService.ReadObjectRange(ctx, session.ObjectPath, 0, screening.HeaderSize)
+	if err != nil {
+		log.WithError(err).WithField("session_id", session.ID).Error("Failed to read uploaded object header")
+		return nil, errors.NewInternalServerError("Failed to finalize upload", err)
+	}
+
+	if reason := checkUploadedObject(session, info, header); reason != "" {
+		s.failUploadSession(ctx, session, reason)
+		return nil, errors.NewBadRequestError(fmt.Sprintf("Upload verification failed: %s. Start a new upload", reason), nil)
+	}
+
+	size, err := s.inspectUploadedContent(ctx, session, info.Size, header)
+	if err != nil {
+		var validationErr *utils.FileValidationError
+		if stderrors.As(err, &validationErr) {
+			s.failUploadSession(ctx, session, validationErr.Message)
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Upload verification failed: %s. Start a new upload", validationErr.Message), validationErr)
+		}
+		log.WithError(err).WithField("session_id", session.ID).Error("Failed to inspect uploaded object")
+		return nil, errors.NewInternalServerError("Failed to finalize upload", err)
+	}
+
+	// Screening needs the hash for its duplicate check; without one the submission
+	// stays pending for manual moderation
+	sha256Hex, err := s.hashStoredObject(ctx, session.ObjectPath)
+	if err != nil {
+		log.WithError(err).WithField("session_id", session.ID).Warn("Failed to hash uploaded object")
+	}
+
+	// Duration is not probed from storage, so the media pipeline fills it in later
+	return &submissionMedia{
+		URL:         s.gcsService.GetPublicURL(session.ObjectPath),
+		Key:         session.MediaKey,
+		Path:        session.ObjectPath,
+		Type:        session.MediaType,
+		ContentType: session.ContentType,
+		Size:        size,
+		Probe: &screening.Media{
+			Kind:        session.MediaType,
+			ContentType: session.ContentType,
+			Size:        size,
+			Header:      header,
+			SHA256:      sha256Hex,
+		},
+	}, nil
+}
+
+// hashStoredObject streams a stored object through SHA-256 and returns the hex digest
+func (s *thunderSeatService) hashStoredObject(ctx context.Context, objectPath string) (string, error) {
+	reader, err := s.gcsService.NewObjectReader(ctx, objectPath)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", fmt.Errorf("failed to hash object: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// inspectUploadedContent applies the upload policy's content checks to a stored
+// object. Images are read whole and rewritten without GPS metadata when they carry
+// any; audio and video are checked from their header and tail. It returns the
+// final object size.
+func (s *thunderSeatService) inspectUploadedContent(ctx context.Context, session *entities.UploadSession, size int64, header []byte) (int64, error) {
+	if session.MediaType != "image" {
+		var tail []byte
+		if offset := size - utils.FileTailSize; offset > int64(len(header)) {
+			var err error
+			if tail, err = s.gcsService.ReadObjectRange(ctx, session.ObjectPath, offset, -1); err != nil {
+				return 0, err
+			}
+		}
+		if validationErr := s.filePolicy.CheckContent(session.MediaType, session.ContentType, header, tail); validationErr != nil {
+			return 0, validationErr
+		}
+		return size, nil
+	}
+
+	data, err := s.gcsService.ReadObjectRange(ctx, session.ObjectPath, 0, -1)
+	if err != nil {
+		return 0, err
+	}
+	if validationErr := s.filePolicy.CheckContent(session.MediaType, session.ContentType, data, media.Trailer(data, session.ContentType)); validationErr != nil {
+		return 0, validationErr
+	}
+	if !s.filePolicy.StripImageGPS {
+		return size, nil
+	}
+
+	cleaned, stripped := media.StripGPS(data, session.ContentType)
+	if !stripped {
+		return size, nil
+	}
+	if _, _, err := s.gcsService.UploadFileFromBytes(ctx, cleaned, session.ObjectPath, session.ContentType); err != nil {
+		return 0, fmt.Errorf("failed to store image without location metadata: %w", err)
+	}
+	return int64(len(cleaned)), nil
+}
+
+// checkUploadedObject returns why the stored object does not match the session, or "" when it does
+func checkUploadedObject(session *entities.UploadSession, info *utils.ObjectInfo, header []byte) string {
+	if info.Size != session.ExpectedSize {
+		return fmt.Sprintf("file is %d bytes but %d bytes were declared", info.Size, session.ExpectedSize)
+	}
+	if storedType := normalizeContentType(info.ContentType); storedType != session.ContentType {
+		return fmt.Sprintf("file was stored as %s but %s was declared", storedType, session.ContentType)
+	}
+	if session.ExpectedMD5 != nil && len(info.MD5) > 0 && base64.StdEncoding.EncodeToString(info.MD5) != *session.ExpectedMD5 {
+		return "file checksum does not match the declared md5"
+	}
+	if detected, ok := screening.HeaderMatchesKind(header, session.MediaType); !ok {
+		return fmt.Sprintf("file content is %s, not %s", detected, session.MediaType)
+	}
+	return ""
+}
+
+func (s *thunderSeatService) failUploadSession(ctx context.Context, session *entities.UploadSession, reason string) {
+	logger := log.WithFields(log.Fields{
+		"session_id": session.ID,
+		"reason":     reason,
+	})
+	logger.Warn("Uploaded object failed verification")
+
+	if _, err := s.uploadSessionRepo.ClaimPending(ctx, s.txnManager.GetDB(), session.ID, entities.UploadSessionStatusFailed, map[string]interface{}{
+		"failure_reason": reason,
+	}); err != nil {
+		logger.WithError(err).Error("Failed to mark upload session as failed")
+	}
+	if err := s.gcsService.DeleteFile(ctx, s.gcsService.GetPublicURL(session.ObjectPath)); err != nil {
+		logger.WithError(err).Error("Failed to delete rejected upload")
+	}
+}
+
+// normalizeContentType lowercases a content type and drops any parameters
+func normalizeContentType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,693 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
+	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/i18n"
+	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// maxTranslationLength matches the max binding on dtos.SetTranslationRequestDTO
+const maxTranslationLength = 5000
+
+// TranslationService lets content editors maintain the translations of questions
+// and their options. A question's text in its own language is changed through
+// question versions, so translations are only kept for other languages. An
+// option is written in the language of its question.
+type TranslationService interface {
+	GetQuestionTranslations(ctx context.Context, questionID int) (*dtos.QuestionTranslationsDTO, error)
+	SetQuestionTranslation(ctx context.Context, questionID, languageID int, req dtos.SetTranslationRequestDTO, savedBy string) (*dtos.TranslationDTO, error)
+	DeleteQuestionTranslation(ctx context.Context, questionID, languageID int, deletedBy string) error
+	SetOptionTranslation(ctx context.Context, optionID, languageID int, req dtos.SetTranslationRequestDTO, savedBy string) (*dtos.TranslationDTO, error)
+	DeleteOptionTranslation(ctx context.Context, optionID, languageID int, deletedBy string) error
+	// GetCoverage reports, per active language, the active questions and options
+	// not yet translated into it. A languageID of 0 reports every language.
+	GetCoverage(ctx context.Context, languageID int) ([]dtos.TranslationCoverageDTO, error)
+	// ExportTranslations returns a sheet row for every active question and option
+	// not written in the language, with its translation when it has one
+	ExportTranslations(ctx context.Context, languageID int) ([]i18n.Row, error)
+	// ImportTranslations saves the translations of a sheet. Rows with no text are
+	// skipped; when any other row is invalid nothing is saved.
+	ImportTranslations(ctx context.Context, rows []i18n.Row, importedBy string) (*dtos.TranslationImportResponse, error)
+}
+
+type translationService struct {
+	txnManager                 *utils.TransactionManager
+	questionRepo               repository.QuestionRepository
+	optionMasterRepo           repository.OptionMasterRepository
+	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository
+	optionMasterLanguageRepo   repository.OptionMasterLanguageRepository
+	localizationService        LocalizationService
+	questionCatalog            QuestionCatalog
+}
+
+func NewTranslationService(
+	txnManager *utils.TransactionManager,
+	questionRepo repository.QuestionRepository,
+	optionMasterRepo repository.OptionMasterRepository,
+	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository,
+	optionMasterLanguageRepo repository.OptionMasterLanguageRepository,
+	localizationService LocalizationService,
+	questionCatalog QuestionCatalog,
+) TranslationService {
+	return &translationService{
+		txnManager:                 txnManager,
+		questionRepo:               questionRepo,
+		optionMasterRepo:           optionMasterRepo,
+		questionMasterLanguageRepo: questionMasterLanguageRepo,
+		optionMasterLanguageRepo:   optionMasterLanguageRepo,
+		localizationService:        localizationService,
+		questionCatalog:            questionCatalog,
+	}
+}
+
+type translationChange int
+
+const (
+	translationUnchanged translationChange = iota
+	translationCreated
+	translationUpdated
+)
+
+func (s *translationService) GetQuestionTranslations(ctx context.Context, questionID int) (*dtos.QuestionTranslationsDTO, error) {
+	db := s.txnManager.GetDB()
+	question, err := s.questionRepo.FindByIDTx(ctx, db, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question", err)
+	}
+	if question == nil {
+		return nil, errors.NewNotFoundError("Question not found", nil)
+	}
+
+	questionLanguages, err := s.questionMasterLanguageRepo.FindByQuestionMasterID(ctx, db, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question translations", err)
+	}
+	options, err := s.optionMasterRepo.FindByQuestionID(ctx, db, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question options", err)
+	}
+	optionIDs := make([]int, len(options))
+	for i, option := range options {
+		optionIDs[i] = option.ID
+	}
+	optionLanguages, err := s.optionMasterLanguageRepo.FindByOptionMasterIDs(ctx, db, optionIDs)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get option translations", err)
+	}
+
+	response := &dtos.QuestionTranslationsDTO{
+		ID:           question.ID,
+		QuestionText: question.QuestionText,
+		LanguageID:   question.LanguageID,
+		Translations: make([]dtos.TranslationDTO, len(questionLanguages)),
+		Options:      make([]dtos.OptionTranslationsDTO, len(options)),
+	}
+	for i := range questionLanguages {
+		response.Translations[i] = toQuestionTranslationDTO(&questionLanguages[i])
+	}
+	sort.Slice(response.Translations, func(i, j int) bool {
+		return response.Translations[i].LanguageID < response.Translations[j].LanguageID
+	})
+
+	optionTranslations := make(map[int][]dtos.TranslationDTO, len(options))
+	for i := range optionLanguages {
+		optionLanguage := &optionLanguages[i]
+		optionTranslations[optionLanguage.OptionMasterID] = append(optionTranslations[optionLanguage.OptionMasterID], toOptionTranslationDTO(optionLanguage))
+	}
+	for i, option := range options {
+		translations := optionTranslations[option.ID]
+		if translations == nil {
+			translations = []dtos.TranslationDTO{}
+		}
+		response.Options[i] = dtos.OptionTranslationsDTO{
+			ID:           option.ID,
+			OptionText:   option.OptionText,
+			DisplayOrder: option.DisplayOrder,
+			IsActive:     option.IsActive,
+			Translations: translations,
+		}
+	}
+	return response, nil
+}
+
+func (s *translationService) SetQuestionTranslation(ctx context.Context, questionID, languageID int, req dtos.SetTranslationRequestDTO, savedBy string) (*dtos.TranslationDTO, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, errors.NewBadRequestError("Translation text must not be blank", nil)
+	}
+	if !s.localizationService.IsLanguage(ctx, languageID) {
+		return nil, errors.NewNotFoundError(errors.ErrLanguageNotFound, nil)
+	}
+
+	var saved *entities.QuestionMasterLanguage
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		question, err := s.translatableQuestion(ctx, tx, questionID, languageID)
+		if err != nil {
+			return err
+		}
+		existing, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDAndLanguageID(ctx, tx, question.ID, languageID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get question translation", err)
+		}
+		saved, _, err = s.saveQuestionTranslation(ctx, tx, existing, question.ID, languageID, text, savedBy, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+
+	response := toQuestionTranslationDTO(saved)
+	return &response, nil
+}
+
+func (s *translationService) DeleteQuestionTranslation(ctx context.Context, questionID, languageID int, deletedBy string) error {
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		translation, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDAndLanguageID(ctx, tx, questionID, languageID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get question translation", err)
+		}
+		if translation == nil {
+			return errors.NewNotFoundError("Translation not found", nil)
+		}
+
+		now := time.Now()
+		translation.IsDeleted = true
+		translation.LastModifiedBy = &deletedBy
+		translation.LastModifiedOn = &now
+		if err := s.questionMasterLanguageRepo.Update(ctx, tx, translation); err != nil {
+			return errors.NewInternalServerError("Failed to delete question translation", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.questionCatalog.Invalidate()
+	return nil
+}
+
+func (s *translationService) SetOptionTranslation(ctx context.Context, optionID, languageID int, req dtos.SetTranslationRequestDTO, savedBy string) (*dtos.TranslationDTO, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, errors.NewBadRequestError("Translation text must not be blank", nil)
+	}
+	if !s.localizationService.IsLanguage(ctx, languageID) {
+		return nil, errors.NewNotFoundError(errors.ErrLanguageNotFound, nil)
+	}
+
+	var saved *entities.OptionMasterLanguage
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		option, err := s.optionMasterRepo.FindByID(ctx, tx, optionID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get option", err)
+		}
+		if option == nil {
+			return errors.NewNotFoundError("Option not found", nil)
+		}
+		if _, err := s.translatableQuestion(ctx, tx, option.QuestionMasterID, languageID); err != nil {
+			return err
+		}
+		existing, err := s.optionMasterLanguageRepo.FindByOptionMasterIDAndLanguageID(ctx, tx, option.ID, languageID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get option translation", err)
+		}
+		saved, _, err = s.saveOptionTranslation(ctx, tx, existing, option.ID, languageID, text, savedBy, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.questionCatalog.Invalidate()
+
+	response := toOptionTranslationDTO(saved)
+	return &response, nil
+}
+
+func (s *translationService) DeleteOptionTranslation(ctx context.Context, optionID, languageID int, deletedBy string) error {
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		translation, err := s.optionMasterLanguageRepo.FindByOptionMasterIDAndLanguageID(ctx, tx, optionID, languageID)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get option translation", err)
+		}
+		if translation == nil {
+			return errors.NewNotFoundError("Translation not found", nil)
+		}
+
+		now := time.Now()
+		translation.IsDeleted = true
+		translation.LastModifiedBy = &deletedBy
+		translation.LastModifiedOn = &now
+		if err := s.optionMasterLanguageRepo.Update(ctx, tx, translation); err != nil {
+			return errors.NewInternalServerError("Failed to delete option translation", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.questionCatalog.Invalidate()
+	return nil
+}
+
+func (s *translationService) GetCoverage(ctx context.Context, languageID int) ([]dtos.TranslationCoverageDTO, error) {
+	languages := s.localizationService.GetLanguages(ctx)
+	if languageID > 0 {
+		var requested []dtos.LanguageDTO
+		for _, language := range languages {
+			if language.ID == languageID {
+				requested = append(requested, language)
+			}
+		}
+		if len(requested) == 0 {
+			return nil, errors.NewNotFoundError(errors.ErrLanguageNotFound, nil)
+		}
+		languages = requested
+	}
+
+	db := s.txnManager.GetDB()
+	questions, options, err := s.translatableContent(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := make([]dtos.TranslationCoverageDTO, 0, len(languages))
+	for _, language := range languages {
+		questionTexts, optionTexts, err := s.translations(ctx, db, questions, options, language.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		report := dtos.TranslationCoverageDTO{
+			LanguageID:   language.ID,
+			LanguageName: language.Name,
+			LanguageCode: language.Code,
+			Missing:      []dtos.MissingTranslationDTO{},
+		}
+		for _, question := range questions {
+			if question.LanguageID == language.ID {
+				continue
+			}
+			report.Questions.Total++
+			if _, ok := questionTexts[question.ID]; ok {
+				report.Questions.Translated++
+			} else {
+				report.Missing = append(report.Missing, dtos.MissingTranslationDTO{
+					QuestionID: question.ID,
+					SourceText: question.QuestionText,
+				})
+			}
+			for _, option := range options[question.ID] {
+				report.Options.Total++
+				if _, ok := optionTexts[option.ID]; ok {
+					report.Options.Translated++
+					continue
+				}
+				report.Missing = append(report.Missing, dtos.MissingTranslationDTO{
+					QuestionID: question.ID,
+					OptionID:   option.ID,
+					SourceText: option.OptionText,
+				})
+			}
+		}
+		report.Questions.Percent = translatedPercent(report.Questions)
+		report.Options.Percent = translatedPercent(report.Options)
+		coverage = append(coverage, report)
+	}
+	return coverage, nil
+}
+
+func (s *translationService) ExportTranslations(ctx context.Context, languageID int) ([]i18n.Row, error) {
+	if !s.localizationService.IsLanguage(ctx, languageID) {
+		return nil, errors.NewNotFoundError(errors.ErrLanguageNotFound, nil)
+	}
+
+	db := s.txnManager.GetDB()
+	questions, options, err := s.translatableContent(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	questionTexts, optionTexts, err := s.translations(ctx, db, questions, options, languageID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]i18n.Row, 0, len(questions))
+	for _, question := range questions {
+		if question.LanguageID == languageID {
+			continue
+		}
+		rows = append(rows, i18n.Row{
+			Type:       i18n.RowTypeQuestion,
+			QuestionID: question.ID,
+			LanguageID: languageID,
+			SourceText: question.QuestionText,
+			Text:       questionTexts[question.ID],
+		})
+		for _, option := range options[question.ID] {
+			rows = append(rows, i18n.Row{
+				Type:       i18n.RowTypeOption,
+				QuestionID: question.ID,
+				OptionID:   option.ID,
+				LanguageID: languageID,
+				SourceText: option.OptionText,
+				Text:       optionTexts[option.ID],
+			})
+		}
+	}
+	return rows, nil
+}
+
+func (s *translationService) ImportTranslations(ctx context.Context, rows []i18n.Row, importedBy string) (*dtos.TranslationImportResponse, error) {
+	if len(rows) == 0 {
+		return nil, errors.NewBadRequestError("Sheet has no rows", nil)
+	}
+
+	response := &dtos.TranslationImportResponse{}
+	err := s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		questions, err := s.questionRepo.FindTranslatable(ctx, tx)
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get questions", err)
+		}
+		questionsByID := make(map[int]*entities.QuestionMaster, len(questions))
+		for i := range questions {
+			questionsByID[questions[i].ID] = &questions[i]
+		}
+		options, err := s.optionMasterRepo.FindByQuestionIDs(ctx, tx, questionIDs(questions))
+		if err != nil {
+			return errors.NewInternalServerError("Failed to get question options", err)
+		}
+		optionsByID := make(map[int]*entities.OptionMaster, len(options))
+		for i := range options {
+			if !options[i].IsDeleted {
+				optionsByID[options[i].ID] = &options[i]
+			}
+		}
+
+		// Rows to save, keyed by what they translate so a sheet cannot set the
+		// same translation twice
+		type target struct {
+			rowType  string
+			id       int
+			language int
+		}
+		fieldErrors := utils.FieldErrors{}
+		seen := make(map[target]int, len(rows))
+		var pending []int
+		for i, row := range rows {
+			row.Text = strings.TrimSpace(row.Text)
+			rows[i].Text = row.Text
+			if row.Text == "" {
+				response.Skipped++
+				continue
+			}
+
+			field := func(name string) string {
+				return fmt.Sprintf("rows[%d].%s", i, name)
+			}
+			if utf8.RuneCountInString(row.Text) > maxTranslationLength {
+				fieldErrors.Add(field("text"), fmt.Sprintf("text must be at most %d characters", maxTranslationLength))
+			}
+			if !s.localizationService.IsLanguage(ctx, row.LanguageID) {
+				fieldErrors.Add(field("language_id"), "language does not exist")
+				continue
+			}
+			question, ok := questionsByID[row.QuestionID]
+			if !ok {
+				fieldErrors.Add(field("question_id"), "question does not exist or is not active")
+				continue
+			}
+			if question.LanguageID == row.LanguageID {
+				fieldErrors.Add(field("language_id"), "question is already written in this language")
+				continue
+			}
+
+			key := target{rowType: row.Type, language: row.LanguageID}
+			switch row.Type {
+			case i18n.RowTypeQuestion:
+				if row.OptionID != 0 {
+					fieldErrors.Add(field("option_id"), "option_id must be empty for a question")
+					continue
+				}
+				key.id = row.QuestionID
+			case i18n.RowTypeOption:
+				option, ok := optionsByID[row.OptionID]
+				if !ok || option.QuestionMasterID != row.QuestionID {
+					fieldErrors.Add(field("option_id"), "option does not belong to the question")
+					continue
+				}
+				key.id = row.OptionID
+			default:
+				fieldErrors.Add(field("type"), "type must be question or option")
+				continue
+			}
+
+			if first, ok := seen[key]; ok {
+				fieldErrors.Add(field("text"), fmt.Sprintf("translates the same %s as rows[%d]", row.Type, first))
+				continue
+			}
+			seen[key] = i
+			pending = append(pending, i)
+		}
+		if len(fieldErrors) > 0 {
+			return errors.NewBadRequestError(errors.ErrValidationFailed, fieldErrors)
+		}
+
+		existingQuestions, existingOptions, err := s.existingTranslations(ctx, tx, rows, pending)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, i := range pending {
+			row := rows[i]
+			var change translationChange
+			if row.Type == i18n.RowTypeQuestion {
+				existing := existingQuestions[[2]int{row.QuestionID, row.LanguageID}]
+				_, change, err = s.saveQuestionTranslation(ctx, tx, existing, row.QuestionID, row.LanguageID, row.Text, importedBy, now)
+			} else {
+				existing := existingOptions[[2]int{row.OptionID, row.LanguageID}]
+				_, change, err = s.saveOptionTranslation(ctx, tx, existing, row.OptionID, row.LanguageID, row.Text, importedBy, now)
+			}
+			if err != nil {
+				return err
+			}
+			switch change {
+			case translationCreated:
+				response.Created++
+			case translationUpdated:
+				response.Updated++
+			default:
+				response.Unchanged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Created > 0 || response.Updated > 0 {
+		s.questionCatalog.Invalidate()
+	}
+	return response, nil
+}
+
+// translatableQuestion loads a question that can be translated into the language
+func (s *translationService) translatableQuestion(ctx context.Context, tx *gorm.DB, questionID, languageID int) (*entities.QuestionMaster, error) {
+	question, err := s.questionRepo.FindByIDTx(ctx, tx, questionID)
+	if err != nil {
+		return nil, errors.NewInternalServerError("Failed to get question", err)
+	}
+	if question == nil {
+		return nil, errors.NewNotFoundError("Question not found", nil)
+	}
+	if question.LanguageID == languageID {
+		return nil, errors.NewBadRequestError("Question is already written in this language", nil)
+	}
+	return question, nil
+}
+
+// translatableContent returns the active questions and their active options,
+// keyed by question ID
+func (s *translationService) translatableContent(ctx context.Context, db *gorm.DB) ([]entities.QuestionMaster, map[int][]entities.OptionMaster, error) {
+	questions, err := s.questionRepo.FindTranslatable(ctx, db)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get questions", err)
+	}
+	options, err := s.optionMasterRepo.FindActiveByQuestionIDs(ctx, db, questionIDs(questions))
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get question options", err)
+	}
+	byQuestion := make(map[int][]entities.OptionMaster, len(questions))
+	for _, option := range options {
+		byQuestion[option.QuestionMasterID] = append(byQuestion[option.QuestionMasterID], option)
+	}
+	return questions, byQuestion, nil
+}
+
+// translations returns the translated text of the questions and options in the
+// language, keyed by question and option ID
+func (s *translationService) translations(ctx context.Context, db *gorm.DB, questions []entities.QuestionMaster, options map[int][]entities.OptionMaster, languageID int) (map[int]string, map[int]string, error) {
+	questionLanguages, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDsAndLanguageID(ctx, db, questionIDs(questions), languageID)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get question translations", err)
+	}
+	questionTexts := make(map[int]string, len(questionLanguages))
+	for _, questionLanguage := range questionLanguages {
+		questionTexts[questionLanguage.QuestionMasterID] = questionLanguage.QuestionText
+	}
+
+	var optionIDs []int
+	for _, questionOptions := range options {
+		for _, option := range questionOptions {
+			optionIDs = append(optionIDs, option.ID)
+		}
+	}
+	optionTexts := make(map[int]string)
+	if len(optionIDs) == 0 {
+		return questionTexts, optionTexts, nil
+	}
+	optionLanguages, err := s.optionMasterLanguageRepo.FindByOptionMasterIDsAndLanguageID(ctx, db, optionIDs, languageID)
+	if err != nil {
+		return nil, nil, errors.NewInternalServerError("Failed to get option translations", err)
+	}
+	for _, optionLanguage := range optionLanguages {
+		optionTexts[optionLanguage.OptionMasterID] = optionLanguage.OptionText
+	}
+	return questionTexts, optionTexts, nil
+}
+
+// existingTranslations loads the stored translations the pending rows would
+// replace, keyed by question or option ID and language ID
+func (s *translationService) existingTranslations(ctx context.Context, tx *gorm.DB, rows []i18n.Row, pending []int) (map[[2]int]*entities.QuestionMasterLanguage, map[[2]int]*entities.OptionMasterLanguage, error) {
+	questionIDsByLanguage := make(map[int][]int)
+	optionIDsByLanguage := make(map[int][]int)
+	for _, i := range pending {
+		row := rows[i]
+		if row.Type == i18n.RowTypeQuestion {
+			questionIDsByLanguage[row.LanguageID] = append(questionIDsByLanguage[row.LanguageID], row.QuestionID)
+		} else {
+			optionIDsByLanguage[row.LanguageID] = append(optionIDsByLanguage[row.LanguageID], row.OptionID)
+		}
+	}
+
+	questions := make(map[[2]int]*entities.QuestionMasterLanguage)
+	for languageID, ids := range questionIDsByLanguage {
+		translations, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDsAndLanguageID(ctx, tx, ids, languageID)
+		if err != nil {
+			return nil, nil, errors.NewInternalServerError("Failed to get question translations", err)
+		}
+		for i := range translations {
+			questions[[2]int{translations[i].QuestionMasterID, languageID}] = &translations[i]
+		}
+	}
+	options := make(map[[2]int]*entities.OptionMasterLanguage)
+	for languageID, ids := range optionIDsByLanguage {
+		translations, err := s.optionMasterLanguageRepo.FindByOptionMasterIDsAndLanguageID(ctx, tx, ids, languageID)
+		if err != nil {
+			return nil, nil, errors.NewInternalServerError("Failed to get option translations", err)
+		}
+		for i := range translations {
+			options[[2]int{translations[i].OptionMasterID, languageID}] = &translations[i]
+		}
+	}
+	return questions, options, nil
+}
+
+// saveQuestionTranslation creates the translation or updates existing with text,
+// reactivating it if needed
+func (s *translationService) saveQuestionTranslation(ctx context.Context, tx *gorm.DB, existing *entities.QuestionMasterLanguage, questionID, languageID int, text, savedBy string, now time.Time) (*entities.QuestionMasterLanguage, translationChange, error) {
+	if existing == nil {
+		translation := &entities.QuestionMasterLanguage{
+			QuestionMasterID: questionID,
+			LanguageID:       languageID,
+			QuestionText:     text,
+			IsActive:         true,
+			CreatedBy:        savedBy,
+			CreatedOn:        now,
+		}
+		if err := s.questionMasterLanguageRepo.Create(ctx, tx, translation); err != nil {
+			return nil, translationUnchanged, errors.NewInternalServerError("Failed to save question translation", err)
+		}
+		return translation, translationCreated, nil
+	}
+	if existing.QuestionText == text && existing.IsActive {
+		return existing, translationUnchanged, nil
+	}
+
+	existing.QuestionText = text
+	existing.IsActive = true
+	existing.LastModifiedBy = &savedBy
+	existing.LastModifiedOn = &now
+	if err := s.questionMasterLanguageRepo.Update(ctx, tx, existing); err != nil {
+		return nil, translationUnchanged, errors.NewInternalServerError("Failed to save question translation", err)
+	}
+	return existing, translationUpdated, nil
+}
+
+// saveOptionTranslation creates the translation or updates existing with text,
+// reactivating it if needed
+func (s *translationService) saveOptionTranslation(ctx context.Context, tx *gorm.DB, existing *entities.OptionMasterLanguage, optionID, languageID int, text, savedBy string, now time.Time) (*entities.OptionMasterLanguage, translationChange, error) {
+	if existing == nil {
+		translation := &entities.OptionMasterLanguage{
+			OptionMasterID: optionID,
+			LanguageID:     languageID,
+			OptionText:     text,
+			IsActive:       true,
+			CreatedBy:      savedBy,
+			CreatedOn:      now,
+		}
+		if err := s.optionMasterLanguageRepo.Create(ctx, tx, translation); err != nil {
+			return nil, translationUnchanged, errors.NewInternalServerError("Failed to save option translation", err)
+		}
+		return translation, translationCreated, nil
+	}
+	if existing.OptionText == text && existing.IsActive {
+		return existing, translationUnchanged, nil
+	}
+
+	existing.OptionText = text
+	existing.IsActive = true
+	existing.LastModifiedBy = &savedBy
+	existing.LastModifiedOn = &now
+	if err := s.optionMasterLanguageRepo.Update(ctx, tx, existing); err != nil {
+		return nil, translationUnchanged, errors.NewInternalServerError("Failed to save option translation", err)
+	}
+	return existing, translationUpdated, nil
+}
+
+func translatedPercent(count dtos.TranslationCountDTO) int {
+	if count.Total == 0 {
+		return 100
+	}
+	return count.Translated * 100 / count.Total
+}
+
+func toQuestionTranslationDTO(translation *entities.QuestionMasterLanguage) dtos.TranslationDTO {
+	return dtos.TranslationDTO{
+		ID:         translation.ID,
+		LanguageID: translation.LanguageID,
+		Text:       translation.QuestionText,
+		IsActive:   translation.IsActive,
+	}
+}
+
+func toOptionTranslationDTO(translation *entities.OptionMasterLanguage) dtos.TranslationDTO {
+	return dtos.TranslationDTO{
+		ID:         translation.ID,
+		LanguageID: translation.LanguageID,
+		Text:       translation.OptionText,
+		IsActive:   translation.IsActive,
+	}
+}
@@ -11,18 +11,19 @@ import (
 
 	"github.com/Infinite-Locus-Product/thums_up_backend/dtos"
 	"github.com/Infinite-Locus-Product/thums_up_backend/entities"
+	"github.com/Infinite-Locus-Product/thums_up_backend/errors"
 	"github.com/Infinite-Locus-Product/thums_up_backend/repository"
 	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
 )
 
 type UserService interface {
-	GetUser(ctx context.Context, userID string) (*entities.User, *string, *string, bool, error)
+	GetUser(ctx context.Context, userID string) (*entities.User, *dtos.ImageVariants, *string, bool, error)
 	UpdateUser(ctx context.Context, userID string, req dtos.UpdateProfileRequestDTO) (*entities.User, error)
 	GetUserAddresses(ctx context.Context, userID string) ([]dtos.AddressResponseDTO, error)
 	AddUserAddress(ctx context.Context, userID string, req dtos.AddressRequestDTO) (*dtos.AddressResponseDTO, error)
 	UpdateUserAddress(ctx context.Context, userID string, addressID string, req dtos.AddressRequestDTO) (*dtos.AddressResponseDTO, error)
 	DeleteUserAddress(ctx context.Context, userID string, addressID string) error
-	GetQuestions(ctx context.Context, userID string, languageID int) ([]dtos.QuestionResponseDTO, error)
+	GetQuestions(ctx context.Context, userID string, languageID int) (*dtos.QuestionnaireResponseDTO, error)
 	GetQuestionIDByText(ctx context.Context, questionText string, languageID int) (int, error)
 	GetQuestionByID(ctx context.Context, questionID int, languageID int) (*dtos.QuestionResponseDTO, error)
 	AnswerQuestions(ctx context.Context, userID string, answers []dtos.AnswerQuestionsRequestDTO) error
@@ -37,12 +38,18 @@ type userService struct {
 	pinCodeRepo                repository.PinCodeRepository
 	avatarRepo                 repository.GenericRepository[entities.Avatar]
 	gcsService                 utils.GCSService
+	mediaResolver              MediaURLResolver
 	questionAnswerRepo         repository.UserQuestionAnswerRepository
 	questionMasterRepo         repository.QuestionRepository
 	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository
 	optionMasterRepo           repository.OptionMasterRepository
 	optionMasterLanguageRepo   repository.OptionMasterLanguageRepository
 	winnerRepo                 repository.WinnerRepository
+	pointsService              PointsService
+	questionSectionRepo        repository.QuestionSectionRepository
+	questionConditionRepo      repository.QuestionConditionRepository
+	localizationService        LocalizationService
+	questionCatalog            QuestionCatalog
 }
 
 func NewUserService(
@@ -54,12 +61,18 @@ func NewUserService(
 	pinCodeRepo repository.PinCodeRepository,
 	avatarRepo repository.GenericRepository[entities.Avatar],
 	gcsService utils.GCSService,
+	mediaResolver MediaURLResolver,
 	questionAnswerRepo repository.UserQuestionAnswerRepository,
 	questionMasterRepo repository.QuestionRepository,
 	questionMasterLanguageRepo repository.QuestionMasterLanguageRepository,
 	optionMasterRepo repository.OptionMasterRepository,
 	optionMasterLanguageRepo repository.OptionMasterLanguageRepository,
 	winnerRepo repository.WinnerRepository,
+	pointsService PointsService,
+	questionSectionRepo repository.QuestionSectionRepository,
+	questionConditionRepo repository.QuestionConditionRepository,
+	localizationService LocalizationService,
+	questionCatalog QuestionCatalog,
 ) UserService {
 	return &userService{
 		txnManager:                 txnManager,
@@ -70,16 +83,22 @@ func NewUserService(
 		pinCodeRepo:                pinCodeRepo,
 		avatarRepo:                 avatarRepo,
 		gcsService:                 gcsService,
+		mediaResolver:              mediaResolver,
 		questionAnswerRepo:         questionAnswerRepo,
 		questionMasterRepo:         questionMasterRepo,
 		questionMasterLanguageRepo: questionMasterLanguageRepo,
 		optionMasterRepo:           optionMasterRepo,
 		optionMasterLanguageRepo:   optionMasterLanguageRepo,
 		winnerRepo:                 winnerRepo,
+		pointsService:              pointsService,
+		questionSectionRepo:        questionSectionRepo,
+		questionConditionRepo:      questionConditionRepo,
+		localizationService:        localizationService,
+		questionCatalog:            questionCatalog,
 	}
 }
 
-func (s *userService) GetUser(ctx context.Context, userID string) (*entities.User, *string, *string, bool, error) {
+func (s *userService) GetUser(ctx context.Context, userID string) (*entities.User, *dtos.ImageVariants, *string, bool, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, nil, nil, false, fmt.Errorf("invalid user ID format: %w", err)
@@ -104,8 +123,8 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*entities.Use
 
 	// Use goroutines to fetch avatar and winner status concurrently
 	type avatarResult struct {
-		url *string
-		err error
+		variants *dtos.ImageVariants
+		err      error
 	}
 	type winnerResult struct {
 		qrURL    *string
@@ -116,18 +135,15 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*entities.Use
 	avatarChan := make(chan avatarResult, 1)
 	winnerChan := make(chan winnerResult, 1)
 
-	// Fetch avatar concurrently
+	// Fetch avatar concurrently. Users keep avatars that were unpublished after
+	// they chose them; deleted or missing ones resolve to the default avatar.
 	go func() {
-		var avatarImageURL *string
+		var avatarVariants *dtos.ImageVariants
 		if user.AvatarID != nil {
-			avatar, err := s.avatarRepo.FindByID(ctx, tx, *user.AvatarID)
-			if err == nil && avatar != nil && !avatar.IsDeleted && avatar.IsActive {
-				fullPath := fmt.Sprintf("avatars/%s/%s", avatar.CreatedBy, avatar.ImageKey)
-				imageURL := s.gcsService.GetPublicURL(fullPath)
-				avatarImageURL = &imageURL
-			}
+			variants := s.mediaResolver.AvatarImagesByID(ctx, []int{*user.AvatarID})[*user.AvatarID]
+			avatarVariants = &variants
 		}
-		avatarChan <- avatarResult{url: avatarImageURL, err: nil}
+		avatarChan <- avatarResult{variants: avatarVariants, err: nil}
 	}()
 
 	// Fetch winner status concurrently
@@ -137,10 +153,7 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*entities.Use
 		winner, err := s.winnerRepo.FindLatestByUserID(ctx, tx, userID)
 		if err == nil && winner != nil {
 			isWinner = true
-			if winner.QRCode != "" {
-				url := s.gcsService.GetPublicURL(winner.QRCode)
-				qrCodeURL = &url
-			}
+			qrCodeURL = s.mediaResolver.QRCodeURL(ctx, winner)
 		}
 		winnerChan <- winnerResult{qrURL: qrCodeURL, isWinner: isWinner, err: nil}
 	}()
@@ -150,7 +163,7 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*entities.Use
 	winnerRes := <-winnerChan
 
 	s.txnManager.CommitTxn(tx)
-	return user, avatarRes.url, winnerRes.qrURL, winnerRes.isWinner, nil
+	return user, avatarRes.variants, winnerRes.qrURL, winnerRes.isWinner, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, userID string, req dtos.UpdateProfileRequestDTO) (*entities.User, error) {
@@ -195,15 +208,20 @@ func (s *userService) UpdateUser(ctx context.Context, userID string, req dtos.Up
 		updateFields["email"] = *req.Email
 	}
 
-	if req.AvatarID != nil {
+	// Users keep an avatar that was retired after they chose it, so resending the
+	// current avatar is accepted; switching requires a selectable one and 0 clears it
+	keepsAvatar := req.AvatarID != nil && user.AvatarID != nil && *req.AvatarID == *user.AvatarID
+	if req.AvatarID != nil && *req.AvatarID == 0 {
+		updateFields["avatar_id"] = nil
+	} else if req.AvatarID != nil && !keepsAvatar {
 		avatar, err := s.avatarRepo.FindByID(ctx, tx, *req.AvatarID)
 		if err != nil {
 			s.txnManager.AbortTxn(tx)
 			return nil, fmt.Errorf("failed to fetch avatar: %v", err)
 		}
-		if avatar == nil || avatar.IsDeleted || !avatar.IsActive {
+		if avatar == nil || !avatar.IsSelectable() {
 			s.txnManager.AbortTxn(tx)
-			return nil, fmt.Errorf("avatar not found or not available")
+			return nil, errors.NewBadRequestError("Avatar not found or no longer available, please choose another", nil)
 		}
 		updateFields["avatar_id"] = *req.AvatarID
 	}
@@ -220,6 +238,21 @@ func (s *userService) UpdateUser(ctx context.Context, userID string, req dtos.Up
 		updateFields["platform_user_name"] = *req.PlatformUserName
 	}
 
+	if req.LeaderboardOptOut != nil {
+		updateFields["leaderboard_opt_out"] = *req.LeaderboardOptOut
+	}
+
+	// 0 clears the preference so requests follow Accept-Language again
+	if req.PreferredLanguageID != nil && *req.PreferredLanguageID == 0 {
+		updateFields["preferred_language_id"] = nil
+	} else if req.PreferredLanguageID != nil {
+		if !s.localizationService.IsLanguage(ctx, *req.PreferredLanguageID) {
+			s.txnManager.AbortTxn(tx)
+			return nil, errors.NewBadRequestError(errors.ErrLanguageNotFound, nil)
+		}
+		updateFields["preferred_language_id"] = *req.PreferredLanguageID
+	}
+
 	if len(updateFields) > 0 {
 		if err := s.userRepo.UpdateFields(ctx, tx, userID, updateFields); err != nil {
 			s.txnManager.AbortTxn(tx)
@@ -695,308 +728,175 @@ func (s *userService) DeleteUserAddress(ctx context.Context, userID string, addr
 	return nil
 }
 
-func (s *userService) GetQuestions(ctx context.Context, userID string, languageID int) ([]dtos.QuestionResponseDTO, error) {
-	tx, err := s.txnManager.StartTxn()
+// GetQuestions returns the profile questions that apply to the user given their
+// answers so far, in section and display order, with the next unanswered
+// question and how much of the questionnaire is complete. Question content comes
+// from the cached catalog; only the user's answers are read per request.
+func (s *userService) GetQuestions(ctx context.Context, userID string, languageID int) (*dtos.QuestionnaireResponseDTO, error) {
+	catalog, err := s.questionCatalog.get(ctx, languageID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
 		return nil, err
 	}
-	defer s.txnManager.RollbackOnPanic(tx)
-
-	// Step 1: Fetch all active questions from question_master
-	questions, err := s.questionMasterRepo.FindActive(ctx, tx)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get questions: %v", err)
-	}
-
-	// Step 2: Get question details from question_master_language table based on question ID and language ID
-	questionLanguageMap := make(map[int]string)
-	for _, question := range questions {
-		questionLanguage, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDAndLanguageID(ctx, tx, question.ID, languageID)
-		if err != nil {
-			s.txnManager.AbortTxn(tx)
-			return nil, fmt.Errorf("failed to get question language text for question %d: %v", question.ID, err)
-		}
-		if questionLanguage != nil {
-			questionLanguageMap[question.ID] = questionLanguage.QuestionText
-		} else {
-			questionLanguageMap[question.ID] = question.QuestionText // fallback to original text
-		}
-	}
-
-	// Step 3: Fetch options from option_master table using question IDs
-	questionIDs := make([]int, 0)
-	for _, q := range questions {
-		questionIDs = append(questionIDs, q.ID)
-	}
-	options, err := s.optionMasterRepo.FindByQuestionIDs(ctx, tx, questionIDs)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get options: %v", err)
-	}
-
-	// Step 4: Fetch option details from option_master_language table based on option ID and language ID
-	optionLanguageMap := make(map[int]string)
-	optionIDs := make([]int, 0)
-	for _, opt := range options {
-		optionIDs = append(optionIDs, opt.ID)
-	}
-	optionLanguages, err := s.optionMasterLanguageRepo.FindByOptionMasterIDsAndLanguageID(ctx, tx, optionIDs, languageID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get option language texts: %v", err)
-	}
-	for _, optLang := range optionLanguages {
-		optionLanguageMap[optLang.OptionMasterID] = optLang.OptionText
-	}
 
-	// Step 5: Fetch all user questions from user_question_answer table
-	userAnswers, err := s.questionAnswerRepo.FindByUserID(ctx, tx, userID)
+	userAnswers, err := s.questionAnswerRepo.FindByUserID(ctx, s.txnManager.GetDB(), userID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get user answers: %v", err)
+		return nil, fmt.Errorf("failed to get user answers: %w", err)
 	}
 
-	// Create a map of user answers for quick lookup
-	userAnswerMap := make(map[int]entities.UserQuestionAnswer)
+	// Group user answers by question; multi-select answers have one row per option
+	userAnswerMap := make(map[int][]entities.UserQuestionAnswer)
 	for _, ans := range userAnswers {
-		userAnswerMap[ans.QuestionMasterID] = ans
-	}
-
-	// Step 6: Fill options accordingly and construct response
-	response := make([]dtos.QuestionResponseDTO, 0)
-	for _, question := range questions {
-		// Get question text (language-specific or fallback)
-		questionText := questionLanguageMap[question.ID]
-
-		// Get options for this question
-		var questionOptions []dtos.OptionDTO
-		for _, opt := range options {
-			if opt.QuestionMasterID == question.ID {
-				// Get option text (language-specific or fallback)
-				optionText := opt.OptionText
-				if langText, exists := optionLanguageMap[opt.ID]; exists {
-					optionText = langText
-				}
-
-				questionOptions = append(questionOptions, dtos.OptionDTO{
-					ID:           opt.ID,
-					OptionText:   optionText,
-					DisplayOrder: opt.DisplayOrder,
-				})
-			}
-		}
+		userAnswerMap[ans.QuestionMasterID] = append(userAnswerMap[ans.QuestionMasterID], ans)
+	}
 
-		// Check if user has answered this question
-		var selectedOption *int
-		if userAnswer, exists := userAnswerMap[question.ID]; exists {
-			selectedOption = &userAnswer.OptionID
+	// Leave out questions whose display conditions are not met
+	questionnaire := catalog.questionnaire(time.Now())
+	shown := questionnaire.applicable(userAnswerMap)
+	response := make([]dtos.QuestionResponseDTO, 0, len(questionnaire.questions))
+	for i := range questionnaire.questions {
+		question := &questionnaire.questions[i]
+		if !shown[question.ID] {
+			continue
 		}
 
-		response = append(response, dtos.QuestionResponseDTO{
-			ID:             question.ID,
-			QuestionText:   questionText,
-			LanguageID:     languageID,
-			Options:        questionOptions,
-			SelectedOption: selectedOption,
-		})
+		questionResponse := catalog.content.response(question, languageID)
+		questionResponse.Answered = len(userAnswerMap[question.ID]) > 0
+		setUserAnswer(&questionResponse, userAnswerMap[question.ID])
+		response = append(response, questionResponse)
 	}
 
-	s.txnManager.CommitTxn(tx)
-	return response, nil
+	sections, next, completion := questionnaire.summarize(response)
+	return &dtos.QuestionnaireResponseDTO{
+		Sections:     sections,
+		Questions:    response,
+		NextQuestion: next,
+		Completion:   completion,
+	}, nil
 }
 
 func (s *userService) GetQuestionIDByText(ctx context.Context, questionText string, languageID int) (int, error) {
-	tx, err := s.txnManager.StartTxn()
+	db := s.txnManager.GetDB()
+	questionLanguage, err := s.questionMasterLanguageRepo.FindByQuestionTextAndLanguageID(ctx, db, questionText, languageID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return 0, err
-	}
-	defer s.txnManager.RollbackOnPanic(tx)
-
-	questionLanguage, err := s.questionMasterLanguageRepo.FindByQuestionTextAndLanguageID(ctx, tx, questionText, languageID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
 		return 0, fmt.Errorf("failed to search question in language table: %v", err)
 	}
 	if questionLanguage != nil {
-		s.txnManager.CommitTxn(tx)
 		return questionLanguage.QuestionMasterID, nil
 	}
 
-	question, err := s.questionMasterRepo.FindByQuestionTextAndLanguageID(ctx, tx, questionText, languageID)
+	question, err := s.questionMasterRepo.FindByQuestionTextAndLanguageID(ctx, db, questionText, languageID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
 		return 0, fmt.Errorf("failed to search question in master table: %v", err)
 	}
 	if question != nil {
-		s.txnManager.CommitTxn(tx)
 		return question.ID, nil
 	}
 
-	s.txnManager.AbortTxn(tx)
 	return 0, fmt.Errorf("question not found")
 }
 
 func (s *userService) GetQuestionByID(ctx context.Context, questionID int, languageID int) (*dtos.QuestionResponseDTO, error) {
-	tx, err := s.txnManager.StartTxn()
+	db := s.txnManager.GetDB()
+	question, err := s.questionMasterRepo.FindByIDTx(ctx, db, questionID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, err
-	}
-	defer s.txnManager.RollbackOnPanic(tx)
-
-	question, err := s.questionMasterRepo.FindByIDTx(ctx, tx, questionID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
 		return nil, fmt.Errorf("failed to get question: %v", err)
 	}
-	if question == nil {
-		s.txnManager.AbortTxn(tx)
+	if question == nil || !question.IsLive(time.Now()) {
 		return nil, fmt.Errorf("question not found")
 	}
 
-	questionLanguage, err := s.questionMasterLanguageRepo.FindByQuestionMasterIDAndLanguageID(ctx, tx, question.ID, languageID)
+	content, err := s.questionCatalog.localize(ctx, db, []entities.QuestionMaster{*question}, languageID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get question language text: %v", err)
-	}
-
-	questionText := question.QuestionText
-	if questionLanguage != nil {
-		questionText = questionLanguage.QuestionText
-	}
-
-	options, err := s.optionMasterRepo.FindByQuestionID(ctx, tx, questionID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get options: %v", err)
-	}
-
-	optionIDs := make([]int, 0)
-	for _, opt := range options {
-		optionIDs = append(optionIDs, opt.ID)
-	}
-
-	optionLanguages, err := s.optionMasterLanguageRepo.FindByOptionMasterIDsAndLanguageID(ctx, tx, optionIDs, languageID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return nil, fmt.Errorf("failed to get option language texts: %v", err)
-	}
-
-	optionLanguageMap := make(map[int]string)
-	for _, optLang := range optionLanguages {
-		optionLanguageMap[optLang.OptionMasterID] = optLang.OptionText
-	}
-
-	var questionOptions []dtos.OptionDTO
-	for _, opt := range options {
-		optionText := opt.OptionText
-		if langText, exists := optionLanguageMap[opt.ID]; exists {
-			optionText = langText
-		}
-
-		questionOptions = append(questionOptions, dtos.OptionDTO{
-			ID:           opt.ID,
-			OptionText:   optionText,
-			DisplayOrder: opt.DisplayOrder,
-		})
-	}
-
-	response := &dtos.QuestionResponseDTO{
-		ID:           question.ID,
-		QuestionText: questionText,
-		LanguageID:   languageID,
-		Options:      questionOptions,
+		return nil, err
 	}
 
-	s.txnManager.CommitTxn(tx)
-	return response, nil
+	response := content.response(question, languageID)
+	return &response, nil
 }
 
+// AnswerQuestions validates every answer against its question's type and active
+// options before storing any of them. Answers replace earlier answers to the same
+// question. Invalid answers fail with field-level errors readable through
+// utils.FormatValidationErrors.
 func (s *userService) AnswerQuestions(ctx context.Context, userID string, answers []dtos.AnswerQuestionsRequestDTO) error {
-	tx, err := s.txnManager.StartTxn()
-	if err != nil {
-		return err
-	}
-	defer s.txnManager.RollbackOnPanic(tx)
-
-	// First verify if user exists
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
-		s.txnManager.AbortTxn(tx)
 		return fmt.Errorf("invalid user ID format: %w", err)
 	}
 
-	user, err := s.userRepo.FindById(ctx, tx, userUUID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return err
-	}
-
-	if user == nil {
-		s.txnManager.AbortTxn(tx)
-		return fmt.Errorf("user not found")
-	}
+	return s.txnManager.ExecuteInTransaction(ctx, func(tx *gorm.DB) error {
+		user, err := s.userRepo.FindById(ctx, tx, userUUID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("user not found")
+		}
 
-	// Check existing answers before creating new ones
-	existingAnswers, err := s.questionAnswerRepo.FindByUserID(ctx, tx, userID)
-	if err != nil {
-		s.txnManager.AbortTxn(tx)
-		return fmt.Errorf("failed to get existing answers: %v", err)
-	}
+		now := time.Now()
+		fieldErrors := utils.FieldErrors{}
+		answered := make([]answeredQuestion, 0, len(answers))
+		seen := make(map[int]bool, len(answers))
+		for i, answer := range answers {
+			if seen[answer.QuestionID] {
+				fieldErrors.Add(fmt.Sprintf("answers[%d].question_id", i), "question is answered more than once")
+				continue
+			}
+			seen[answer.QuestionID] = true
 
-	// Create a map of existing answers for quick lookup
-	existingAnswerMap := make(map[int]entities.UserQuestionAnswer)
-	for _, ans := range existingAnswers {
-		existingAnswerMap[ans.QuestionMasterID] = ans
-	}
+			question, err := s.questionMasterRepo.FindByIDTx(ctx, tx, answer.QuestionID)
+			if err != nil {
+				return fmt.Errorf("failed to get question %d: %w", answer.QuestionID, err)
+			}
+			if question == nil || !question.IsLive(now) {
+				fieldErrors.Add(fmt.Sprintf("answers[%d].question_id", i), "question does not exist or is no longer active")
+				continue
+			}
 
-	// Process each answer
-	now := time.Now()
-	newAnswers := make([]entities.UserQuestionAnswer, 0)
-	updatedAnswers := make([]entities.UserQuestionAnswer, 0)
-
-	for _, answer := range answers {
-		if existingAnswer, exists := existingAnswerMap[answer.QuestionID]; exists {
-			// Question already answered, just update the option ID
-			existingAnswer.OptionID = answer.AnswerID
-			existingAnswer.LastModifiedBy = &userID
-			existingAnswer.LastModifiedOn = &now
-			updatedAnswers = append(updatedAnswers, existingAnswer)
-		} else {
-			// New answer, create it
-			newAnswer := entities.UserQuestionAnswer{
-				UserID:           userID,
-				QuestionMasterID: answer.QuestionID,
-				OptionID:         answer.AnswerID,
-				SelectedAnswer:   true,
-				IsActive:         true,
-				IsDeleted:        false,
-				CreatedBy:        userID,
-				CreatedOn:        now,
+			var options []entities.OptionMaster
+			if question.IsChoice() {
+				options, err = s.optionMasterRepo.FindActiveByQuestionID(ctx, tx, question.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get options of question %d: %w", question.ID, err)
+				}
 			}
-			newAnswers = append(newAnswers, newAnswer)
+
+			validateAnswer(fieldErrors, i, answer, question, options)
+			answered = append(answered, answeredQuestion{question: question, answer: answer, index: i})
+		}
+		if len(fieldErrors) > 0 {
+			return errors.NewBadRequestError(errors.ErrValidationFailed, fieldErrors)
 		}
-	}
 
-	// Update existing answers
-	for _, answer := range updatedAnswers {
-		if err := s.questionAnswerRepo.Update(ctx, tx, &answer); err != nil {
-			s.txnManager.AbortTxn(tx)
-			return fmt.Errorf("failed to update existing answer: %v", err)
+		if err := s.checkApplicable(ctx, tx, userID, answered, now, fieldErrors); err != nil {
+			return err
+		}
+		if len(fieldErrors) > 0 {
+			return errors.NewBadRequestError(errors.ErrValidationFailed, fieldErrors)
 		}
-	}
 
-	// Save new answers
-	if len(newAnswers) > 0 {
-		if err := s.questionAnswerRepo.CreateMany(ctx, tx, newAnswers); err != nil {
-			s.txnManager.AbortTxn(tx)
-			return fmt.Errorf("failed to save new answers: %v", err)
+		questionIDs := make([]int, len(answered))
+		for i, a := range answered {
+			questionIDs[i] = a.question.ID
+		}
+		if err := s.questionAnswerRepo.DeleteByUserAndQuestionIDs(ctx, tx, userID, questionIDs); err != nil {
+			return fmt.Errorf("failed to replace existing answers: %w", err)
 		}
-	}
 
-	s.txnManager.CommitTxn(tx)
-	return nil
+		rows := make([]entities.UserQuestionAnswer, 0, len(answered))
+		for _, a := range answered {
+			rows = append(rows, answerRows(userID, a, now)...)
+		}
+		if err := s.questionAnswerRepo.CreateMany(ctx, tx, rows); err != nil {
+			return fmt.Errorf("failed to save answers: %w", err)
+		}
+
+		// Points are paid once per question, so re-answering never credits again
+		for _, a := range answered {
+			if _, err := s.pointsService.Award(ctx, tx, profileQuestionAward(userID, a.question)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
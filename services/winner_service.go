@@ -36,6 +36,7 @@ type winnerService struct {
 	userAadharRepo         repository.UserAadharCardRepository
 	userAdditionalInfoRepo repository.UserAdditionalInfoRepository
 	gcsService             utils.GCSService
+	mediaResolver          MediaURLResolver
 }
 
 func NewWinnerService(
@@ -47,6 +48,7 @@ func NewWinnerService(
 	userAadharRepo repository.UserAadharCardRepository,
 	userAdditionalInfoRepo repository.UserAdditionalInfoRepository,
 	gcsService utils.GCSService,
+	mediaResolver MediaURLResolver,
 ) WinnerService {
 	return &winnerService{
 		txnManager:             txnManager,
@@ -57,6 +59,7 @@ func NewWinnerService(
 		userAadharRepo:         userAadharRepo,
 		userAdditionalInfoRepo: userAdditionalInfoRepo,
 		gcsService:             gcsService,
+		mediaResolver:          mediaResolver,
 	}
 }
 
@@ -137,35 +140,26 @@ func (s *winnerService) GetWinnersByWeek(ctx context.Context, weekNumber int) ([
 		return nil, errors.NewInternalServerError("Failed to get winners", err)
 	}
 
+	avatarImages := s.winnerAvatarImages(ctx, winners)
+
 	responses := make([]dtos.WinnerResponse, len(winners))
 	for i, winner := range winners {
-		var qrURL *string
-		if winner.QRCode != "" {
-			url := s.gcsService.GetPublicURL(winner.QRCode)
-			qrURL = &url
-		}
+		qrURL := s.mediaResolver.QRCodeURL(ctx, &winner)
 
-		var avatarURL *string
-		var avatarName *string
-		if winner.User.Avatar != nil {
-			// Reconstruct full path: avatars/{createdBy}/{imageKey}
-			fullPath := fmt.Sprintf("avatars/%s/%s", winner.User.Avatar.CreatedBy, winner.User.Avatar.ImageKey)
-			url := s.gcsService.GetPublicURL(fullPath)
-			avatarURL = &url
-			avatarName = &winner.User.Avatar.Name
-		}
+		avatarURL, avatarName, avatarVariants := avatarSummary(&winner.User, avatarImages)
 
 		responses[i] = dtos.WinnerResponse{
-			ID:            winner.ID,
-			UserID:        winner.UserID,
-			ThunderSeatID: winner.ThunderSeatID,
-			WeekNumber:    winner.WeekNumber,
-			QRCodeURL:     qrURL,
-			CreatedOn:     winner.CreatedOn.Format(time.RFC3339),
-			Name:          winner.User.Name,
-			Email:         winner.User.Email,
-			AvatarURL:     avatarURL,
-			AvatarName:    avatarName,
+			ID:             winner.ID,
+			UserID:         winner.UserID,
+			ThunderSeatID:  winner.ThunderSeatID,
+			WeekNumber:     winner.WeekNumber,
+			QRCodeURL:      qrURL,
+			CreatedOn:      winner.CreatedOn.Format(time.RFC3339),
+			Name:           winner.User.Name,
+			Email:          winner.User.Email,
+			AvatarURL:      avatarURL,
+			AvatarName:     avatarName,
+			AvatarVariants: avatarVariants,
 		}
 	}
 
@@ -178,35 +172,26 @@ func (s *winnerService) GetAllWinners(ctx context.Context, limit, offset int) ([
 		return nil, 0, errors.NewInternalServerError("Failed to get winners", err)
 	}
 
+	avatarImages := s.winnerAvatarImages(ctx, winners)
+
 	responses := make([]dtos.WinnerResponse, len(winners))
 	for i, winner := range winners {
-		var qrURL *string
-		if winner.QRCode != "" {
-			url := s.gcsService.GetPublicURL(winner.QRCode)
-			qrURL = &url
-		}
+		qrURL := s.mediaResolver.QRCodeURL(ctx, &winner)
 
-		var avatarURL *string
-		var avatarName *string
-		if winner.User.Avatar != nil {
-			// Reconstruct full path: avatars/{createdBy}/{imageKey}
-			fullPath := fmt.Sprintf("avatars/%s/%s", winner.User.Avatar.CreatedBy, winner.User.Avatar.ImageKey)
-			url := s.gcsService.GetPublicURL(fullPath)
-			avatarURL = &url
-			avatarName = &winner.User.Avatar.Name
-		}
+		avatarURL, avatarName, avatarVariants := avatarSummary(&winner.User, avatarImages)
 
 		responses[i] = dtos.WinnerResponse{
-			ID:            winner.ID,
-			UserID:        winner.UserID,
-			ThunderSeatID: winner.ThunderSeatID,
-			WeekNumber:    winner.WeekNumber,
-			QRCodeURL:     qrURL,
-			CreatedOn:     winner.CreatedOn.Format(time.RFC3339),
-			Name:          winner.User.Name,
-			Email:         winner.User.Email,
-			AvatarURL:     avatarURL,
-			AvatarName:    avatarName,
+			ID:             winner.ID,
+			UserID:         winner.UserID,
+			ThunderSeatID:  winner.ThunderSeatID,
+			WeekNumber:     winner.WeekNumber,
+			QRCodeURL:      qrURL,
+			CreatedOn:      winner.CreatedOn.Format(time.RFC3339),
+			Name:           winner.User.Name,
+			Email:          winner.User.Email,
+			AvatarURL:      avatarURL,
+			AvatarName:     avatarName,
+			AvatarVariants: avatarVariants,
 		}
 	}
 
@@ -448,11 +433,7 @@ func (s *winnerService) CheckUserWinnerStatus(ctx context.Context, userID string
 		return nil, errors.NewInternalServerError("Failed to check winner status", err)
 	}
 
-	var qrURL *string
-	if winner.QRCode != "" {
-		url := s.gcsService.GetPublicURL(winner.QRCode)
-		qrURL = &url
-	}
+	qrURL := s.mediaResolver.QRCodeURL(ctx, winner)
 
 	weekNumber := winner.WeekNumber
 	return &dtos.WinnerStatusResponse{
@@ -486,3 +467,11 @@ func (s *winnerService) MarkBannerAsViewed(ctx context.Context, userID string) e
 
 	return nil
 }
+
+func (s *winnerService) winnerAvatarImages(ctx context.Context, winners []entities.ThunderSeatWinner) map[string]dtos.ImageVariants {
+	users := make([]*entities.User, len(winners))
+	for i := range winners {
+		users[i] = &winners[i].User
+	}
+	return s.mediaResolver.UserImages(ctx, users)
+}
@@ -23,24 +23,93 @@ func RunDBMigrations(db *gorm.DB) error {
 		&entities.RefreshToken{},
 		&entities.NotifyMe{},
 		&entities.Address{},
+		&entities.MediaAsset{},
 		&entities.Avatar{},
+		&entities.AvatarTag{},
+		&entities.UserProfilePhoto{},
+		&entities.PointTransaction{},
+		&entities.LeaderboardEntry{},
 		&entities.State{},
 		&entities.City{},
 		&entities.PinCode{},
 		&entities.UserAadharCard{},
 		&entities.UserAdditionalInfo{},
+		&entities.LanguageMaster{},
+		&entities.LanguageKey{},
+		&entities.LanguageKeyValue{},
 		&entities.QuestionMaster{},
 		&entities.OptionMaster{},
 		&entities.QuestionMasterLanguage{},
 		&entities.OptionMasterLanguage{},
+		&entities.QuestionVersion{},
+		&entities.OptionVersion{},
+		&entities.QuestionSection{},
+		&entities.QuestionCondition{},
 		&entities.UserQuestionAnswer{},
+		&entities.QuestionAnswerStat{},
+		&entities.QuestionSubmission{},
 		&entities.ThunderSeat{},
+		&entities.ThunderSeatRevision{},
+		&entities.ThunderSeatReport{},
+		&entities.ThunderSeatScreening{},
+		&entities.UploadSession{},
 		&entities.ThunderSeatWinner{},
 		&entities.ContestWeek{},
+		&entities.ContestWeekRule{},
+		&entities.ContestWeekPrompt{},
 	); err != nil {
 		return fmt.Errorf("failed to run GORM automigrations: %w", err)
 	}
 
+	if err := backfillQuestionVersions(db); err != nil {
+		return fmt.Errorf("failed to backfill question versions: %w", err)
+	}
+
 	log.Info("Database migrations completed")
 	return nil
 }
+
+// questionVersionBackfillQuery records the live content of every published question
+// that has no version yet as its published base version, options included, so that
+// new answers always reference a version. It only touches unversioned questions and
+// is safe to run on every start.
+const questionVersionBackfillQuery = `
+WITH base AS (
+	INSERT INTO question_version (
+		question_master_id, version, status, question_text, ques_point, language_id, question_type,
+		min_selections, max_selections, min_value, max_value, max_length,
+		created_by, created_on, published_by, published_on
+	)
+	SELECT q.id,
+		COALESCE((SELECT MAX(v.version) FROM question_version v WHERE v.question_master_id = q.id), 0) + 1,
+		?, COALESCE(q.question_text, ''), q.ques_point, q.language_id, COALESCE(NULLIF(q.question_type, ''), ?),
+		q.min_selections, q.max_selections, q.min_value, q.max_value, q.max_length,
+		q.created_by, q.created_on, q.created_by, NOW()
+	FROM question_master q
+	WHERE q.status = ? AND q.published_version_id IS NULL
+	RETURNING id, question_master_id
+), options AS (
+	INSERT INTO option_version (question_version_id, option_master_id, option_text, display_order, is_active)
+	SELECT base.id, o.id, COALESCE(o.option_text, ''), o.display_order, o.is_active
+	FROM base JOIN option_master o ON o.question_master_id = base.question_master_id AND o.is_deleted = false
+)
+UPDATE question_master q SET published_version_id = base.id
+FROM base WHERE q.id = base.question_master_id
+`
+
+// backfillQuestionVersions gives questions published before versioning their base
+// version. It runs after AutoMigrate because the version tables are created there.
+func backfillQuestionVersions(db *gorm.DB) error {
+	result := db.Exec(questionVersionBackfillQuery,
+		entities.QuestionVersionStatusPublished,
+		entities.QuestionTypeSingleChoice,
+		entities.QuestionStatusPublished,
+	)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Infof("Backfilled base versions for %d questions", result.RowsAffected)
+	}
+	return nil
+}
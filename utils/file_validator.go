@@ -1,9 +1,19 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"mime"
 	"mime/multipart"
+	"path/filepath"
 	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/media"
 )
 
 const (
@@ -11,6 +21,23 @@ const (
 	MaxVideoSize      = 10 * 1024 * 1024  // 10MB in bytes
 	MaxImageSize      = 10 * 1024 * 1024  // 10MB in bytes
 	ContentTypeHeader = "Content-Type"
+	// ContentLengthRangeHeader bounds the size of a signed upload as "min,max" bytes
+	ContentLengthRangeHeader = "x-goog-content-length-range"
+
+	// FileTailSize is how much of the end of audio and video files is checked for
+	// appended content; images are checked in full
+	FileTailSize = 64 * 1024
+)
+
+// File validation error codes returned to clients for localized messages
+const (
+	FileErrRequired       = "FILE_REQUIRED"
+	FileErrEmpty          = "FILE_EMPTY"
+	FileErrUnreadable     = "FILE_UNREADABLE"
+	FileErrTooLarge       = "FILE_TOO_LARGE"
+	FileErrTypeNotAllowed = "FILE_TYPE_NOT_ALLOWED"
+	FileErrTypeMismatch   = "FILE_TYPE_MISMATCH"
+	FileErrPolyglot       = "FILE_POLYGLOT"
 )
 
 var (
@@ -65,171 +92,413 @@ var (
 	AllowedImageExtensions = []string{
 		".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg", ".bmp", ".ico",
 	}
+
+	// mediaKinds is the order kinds are tried in when sniffed content fits more than one
+	mediaKinds = []string{"image", "video", "audio"}
+
+	// containerTypes are sniffed types that hold audio as well as video
+	containerTypes = []string{"video/mp4", "video/webm", "application/ogg"}
 )
 
+// FileValidationError describes why an upload was rejected. Code is stable and
+// meant for clients to localize; Params carries the values the message refers to.
 type FileValidationError struct {
+	Code    string
 	Message string
+	Params  map[string]interface{}
 }
 
 func (e *FileValidationError) Error() string {
 	return e.Message
 }
 
-func ValidateMediaFile(file *multipart.FileHeader) error {
-	if file == nil {
-		return nil // File is optional
+func newFileValidationError(code, message string, params map[string]interface{}) *FileValidationError {
+	return &FileValidationError{Code: code, Message: message, Params: params}
+}
+
+// FileRule is what a FilePolicy accepts for one media kind
+type FileRule struct {
+	MaxSize int64
+	// ContentTypes are matched against the type sniffed from the file's content
+	ContentTypes []string
+	// Extensions and DeclaredTypes are what clients may name and label such files as
+	Extensions    []string
+	DeclaredTypes []string
+}
+
+// FilePolicy decides which uploads are accepted. Files are classified from their
+// leading bytes; the client supplied Content-Type and filename must agree with the
+// content but are never trusted on their own.
+type FilePolicy struct {
+	Rules         map[string]FileRule // keyed by media kind: audio, video or image
+	StripImageGPS bool
+}
+
+// NewFilePolicy creates the default policy with the given size limits in bytes
+func NewFilePolicy(maxAudioSize, maxVideoSize, maxImageSize int64) *FilePolicy {
+	return &FilePolicy{
+		Rules: map[string]FileRule{
+			"audio": {
+				MaxSize:       maxAudioSize,
+				ContentTypes:  append(append([]string(nil), AllowedAudioTypes...), containerTypes...),
+				Extensions:    AllowedAudioExtensions,
+				DeclaredTypes: AllowedAudioTypes,
+			},
+			"video": {
+				MaxSize:       maxVideoSize,
+				ContentTypes:  append(append([]string(nil), AllowedVideoTypes...), "video/x-ms-asf", "application/ogg"),
+				Extensions:    AllowedVideoExtensions,
+				DeclaredTypes: AllowedVideoTypes,
+			},
+			"image": {
+				MaxSize:       maxImageSize,
+				ContentTypes:  AllowedImageTypes,
+				Extensions:    AllowedImageExtensions,
+				DeclaredTypes: AllowedImageTypes,
+			},
+		},
+		StripImageGPS: true,
+	}
+}
+
+// DefaultFilePolicy returns the policy with the package default size limits
+func DefaultFilePolicy() *FilePolicy {
+	return NewFilePolicy(MaxAudioSize, MaxVideoSize, MaxImageSize)
+}
+
+// ValidatedFile is an upload that passed a FilePolicy. Kind and ContentType come
+// from the file's content. When metadata had to be stripped the cleaned bytes are
+// kept in memory and Open and UploadValidatedFile use them instead of the upload.
+type ValidatedFile struct {
+	Header      *multipart.FileHeader
+	Kind        string
+	ContentType string
+	Size        int64
+	sanitized   []byte
+}
+
+type bytesFile struct {
+	*bytes.Reader
+}
+
+func (bytesFile) Close() error {
+	return nil
+}
+
+// Open returns the validated contents of the file
+func (f *ValidatedFile) Open() (multipart.File, error) {
+	if f.sanitized != nil {
+		return bytesFile{bytes.NewReader(f.sanitized)}, nil
+	}
+	return f.Header.Open()
+}
+
+// Sanitized reports whether metadata was stripped from the upload
+func (f *ValidatedFile) Sanitized() bool {
+	return f.sanitized != nil
+}
+
+// UploadValidatedFile stores a validated upload under path like GCSService.UploadFile,
+// uploading the cleaned bytes when metadata was stripped. It returns the public URL
+// and the generated file name.
+func UploadValidatedFile(ctx context.Context, gcsService GCSService, file *ValidatedFile, path string) (string, string, error) {
+	if file.sanitized == nil {
+		return gcsService.UploadFile(ctx, file.Header, path)
+	}
+
+	uniqueFilename := uuid.New().String() + ExtensionForContentType(file.ContentType)
+	url, _, err := gcsService.UploadFileFromBytes(ctx, file.sanitized, fmt.Sprintf("%s/%s", path, uniqueFilename), file.ContentType)
+	if err != nil {
+		return "", "", err
 	}
+	return url, uniqueFilename, nil
+}
+
+// ValidateMedia accepts an audio, video or image upload
+func (p *FilePolicy) ValidateMedia(file *multipart.FileHeader) (*ValidatedFile, error) {
+	return p.Validate(file, "audio", "video", "image")
+}
 
-	// Check if file size is 0
+// ValidateImage accepts an image upload
+func (p *FilePolicy) ValidateImage(file *multipart.FileHeader) (*ValidatedFile, error) {
+	return p.Validate(file, "image")
+}
+
+// Validate sniffs the upload, checks it is one of kinds, that its declared type and
+// extension agree with its content, that it is within the size limit for its kind
+// and that nothing else is hidden in it. GPS metadata is stripped from images when
+// the policy asks for it. Errors are always *FileValidationError.
+func (p *FilePolicy) Validate(file *multipart.FileHeader, kinds ...string) (*ValidatedFile, error) {
+	if file == nil {
+		return nil, newFileValidationError(FileErrRequired, "File is required", nil)
+	}
 	if file.Size == 0 {
-		return &FileValidationError{
-			Message: "File is empty",
-		}
+		return nil, newFileValidationError(FileErrEmpty, "File is empty", nil)
 	}
 
-	// Get content type and filename
-	contentType := file.Header.Get(ContentTypeHeader)
-	filename := strings.ToLower(file.Filename)
+	src, err := file.Open()
+	if err != nil {
+		return nil, newFileValidationError(FileErrUnreadable, "File could not be read", nil)
+	}
+	defer src.Close()
 
-	// Determine file type and validate size accordingly
-	isValidAudio := isValidAudioFile(contentType, filename)
-	isValidVideo := isValidVideoFile(contentType, filename)
-	isValidImage := isValidImageFile(contentType, filename)
+	header := make([]byte, media.HeaderSize)
+	n, err := io.ReadFull(src, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, newFileValidationError(FileErrUnreadable, "File could not be read", nil)
+	}
+	header = header[:n]
+	detected := mimetype.Detect(header)
 
-	if !isValidAudio && !isValidVideo && !isValidImage {
-		return &FileValidationError{
-			Message: "Invalid file type. Only audio, video, and image files are allowed. Supported formats: mp3, wav, aac, m4a, ogg, flac (audio, max 100MB), mp4, mov, avi, webm (video, max 10MB), and jpg, jpeg, png, gif, webp (image, max 10MB)",
-		}
+	declaredType := file.Header.Get(ContentTypeHeader)
+	if parsed, _, err := mime.ParseMediaType(declaredType); err == nil {
+		declaredType = parsed
+	}
+	declaredType = strings.ToLower(declaredType)
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+
+	kind := p.resolveKind(detected, declaredType, ext, kinds)
+	if kind == "" {
+		return nil, newFileValidationError(FileErrTypeNotAllowed,
+			fmt.Sprintf("File type %s is not allowed. %s", detected.String(), p.describe(kinds)),
+			map[string]interface{}{"detected_type": detected.String(), "allowed_kinds": kinds})
+	}
+	if err := p.checkDeclared(kind, detected, declaredType, ext); err != nil {
+		return nil, err
 	}
 
-	// Check file size based on file type
-	if isValidAudio && file.Size > MaxAudioSize {
-		return &FileValidationError{
-			Message: fmt.Sprintf("Audio file size exceeds maximum allowed size of 100MB. Current size: %.2fMB", float64(file.Size)/(1024*1024)),
-		}
+	rule := p.Rules[kind]
+	if file.Size > rule.MaxSize {
+		return nil, newFileValidationError(FileErrTooLarge,
+			fmt.Sprintf("%s file size exceeds maximum allowed size of %s. Current size: %.2fMB", capitalize(kind), formatMB(rule.MaxSize), float64(file.Size)/(1024*1024)),
+			map[string]interface{}{"kind": kind, "max_size_mb": float64(rule.MaxSize) / (1024 * 1024), "size_mb": float64(file.Size) / (1024 * 1024)})
 	}
 
-	if isValidVideo && file.Size > MaxVideoSize {
-		return &FileValidationError{
-			Message: fmt.Sprintf("Video file size exceeds maximum allowed size of 10MB. Current size: %.2fMB", float64(file.Size)/(1024*1024)),
+	validated := &ValidatedFile{
+		Header:      file,
+		Kind:        kind,
+		ContentType: detected.String(),
+		Size:        file.Size,
+	}
+
+	if kind != "image" {
+		tail, err := readTail(src, file.Size)
+		if err != nil {
+			return nil, newFileValidationError(FileErrUnreadable, "File could not be read", nil)
+		}
+		if err := p.CheckContent(kind, detected.String(), header, tail); err != nil {
+			return nil, err
 		}
+		return validated, nil
 	}
 
-	if isValidImage && file.Size > MaxImageSize {
-		return &FileValidationError{
-			Message: fmt.Sprintf("Image file size exceeds maximum allowed size of 10MB. Current size: %.2fMB", float64(file.Size)/(1024*1024)),
+	// Images are small enough to check and clean in full
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, newFileValidationError(FileErrUnreadable, "File could not be read", nil)
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, newFileValidationError(FileErrUnreadable, "File could not be read", nil)
+	}
+	if err := p.CheckContent(kind, detected.String(), data, media.Trailer(data, detected.String())); err != nil {
+		return nil, err
+	}
+	if p.StripImageGPS {
+		if cleaned, stripped := media.StripGPS(data, detected.String()); stripped {
+			validated.sanitized = cleaned
+			validated.Size = int64(len(cleaned))
 		}
 	}
 
-	return nil
+	return validated, nil
 }
 
-func isValidAudioFile(contentType, filename string) bool {
-	// Check by content type
-	for _, allowedType := range AllowedAudioTypes {
-		if contentType == allowedType {
-			return true
-		}
+// CheckContent rejects files carrying scripts or markup, or a second file appended
+// after the real one. body is the part of the file to scan, tail its last bytes or,
+// for images, whatever follows the image's end marker.
+func (p *FilePolicy) CheckContent(kind, contentType string, body, tail []byte) *FileValidationError {
+	if marker := media.ActiveContent(body, contentType); marker != "" {
+		return polyglotError(kind, contentType, marker)
+	}
+	if len(tail) == 0 {
+		return nil
+	}
+	if marker := media.ActiveContent(tail, ""); marker != "" {
+		return polyglotError(kind, contentType, marker)
 	}
 
-	// Check by extension as fallback
-	for _, ext := range AllowedAudioExtensions {
-		if strings.HasSuffix(filename, ext) {
-			return true
+	if kind == "image" {
+		// Some cameras append their own data after the image; only a recognizable
+		// second file is rejected
+		if hidden := mimetype.Detect(tail); !hidden.Is("application/octet-stream") && !hidden.Is("text/plain") {
+			return polyglotError(kind, contentType, hidden.String())
 		}
+		if bytes.Contains(tail, []byte("PK\x03\x04")) {
+			return polyglotError(kind, contentType, "application/zip")
+		}
+		return nil
 	}
 
-	return false
+	// End of central directory record of an appended ZIP archive
+	if bytes.Contains(tail, []byte("PK\x05\x06")) {
+		return polyglotError(kind, contentType, "application/zip")
+	}
+	return nil
 }
 
-func isValidVideoFile(contentType, filename string) bool {
-	// Check by content type
-	for _, allowedType := range AllowedVideoTypes {
-		if contentType == allowedType {
-			return true
+// MaxSize returns the upload size limit for a media kind, or 0 when the kind is not allowed
+func (p *FilePolicy) MaxSize(kind string) int64 {
+	return p.Rules[kind].MaxSize
+}
+
+// MaxUploadSize returns the largest object clients may upload to objectPath with the
+// given declared content type. The declared type picks the kind when it belongs to one
+// of the object class's upload kinds; otherwise the class's largest limit applies.
+// It returns 0 when the class accepts no uploads.
+func (p *FilePolicy) MaxUploadSize(objectPath, contentType string) int64 {
+	kinds := ClassifyObject(objectPath).UploadKinds
+	var largest int64
+	for _, kind := range kinds {
+		rule, ok := p.Rules[kind]
+		if !ok {
+			continue
+		}
+		if containsString(rule.DeclaredTypes, contentType) {
+			return rule.MaxSize
+		}
+		if rule.MaxSize > largest {
+			largest = rule.MaxSize
 		}
 	}
+	return largest
+}
 
-	// Check by extension as fallback
-	for _, ext := range AllowedVideoExtensions {
-		if strings.HasSuffix(filename, ext) {
-			return true
+// resolveKind returns the allowed kind the sniffed content belongs to, preferring
+// the kind the client declared when the content fits several
+func (p *FilePolicy) resolveKind(detected *mimetype.MIME, declaredType, ext string, kinds []string) string {
+	var candidates []string
+	for _, kind := range mediaKinds {
+		rule, ok := p.Rules[kind]
+		if !ok || !containsString(kinds, kind) {
+			continue
 		}
+		for _, contentType := range rule.ContentTypes {
+			if detected.Is(contentType) {
+				candidates = append(candidates, kind)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
 	}
 
-	return false
+	for _, kind := range candidates {
+		rule := p.Rules[kind]
+		if containsString(rule.DeclaredTypes, declaredType) || containsString(rule.Extensions, ext) {
+			return kind
+		}
+	}
+	return candidates[0]
 }
 
-func GetMediaType(file *multipart.FileHeader) string {
-	contentType := file.Header.Get(ContentTypeHeader)
-	filename := strings.ToLower(file.Filename)
-
-	if isValidAudioFile(contentType, filename) {
-		return "audio"
+// checkDeclared rejects uploads whose Content-Type header or extension names a
+// different kind of file than their content
+func (p *FilePolicy) checkDeclared(kind string, detected *mimetype.MIME, declaredType, ext string) *FileValidationError {
+	rule := p.Rules[kind]
+	params := map[string]interface{}{
+		"detected_type": detected.String(),
+		"declared_type": declaredType,
+		"extension":     ext,
 	}
 
-	if isValidVideoFile(contentType, filename) {
-		return "video"
+	if declaredType != "" && declaredType != "application/octet-stream" && !containsString(rule.DeclaredTypes, declaredType) {
+		return newFileValidationError(FileErrTypeMismatch,
+			fmt.Sprintf("File content is %s but it was uploaded as %s", detected.String(), declaredType), params)
+	}
+	if ext != "" && !containsString(rule.Extensions, ext) {
+		return newFileValidationError(FileErrTypeMismatch,
+			fmt.Sprintf("File content is %s but its name ends in %s", detected.String(), ext), params)
 	}
+	return nil
+}
 
-	if isValidImageFile(contentType, filename) {
-		return "image"
+// describe lists the accepted formats and limits for kinds
+func (p *FilePolicy) describe(kinds []string) string {
+	var parts []string
+	for _, kind := range mediaKinds {
+		rule, ok := p.Rules[kind]
+		if !ok || !containsString(kinds, kind) {
+			continue
+		}
+		formats := make([]string, 0, len(rule.Extensions))
+		for _, ext := range rule.Extensions {
+			formats = append(formats, strings.TrimPrefix(ext, "."))
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s, max %s)", kind, strings.Join(formats, ", "), formatMB(rule.MaxSize)))
 	}
+	return "Supported formats: " + strings.Join(parts, "; ")
+}
 
-	return "unknown"
+func polyglotError(kind, contentType, marker string) *FileValidationError {
+	return newFileValidationError(FileErrPolyglot,
+		fmt.Sprintf("File contains content that is not a valid %s", kind),
+		map[string]interface{}{"detected_type": contentType, "found": marker})
 }
 
-func ValidateImageFile(file *multipart.FileHeader) error {
-	if file == nil {
-		return &FileValidationError{
-			Message: "Image file is required",
-		}
+func readTail(src io.ReadSeeker, size int64) ([]byte, error) {
+	offset := size - FileTailSize
+	if offset < media.HeaderSize {
+		offset = media.HeaderSize
+	}
+	if offset >= size {
+		return nil, nil
 	}
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(src)
+}
 
-	// Check file size
-	if file.Size > MaxImageSize {
-		return &FileValidationError{
-			Message: fmt.Sprintf("Image file size exceeds maximum allowed size of 10MB. Current size: %.2fMB", float64(file.Size)/(1024*1024)),
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
+	return false
+}
 
-	// Check if file size is 0
-	if file.Size == 0 {
-		return &FileValidationError{
-			Message: "File is empty",
-		}
+func capitalize(s string) string {
+	if s == "" {
+		return s
 	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
 
-	// Get content type
-	contentType := file.Header.Get(ContentTypeHeader)
+func formatMB(size int64) string {
+	return fmt.Sprintf("%gMB", float64(size)/(1024*1024))
+}
 
-	// Get file extension
-	filename := strings.ToLower(file.Filename)
+// GetMediaTypeFromContentType classifies a declared content type as audio, video or image
+func GetMediaTypeFromContentType(contentType string) string {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
 
-	// Check if it's a valid image file
-	if !isValidImageFile(contentType, filename) {
-		return &FileValidationError{
-			Message: "Invalid file type. Only image files are allowed. Supported formats: jpg, jpeg, png, gif, webp, svg, bmp, ico",
+	for _, allowedType := range AllowedAudioTypes {
+		if contentType == allowedType {
+			return "audio"
 		}
 	}
-
-	return nil
-}
-
-func isValidImageFile(contentType, filename string) bool {
-	// Check by content type
-	for _, allowedType := range AllowedImageTypes {
+	for _, allowedType := range AllowedVideoTypes {
 		if contentType == allowedType {
-			return true
+			return "video"
 		}
 	}
-
-	// Check by extension as fallback
-	for _, ext := range AllowedImageExtensions {
-		if strings.HasSuffix(filename, ext) {
-			return true
+	for _, allowedType := range AllowedImageTypes {
+		if contentType == allowedType {
+			return "image"
 		}
 	}
 
-	return false
+	return "unknown"
 }
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileHeader(t *testing.T, filename, contentType string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	if contentType != "" {
+		header.Set(ContentTypeHeader, contentType)
+	}
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(32 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+	return buf.Bytes()
+}
+
+func testWAV() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+800))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(buf, binary.LittleEndian, []uint32{16})
+	binary.Write(buf, binary.LittleEndian, []uint16{1, 1})
+	binary.Write(buf, binary.LittleEndian, []uint32{8000, 8000})
+	binary.Write(buf, binary.LittleEndian, []uint16{1, 8})
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(800))
+	buf.Write(make([]byte, 800))
+	return buf.Bytes()
+}
+
+func assertFileValidationCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var validationErr *FileValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, code, validationErr.Code, validationErr.Message)
+}
+
+func TestFilePolicy_ClassifiesByContent(t *testing.T) {
+	policy := DefaultFilePolicy()
+
+	image, err := policy.ValidateMedia(newTestFileHeader(t, "photo.png", "image/png", testPNG(t)))
+	require.NoError(t, err)
+	assert.Equal(t, "image", image.Kind)
+	assert.Equal(t, "image/png", image.ContentType)
+
+	// Clients often send uploads without a useful Content-Type
+	audio, err := policy.ValidateMedia(newTestFileHeader(t, "voice.wav", "application/octet-stream", testWAV()))
+	require.NoError(t, err)
+	assert.Equal(t, "audio", audio.Kind)
+}
+
+func TestFilePolicy_RejectsDisguisedFiles(t *testing.T) {
+	policy := DefaultFilePolicy()
+	html := []byte("<!DOCTYPE html><html><body><script>alert(1)</script></body></html>")
+
+	_, err := policy.ValidateImage(newTestFileHeader(t, "photo.png", "image/png", html))
+	assertFileValidationCode(t, err, FileErrTypeNotAllowed)
+
+	_, err = policy.ValidateImage(newTestFileHeader(t, "photo.html", "image/png", testPNG(t)))
+	assertFileValidationCode(t, err, FileErrTypeMismatch)
+
+	_, err = policy.ValidateImage(newTestFileHeader(t, "photo.png", "text/html", testPNG(t)))
+	assertFileValidationCode(t, err, FileErrTypeMismatch)
+
+	_, err = policy.ValidateImage(newTestFileHeader(t, "voice.wav", "audio/wav", testWAV()))
+	assertFileValidationCode(t, err, FileErrTypeNotAllowed)
+}
+
+func TestFilePolicy_RejectsPolyglots(t *testing.T) {
+	policy := DefaultFilePolicy()
+
+	withZip := append(testPNG(t), []byte("PK\x03\x04\x14\x00\x00\x00payload.php")...)
+	_, err := policy.ValidateImage(newTestFileHeader(t, "photo.png", "image/png", withZip))
+	assertFileValidationCode(t, err, FileErrPolyglot)
+
+	withScript := append(testPNG(t), []byte("<?php system($_GET['c']); ?>")...)
+	_, err = policy.ValidateImage(newTestFileHeader(t, "photo.png", "image/png", withScript))
+	assertFileValidationCode(t, err, FileErrPolyglot)
+}
+
+func TestFilePolicy_Limits(t *testing.T) {
+	policy := NewFilePolicy(MaxAudioSize, MaxVideoSize, 64)
+
+	_, err := policy.ValidateImage(newTestFileHeader(t, "photo.png", "image/png", append(testPNG(t), make([]byte, 64)...)))
+	assertFileValidationCode(t, err, FileErrTooLarge)
+
+	_, err = policy.ValidateImage(newTestFileHeader(t, "photo.png", "image/png", nil))
+	assertFileValidationCode(t, err, FileErrEmpty)
+
+	_, err = policy.ValidateImage(nil)
+	assertFileValidationCode(t, err, FileErrRequired)
+}
+
+func TestFilePolicy_MaxUploadSize(t *testing.T) {
+	policy := NewFilePolicy(10, 20, 30)
+
+	assert.Equal(t, int64(20), policy.MaxUploadSize("thunder-seat/u/week-1/clip.mp4", "video/mp4"))
+	assert.Equal(t, int64(10), policy.MaxUploadSize("thunder-seat/u/week-1/clip.mp3", "audio/mpeg"))
+	assert.Equal(t, int64(30), policy.MaxUploadSize("thunder-seat/u/week-1/clip.bin", "application/octet-stream"))
+	assert.Equal(t, int64(30), policy.MaxUploadSize("profile-photos/u/photo.mp4", "video/mp4"))
+	assert.Equal(t, int64(0), policy.MaxUploadSize("other/file.png", "image/png"))
+}
+
+func TestValidatedFile_OpenReturnsUpload(t *testing.T) {
+	content := testPNG(t)
+	validated, err := DefaultFilePolicy().ValidateImage(newTestFileHeader(t, "photo.png", "image/png", content))
+	require.NoError(t, err)
+	assert.False(t, validated.Sanitized())
+
+	src, err := validated.Open()
+	require.NoError(t, err)
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+}
@@ -8,12 +8,14 @@ import (
 	"mime"
 	"mime/multipart"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -23,8 +25,65 @@ type GCSService interface {
 	UploadFileFromReader(ctx context.Context, file io.ReadCloser, folder string) (string, string, error)
 	UploadFileFromBytes(ctx context.Context, data []byte, path string, contentType string) (string, string, error)
 	GetFileSignedURL(ctx context.Context, objectPath string, expiry time.Duration) (string, error)
+	// GetSignedUploadURL signs a direct upload of at most maxSize bytes; storage
+	// rejects larger uploads as long as the client sends the returned headers
+	GetSignedUploadURL(ctx context.Context, objectPath string, contentType string, maxSize int64, expiry time.Duration) (*SignedUpload, error)
+	GetObjectInfo(ctx context.Context, objectPath string) (*ObjectInfo, error)
+	ReadObjectRange(ctx context.Context, objectPath string, offset, length int64) ([]byte, error)
+	// NewObjectReader streams a whole object; the caller closes the reader
+	NewObjectReader(ctx context.Context, objectPath string) (io.ReadCloser, error)
 	ReadFileContent(ctx context.Context, objectPath string) (string, error)
+	// ListObjects returns the objects whose path starts with prefix, without MD5
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	SetCacheControl(ctx context.Context, objectPath string, cacheControl string) error
 	GetPublicURL(objectPath string) string
+	// ObjectPathFromURL reports the object path of a public URL of this storage
+	ObjectPathFromURL(fileURL string) (string, bool)
+}
+
+// ErrObjectNotFound is returned when the requested object does not exist in the bucket
+var ErrObjectNotFound = errors.New("storage object not found")
+
+// ContentLengthRange formats the ContentLengthRangeHeader value allowing up to maxSize bytes
+func ContentLengthRange(maxSize int64) (string, error) {
+	if maxSize <= 0 {
+		return "", fmt.Errorf("signed uploads need a positive size limit, got %d", maxSize)
+	}
+	return fmt.Sprintf("0,%d", maxSize), nil
+}
+
+// parseContentLengthRange returns the maximum size in a ContentLengthRangeHeader value
+func parseContentLengthRange(value string) (int64, bool) {
+	minPart, maxPart, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, false
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(minPart), 10, 64); err != nil {
+		return 0, false
+	}
+	maxSize, err := strconv.ParseInt(strings.TrimSpace(maxPart), 10, 64)
+	if err != nil || maxSize <= 0 {
+		return 0, false
+	}
+	return maxSize, true
+}
+
+// SignedUpload describes how a client uploads an object directly to storage
+type SignedUpload struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	Resumable bool
+	ExpiresAt time.Time
+}
+
+// ObjectInfo holds the stored attributes of an object
+type ObjectInfo struct {
+	Path        string
+	Size        int64
+	ContentType string
+	MD5         []byte
+	CreatedOn   time.Time
 }
 
 type gcsService struct {
@@ -139,7 +198,7 @@ func (s *gcsService) UploadFile(ctx context.Context, file *multipart.FileHeader,
 	writer.Metadata = map[string]string{
 		"original-filename": file.Filename,
 	}
-	writer.CacheControl = "public, max-age=86400"
+	writer.CacheControl = CacheControlFor(gcsObjectPath)
 	// Note: ACL is not set here because bucket uses Uniform Bucket-Level Access
 	// Public access is controlled via bucket-level IAM policy
 
@@ -163,7 +222,7 @@ func (s *gcsService) UploadFileFromReader(ctx context.Context, file io.ReadClose
 
 	writer := s.client.Bucket(s.bucketName).Object(filename).NewWriter(ctx)
 	writer.ContentType = "application/octet-stream"
-	writer.CacheControl = "public, max-age=86400"
+	writer.CacheControl = CacheControlFor(filename)
 	// Note: ACL is not set here because bucket uses Uniform Bucket-Level Access
 	// Public access is controlled via bucket-level IAM policy
 
@@ -182,7 +241,7 @@ func (s *gcsService) UploadFileFromReader(ctx context.Context, file io.ReadClose
 func (s *gcsService) UploadFileFromBytes(ctx context.Context, data []byte, path string, contentType string) (string, string, error) {
 	writer := s.client.Bucket(s.bucketName).Object(path).NewWriter(ctx)
 	writer.ContentType = contentType
-	writer.CacheControl = "public, max-age=86400"
+	writer.CacheControl = CacheControlFor(path)
 	// Note: ACL is not set here because bucket uses Uniform Bucket-Level Access
 	// Public access is controlled via bucket-level IAM policy
 
@@ -207,6 +266,9 @@ func (s *gcsService) DeleteFile(ctx context.Context, fileURL string) error {
 
 	object := s.client.Bucket(s.bucketName).Object(key)
 	if err := object.Delete(ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = ErrObjectNotFound
+		}
 		return fmt.Errorf("failed to delete GCS object: %w", err)
 	}
 
@@ -226,6 +288,91 @@ func (s *gcsService) GetFileSignedURL(ctx context.Context, objectPath string, ex
 	return url, nil
 }
 
+// GetSignedUploadURL returns a V4 signed URL that starts a resumable upload session.
+// The client POSTs to it with the returned headers and then uploads the bytes in
+// chunks to the session URI GCS returns in the Location header. The signed
+// content length range makes the bucket refuse uploads larger than maxSize.
+func (s *gcsService) GetSignedUploadURL(ctx context.Context, objectPath string, contentType string, maxSize int64, expiry time.Duration) (*SignedUpload, error) {
+	lengthRange, err := ContentLengthRange(maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	opts := &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      "POST",
+		ContentType: contentType,
+		Headers: []string{
+			"x-goog-resumable:start",
+			ContentLengthRangeHeader + ":" + lengthRange,
+		},
+		Expires: expiresAt,
+	}
+	url, err := s.client.Bucket(s.bucketName).SignedURL(objectPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signed upload URL: %w", err)
+	}
+
+	return &SignedUpload{
+		URL:    url,
+		Method: "POST",
+		Headers: map[string]string{
+			ContentTypeHeader:        contentType,
+			"x-goog-resumable":       "start",
+			ContentLengthRangeHeader: lengthRange,
+		},
+		Resumable: true,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *gcsService) GetObjectInfo(ctx context.Context, objectPath string) (*ObjectInfo, error) {
+	attrs, err := s.client.Bucket(s.bucketName).Object(objectPath).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get GCS object attributes: %w", err)
+	}
+
+	return &ObjectInfo{
+		Path:        objectPath,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		MD5:         attrs.MD5,
+		CreatedOn:   attrs.Created,
+	}, nil
+}
+
+func (s *gcsService) ReadObjectRange(ctx context.Context, objectPath string, offset, length int64) ([]byte, error) {
+	reader, err := s.client.Bucket(s.bucketName).Object(objectPath).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to create range reader for GCS object: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GCS object range: %w", err)
+	}
+	return data, nil
+}
+
+func (s *gcsService) NewObjectReader(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	reader, err := s.client.Bucket(s.bucketName).Object(objectPath).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to create reader for GCS object: %w", err)
+	}
+	return reader, nil
+}
+
 func (s *gcsService) ReadFileContent(ctx context.Context, objectPath string) (string, error) {
 	if strings.HasPrefix(objectPath, "https://storage.googleapis.com/") {
 		prefix := fmt.Sprintf("https://storage.googleapis.com/%s/", s.bucketName)
@@ -235,6 +382,9 @@ func (s *gcsService) ReadFileContent(ctx context.Context, objectPath string) (st
 	object := s.client.Bucket(s.bucketName).Object(objectPath)
 	reader, err := object.NewReader(ctx)
 	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return "", ErrObjectNotFound
+		}
 		return "", fmt.Errorf("failed to create reader for GCS object: %w", err)
 	}
 	defer reader.Close()
@@ -247,11 +397,58 @@ func (s *gcsService) ReadFileContent(ctx context.Context, objectPath string) (st
 	return string(content), nil
 }
 
+func (s *gcsService) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	it := s.client.Bucket(s.bucketName).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var objects []ObjectInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCS objects: %w", err)
+		}
+		objects = append(objects, ObjectInfo{
+			Path:        attrs.Name,
+			Size:        attrs.Size,
+			ContentType: attrs.ContentType,
+			CreatedOn:   attrs.Created,
+		})
+	}
+	return objects, nil
+}
+
+func (s *gcsService) SetCacheControl(ctx context.Context, objectPath string, cacheControl string) error {
+	_, err := s.client.Bucket(s.bucketName).Object(objectPath).Update(ctx, storage.ObjectAttrsToUpdate{
+		CacheControl: cacheControl,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return ErrObjectNotFound
+		}
+		return fmt.Errorf("failed to update GCS object cache control: %w", err)
+	}
+	return nil
+}
+
 func (s *gcsService) GetPublicURL(objectPath string) string {
 	// objectPath should be the full path (e.g., "avatars/{userID}/{filename}")
 	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.bucketName, objectPath)
 }
 
+func (s *gcsService) ObjectPathFromURL(fileURL string) (string, bool) {
+	prefix := fmt.Sprintf("https://storage.googleapis.com/%s/", s.bucketName)
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+	objectPath := strings.TrimPrefix(fileURL, prefix)
+	if i := strings.IndexByte(objectPath, '?'); i >= 0 {
+		objectPath = objectPath[:i]
+	}
+	return objectPath, true
+}
+
 // getContentTypeFromExtension returns the MIME type for common file extensions
 // that might not be detected by mime.TypeByExtension
 func getContentTypeFromExtension(ext string) string {
@@ -348,6 +545,11 @@ func isValidContentTypeForExtension(contentType, ext string) bool {
 	return contentType != "application/octet-stream"
 }
 
+// ExtensionForContentType returns the file extension used for objects of the given content type
+func ExtensionForContentType(contentType string) string {
+	return getExtensionFromContentType(contentType)
+}
+
 // getExtensionFromContentType returns the file extension based on Content-Type
 func getExtensionFromContentType(contentType string) string {
 	contentType = strings.ToLower(contentType)
@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const localStorageMetaDir = ".meta"
+
+var _ GCSService = (*LocalStorageService)(nil)
+
+// LocalStorageService is a GCSService backed by a directory on disk. Signed URLs
+// are HMAC signed and point at baseURL, which should be routed to the service's
+// ServeHTTP. It is meant for local development and tests, not production traffic.
+type LocalStorageService struct {
+	*objectStore
+}
+
+func NewLocalStorageService(baseDir, baseURL, signingKey string) (*LocalStorageService, error) {
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve local storage directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(absDir, localStorageMetaDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+
+	store, err := newObjectStore(&diskBackend{baseDir: absDir}, baseURL, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStorageService{objectStore: store}, nil
+}
+
+// diskBackend stores each object as a file under baseDir and its content type
+// under baseDir/.meta
+type diskBackend struct {
+	baseDir string
+}
+
+func (b *diskBackend) put(objectPath, contentType string, r io.Reader) (int64, error) {
+	objectFile, metaFile, err := b.objectFiles(objectPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(objectFile), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create local object directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(metaFile), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create local metadata directory: %w", err)
+	}
+
+	f, err := os.Create(objectFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create local object: %w", err)
+	}
+	written, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a truncated object behind
+		_ = os.Remove(objectFile)
+		return 0, fmt.Errorf("failed to write local object: %w", err)
+	}
+
+	if err := os.WriteFile(metaFile, []byte(contentType), 0o644); err != nil {
+		return 0, fmt.Errorf("failed to write local object metadata: %w", err)
+	}
+
+	return written, nil
+}
+
+func (b *diskBackend) get(objectPath string) (io.ReadSeekCloser, *ObjectInfo, error) {
+	objectFile, metaFile, err := b.objectFiles(objectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(objectFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrObjectNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open local object: %w", err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("failed to stat local object: %w", err)
+	}
+	if stat.IsDir() {
+		f.Close()
+		return nil, nil, ErrObjectNotFound
+	}
+
+	contentType := "application/octet-stream"
+	if meta, err := os.ReadFile(metaFile); err == nil && len(meta) > 0 {
+		contentType = string(meta)
+	}
+
+	return f, &ObjectInfo{
+		Size:        stat.Size(),
+		ContentType: contentType,
+		CreatedOn:   stat.ModTime(),
+	}, nil
+}
+
+func (b *diskBackend) remove(objectPath string) error {
+	objectFile, metaFile, err := b.objectFiles(objectPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(objectFile); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+		return err
+	}
+	if err := os.Remove(metaFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (b *diskBackend) list(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := filepath.WalkDir(b.baseDir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(b.baseDir, file)
+		if err != nil {
+			return err
+		}
+		objectPath := filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if objectPath == localStorageMetaDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(objectPath, prefix) {
+			return nil
+		}
+
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		contentType := "application/octet-stream"
+		if meta, err := os.ReadFile(filepath.Join(b.baseDir, localStorageMetaDir, rel)); err == nil && len(meta) > 0 {
+			contentType = string(meta)
+		}
+		objects = append(objects, ObjectInfo{
+			Path:        objectPath,
+			Size:        stat.Size(),
+			ContentType: contentType,
+			CreatedOn:   stat.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local objects: %w", err)
+	}
+	sortObjects(objects)
+	return objects, nil
+}
+
+// objectFiles maps an object path to its data and metadata files. The metadata
+// directory itself is not addressable as an object.
+func (b *diskBackend) objectFiles(objectPath string) (string, string, error) {
+	cleaned := filepath.FromSlash(objectPath)
+	if cleaned == localStorageMetaDir || strings.HasPrefix(cleaned, localStorageMetaDir+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("invalid object path %q", objectPath)
+	}
+
+	return filepath.Join(b.baseDir, cleaned), filepath.Join(b.baseDir, localStorageMetaDir, cleaned), nil
+}
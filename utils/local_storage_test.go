@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"context"
+	"crypto/md5"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalStorageService {
+	t.Helper()
+	storage, err := NewLocalStorageService(t.TempDir(), "http://localhost:8080/storage", "test-signing-key")
+	require.NoError(t, err)
+	return storage
+}
+
+func TestLocalStorage_SignedUploadURL(t *testing.T) {
+	storage := newTestLocalStorage(t)
+	objectPath := "thunder-seat/user-1/week-1/clip.mp4"
+
+	upload, err := storage.GetSignedUploadURL(context.Background(), objectPath, "video/mp4", 1024, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "PUT", upload.Method)
+	assert.Equal(t, "video/mp4", upload.Headers[ContentTypeHeader])
+	assert.Equal(t, "0,1024", upload.Headers[ContentLengthRangeHeader])
+
+	parsed, err := url.Parse(upload.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/storage/"+objectPath, parsed.Path)
+
+	signed := signedHeaders("video/mp4", "0,1024")
+	assert.NoError(t, storage.VerifySignedURL("PUT", objectPath, signed, parsed.Query()))
+	assert.ErrorIs(t, storage.VerifySignedURL("PUT", objectPath, signedHeaders("audio/mpeg", "0,1024"), parsed.Query()), ErrInvalidSignature)
+	assert.ErrorIs(t, storage.VerifySignedURL("PUT", objectPath, signedHeaders("video/mp4", "0,999999"), parsed.Query()), ErrInvalidSignature)
+	assert.ErrorIs(t, storage.VerifySignedURL("PUT", objectPath, signedHeaders("video/mp4", ""), parsed.Query()), ErrInvalidSignature)
+	assert.ErrorIs(t, storage.VerifySignedURL("GET", objectPath, signed, parsed.Query()), ErrInvalidSignature)
+	assert.ErrorIs(t, storage.VerifySignedURL("PUT", "thunder-seat/other.mp4", signed, parsed.Query()), ErrInvalidSignature)
+}
+
+func signedHeaders(contentType, lengthRange string) http.Header {
+	header := http.Header{}
+	header.Set(ContentTypeHeader, contentType)
+	if lengthRange != "" {
+		header.Set(ContentLengthRangeHeader, lengthRange)
+	}
+	return header
+}
+
+func TestLocalStorage_SignedUploadRejectsOversizedBody(t *testing.T) {
+	storage := newTestLocalStorage(t)
+	objectPath := "thunder-seat/user-1/week-1/clip.mp4"
+
+	upload, err := storage.GetSignedUploadURL(context.Background(), objectPath, "video/mp4", 16, time.Hour)
+	require.NoError(t, err)
+	parsed, err := url.Parse(upload.URL)
+	require.NoError(t, err)
+
+	put := func(body io.Reader) int {
+		req := httptest.NewRequest(http.MethodPut, "/"+objectPath+"?"+parsed.RawQuery, body)
+		for name, value := range upload.Headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		storage.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, put(strings.NewReader(strings.Repeat("x", 17))))
+	// A body without a declared length is cut off while it streams
+	assert.Equal(t, http.StatusRequestEntityTooLarge, put(io.MultiReader(strings.NewReader(strings.Repeat("x", 17)))))
+	_, err = storage.GetObjectInfo(context.Background(), objectPath)
+	assert.ErrorIs(t, err, ErrObjectNotFound, "an oversized upload must not leave a partial object")
+
+	assert.Equal(t, http.StatusOK, put(strings.NewReader(strings.Repeat("x", 16))))
+}
+
+func TestLocalStorage_SignedURLExpired(t *testing.T) {
+	storage := newTestLocalStorage(t)
+
+	signedURL, err := storage.GetFileSignedURL(context.Background(), "avatars/a.png", -time.Minute)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(signedURL)
+	require.NoError(t, err)
+	assert.ErrorIs(t, storage.VerifySignedURL("GET", "avatars/a.png", nil, parsed.Query()), ErrSignedURLExpired)
+}
+
+func TestLocalStorage_ObjectInfoAndRange(t *testing.T) {
+	storage := newTestLocalStorage(t)
+	ctx := context.Background()
+	content := "hello direct upload"
+
+	_, err := storage.GetObjectInfo(ctx, "uploads/missing.txt")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+
+	written, err := storage.WriteObject("uploads/hello.txt", "text/plain", strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), written)
+
+	info, err := storage.GetObjectInfo(ctx, "uploads/hello.txt")
+	require.NoError(t, err)
+	expectedMD5 := md5.Sum([]byte(content))
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, expectedMD5[:], info.MD5)
+
+	data, err := storage.ReadObjectRange(ctx, "uploads/hello.txt", 6, 6)
+	require.NoError(t, err)
+	assert.Equal(t, "direct", string(data))
+
+	require.NoError(t, storage.DeleteFile(ctx, storage.GetPublicURL("uploads/hello.txt")))
+	_, err = storage.GetObjectInfo(ctx, "uploads/hello.txt")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
+
+func TestLocalStorage_RejectsEscapingPaths(t *testing.T) {
+	storage := newTestLocalStorage(t)
+
+	for _, objectPath := range []string{"../secret.txt", "a/../../secret.txt", ".meta/a.txt"} {
+		_, err := storage.WriteObject(objectPath, "text/plain", strings.NewReader("x"))
+		assert.Error(t, err, objectPath)
+	}
+}
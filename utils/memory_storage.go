@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ GCSService = (*MemoryStorageService)(nil)
+
+// MemoryStorageService is a GCSService that keeps objects in memory. Signed URLs
+// work like LocalStorageService's when baseURL is routed to ServeHTTP. Objects are
+// lost when the process exits, which makes it suited to tests and demos.
+type MemoryStorageService struct {
+	*objectStore
+}
+
+func NewMemoryStorageService(baseURL, signingKey string) (*MemoryStorageService, error) {
+	backend := &memoryBackend{objects: make(map[string]memoryObject)}
+	store, err := newObjectStore(backend, baseURL, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStorageService{objectStore: store}, nil
+}
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	createdOn   time.Time
+}
+
+type memoryBackend struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func (b *memoryBackend) put(objectPath, contentType string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read object data: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[objectPath] = memoryObject{
+		data:        data,
+		contentType: contentType,
+		createdOn:   time.Now(),
+	}
+	return int64(len(data)), nil
+}
+
+func (b *memoryBackend) get(objectPath string) (io.ReadSeekCloser, *ObjectInfo, error) {
+	b.mu.RLock()
+	object, ok := b.objects[objectPath]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrObjectNotFound
+	}
+
+	contentType := object.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	// Stored data is never modified in place, so readers can share it
+	return bytesFile{bytes.NewReader(object.data)}, &ObjectInfo{
+		Size:        int64(len(object.data)),
+		ContentType: contentType,
+		CreatedOn:   object.createdOn,
+	}, nil
+}
+
+func (b *memoryBackend) list(prefix string) ([]ObjectInfo, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var objects []ObjectInfo
+	for objectPath, object := range b.objects {
+		if !strings.HasPrefix(objectPath, prefix) {
+			continue
+		}
+		objects = append(objects, ObjectInfo{
+			Path:        objectPath,
+			Size:        int64(len(object.data)),
+			ContentType: object.contentType,
+			CreatedOn:   object.createdOn,
+		})
+	}
+	sortObjects(objects)
+	return objects, nil
+}
+
+func (b *memoryBackend) remove(objectPath string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.objects[objectPath]; !ok {
+		return ErrObjectNotFound
+	}
+	delete(b.objects, objectPath)
+	return nil
+}
@@ -0,0 +1,380 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidSignature is returned when a local signed URL does not match its signature
+	ErrInvalidSignature = errors.New("invalid storage URL signature")
+	// ErrSignedURLExpired is returned when a local signed URL is used after it expired
+	ErrSignedURLExpired = errors.New("storage URL has expired")
+)
+
+// objectBackend keeps the bytes for an objectStore. Object paths handed to a
+// backend have already been cleaned and checked not to escape the store.
+type objectBackend interface {
+	put(objectPath, contentType string, r io.Reader) (int64, error)
+	// get returns ErrObjectNotFound when the object does not exist. The returned
+	// info has no MD5; objectStore computes it when asked.
+	get(objectPath string) (io.ReadSeekCloser, *ObjectInfo, error)
+	remove(objectPath string) error
+	// list returns the objects under prefix sorted by path, without MD5
+	list(prefix string) ([]ObjectInfo, error)
+}
+
+// objectStore implements GCSService on top of an objectBackend. Signed URLs are
+// HMAC signed and point at baseURL, which is expected to be served by the store's
+// ServeHTTP so that clients can use them like GCS signed URLs.
+type objectStore struct {
+	backend    objectBackend
+	baseURL    string
+	signingKey []byte
+}
+
+func newObjectStore(backend objectBackend, baseURL, signingKey string) (*objectStore, error) {
+	if signingKey == "" {
+		return nil, fmt.Errorf("signing key is required for local storage")
+	}
+
+	return &objectStore{
+		backend:    backend,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: []byte(signingKey),
+	}, nil
+}
+
+func (s *objectStore) UploadFile(ctx context.Context, file *multipart.FileHeader, path string) (string, string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	contentType := file.Header.Get(ContentTypeHeader)
+	if ext == "" && contentType != "" {
+		ext = getExtensionFromContentType(contentType)
+	}
+	if contentType == "" || contentType == "application/octet-stream" || !isValidContentTypeForExtension(contentType, ext) {
+		if detectedType := mime.TypeByExtension(ext); detectedType != "" {
+			contentType = detectedType
+		} else {
+			contentType = getContentTypeFromExtension(ext)
+		}
+	}
+
+	uniqueFilename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
+	objectPath := fmt.Sprintf("%s/%s", path, uniqueFilename)
+
+	if _, err := s.WriteObject(objectPath, contentType, src); err != nil {
+		return "", "", err
+	}
+
+	return s.GetPublicURL(objectPath), uniqueFilename, nil
+}
+
+func (s *objectStore) UploadFileFromReader(ctx context.Context, file io.ReadCloser, path string) (string, string, error) {
+	defer file.Close()
+
+	objectPath := fmt.Sprintf("%s/%s", path, uuid.New().String())
+	if _, err := s.WriteObject(objectPath, "application/octet-stream", file); err != nil {
+		return "", "", err
+	}
+
+	return s.GetPublicURL(objectPath), objectPath, nil
+}
+
+func (s *objectStore) UploadFileFromBytes(ctx context.Context, data []byte, path string, contentType string) (string, string, error) {
+	if _, err := s.WriteObject(path, contentType, bytes.NewReader(data)); err != nil {
+		return "", "", err
+	}
+
+	return s.GetPublicURL(path), path, nil
+}
+
+func (s *objectStore) DeleteFile(ctx context.Context, fileURL string) error {
+	prefix := s.baseURL + "/"
+	if !strings.HasPrefix(fileURL, prefix) {
+		return fmt.Errorf("invalid local storage URL format")
+	}
+
+	objectPath, err := cleanObjectPath(strings.TrimPrefix(fileURL, prefix))
+	if err != nil {
+		return err
+	}
+	if err := s.backend.remove(objectPath); err != nil {
+		return fmt.Errorf("failed to delete local object: %w", err)
+	}
+	return nil
+}
+
+func (s *objectStore) GetFileSignedURL(ctx context.Context, objectPath string, expiry time.Duration) (string, error) {
+	return s.signURL("GET", objectPath, "", "", time.Now().Add(expiry)), nil
+}
+
+// GetSignedUploadURL returns a signed URL accepting a single PUT of the whole object.
+// Like GCS, the content length range is a signed header the PUT has to carry.
+func (s *objectStore) GetSignedUploadURL(ctx context.Context, objectPath string, contentType string, maxSize int64, expiry time.Duration) (*SignedUpload, error) {
+	if _, err := cleanObjectPath(objectPath); err != nil {
+		return nil, err
+	}
+	lengthRange, err := ContentLengthRange(maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	return &SignedUpload{
+		URL:    s.signURL("PUT", objectPath, contentType, lengthRange, expiresAt),
+		Method: "PUT",
+		Headers: map[string]string{
+			ContentTypeHeader:        contentType,
+			ContentLengthRangeHeader: lengthRange,
+		},
+		Resumable: false,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *objectStore) GetObjectInfo(ctx context.Context, objectPath string) (*ObjectInfo, error) {
+	reader, info, err := s.openObject(objectPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return nil, fmt.Errorf("failed to hash local object: %w", err)
+	}
+	info.MD5 = hash.Sum(nil)
+	return info, nil
+}
+
+// ReadObjectRange reads length bytes starting at offset; a negative length reads to the end
+func (s *objectStore) ReadObjectRange(ctx context.Context, objectPath string, offset, length int64) ([]byte, error) {
+	reader, err := s.OpenObject(objectPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if _, err := reader.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek local object: %w", err)
+	}
+
+	var src io.Reader = reader
+	if length >= 0 {
+		src = io.LimitReader(reader, length)
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local object range: %w", err)
+	}
+	return data, nil
+}
+
+func (s *objectStore) NewObjectReader(ctx context.Context, objectPath string) (io.ReadCloser, error) {
+	return s.OpenObject(objectPath)
+}
+
+// ReadFileContent reads a whole object given its path or public URL
+func (s *objectStore) ReadFileContent(ctx context.Context, objectPath string) (string, error) {
+	objectPath = strings.TrimPrefix(objectPath, s.baseURL+"/")
+
+	data, err := s.ReadObjectRange(ctx, objectPath, 0, -1)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *objectStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	return s.backend.list(strings.TrimPrefix(prefix, "/"))
+}
+
+// SetCacheControl only checks the object exists; ServeHTTP derives Cache-Control
+// from the object's class
+func (s *objectStore) SetCacheControl(ctx context.Context, objectPath string, cacheControl string) error {
+	reader, _, err := s.openObject(objectPath)
+	if err != nil {
+		return err
+	}
+	return reader.Close()
+}
+
+func (s *objectStore) GetPublicURL(objectPath string) string {
+	return fmt.Sprintf("%s/%s", s.baseURL, objectPath)
+}
+
+func (s *objectStore) ObjectPathFromURL(fileURL string) (string, bool) {
+	prefix := s.baseURL + "/"
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+	objectPath := strings.TrimPrefix(fileURL, prefix)
+	if i := strings.IndexByte(objectPath, '?'); i >= 0 {
+		objectPath = objectPath[:i]
+	}
+	return objectPath, true
+}
+
+// WriteObject stores the contents of r at objectPath, replacing any existing object
+func (s *objectStore) WriteObject(objectPath string, contentType string, r io.Reader) (int64, error) {
+	cleaned, err := cleanObjectPath(objectPath)
+	if err != nil {
+		return 0, err
+	}
+	return s.backend.put(cleaned, contentType, r)
+}
+
+// OpenObject opens the stored object for reading
+func (s *objectStore) OpenObject(objectPath string) (io.ReadSeekCloser, error) {
+	reader, _, err := s.openObject(objectPath)
+	return reader, err
+}
+
+func (s *objectStore) openObject(objectPath string) (io.ReadSeekCloser, *ObjectInfo, error) {
+	cleaned, err := cleanObjectPath(objectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, info, err := s.backend.get(cleaned)
+	if err != nil {
+		return nil, nil, err
+	}
+	info.Path = objectPath
+	return reader, info, nil
+}
+
+// VerifySignedURL checks the expires and signature query parameters of a URL
+// produced by GetFileSignedURL or GetSignedUploadURL against the request's
+// signed headers; reads pass a nil header
+func (s *objectStore) VerifySignedURL(method, objectPath string, header http.Header, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := s.signature(method, objectPath, header.Get(ContentTypeHeader), header.Get(ContentLengthRangeHeader), expires)
+	provided, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || !hmac.Equal(expected, provided) {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expires {
+		return ErrSignedURLExpired
+	}
+
+	return nil
+}
+
+// ServeHTTP serves the store's URLs relative to baseURL: GET and HEAD read an
+// object, PUT uploads one through a URL from GetSignedUploadURL. Like a private
+// bucket, only objects of public classes can be read without a valid signature.
+func (s *objectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	objectPath := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if query.Has("signature") || ClassifyObject(objectPath).Visibility != VisibilityPublic {
+			if err := s.VerifySignedURL(http.MethodGet, objectPath, nil, query); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+
+		reader, info, err := s.openObject(objectPath)
+		if err != nil {
+			if errors.Is(err, ErrObjectNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer reader.Close()
+
+		w.Header().Set(ContentTypeHeader, info.ContentType)
+		w.Header().Set("Cache-Control", CacheControlFor(objectPath))
+		http.ServeContent(w, r, path.Base(objectPath), info.CreatedOn, reader)
+	case http.MethodPut:
+		if err := s.VerifySignedURL(http.MethodPut, objectPath, r.Header, query); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		limit, ok := parseContentLengthRange(r.Header.Get(ContentLengthRangeHeader))
+		if !ok {
+			http.Error(w, "missing or invalid "+ContentLengthRangeHeader+" header", http.StatusBadRequest)
+			return
+		}
+		if r.ContentLength > limit {
+			http.Error(w, fmt.Sprintf("upload exceeds the maximum size of %d bytes", limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		body := http.MaxBytesReader(w, r.Body, limit)
+		if _, err := s.WriteObject(objectPath, r.Header.Get(ContentTypeHeader), body); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, fmt.Sprintf("upload exceeds the maximum size of %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *objectStore) signURL(method, objectPath, contentType, lengthRange string, expiresAt time.Time) string {
+	expires := expiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", hex.EncodeToString(s.signature(method, objectPath, contentType, lengthRange, expires)))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, objectPath, query.Encode())
+}
+
+func (s *objectStore) signature(method, objectPath, contentType, lengthRange string, expires int64) []byte {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%d", method, objectPath, contentType, lengthRange, expires)
+	return mac.Sum(nil)
+}
+
+func sortObjects(objects []ObjectInfo) {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Path < objects[j].Path
+	})
+}
+
+// cleanObjectPath normalizes an object path, rejecting paths that would escape the store
+func cleanObjectPath(objectPath string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(objectPath, "/"))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid object path %q", objectPath)
+	}
+	return cleaned, nil
+}
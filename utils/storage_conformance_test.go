@@ -0,0 +1,42 @@
+package utils_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils/storagetest"
+)
+
+func TestLocalStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, baseURL string) utils.GCSService {
+		storage, err := utils.NewLocalStorageService(t.TempDir(), baseURL, "conformance-key")
+		require.NoError(t, err)
+		return storage
+	})
+}
+
+func TestMemoryStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, baseURL string) utils.GCSService {
+		storage, err := utils.NewMemoryStorageService(baseURL, "conformance-key")
+		require.NoError(t, err)
+		return storage
+	})
+}
+
+// TestGCSStorageConformance runs against a real bucket when STORAGE_TEST_GCS_BUCKET
+// and STORAGE_TEST_GCS_PROJECT are set, using application default credentials
+func TestGCSStorageConformance(t *testing.T) {
+	bucket, project := os.Getenv("STORAGE_TEST_GCS_BUCKET"), os.Getenv("STORAGE_TEST_GCS_PROJECT")
+	if bucket == "" || project == "" {
+		t.Skip("STORAGE_TEST_GCS_BUCKET and STORAGE_TEST_GCS_PROJECT are not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T, baseURL string) utils.GCSService {
+		storage, err := utils.NewGCSService(bucket, project)
+		require.NoError(t, err)
+		return storage
+	})
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/pkg/cache"
+)
+
+// ObjectVisibility controls who may read objects of a class
+type ObjectVisibility string
+
+const (
+	// VisibilityPublic objects are readable by anyone through their public URL
+	VisibilityPublic ObjectVisibility = "public"
+	// VisibilityPrivate objects are only handed out as short-lived signed URLs
+	VisibilityPrivate ObjectVisibility = "private"
+	// VisibilityModerated objects are private in storage; services only show them
+	// to other users once moderation approved them, still through signed URLs
+	VisibilityModerated ObjectVisibility = "moderated"
+)
+
+const (
+	publicCacheControl  = "public, max-age=86400"
+	privateCacheControl = "private, no-store"
+)
+
+// ObjectClass groups stored objects by path prefix and decides how they are exposed
+type ObjectClass struct {
+	Name       string
+	Prefix     string
+	Visibility ObjectVisibility
+	// URLExpiry is how long signed URLs for the class stay valid
+	URLExpiry time.Duration
+	// UploadKinds are the media kinds clients may upload into the class
+	UploadKinds []string
+}
+
+// ObjectClasses lists the known classes. Anything else falls back to
+// DefaultObjectClass, so new prefixes are private until classified here.
+var ObjectClasses = []ObjectClass{
+	{Name: "avatar", Prefix: "avatars/", Visibility: VisibilityPublic, UploadKinds: []string{"image"}},
+	{Name: "kyc", Prefix: "winners/kyc/", Visibility: VisibilityPrivate, URLExpiry: 15 * time.Minute, UploadKinds: []string{"image"}},
+	{Name: "qr_code", Prefix: "winners/week_", Visibility: VisibilityPrivate, URLExpiry: time.Hour},
+	{Name: "submission", Prefix: "thunder-seat/", Visibility: VisibilityModerated, URLExpiry: time.Hour, UploadKinds: []string{"audio", "video", "image"}},
+	{Name: "profile_photo", Prefix: "profile-photos/", Visibility: VisibilityModerated, URLExpiry: time.Hour, UploadKinds: []string{"image"}},
+}
+
+// DefaultObjectClass applies to objects outside every known prefix
+var DefaultObjectClass = ObjectClass{Name: "default", Visibility: VisibilityPrivate, URLExpiry: 15 * time.Minute}
+
+// ClassifyObject returns the class an object path belongs to
+func ClassifyObject(objectPath string) ObjectClass {
+	objectPath = strings.TrimPrefix(objectPath, "/")
+	for _, class := range ObjectClasses {
+		if strings.HasPrefix(objectPath, class.Prefix) {
+			return class
+		}
+	}
+	return DefaultObjectClass
+}
+
+// CacheControlFor returns the Cache-Control header objects at objectPath are stored
+// with; private objects must never be kept by shared caches
+func CacheControlFor(objectPath string) string {
+	if ClassifyObject(objectPath).Visibility == VisibilityPublic {
+		return publicCacheControl
+	}
+	return privateCacheControl
+}
+
+// ObjectURLResolver returns the URL clients should use for a stored object: the
+// public URL for public classes and a signed URL for everything else. Signed URLs
+// are cached for half their lifetime so every URL handed out stays valid for at
+// least the other half, and repeated requests do not re-sign.
+type ObjectURLResolver struct {
+	storage GCSService
+	signed  *cache.TTLCache[string, string]
+}
+
+func NewObjectURLResolver(storage GCSService) *ObjectURLResolver {
+	return &ObjectURLResolver{
+		storage: storage,
+		signed:  cache.NewTTLCache[string, string](DefaultObjectClass.URLExpiry / 2),
+	}
+}
+
+// URL returns the URL for objectPath according to its class
+func (r *ObjectURLResolver) URL(ctx context.Context, objectPath string) (string, error) {
+	class := ClassifyObject(objectPath)
+	if class.Visibility == VisibilityPublic {
+		return r.storage.GetPublicURL(objectPath), nil
+	}
+
+	if url, ok := r.signed.Get(objectPath); ok {
+		return url, nil
+	}
+
+	url, err := r.storage.GetFileSignedURL(ctx, objectPath, class.URLExpiry)
+	if err != nil {
+		return "", err
+	}
+	r.signed.SetWithTTL(objectPath, url, class.URLExpiry/2)
+	return url, nil
+}
+
+// Resolve is URL for optional fields in responses: it returns nil for an empty
+// path and logs and returns nil when signing fails
+func (r *ObjectURLResolver) Resolve(ctx context.Context, objectPath string) *string {
+	if objectPath == "" {
+		return nil
+	}
+
+	url, err := r.URL(ctx, objectPath)
+	if err != nil {
+		log.WithError(err).WithField("object_path", objectPath).Warn("Failed to resolve object URL")
+		return nil
+	}
+	return &url
+}
+
+// ResolveStored is Resolve for values that may hold a URL of this storage, as
+// older rows do, instead of an object path. Foreign URLs are returned unchanged.
+func (r *ObjectURLResolver) ResolveStored(ctx context.Context, stored string) *string {
+	if objectPath, ok := r.storage.ObjectPathFromURL(stored); ok {
+		return r.Resolve(ctx, objectPath)
+	}
+	if strings.HasPrefix(stored, "http://") || strings.HasPrefix(stored, "https://") {
+		return &stored
+	}
+	return r.Resolve(ctx, stored)
+}
+
+// Forget drops the cached signed URL of an object, e.g. after it was deleted or replaced
+func (r *ObjectURLResolver) Forget(objectPath string) {
+	r.signed.Delete(objectPath)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyObject(t *testing.T) {
+	tests := []struct {
+		path       string
+		class      string
+		visibility ObjectVisibility
+	}{
+		{"avatars/admin/a.png", "avatar", VisibilityPublic},
+		{"/avatars/admin/a.png", "avatar", VisibilityPublic},
+		{"winners/kyc/aadhar/front.jpg", "kyc", VisibilityPrivate},
+		{"winners/week_3/qr.png", "qr_code", VisibilityPrivate},
+		{"thunder-seat/user/week-1/clip.mp4", "submission", VisibilityModerated},
+		{"profile-photos/user/photo_full.jpg", "profile_photo", VisibilityModerated},
+		{"exports/report.csv", "default", VisibilityPrivate},
+	}
+
+	for _, tt := range tests {
+		class := ClassifyObject(tt.path)
+		assert.Equal(t, tt.class, class.Name, tt.path)
+		assert.Equal(t, tt.visibility, class.Visibility, tt.path)
+	}
+
+	assert.Equal(t, publicCacheControl, CacheControlFor("avatars/admin/a.png"))
+	assert.Equal(t, privateCacheControl, CacheControlFor("winners/kyc/aadhar/front.jpg"))
+}
+
+func TestObjectURLResolver(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewMemoryStorageService("http://storage.test/storage", "key")
+	require.NoError(t, err)
+	resolver := NewObjectURLResolver(storage)
+
+	avatar, err := resolver.URL(ctx, "avatars/admin/a.png")
+	require.NoError(t, err)
+	assert.Equal(t, "http://storage.test/storage/avatars/admin/a.png", avatar)
+
+	qr, err := resolver.URL(ctx, "winners/week_1/qr.png")
+	require.NoError(t, err)
+	assert.Contains(t, qr, "signature=")
+
+	cached, err := resolver.URL(ctx, "winners/week_1/qr.png")
+	require.NoError(t, err)
+	assert.Equal(t, qr, cached)
+
+	assert.Nil(t, resolver.Resolve(ctx, ""))
+
+	stored := resolver.ResolveStored(ctx, storage.GetPublicURL("winners/kyc/aadhar/front.jpg"))
+	require.NotNil(t, stored)
+	assert.True(t, strings.Contains(*stored, "signature="), *stored)
+
+	foreign := "https://cdn.example.com/image.png"
+	assert.Equal(t, &foreign, resolver.ResolveStored(ctx, foreign))
+}
@@ -0,0 +1,355 @@
+// Package storagetest is a conformance suite every utils.GCSService driver must pass
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Infinite-Locus-Product/thums_up_backend/utils"
+)
+
+// Factory creates the driver under test. baseURL is where the suite serves the
+// driver's ServeHTTP when it implements http.Handler; drivers that serve their own
+// URLs, like GCS, ignore it.
+type Factory func(t *testing.T, baseURL string) utils.GCSService
+
+// Run exercises every GCSService method against the driver. Objects are written
+// under a random prefix and deleted afterwards.
+func Run(t *testing.T, newStorage Factory) {
+	var handler http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler == nil {
+			http.NotFound(w, r)
+			return
+		}
+		http.StripPrefix("/storage", handler).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	storage := newStorage(t, server.URL+"/storage")
+	if h, ok := storage.(http.Handler); ok {
+		handler = h
+	}
+
+	prefix := "conformance/" + uuid.New().String()
+	s := &suite{storage: storage, prefix: prefix}
+
+	t.Run("UploadFileFromBytes", s.testUploadFileFromBytes)
+	t.Run("UploadFile", s.testUploadFile)
+	t.Run("UploadFileFromReader", s.testUploadFileFromReader)
+	t.Run("ReadObjectRange", s.testReadObjectRange)
+	t.Run("NewObjectReader", s.testNewObjectReader)
+	t.Run("MissingObject", s.testMissingObject)
+	t.Run("DeleteFile", s.testDeleteFile)
+	t.Run("GetFileSignedURL", s.testGetFileSignedURL)
+	t.Run("GetSignedUploadURL", s.testGetSignedUploadURL)
+	t.Run("ListObjects", s.testListObjects)
+	t.Run("SetCacheControl", s.testSetCacheControl)
+	t.Run("ObjectPathFromURL", s.testObjectPathFromURL)
+}
+
+type suite struct {
+	storage utils.GCSService
+	prefix  string
+}
+
+func (s *suite) objectPath(name string) string {
+	return fmt.Sprintf("%s/%s", s.prefix, name)
+}
+
+// put stores content and deletes it when the test ends
+func (s *suite) put(t *testing.T, name, contentType, content string) string {
+	t.Helper()
+	objectPath := s.objectPath(name)
+	url, key, err := s.storage.UploadFileFromBytes(context.Background(), []byte(content), objectPath, contentType)
+	require.NoError(t, err)
+	assert.Equal(t, objectPath, key)
+	assert.Equal(t, s.storage.GetPublicURL(objectPath), url)
+	s.cleanup(t, url)
+	return objectPath
+}
+
+func (s *suite) cleanup(t *testing.T, url string) {
+	t.Cleanup(func() {
+		_ = s.storage.DeleteFile(context.Background(), url)
+	})
+}
+
+func (s *suite) testUploadFileFromBytes(t *testing.T) {
+	ctx := context.Background()
+	content := "conformance content"
+	objectPath := s.put(t, "bytes.txt", "text/plain", content)
+
+	info, err := s.storage.GetObjectInfo(ctx, objectPath)
+	require.NoError(t, err)
+	checksum := md5.Sum([]byte(content))
+	assert.Equal(t, int64(len(content)), info.Size)
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, checksum[:], info.MD5)
+	assert.False(t, info.CreatedOn.IsZero())
+
+	byPath, err := s.storage.ReadFileContent(ctx, objectPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, byPath)
+
+	byURL, err := s.storage.ReadFileContent(ctx, s.storage.GetPublicURL(objectPath))
+	require.NoError(t, err)
+	assert.Equal(t, content, byURL)
+}
+
+func (s *suite) testUploadFile(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("\x89PNG\r\n\x1a\nnot really a png")
+	file := fileHeader(t, "picture.PNG", "image/png", content)
+
+	url, filename, err := s.storage.UploadFile(ctx, file, s.prefix)
+	require.NoError(t, err)
+	s.cleanup(t, url)
+	assert.True(t, strings.HasSuffix(filename, ".png"), filename)
+	assert.NotContains(t, filename, "/")
+	assert.Equal(t, s.storage.GetPublicURL(s.objectPath(filename)), url)
+
+	info, err := s.storage.GetObjectInfo(ctx, s.objectPath(filename))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.Equal(t, int64(len(content)), info.Size)
+}
+
+func (s *suite) testUploadFileFromReader(t *testing.T) {
+	ctx := context.Background()
+
+	url, objectPath, err := s.storage.UploadFileFromReader(ctx, io.NopCloser(strings.NewReader("streamed")), s.prefix)
+	require.NoError(t, err)
+	s.cleanup(t, url)
+	assert.True(t, strings.HasPrefix(objectPath, s.prefix+"/"), objectPath)
+	assert.Equal(t, s.storage.GetPublicURL(objectPath), url)
+
+	content, err := s.storage.ReadFileContent(ctx, objectPath)
+	require.NoError(t, err)
+	assert.Equal(t, "streamed", content)
+}
+
+func (s *suite) testReadObjectRange(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.put(t, "range.txt", "text/plain", "0123456789")
+
+	data, err := s.storage.ReadObjectRange(ctx, objectPath, 2, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "234", string(data))
+
+	data, err = s.storage.ReadObjectRange(ctx, objectPath, 7, -1)
+	require.NoError(t, err)
+	assert.Equal(t, "789", string(data))
+
+	data, err = s.storage.ReadObjectRange(ctx, objectPath, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+}
+
+func (s *suite) testNewObjectReader(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.put(t, "stream.txt", "text/plain", "0123456789")
+
+	reader, err := s.storage.NewObjectReader(ctx, objectPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+}
+
+func (s *suite) testMissingObject(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.objectPath("missing.txt")
+
+	_, err := s.storage.GetObjectInfo(ctx, objectPath)
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+
+	_, err = s.storage.ReadObjectRange(ctx, objectPath, 0, -1)
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+
+	_, err = s.storage.ReadFileContent(ctx, objectPath)
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+
+	_, err = s.storage.NewObjectReader(ctx, objectPath)
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+
+	err = s.storage.DeleteFile(ctx, s.storage.GetPublicURL(objectPath))
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+}
+
+func (s *suite) testDeleteFile(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.put(t, "delete.txt", "text/plain", "short lived")
+
+	require.NoError(t, s.storage.DeleteFile(ctx, s.storage.GetPublicURL(objectPath)))
+
+	_, err := s.storage.GetObjectInfo(ctx, objectPath)
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+
+	assert.Error(t, s.storage.DeleteFile(ctx, "https://example.com/not/a/storage/url"))
+}
+
+func (s *suite) testGetFileSignedURL(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.put(t, "signed.txt", "text/plain", "signed read")
+
+	signedURL, err := s.storage.GetFileSignedURL(ctx, objectPath, time.Minute)
+	require.NoError(t, err)
+
+	resp, err := http.Get(signedURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Equal(t, "signed read", string(body))
+
+	// The suite's prefix is outside every public class, so unsigned reads are refused
+	resp, err = http.Get(s.storage.GetPublicURL(objectPath))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+
+	tampered := strings.Replace(signedURL, "signed.txt", "delete.txt", 1)
+	resp, err = http.Get(tampered)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func (s *suite) testGetSignedUploadURL(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.objectPath("direct.bin")
+	content := bytes.Repeat([]byte("direct upload "), 64)
+
+	upload, err := s.storage.GetSignedUploadURL(ctx, objectPath, "application/octet-stream", int64(len(content)), time.Minute)
+	require.NoError(t, err)
+	s.cleanup(t, s.storage.GetPublicURL(objectPath))
+	assert.True(t, upload.ExpiresAt.After(time.Now()))
+	assert.Equal(t, fmt.Sprintf("0,%d", len(content)), upload.Headers[utils.ContentLengthRangeHeader])
+
+	uploadURL := upload.URL
+	method := upload.Method
+	if upload.Resumable {
+		// Start the resumable session, then upload the bytes to the session URI
+		resp := doRequest(t, upload.Method, upload.URL, upload.Headers, nil)
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+		uploadURL = resp.Header.Get("Location")
+		require.NotEmpty(t, uploadURL)
+		method = http.MethodPut
+	}
+
+	resp := doRequest(t, method, uploadURL, upload.Headers, content)
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+
+	info, err := s.storage.GetObjectInfo(ctx, objectPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.Size)
+
+	// A signed upload URL only accepts the content type it was signed for
+	if !upload.Resumable {
+		headers := map[string]string{utils.ContentTypeHeader: "text/html"}
+		resp := doRequest(t, method, uploadURL, headers, []byte("<html></html>"))
+		assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// Nor more bytes than its content length range allows
+	if !upload.Resumable {
+		resp := doRequest(t, method, uploadURL, upload.Headers, append(content, 'x'))
+		assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+	}
+
+	_, err = s.storage.GetSignedUploadURL(ctx, objectPath, "application/octet-stream", 0, time.Minute)
+	assert.Error(t, err, "a signed upload without a size limit must be refused")
+}
+
+func (s *suite) testListObjects(t *testing.T) {
+	ctx := context.Background()
+	first := s.put(t, "list/a.txt", "text/plain", "a")
+	second := s.put(t, "list/b.txt", "text/plain", "bb")
+	s.put(t, "unlisted.txt", "text/plain", "c")
+
+	objects, err := s.storage.ListObjects(ctx, s.objectPath("list/"))
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, first, objects[0].Path)
+	assert.Equal(t, int64(1), objects[0].Size)
+	assert.Equal(t, second, objects[1].Path)
+	assert.Equal(t, "text/plain", objects[1].ContentType)
+	assert.False(t, objects[1].CreatedOn.IsZero())
+
+	objects, err = s.storage.ListObjects(ctx, s.objectPath("nothing/"))
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func (s *suite) testSetCacheControl(t *testing.T) {
+	ctx := context.Background()
+	objectPath := s.put(t, "cache.txt", "text/plain", "cached")
+
+	require.NoError(t, s.storage.SetCacheControl(ctx, objectPath, "private, no-store"))
+
+	err := s.storage.SetCacheControl(ctx, s.objectPath("missing-cache.txt"), "private, no-store")
+	assert.ErrorIs(t, err, utils.ErrObjectNotFound)
+}
+
+func (s *suite) testObjectPathFromURL(t *testing.T) {
+	objectPath := s.objectPath("some/file.png")
+
+	got, ok := s.storage.ObjectPathFromURL(s.storage.GetPublicURL(objectPath))
+	assert.True(t, ok)
+	assert.Equal(t, objectPath, got)
+
+	_, ok = s.storage.ObjectPathFromURL("https://example.com/elsewhere/file.png")
+	assert.False(t, ok)
+}
+
+func doRequest(t *testing.T, method, url string, headers map[string]string, body []byte) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+func fileHeader(t *testing.T, filename, contentType string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	header.Set(utils.ContentTypeHeader, contentType)
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
@@ -50,6 +50,25 @@ func GenerateReferralCode() (string, error) {
 	return string(code), nil
 }
 
+// MaskName shortens a name for public listings: the first name keeps its first two
+// letters and later names only their initial, e.g. "Rahul Kumar" becomes "Ra*** K."
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	first := []rune(words[0])
+	if len(first) > 2 {
+		first = first[:2]
+	}
+	masked := string(first) + "***"
+	for _, word := range words[1:] {
+		masked += " " + string([]rune(word)[:1]) + "."
+	}
+	return masked
+}
+
 func PtrString(s string) *string {
 	return &s
 }
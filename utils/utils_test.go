@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -73,6 +74,28 @@ func TestFormatPhoneNumber(t *testing.T) {
 	}
 }
 
+func TestMaskName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Full name", "Rahul Kumar", "Ra*** K."},
+		{"Single name", "Rahul", "Ra***"},
+		{"Short name", "Al", "Al***"},
+		{"Three names", "Anil Kumar Singh", "An*** K. S."},
+		{"Extra spaces", "  Rahul   Kumar ", "Ra*** K."},
+		{"Non-ASCII", "अमित शर्मा", "अम*** श."},
+		{"Empty", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MaskName(tt.input))
+		})
+	}
+}
+
 func TestIsValidEmail(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -115,3 +138,18 @@ func TestIsValidPhoneNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatValidationErrors_FieldErrors(t *testing.T) {
+	fieldErrors := FieldErrors{}
+	fieldErrors.Add("answers[0].answer_id", "answer_id is not an option of this question")
+	fieldErrors.Add("answers[1].rating", "rating must be between 1 and 5")
+
+	wrapped := fmt.Errorf("validation failed: %w", fieldErrors)
+	assert.Equal(t, map[string][]string{
+		"answers[0].answer_id": {"answer_id is not an option of this question"},
+		"answers[1].rating":    {"rating must be between 1 and 5"},
+	}, FormatValidationErrors(wrapped))
+	assert.Equal(t, "answer_id is not an option of this question; rating must be between 1 and 5", fieldErrors.Error())
+
+	assert.Empty(t, FormatValidationErrors(fmt.Errorf("unrelated")))
+}
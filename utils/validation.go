@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -16,6 +18,28 @@ type ValidationError struct {
 	Value   interface{} `json:"value,omitempty"`
 }
 
+// FieldErrors collects validation failures found outside binding tags, such as
+// checks against stored data, keyed by the JSON path of the offending field
+type FieldErrors map[string][]string
+
+func (e FieldErrors) Add(field, message string) {
+	e[field] = append(e[field], message)
+}
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, strings.Join(e[field], ", "))
+	}
+	return strings.Join(messages, "; ")
+}
+
 func FormatValidationErrors(err error) map[string][]string {
 	fieldErrors := make(map[string][]string)
 
@@ -27,6 +51,13 @@ func FormatValidationErrors(err error) map[string][]string {
 		}
 	}
 
+	var checked FieldErrors
+	if errors.As(err, &checked) {
+		for field, messages := range checked {
+			fieldErrors[field] = append(fieldErrors[field], messages...)
+		}
+	}
+
 	return fieldErrors
 }
 